@@ -0,0 +1,115 @@
+package backup
+
+import (
+	"io"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// encodeArtifact wraps the raw artifact writer so that the tar stream written to
+// it is compressed and then encrypted, as configured for the backup.
+func encodeArtifact(raw io.WriteCloser, compression string, key []byte) (io.WriteCloser, error) {
+	compressor, err := compressorFor(compression)
+	if err != nil {
+		return nil, err
+	}
+
+	var writer = raw
+	if key != nil {
+		writer, err = newEncryptingWriter(key, raw)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to set up encryption")
+		}
+	}
+
+	if compressor != nil {
+		return compressor.NewWriter(writer), nil
+	}
+	return writer, nil
+}
+
+// decodeArtifact is the inverse of encodeArtifact: it returns the plain tar
+// stream of an artifact read from the raw reader.
+func decodeArtifact(raw io.ReadCloser, compression string, key []byte) (io.ReadCloser, error) {
+	compressor, err := compressorFor(compression)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader = raw
+	if key != nil {
+		reader, err = newDecryptingReader(key, raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if compressor != nil {
+		decompressedReader, err := compressor.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress artifact")
+		}
+		return decompressedReader, nil
+	}
+	return reader, nil
+}
+
+// plaintextSizes remembers how many bytes of tar stream were written to each
+// encoded artifact, until the size is recorded in the metadata along with the
+// checksum. Once an artifact is compressed or encrypted, its stored size says
+// little about how much data a restore streams to the instance.
+type plaintextSizes struct {
+	sync.Mutex
+	sizes map[string]int64
+}
+
+// count wraps the encoded writer of an artifact, unless it writes the tar
+// stream as it is.
+func (plaintextSizes *plaintextSizes) count(artifactIdentifier orchestrator.ArtifactIdentifier, raw, encoded io.WriteCloser) io.WriteCloser {
+	if encoded == raw {
+		return encoded
+	}
+
+	return &plaintextCounter{WriteCloser: encoded, closed: func(size int64) {
+		defer plaintextSizes.Unlock()
+		plaintextSizes.Lock()
+
+		if plaintextSizes.sizes == nil {
+			plaintextSizes.sizes = map[string]int64{}
+		}
+		plaintextSizes.sizes[fileName(artifactIdentifier)] = size
+	}}
+}
+
+// take returns the plaintext size of an artifact written since it was last
+// taken, or zero if there is none.
+func (plaintextSizes *plaintextSizes) take(artifactIdentifier orchestrator.ArtifactIdentifier) int64 {
+	defer plaintextSizes.Unlock()
+	plaintextSizes.Lock()
+
+	size := plaintextSizes.sizes[fileName(artifactIdentifier)]
+	delete(plaintextSizes.sizes, fileName(artifactIdentifier))
+	return size
+}
+
+type plaintextCounter struct {
+	io.WriteCloser
+	written int64
+	closed  func(size int64)
+}
+
+func (w *plaintextCounter) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+func (w *plaintextCounter) Close() error {
+	if err := w.WriteCloser.Close(); err != nil {
+		return err
+	}
+	w.closed(w.written)
+	return nil
+}
@@ -1,7 +1,7 @@
 package backup
 
 import (
-	"archive/tar"
+	"crypto/ed25519"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -11,7 +11,6 @@ import (
 	"strconv"
 	"strings"
 
-	"crypto/sha256"
 	"time"
 
 	"sync"
@@ -24,11 +23,39 @@ const timestampFormat = "2006/01/02 15:04:05 MST"
 
 type BackupDirectory struct {
 	orchestrator.Logger
-	baseDirName string
+	baseDirName    string
+	compression    string
+	encryptionKey  *EncryptionKey
+	derivedKey     []byte
+	chunkStore     *ChunkStore
+	previousPath   string
+	signingKey     ed25519.PrivateKey
+	trustedKey     ed25519.PublicKey
+	plaintextSizes plaintextSizes
 	sync.Mutex
 }
 
 func (backupDirectory *BackupDirectory) GetArtifactSize(artifactIdentifier orchestrator.ArtifactIdentifier) (string, error) {
+	if backupDirectory.isIncremental() {
+		size, err := backupDirectory.GetArtifactByteSize(artifactIdentifier)
+		if err != nil {
+			return "", err
+		}
+		return humanReadableSize(int64(size)), nil
+	}
+
+	if size, found := backupDirectory.recordedPlaintextSize(artifactIdentifier); found {
+		return humanReadableSize(size), nil
+	}
+
+	if backupDirectory.isChunked(artifactIdentifier) {
+		size, err := backupDirectory.chunkedArtifactSize(artifactIdentifier)
+		if err != nil {
+			return "", err
+		}
+		return humanReadableSize(size), nil
+	}
+
 	filename := backupDirectory.instanceFilename(artifactIdentifier)
 
 	cmd := exec.Command("du", "-sh", filename)
@@ -44,6 +71,29 @@ func (backupDirectory *BackupDirectory) GetArtifactSize(artifactIdentifier orche
 }
 
 func (backupDirectory *BackupDirectory) GetArtifactByteSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int, error) {
+	size, err := backupDirectory.storedArtifactByteSize(artifactIdentifier)
+	if err != nil {
+		return 0, err
+	}
+
+	previousSize, err := backupDirectory.previousArtifactByteSize(artifactIdentifier)
+	if err != nil {
+		return 0, err
+	}
+
+	return size + previousSize, nil
+}
+
+func (backupDirectory *BackupDirectory) storedArtifactByteSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int, error) {
+	if size, found := backupDirectory.recordedPlaintextSize(artifactIdentifier); found {
+		return int(size), nil
+	}
+
+	if backupDirectory.isChunked(artifactIdentifier) {
+		size, err := backupDirectory.chunkedArtifactSize(artifactIdentifier)
+		return int(size), err
+	}
+
 	filename := backupDirectory.instanceFilename(artifactIdentifier)
 
 	cmd := exec.Command("du", filename)
@@ -61,6 +111,21 @@ func (backupDirectory *BackupDirectory) GetArtifactByteSize(artifactIdentifier o
 	return size * 512, nil
 }
 
+// recordedPlaintextSize is the size of the tar stream of a compressed or
+// encrypted artifact, which its file on disk says little about.
+func (backupDirectory *BackupDirectory) recordedPlaintextSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int64, bool) {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return 0, false
+	}
+
+	artifact, found := metadata.findArtifactMetadata(artifactIdentifier)
+	return artifact.PlaintextSize, found && artifact.PlaintextSize > 0
+}
+
 func (backupDirectory *BackupDirectory) logAndReturn(err error, message string, args ...interface{}) error {
 	message = fmt.Sprintf(message, args...)
 	backupDirectory.Debug("bbr", "%s: %v", message, err)
@@ -77,30 +142,56 @@ func (backupDirectory *BackupDirectory) DeploymentMatches(deployment string, ins
 		return false, backupDirectory.logAndReturn(err, "Error reading metadata file")
 	}
 
-	for _, inst := range meta.MetadataForEachInstance {
-		present := backupDirectory.backupInstanceIsPresent(inst, instances)
-		if present != true {
-			backupDirectory.Debug("bbr", "Instance %v/%v not found in %v", inst.Name, inst.Index, instances)
-			return false, nil
-		}
+	if missing := meta.instancesNotIn(instances); len(missing) > 0 {
+		return false, orchestrator.NewDeploymentMismatchError(fmt.Sprintf("the backup has instances that are not in deployment '%s'", deployment), missing)
 	}
 
 	return true, nil
 }
 
+func (backupDirectory *BackupDirectory) BackedUpInstances() ([]orchestrator.InstanceMetadata, error) {
+	meta, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return nil, backupDirectory.logAndReturn(err, "Error reading metadata file")
+	}
+
+	return meta.instances(), nil
+}
+
 func (backupDirectory *BackupDirectory) CreateArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.WriteCloser, error) {
 	backupDirectory.Debug("bbr", "Trying to create file %s", fileName(artifactIdentifier))
 
+	if backupDirectory.chunkStore != nil {
+		return backupDirectory.chunkStore.newArtifactWriter(backupDirectory.chunkManifestFilename(artifactIdentifier)), nil
+	}
+
+	key, err := backupDirectory.artifactKey()
+	if err != nil {
+		return nil, backupDirectory.logAndReturn(err, "Error creating file %s", fileName(artifactIdentifier))
+	}
+
 	file, err := os.Create(path.Join(backupDirectory.baseDirName, fileName(artifactIdentifier)))
 	if err != nil {
 		return nil, backupDirectory.logAndReturn(err, "Error creating file %s", fileName(artifactIdentifier))
 
 	}
 
-	return file, err
+	writer, err := encodeArtifact(file, backupDirectory.compression, key)
+	if err != nil {
+		file.Close()
+		return nil, backupDirectory.logAndReturn(err, "Error creating file %s", fileName(artifactIdentifier))
+	}
+
+	return backupDirectory.plaintextSizes.count(artifactIdentifier, file, writer), nil
 }
 
-func (backupDirectory *BackupDirectory) ReadArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.ReadCloser, error) {
+// readStoredArtifact returns the tar stream stored in this directory. For
+// incremental backups it only contains the files that changed.
+func (backupDirectory *BackupDirectory) readStoredArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.ReadCloser, error) {
+	if backupDirectory.isChunked(artifactIdentifier) {
+		return backupDirectory.readChunkedArtifact(artifactIdentifier)
+	}
+
 	filename := backupDirectory.instanceFilename(artifactIdentifier)
 	backupDirectory.Debug("bbr", "Trying to open %s", filename)
 	file, err := os.Open(filename)
@@ -109,7 +200,123 @@ func (backupDirectory *BackupDirectory) ReadArtifact(artifactIdentifier orchestr
 		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
 	}
 
-	return file, nil
+	key, err := backupDirectory.artifactKey()
+	if err != nil {
+		file.Close()
+		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
+	}
+
+	reader, err := decodeArtifact(file, backupDirectory.artifactCompression(artifactIdentifier), key)
+	if err != nil {
+		file.Close()
+		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
+	}
+
+	return reader, nil
+}
+
+func (backupDirectory *BackupDirectory) readChunkedArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.ReadCloser, error) {
+	filename := backupDirectory.chunkManifestFilename(artifactIdentifier)
+	backupDirectory.Debug("bbr", "Trying to reassemble %s", filename)
+
+	store, err := backupDirectory.artifactChunkStore()
+	if err != nil {
+		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
+	}
+
+	reader, err := store.openArtifact(filename)
+	if err != nil {
+		return nil, backupDirectory.logAndReturn(err, "Error reading artifact file %s", filename)
+	}
+	return reader, nil
+}
+
+func (backupDirectory *BackupDirectory) chunkedArtifactSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int64, error) {
+	filename := backupDirectory.chunkManifestFilename(artifactIdentifier)
+
+	store, err := backupDirectory.artifactChunkStore()
+	if err != nil {
+		return 0, backupDirectory.logAndReturn(err, "failed to determine file size for file %s", filename)
+	}
+
+	size, err := store.artifactSize(filename)
+	if err != nil {
+		return 0, backupDirectory.logAndReturn(err, "failed to determine file size for file %s", filename)
+	}
+	return size, nil
+}
+
+// isChunked reports whether the artifact was written to a chunk store, in which
+// case the backup directory only holds its chunk manifest.
+func (backupDirectory *BackupDirectory) isChunked(artifactIdentifier orchestrator.ArtifactIdentifier) bool {
+	_, err := os.Stat(backupDirectory.chunkManifestFilename(artifactIdentifier))
+	return err == nil
+}
+
+// artifactChunkStore returns the chunk store this directory was opened with,
+// falling back to the store recorded in the metadata file, so that chunked
+// backups can be restored without naming the store again.
+func (backupDirectory *BackupDirectory) artifactChunkStore() (*ChunkStore, error) {
+	if backupDirectory.chunkStore != nil {
+		return backupDirectory.chunkStore, nil
+	}
+
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return nil, err
+	}
+	if metadata.ChunkStore == "" {
+		return nil, errors.New("no chunk store is recorded in the backup metadata")
+	}
+
+	return &ChunkStore{path: metadata.ChunkStore}, nil
+}
+
+// artifactCompression returns the compression recorded for the artifact in the
+// metadata file, falling back to the compression this directory was created with
+// for artifacts that have not been checksummed yet.
+func (backupDirectory *BackupDirectory) artifactCompression(artifactIdentifier orchestrator.ArtifactIdentifier) string {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return backupDirectory.compression
+	}
+
+	artifact, found := metadata.findArtifactMetadata(artifactIdentifier)
+	if !found {
+		return backupDirectory.compression
+	}
+	return artifact.Compression
+}
+
+func (backupDirectory *BackupDirectory) artifactKey() ([]byte, error) {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	if backupDirectory.derivedKey != nil {
+		return backupDirectory.derivedKey, nil
+	}
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		if backupDirectory.encryptionKey != nil {
+			return nil, errors.Wrap(err, "failed to read the encryption settings of the backup")
+		}
+		return nil, nil
+	}
+
+	key, err := metadata.resolveEncryptionKey(backupDirectory.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	backupDirectory.derivedKey = key
+	return key, nil
 }
 
 func (backupDirectory *BackupDirectory) FetchChecksum(artifactIdentifier orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
@@ -119,28 +326,24 @@ func (backupDirectory *BackupDirectory) FetchChecksum(artifactIdentifier orchest
 		return nil, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataFilename())
 	}
 
-	if artifactIdentifier.HasCustomName() {
-		for _, customArtifactInMetadata := range metadata.MetadataForEachArtifact {
-			if customArtifactInMetadata.Name == artifactIdentifier.Name() {
-				return customArtifactInMetadata.Checksum, nil
-			}
-		}
-	} else {
-		for _, instanceInMetadata := range metadata.MetadataForEachInstance {
-			if instanceInMetadata.Index == artifactIdentifier.InstanceIndex() && instanceInMetadata.Name == artifactIdentifier.InstanceName() {
-				for _, artifact := range instanceInMetadata.Artifacts {
-					if artifact.Name == artifactIdentifier.Name() {
-						return artifact.Checksum, nil
-					}
-				}
-			}
-		}
+	if artifact, found := metadata.findArtifactMetadata(artifactIdentifier); found {
+		return artifact.Checksum, nil
 	}
 
 	backupDirectory.Warn("bbr", "Checksum for %s not found in artifact", logName(artifactIdentifier))
 	return nil, nil
 }
 
+func (backupDirectory *BackupDirectory) ArtifactDrained(artifactIdentifier orchestrator.ArtifactIdentifier) (bool, error) {
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return false, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataFilename())
+	}
+
+	_, found := metadata.findArtifactMetadata(artifactIdentifier)
+	return found, nil
+}
+
 func logName(artifactIdentifer orchestrator.ArtifactIdentifier) string {
 	if artifactIdentifer.HasCustomName() {
 		return fmt.Sprintf("%s", artifactIdentifer.Name())
@@ -155,32 +358,20 @@ func (backupDirectory *BackupDirectory) CalculateChecksum(artifactIdentifier orc
 	}
 	defer file.Close()
 
-	tarReader := tar.NewReader(file)
-	checksum := map[string]string{}
-	for {
-		tarHeader, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, backupDirectory.logAndReturn(err, "Error reading tar for %s", logName(artifactIdentifier))
-		}
-		if tarHeader.FileInfo().IsDir() || tarHeader.FileInfo().Name() == "./" {
-			continue
-		}
-
-		fileShasum := sha256.New()
-		if _, err := io.Copy(fileShasum, tarReader); err != nil {
-			return nil, backupDirectory.logAndReturn(err, "Error calculating sha for %s", logName(artifactIdentifier))
-		}
-		backupDirectory.Logger.Debug("bbr", "Calculating shasum for local file %s", tarHeader.Name)
-		checksum[tarHeader.Name] = fmt.Sprintf("%x", fileShasum.Sum(nil))
+	checksum, err := orchestrator.CalculateTarChecksum(file, backupDirectory.Logger)
+	if err != nil {
+		return nil, backupDirectory.logAndReturn(err, "Error checksumming %s", logName(artifactIdentifier))
 	}
 
 	return checksum, nil
 }
 
 func (backupDirectory *BackupDirectory) AddChecksum(artifactIdentifier orchestrator.ArtifactIdentifier, shasum orchestrator.BackupChecksum) error {
+	size, err := backupDirectory.storedArtifactFileSize(artifactIdentifier)
+	if err != nil {
+		backupDirectory.Warn("bbr", "Could not determine the size of %s, it will not be recorded in the metadata: %s", logName(artifactIdentifier), err)
+	}
+
 	defer backupDirectory.Unlock()
 	backupDirectory.Lock()
 
@@ -193,22 +384,42 @@ func (backupDirectory *BackupDirectory) AddChecksum(artifactIdentifier orchestra
 		return backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataFilename())
 	}
 
-	if artifactIdentifier.HasCustomName() {
-		metadata.MetadataForEachArtifact = append(metadata.MetadataForEachArtifact, artifactMetadata{
-			Name:     artifactIdentifier.Name(),
-			Checksum: shasum,
-		})
-	} else {
-		instanceMetadata := metadata.findOrCreateInstanceMetadata(artifactIdentifier.InstanceName(), artifactIdentifier.InstanceIndex())
-		instanceMetadata.Artifacts = append(instanceMetadata.Artifacts, artifactMetadata{
-			Name:     artifactIdentifier.Name(),
-			Checksum: shasum,
-		})
-	}
+	metadata.addArtifact(artifactIdentifier, backupDirectory.compression, shasum, size, backupDirectory.plaintextSizes.take(artifactIdentifier))
 
 	return metadata.save(backupDirectory.metadataFilename())
 }
 
+// storedArtifactFileSize returns the exact number of bytes the artifact takes up
+// in this directory, or in the chunk store for chunked artifacts.
+func (backupDirectory *BackupDirectory) storedArtifactFileSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int64, error) {
+	if backupDirectory.isChunked(artifactIdentifier) {
+		return backupDirectory.chunkedArtifactSize(artifactIdentifier)
+	}
+
+	fileInfo, err := os.Stat(backupDirectory.instanceFilename(artifactIdentifier))
+	if err != nil {
+		return 0, err
+	}
+	return fileInfo.Size(), nil
+}
+
+func (backupDirectory *BackupDirectory) AddDeploymentMetadata(deploymentMetadata orchestrator.DeploymentMetadata) error {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
+	}
+
+	metadata.addDeploymentMetadata(deploymentMetadata)
+	if err := metadata.save(backupDirectory.metadataFilename()); err != nil {
+		return backupDirectory.logAndReturn(err, "unable to save metadata")
+	}
+
+	return nil
+}
+
 func (backupDirectory *BackupDirectory) CreateMetadataFileWithStartTime(startTime time.Time) error {
 	exists, _ := backupDirectory.metadataExistsAndIsReadable()
 	if exists {
@@ -218,16 +429,92 @@ func (backupDirectory *BackupDirectory) CreateMetadataFileWithStartTime(startTim
 	}
 
 	metadata := metadata{
+		Version: currentMetadataVersion,
 		MetadataForBackupActivity: backupActivityMetadata{
 			StartTime: startTime.Format(timestampFormat),
+			Status:    statusInProgress,
 		},
 	}
-	metadata.save(backupDirectory.metadataFilename())
+
+	if backupDirectory.chunkStore != nil {
+		metadata.ChunkStore = backupDirectory.chunkStore.Path()
+	}
+
+	metadata.IncrementalFrom = backupDirectory.previousPath
+
+	if backupDirectory.encryptionKey != nil {
+		key, err := metadata.setUpEncryption(backupDirectory.encryptionKey)
+		if err != nil {
+			return backupDirectory.logAndReturn(err, "unable to set up encryption")
+		}
+		backupDirectory.derivedKey = key
+	}
+
+	if err := metadata.save(backupDirectory.metadataFilename()); err != nil {
+		return backupDirectory.logAndReturn(err, "unable to save metadata")
+	}
+
+	return nil
+}
+
+func (backupDirectory *BackupDirectory) MarkComplete() error {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
+	}
+
+	metadata.MetadataForBackupActivity.Status = statusComplete
+	if err := metadata.save(backupDirectory.metadataFilename()); err != nil {
+		return backupDirectory.logAndReturn(err, "unable to save metadata")
+	}
+
+	return nil
+}
+
+func (backupDirectory *BackupDirectory) MarkDrainFailed() error {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
+	}
+
+	metadata.MetadataForBackupActivity.Status = statusDrainFailed
+	if err := metadata.save(backupDirectory.metadataFilename()); err != nil {
+		return backupDirectory.logAndReturn(err, "unable to save metadata")
+	}
+
+	return nil
+}
+
+func (backupDirectory *BackupDirectory) MarkResumed() error {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
+	}
+
+	if err := metadata.markResumed(); err != nil {
+		return backupDirectory.logAndReturn(err, "unable to resume %s", backupDirectory.baseDirName)
+	}
+
+	if err := metadata.save(backupDirectory.metadataFilename()); err != nil {
+		return backupDirectory.logAndReturn(err, "unable to save metadata")
+	}
 
 	return nil
 }
 
 func (backupDirectory *BackupDirectory) AddFinishTime(finishTime time.Time) error {
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
 	metadata, err := readMetadata(backupDirectory.metadataFilename())
 	if err != nil {
 		message := "unable to load metadata"
@@ -235,8 +522,10 @@ func (backupDirectory *BackupDirectory) AddFinishTime(finishTime time.Time) erro
 		return backupDirectory.logAndReturn(err, message)
 	}
 
-	metadata.MetadataForBackupActivity.FinishTime = finishTime.Format(timestampFormat)
-	metadata.save(backupDirectory.metadataFilename())
+	metadata.markFinished(finishTime.Format(timestampFormat))
+	if err := metadata.save(backupDirectory.metadataFilename()); err != nil {
+		return backupDirectory.logAndReturn(err, "unable to save metadata")
+	}
 
 	return nil
 }
@@ -245,14 +534,69 @@ func (backupDirectory *BackupDirectory) SaveManifest(manifest string) error {
 	return errors.Wrap(ioutil.WriteFile(backupDirectory.manifestFilename(), []byte(manifest), 0666), "failed to save manifest")
 }
 
+// Sign writes a detached signature of the metadata file next to it. It does
+// nothing when the backup was not given a signing key.
+func (backupDirectory *BackupDirectory) Sign() error {
+	if backupDirectory.signingKey == nil {
+		return nil
+	}
+
+	defer backupDirectory.Unlock()
+	backupDirectory.Lock()
+
+	contents, err := ioutil.ReadFile(backupDirectory.metadataFilename())
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
+	}
+
+	if err := writeFileAtomically(backupDirectory.signatureFilename(), signMetadata(backupDirectory.signingKey, contents)); err != nil {
+		return backupDirectory.logAndReturn(err, "unable to sign metadata")
+	}
+	return nil
+}
+
+// VerifySignature checks the metadata file against its detached signature. It
+// does nothing when the backup was not given a trusted key.
+func (backupDirectory *BackupDirectory) VerifySignature() error {
+	if backupDirectory.trustedKey == nil {
+		return nil
+	}
+
+	contents, err := ioutil.ReadFile(backupDirectory.metadataFilename())
+	if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata")
+	}
+
+	signature, err := ioutil.ReadFile(backupDirectory.signatureFilename())
+	if os.IsNotExist(err) {
+		return errors.Errorf("backup is not signed, %s is missing", signatureFileName)
+	} else if err != nil {
+		return backupDirectory.logAndReturn(err, "unable to load metadata signature")
+	}
+
+	return verifyMetadataSignature(backupDirectory.trustedKey, contents, signature)
+}
+
 func (backupDirectory *BackupDirectory) Valid() (bool, error) {
 	meta, err := readMetadata(backupDirectory.metadataFilename())
 	if err != nil {
 		return false, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataFilename())
 	}
 
+	if err := meta.checkComplete(); err != nil {
+		return false, backupDirectory.logAndReturn(err, "Error validating %s", backupDirectory.baseDirName)
+	}
+
+	if _, err := backupDirectory.artifactKey(); err != nil {
+		return false, backupDirectory.logAndReturn(err, "Error checking encryption key")
+	}
+
 	for _, artifact := range meta.MetadataForEachArtifact {
-		actualArtifactChecksum, _ := backupDirectory.CalculateChecksum(makeCustomArtifactIdentifier(artifact))
+		actualArtifactChecksum, err := backupDirectory.CalculateChecksum(makeCustomArtifactIdentifier(artifact))
+		if err != nil {
+			return false, backupDirectory.logAndReturn(err, "Error calculating checksum for artifact")
+		}
+
 		match, _ := actualArtifactChecksum.Match(artifact.Checksum)
 		if !match {
 			return false, backupDirectory.logAndReturn(err, "Can't match checksums for %s, in metadata: %v, in actual file: %v", artifact.Name, actualArtifactChecksum, artifact.Checksum)
@@ -278,23 +622,22 @@ func (backupDirectory *BackupDirectory) Valid() (bool, error) {
 	return true, nil
 }
 
-func (backupDirectory *BackupDirectory) backupInstanceIsPresent(backupInstance *instanceMetadata, instances []orchestrator.Instance) bool {
-	for _, inst := range instances {
-		if inst.Index() == backupInstance.Index && inst.Name() == backupInstance.Name {
-			return true
-		}
-	}
-	return false
-}
-
 func (backupDirectory *BackupDirectory) instanceFilename(artifactIdentifier orchestrator.ArtifactIdentifier) string {
 	return path.Join(backupDirectory.baseDirName, fileName(artifactIdentifier))
 }
 
+func (backupDirectory *BackupDirectory) chunkManifestFilename(artifactIdentifier orchestrator.ArtifactIdentifier) string {
+	return path.Join(backupDirectory.baseDirName, chunkManifestFileName(fileName(artifactIdentifier)))
+}
+
 func (backupDirectory *BackupDirectory) metadataFilename() string {
 	return path.Join(backupDirectory.baseDirName, "metadata")
 }
 
+func (backupDirectory *BackupDirectory) signatureFilename() string {
+	return path.Join(backupDirectory.baseDirName, signatureFileName)
+}
+
 func (backupDirectory *BackupDirectory) manifestFilename() string {
 	return path.Join(backupDirectory.baseDirName, "manifest.yml")
 }
@@ -1,6 +1,7 @@
 package backup
 
 import (
+	"crypto/ed25519"
 	"os"
 
 	"fmt"
@@ -9,9 +10,15 @@ import (
 	"github.com/pkg/errors"
 )
 
-type BackupDirectoryManager struct{}
+type BackupDirectoryManager struct {
+	Compression     string
+	EncryptionKey   *EncryptionKey
+	IncrementalFrom string
+	SigningKey      ed25519.PrivateKey
+	TrustedKey      ed25519.PublicKey
+}
 
-func (BackupDirectoryManager) Create(path, directoryName string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+func (manager BackupDirectoryManager) Create(path, directoryName string, logger orchestrator.Logger) (orchestrator.Backup, error) {
 	var (
 		backupPath string
 		err        error
@@ -32,15 +39,38 @@ func (BackupDirectoryManager) Create(path, directoryName string, logger orchestr
 		backupPath = directoryName
 	}
 
+	var previousPath string
+	if manager.IncrementalFrom != "" {
+		previousPath, err = checkPreviousBackup(manager.IncrementalFrom, manager.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = os.Mkdir(backupPath, 0700)
 	if err != nil {
 		return nil, errors.New("failed creating artifact directory")
 	}
 
-	return &BackupDirectory{baseDirName: backupPath, Logger: logger}, nil
+	return &BackupDirectory{
+		baseDirName:   backupPath,
+		Logger:        logger,
+		compression:   manager.Compression,
+		encryptionKey: manager.EncryptionKey,
+		previousPath:  previousPath,
+		signingKey:    manager.SigningKey,
+		trustedKey:    manager.TrustedKey,
+	}, nil
 }
 
-func (BackupDirectoryManager) Open(name string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+func (manager BackupDirectoryManager) Open(name string, logger orchestrator.Logger) (orchestrator.Backup, error) {
 	_, err := os.Stat(name)
-	return &BackupDirectory{baseDirName: name, Logger: logger}, errors.Wrap(err, "failed opening the directory")
+	return &BackupDirectory{
+		baseDirName:   name,
+		Logger:        logger,
+		compression:   manager.Compression,
+		encryptionKey: manager.EncryptionKey,
+		signingKey:    manager.SigningKey,
+		trustedKey:    manager.TrustedKey,
+	}, errors.Wrap(err, "failed opening the directory")
 }
@@ -3,12 +3,14 @@ package backup_test
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"sync"
@@ -89,7 +91,16 @@ instances:
   checksum: foo
 - name: broker
   index: 2
-  checksum: foo
+  artifacts:
+  - name: broker
+    checksums:
+      file1: foo
+- name: worker
+  index: 0
+  artifacts:
+  - name: worker
+    checksums:
+      file1: foo
 `)
 			})
 
@@ -97,6 +108,42 @@ instances:
 				match, _ := artifact.DeploymentMatches(backupName, []orchestrator.Instance{instance1, instance2})
 				Expect(match).To(BeFalse())
 			})
+
+			It("lists every instance that is not in the deployment", func() {
+				_, err := artifact.DeploymentMatches("redis-deployment", []orchestrator.Instance{instance1, instance2})
+				Expect(err).To(MatchError("the backup has instances that are not in deployment 'redis-deployment': broker/2, worker/0"))
+			})
+		})
+
+		Context("when an instance that is not in the current deployment has no artifacts in the backup", func() {
+			BeforeEach(func() {
+				createTestMetadata(backupName, `---
+instances:
+- name: redis
+  index: 0
+  artifacts:
+  - name: redis
+    checksums:
+      file1: foo
+- name: redis
+  index: 1
+  artifacts:
+  - name: redis
+    checksums:
+      file1: foo
+- name: redis
+  index: 2
+  jobs:
+  - name: redis
+  artifacts: []
+`)
+			})
+
+			It("returns true, as there is nothing to restore to it", func() {
+				match, err := artifact.DeploymentMatches(backupName, []orchestrator.Instance{instance1, instance2})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(match).To(BeTrue())
+			})
 		})
 
 		Context("when an error occurs unmarshaling the metadata", func() {
@@ -211,6 +258,22 @@ custom_artifacts:
 			})
 		})
 
+		Context("when the named artifact file is missing", func() {
+			BeforeEach(func() {
+				createTestMetadata(backupName, fmt.Sprintf(`---
+custom_artifacts:
+- name: foo_redis
+  checksums:
+    file1: %x
+`, sha256.Sum256([]byte("This archive contains some text files."))))
+			})
+
+			It("returns an error", func() {
+				Expect(verifyResult).To(BeFalse())
+				Expect(verifyError).To(MatchError(ContainSubstring("Error calculating checksum for artifact")))
+			})
+		})
+
 		Context("when one of the default artifact file's contents don't match the sha", func() {
 			BeforeEach(func() {
 				contents := createTarWithContents(map[string]string{
@@ -280,6 +343,35 @@ instances:
 			})
 		})
 
+		Context("when the backup did not complete", func() {
+			BeforeEach(func() {
+				contents := createTarWithContents(map[string]string{
+					"file1": "This archive contains some text files.",
+				})
+				Expect(ioutil.WriteFile(backupName+"/redis-0-broker.tar", contents, 0666)).NotTo(HaveOccurred())
+
+				createTestMetadata(backupName, fmt.Sprintf(`---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress
+instances:
+- name: redis
+  index: 0
+  artifacts:
+  - name: broker
+    checksums:
+      file1: %x
+`, sha256.Sum256([]byte("This archive contains some text files."))))
+			})
+
+			It("returns false", func() {
+				Expect(verifyResult).To(BeFalse())
+			})
+			It("returns an error", func() {
+				Expect(verifyError).To(MatchError(ContainSubstring("backup is not complete (status: in_progress), it cannot be used for a restore")))
+			})
+		})
+
 		Context("metadata file doesn't exist", func() {
 			BeforeEach(func() {
 				contents := createTarWithContents(map[string]string{
@@ -595,13 +687,16 @@ instances:
 					Expect(backupName + "/metadata").To(BeARegularFile())
 
 					expectedMetadata := `---
+version: 2
 backup_activity:
   start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress
 instances:
 - name: redis-server
   index: "0"
   artifacts:
   - name: redis
+    file_count: 1
     checksums:
       filename: foobar`
 					Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
@@ -622,16 +717,20 @@ instances:
 					Expect(backupName + "/metadata").To(BeARegularFile())
 
 					expectedMetadata := `---
+version: 2
 backup_activity:
   start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress
 instances:
 - name: redis-server
   index: "0"
   artifacts:
   - name: broker
+    file_count: 1
     checksums:
       filename: foobar
   - name: redis
+    file_count: 1
     checksums:
       filename: foobar`
 					Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
@@ -652,19 +751,23 @@ instances:
 					Expect(backupName + "/metadata").To(BeARegularFile())
 
 					expectedMetadata := `---
+version: 2
 backup_activity:
   start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress
 instances:
 - name: memcached-server
   index: "0"
   artifacts:
   - name: memcached
+    file_count: 1
     checksums:
       filename: foobar
 - name: redis-server
   index: "0"
   artifacts:
   - name: redis
+    file_count: 1
     checksums:
       filename: foobar
 `
@@ -684,10 +787,13 @@ instances:
 					Expect(backupName + "/metadata").To(BeARegularFile())
 
 					expectedMetadata := `---
+version: 2
 backup_activity:
   start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress
 custom_artifacts:
 - name: foo
+  file_count: 1
   checksums:
     filename: foobar`
 					Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
@@ -706,13 +812,17 @@ custom_artifacts:
 					Expect(backupName + "/metadata").To(BeARegularFile())
 
 					expectedMetadata := `---
+version: 2
 backup_activity:
   start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress
 custom_artifacts:
 - name: bar
+  file_count: 1
   checksums:
     filename: foobar
 - name: foo
+  file_count: 1
   checksums:
     filename: foobar
 `
@@ -937,11 +1047,22 @@ instances:
 				Expect(artifact.CreateMetadataFileWithStartTime(theTime)).To(Succeed())
 
 				expectedMetadata := `---
+version: 2
 backup_activity:
-  start_time: 2015/10/21 01:02:03 UTC`
+  start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress`
 
 				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
 			})
+
+			It("does not leave temporary files behind", func() {
+				Expect(artifact.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+				files, err := ioutil.ReadDir(backupName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(HaveLen(1))
+				Expect(files[0].Name()).To(Equal("metadata"))
+			})
 		})
 
 		Context("when the metadata file already exists", func() {
@@ -975,15 +1096,637 @@ backup_activity:
 				Expect(artifact.AddFinishTime(finishTime)).To(Succeed())
 
 				expectedMetadata := `---
+version: 2
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  finish_time: 2016/10/21 04:05:06 UTC
+  status: failed`
+
+				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+			})
+
+			Context("and the backup has been marked complete", func() {
+				It("keeps the complete status", func() {
+					startTime := time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC)
+					finishTime := time.Date(2016, 10, 21, 4, 5, 6, 0, time.UTC)
+					Expect(artifact.CreateMetadataFileWithStartTime(startTime)).To(Succeed())
+					Expect(artifact.MarkComplete()).To(Succeed())
+					Expect(artifact.AddFinishTime(finishTime)).To(Succeed())
+
+					expectedMetadata := `---
+version: 2
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  finish_time: 2016/10/21 04:05:06 UTC
+  status: complete`
+
+					Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+				})
+			})
+		})
+	})
+
+	Describe("MarkComplete", func() {
+		var artifact orchestrator.Backup
+
+		BeforeEach(func() {
+			var err error
+			artifact, err = backupDirectoryManager.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when no metadata file exists", func() {
+			It("returns an error", func() {
+				Expect(artifact.MarkComplete()).To(MatchError(ContainSubstring("unable to load metadata")))
+			})
+		})
+
+		Context("when the metadata file already exists", func() {
+			It("records the complete status", func() {
+				Expect(artifact.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+				Expect(artifact.MarkComplete()).To(Succeed())
+
+				expectedMetadata := `---
+version: 2
 backup_activity:
   start_time: 2015/10/21 01:02:03 UTC
-  finish_time: 2016/10/21 04:05:06 UTC`
+  status: complete`
 
 				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
 			})
 		})
 	})
 
+	Describe("BackedUpInstances", func() {
+		It("describes every instance in the backup, including those without artifacts", func() {
+			artifact, err := backupDirectoryManager.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			createTestMetadata(backupName, `---
+instances:
+- name: redis
+  index: "0"
+  id: abc
+  bootstrap: true
+  jobs:
+  - name: redis-server
+    release: redis
+  artifacts:
+  - name: redis-server
+    checksums:
+      file1: foo
+- name: broker
+  index: "1"
+  artifacts: []
+`)
+
+			instances, err := artifact.BackedUpInstances()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(Equal([]orchestrator.InstanceMetadata{
+				{Name: "redis", Index: "0", ID: "abc", Bootstrap: true, Jobs: []orchestrator.JobSpecifier{{Name: "redis-server", Release: "redis"}}},
+				{Name: "broker", Index: "1"},
+			}))
+		})
+	})
+
+	Describe("MarkDrainFailed", func() {
+		It("records that the backup failed while it was drained, which the finish time keeps", func() {
+			artifact, err := backupDirectoryManager.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+
+			Expect(artifact.MarkDrainFailed()).To(Succeed())
+			Expect(artifact.AddFinishTime(time.Date(2015, 10, 21, 4, 5, 6, 0, time.UTC))).To(Succeed())
+
+			expectedMetadata := `---
+version: 2
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  finish_time: 2015/10/21 04:05:06 UTC
+  status: drain_failed`
+
+			Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+		})
+	})
+
+	Describe("MarkResumed", func() {
+		var artifact orchestrator.Backup
+
+		BeforeEach(func() {
+			var err error
+			artifact, err = backupDirectoryManager.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the backup failed to drain", func() {
+			It("sets the backup back in progress", func() {
+				createTestMetadata(backupName, `---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  finish_time: 2015/10/21 04:05:06 UTC
+  status: drain_failed`)
+				Expect(artifact.MarkResumed()).To(Succeed())
+
+				expectedMetadata := `---
+version: 2
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress`
+
+				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+			})
+		})
+
+		Context("when the backup failed before it was drained", func() {
+			It("returns an error", func() {
+				createTestMetadata(backupName, `---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  finish_time: 2015/10/21 04:05:06 UTC
+  status: failed`)
+				Expect(artifact.MarkResumed()).To(MatchError(ContainSubstring("backup did not fail while copying its artifacts (status: failed), it cannot be resumed")))
+			})
+		})
+
+		Context("when the backup is complete", func() {
+			It("returns an error", func() {
+				createTestMetadata(backupName, `---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  status: complete`)
+				Expect(artifact.MarkResumed()).To(MatchError(ContainSubstring("backup is already complete, there is nothing to resume")))
+			})
+		})
+
+		Context("when the backup has no status", func() {
+			It("treats it as complete", func() {
+				createTestMetadata(backupName, `---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC`)
+				Expect(artifact.MarkResumed()).To(MatchError(ContainSubstring("backup is already complete")))
+			})
+		})
+	})
+
+	Describe("AddDeploymentMetadata", func() {
+		var artifact orchestrator.Backup
+
+		BeforeEach(func() {
+			var err error
+			artifact, err = backupDirectoryManager.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when no metadata file exists", func() {
+			It("returns an error", func() {
+				Expect(artifact.AddDeploymentMetadata(orchestrator.DeploymentMetadata{})).To(MatchError(ContainSubstring("unable to load metadata")))
+			})
+		})
+
+		Context("when the metadata file already exists", func() {
+			It("records the deployment, director and instances", func() {
+				Expect(artifact.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+				Expect(artifact.AddDeploymentMetadata(orchestrator.DeploymentMetadata{
+					Name:       "redis",
+					BBRVersion: "1.2.3",
+					Director:   orchestrator.DirectorInfo{UUID: "director-uuid", Name: "bosh", Version: "264.1.0"},
+					Instances: []orchestrator.InstanceMetadata{{
+						Name:      "redis-server",
+						Index:     "0",
+						ID:        "f1b2c3",
+						Bootstrap: true,
+						Jobs:      []orchestrator.JobSpecifier{{Name: "redis-backup", Release: "redis"}},
+					}},
+				})).To(Succeed())
+
+				expectedMetadata := `---
+version: 2
+bbr_version: 1.2.3
+deployment: redis
+director:
+  uuid: director-uuid
+  name: bosh
+  version: 264.1.0
+instances:
+- name: redis-server
+  index: "0"
+  id: f1b2c3
+  bootstrap: true
+  jobs:
+  - name: redis-backup
+    release: redis
+  artifacts: []
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress`
+
+				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+			})
+		})
+	})
+
+	Describe("reading metadata written by an older version of bbr", func() {
+		var artifact orchestrator.Backup
+
+		BeforeEach(func() {
+			artifact, _ = backupDirectoryManager.Open(backupName, logger)
+		})
+
+		It("migrates the metadata to the current version when it is next saved", func() {
+			createTestMetadata(backupName, `---
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    checksums:
+      file1: abcd
+      file2: efgh
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  finish_time: 2015/10/21 04:05:06 UTC
+  status: drain_failed`)
+			Expect(artifact.MarkResumed()).To(Succeed())
+
+			expectedMetadata := `---
+version: 2
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    file_count: 2
+    checksums:
+      file1: abcd
+      file2: efgh
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress`
+
+			Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+		})
+
+		It("refuses metadata written by a newer version of bbr", func() {
+			createTestMetadata(backupName, `---
+version: 3
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  status: failed`)
+			Expect(artifact.MarkResumed()).To(MatchError(ContainSubstring("metadata version 3 is not supported by this version of bbr")))
+		})
+	})
+
+	Describe("ArtifactDrained", func() {
+		var artifact orchestrator.Backup
+		var fakeBackupArtifact *fakes.FakeBackupArtifact
+
+		BeforeEach(func() {
+			var err error
+			artifact, err = backupDirectoryManager.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+			fakeBackupArtifact = new(fakes.FakeBackupArtifact)
+			fakeBackupArtifact.InstanceNameReturns("redis-server")
+			fakeBackupArtifact.InstanceIndexReturns("0")
+			fakeBackupArtifact.NameReturns("redis")
+		})
+
+		It("is false until the checksum has been recorded", func() {
+			Expect(artifact.ArtifactDrained(fakeBackupArtifact)).To(BeFalse())
+
+			Expect(artifact.AddChecksum(fakeBackupArtifact, orchestrator.BackupChecksum{"file1": "abcd"})).To(Succeed())
+			Expect(artifact.ArtifactDrained(fakeBackupArtifact)).To(BeTrue())
+		})
+	})
+
+	Describe("Compression", func() {
+		var artifact orchestrator.Backup
+		var fakeBackupArtifact *fakes.FakeBackupArtifact
+		var tarContents []byte
+
+		BeforeEach(func() {
+			var err error
+			artifact, err = BackupDirectoryManager{Compression: GzipCompression}.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+
+			fakeBackupArtifact = new(fakes.FakeBackupArtifact)
+			fakeBackupArtifact.InstanceNameReturns("redis-server")
+			fakeBackupArtifact.InstanceIndexReturns("0")
+			fakeBackupArtifact.NameReturns("redis")
+
+			tarContents = createTarWithContents(map[string]string{
+				"file1": "This archive contains some text files.",
+			})
+
+			writer, err := artifact.CreateArtifact(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = writer.Write(tarContents)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writer.Close()).To(Succeed())
+		})
+
+		It("writes a gzip compressed file", func() {
+			file, err := os.Open(backupName + "/redis-server-0-redis.tar")
+			Expect(err).NotTo(HaveOccurred())
+			defer file.Close()
+
+			gzipReader, err := gzip.NewReader(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.ReadAll(gzipReader)).To(Equal(tarContents))
+		})
+
+		It("calculates the checksum of the uncompressed contents", func() {
+			Expect(artifact.CalculateChecksum(fakeBackupArtifact)).To(Equal(orchestrator.BackupChecksum{
+				"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+			}))
+		})
+
+		Context("when the checksum has been added", func() {
+			BeforeEach(func() {
+				checksum, err := artifact.CalculateChecksum(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(artifact.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+			})
+
+			It("records the compression, compressed size and uncompressed size in the metadata file", func() {
+				artifactFile, err := os.Stat(backupName + "/redis-server-0-redis.tar")
+				Expect(err).NotTo(HaveOccurred())
+
+				expectedMetadata := fmt.Sprintf(`---
+version: 2
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  status: in_progress
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    compression: gzip
+    size: %d
+    plaintext_size: %d
+    file_count: 1
+    checksums:
+      file1: %x
+`, artifactFile.Size(), len(tarContents), sha256.Sum256([]byte("This archive contains some text files.")))
+
+				Expect(ioutil.ReadFile(backupName + "/metadata")).To(MatchYAML(expectedMetadata))
+			})
+
+			Context("and the backup is opened for restore", func() {
+				BeforeEach(func() {
+					Expect(artifact.MarkComplete()).To(Succeed())
+
+					var err error
+					artifact, err = backupDirectoryManager.Open(backupName, logger)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("reads the uncompressed contents", func() {
+					reader, err := artifact.ReadArtifact(fakeBackupArtifact)
+					Expect(err).NotTo(HaveOccurred())
+					defer reader.Close()
+
+					Expect(ioutil.ReadAll(reader)).To(Equal(tarContents))
+				})
+
+				It("is valid", func() {
+					Expect(artifact.Valid()).To(BeTrue())
+				})
+
+				It("reports the size of the uncompressed contents", func() {
+					Expect(artifact.GetArtifactByteSize(fakeBackupArtifact)).To(Equal(len(tarContents)))
+				})
+			})
+		})
+
+		Describe("ValidateCompression", func() {
+			It("accepts none and gzip", func() {
+				Expect(ValidateCompression("")).To(Succeed())
+				Expect(ValidateCompression(NoCompression)).To(Succeed())
+				Expect(ValidateCompression(GzipCompression)).To(Succeed())
+			})
+
+			It("rejects unsupported compressions", func() {
+				Expect(ValidateCompression("lzma")).To(MatchError(ContainSubstring(`unsupported compression "lzma"`)))
+			})
+		})
+	})
+
+	Describe("Encryption", func() {
+		var artifact orchestrator.Backup
+		var fakeBackupArtifact *fakes.FakeBackupArtifact
+		var tarContents []byte
+		var encryptionKey *EncryptionKey
+		var compression string
+
+		writeArtifact := func() {
+			var err error
+			artifact, err = BackupDirectoryManager{Compression: compression, EncryptionKey: encryptionKey}.Create("", backupName, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+
+			writer, err := artifact.CreateArtifact(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = writer.Write(tarContents)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writer.Close()).To(Succeed())
+
+			checksum, err := artifact.CalculateChecksum(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+			Expect(artifact.MarkComplete()).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			compression = ""
+			encryptionKey = NewEncryptionKeyFromPassphrase("correct horse battery staple")
+
+			fakeBackupArtifact = new(fakes.FakeBackupArtifact)
+			fakeBackupArtifact.InstanceNameReturns("redis-server")
+			fakeBackupArtifact.InstanceIndexReturns("0")
+			fakeBackupArtifact.NameReturns("redis")
+
+			tarContents = createTarWithContents(map[string]string{
+				"file1": "This archive contains some text files.",
+				"file2": strings.Repeat("a large file ", 20000),
+			})
+		})
+
+		JustBeforeEach(func() {
+			writeArtifact()
+		})
+
+		It("does not write the artifact in plain text", func() {
+			contents, err := ioutil.ReadFile(backupName + "/redis-server-0-redis.tar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contents).NotTo(ContainSubstring("This archive contains some text files."))
+		})
+
+		It("records the cipher and key fingerprint in the metadata file", func() {
+			contents, err := ioutil.ReadFile(backupName + "/metadata")
+			Expect(err).NotTo(HaveOccurred())
+
+			var metadata struct {
+				Encryption map[string]string `yaml:"encryption"`
+			}
+			Expect(yaml.Unmarshal(contents, &metadata)).To(Succeed())
+			Expect(metadata.Encryption).To(HaveKeyWithValue("cipher", "aes-256-gcm"))
+			Expect(metadata.Encryption).To(HaveKey("key_fingerprint"))
+			Expect(metadata.Encryption).To(HaveKey("salt"))
+		})
+
+		Context("when the backup is opened with the same key", func() {
+			BeforeEach(func() {
+				compression = GzipCompression
+			})
+
+			It("decrypts the artifact transparently", func() {
+				artifact, err := BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("correct horse battery staple")}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(artifact.Valid()).To(BeTrue())
+
+				reader, err := artifact.ReadArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+				Expect(ioutil.ReadAll(reader)).To(Equal(tarContents))
+			})
+		})
+
+		Context("when the backup is encrypted with a key file", func() {
+			var keyFile string
+
+			BeforeEach(func() {
+				file, err := ioutil.TempFile("", "bbr-encryption-key")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = file.Write([]byte("some-random-key-material"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(file.Close()).To(Succeed())
+				keyFile = file.Name()
+
+				encryptionKey, err = NewEncryptionKeyFromFile(keyFile)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				Expect(os.Remove(keyFile)).To(Succeed())
+			})
+
+			It("is valid when opened with the same key file", func() {
+				key, err := NewEncryptionKeyFromFile(keyFile)
+				Expect(err).NotTo(HaveOccurred())
+
+				artifact, err := BackupDirectoryManager{EncryptionKey: key}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(artifact.Valid()).To(BeTrue())
+			})
+		})
+
+		Context("when the backup is opened without a key", func() {
+			It("fails validation with a clear error", func() {
+				artifact, err := backupDirectoryManager.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = artifact.Valid()
+				Expect(err).To(MatchError(ContainSubstring("backup is encrypted, an encryption key must be provided")))
+			})
+		})
+
+		Context("when the backup is opened with a different key", func() {
+			It("fails validation before reading any artifact", func() {
+				artifact, err := BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("wrong")}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = artifact.Valid()
+				Expect(err).To(MatchError(ContainSubstring("the supplied encryption key does not match the key the backup was encrypted with")))
+
+				_, err = artifact.ReadArtifact(fakeBackupArtifact)
+				Expect(err).To(MatchError(ContainSubstring("does not match")))
+			})
+		})
+
+		Context("when the metadata can not be read", func() {
+			It("fails to create further artifacts rather than writing them unencrypted", func() {
+				Expect(ioutil.WriteFile(backupName+"/metadata", []byte("not: [valid"), 0600)).To(Succeed())
+
+				artifact, err := BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("correct horse battery staple")}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				otherArtifact := new(fakes.FakeBackupArtifact)
+				otherArtifact.InstanceNameReturns("redis-server")
+				otherArtifact.InstanceIndexReturns("1")
+				otherArtifact.NameReturns("redis")
+
+				_, err = artifact.CreateArtifact(otherArtifact)
+				Expect(err).To(MatchError(ContainSubstring("failed to read the encryption settings of the backup")))
+				Expect(backupName + "/redis-server-1-redis.tar").NotTo(BeAnExistingFile())
+			})
+		})
+
+		Context("when an unencrypted backup is opened with a key", func() {
+			BeforeEach(func() {
+				encryptionKey = nil
+			})
+
+			It("fails to create further artifacts rather than writing them unencrypted", func() {
+				artifact, err := BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("correct horse battery staple")}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = artifact.CreateArtifact(fakeBackupArtifact)
+				Expect(err).To(MatchError(ContainSubstring("an encryption key was provided, but the backup is not encrypted")))
+			})
+		})
+
+		Context("when the encrypted artifact has been tampered with", func() {
+			It("fails to read the artifact", func() {
+				filename := backupName + "/redis-server-0-redis.tar"
+				contents, err := ioutil.ReadFile(filename)
+				Expect(err).NotTo(HaveOccurred())
+				contents[len(contents)/2] ^= 0xff
+				Expect(ioutil.WriteFile(filename, contents, 0600)).To(Succeed())
+
+				artifact, err := BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("correct horse battery staple")}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = artifact.CalculateChecksum(fakeBackupArtifact)
+				Expect(err).To(MatchError(ContainSubstring("failed to decrypt artifact")))
+			})
+		})
+
+		Context("when the encrypted artifact has been truncated", func() {
+			It("fails to read the artifact", func() {
+				filename := backupName + "/redis-server-0-redis.tar"
+				contents, err := ioutil.ReadFile(filename)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(filename, contents[:64*1024+100], 0600)).To(Succeed())
+
+				artifact, err := BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("correct horse battery staple")}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = artifact.CalculateChecksum(fakeBackupArtifact)
+				Expect(err).To(MatchError(ContainSubstring("failed to decrypt artifact")))
+			})
+		})
+
+		Context("when the length of an encrypted chunk has been corrupted", func() {
+			It("fails to read the artifact without allocating the corrupted length", func() {
+				filename := backupName + "/redis-server-0-redis.tar"
+				contents, err := ioutil.ReadFile(filename)
+				Expect(err).NotTo(HaveOccurred())
+				copy(contents[7:11], []byte{0xff, 0xff, 0xff, 0xff})
+				Expect(ioutil.WriteFile(filename, contents, 0600)).To(Succeed())
+
+				artifact, err := BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("correct horse battery staple")}.Open(backupName, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = artifact.CalculateChecksum(fakeBackupArtifact)
+				Expect(err).To(MatchError(ContainSubstring("encrypted stream is corrupted, chunk of 4294967295 bytes is larger than the maximum of 65552")))
+			})
+		})
+	})
+
 	Describe("GetArtifactSize", func() {
 		var (
 			jobName            string
@@ -1127,3 +1870,31 @@ func createTarWithContents(files map[string]string) []byte {
 	Expect(tarFile.Close()).NotTo(HaveOccurred())
 	return bytesBuffer.Bytes()
 }
+
+func checksumOfFiles(files map[string]string) orchestrator.BackupChecksum {
+	checksum := orchestrator.BackupChecksum{}
+	for name, contents := range files {
+		checksum[name] = fmt.Sprintf("%x", sha256.Sum256([]byte(contents)))
+	}
+	return checksum
+}
+
+func newFakeArtifact(instanceName, instanceIndex, name string) *fakes.FakeBackupArtifact {
+	artifact := new(fakes.FakeBackupArtifact)
+	artifact.InstanceNameReturns(instanceName)
+	artifact.InstanceIndexReturns(instanceIndex)
+	artifact.NameReturns(name)
+	artifact.HasCustomNameReturns(instanceName == "")
+	return artifact
+}
+
+// writeTarArtifact writes the files to the backup as a tarred artifact and
+// records their checksum.
+func writeTarArtifact(backup orchestrator.Backup, artifact orchestrator.BackupArtifact, files map[string]string) {
+	writer, err := backup.CreateArtifact(artifact)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = writer.Write(createTarWithContents(files))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(writer.Close()).To(Succeed())
+	Expect(backup.AddChecksum(artifact, checksumOfFiles(files))).To(Succeed())
+}
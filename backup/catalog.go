@@ -0,0 +1,155 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+var backupDirectoryName = regexp.MustCompile(`^(.+)_(\d{8}T\d{6}Z)$`)
+
+// StoredBackup is a backup directory found under an artifact path, as
+// described by its metadata.
+type StoredBackup struct {
+	Path            string
+	Deployment      string
+	StartTime       time.Time
+	FinishTime      time.Time
+	Status          string
+	IncrementalFrom string
+	BBRVersion      string
+	DirectorUUID    string
+	Instances       int
+	Artifacts       int
+	HasManifest     bool
+	Size            int64
+}
+
+// Finished is false for backups that were interrupted before they recorded a
+// finish time.
+func (b StoredBackup) Finished() bool {
+	return !b.FinishTime.IsZero()
+}
+
+// Complete is true for finished backups that can be restored from. Backups
+// written before the status was recorded are assumed to be complete.
+func (b StoredBackup) Complete() bool {
+	return b.Finished() && (b.Status == "" || b.Status == statusComplete)
+}
+
+// FindBackups returns the backups directly under path, oldest first. Directories
+// that are not named <deployment>_<timestamp> or have no readable metadata are
+// not backups and are ignored.
+func FindBackups(path string, logger orchestrator.Logger) ([]StoredBackup, error) {
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list backups in %s", path)
+	}
+
+	var backups []StoredBackup
+	for _, entry := range entries {
+		match := backupDirectoryName.FindStringSubmatch(entry.Name())
+		if !entry.IsDir() || match == nil {
+			continue
+		}
+
+		backupPath, err := filepath.Abs(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve the path of %s", entry.Name())
+		}
+
+		metadata, err := readMetadata(filepath.Join(backupPath, "metadata"))
+		if err != nil {
+			logger.Debug("bbr", "Ignoring %s: %s", backupPath, err)
+			continue
+		}
+
+		backup, err := newStoredBackup(backupPath, match[1], metadata)
+		if err != nil {
+			logger.Debug("bbr", "Ignoring %s: %s", backupPath, err)
+			continue
+		}
+		backups = append(backups, backup)
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].StartTime.Before(backups[j].StartTime)
+	})
+	return backups, nil
+}
+
+func newStoredBackup(path, deployment string, metadata metadata) (StoredBackup, error) {
+	activity := metadata.MetadataForBackupActivity
+
+	startTime, err := time.Parse(timestampFormat, activity.StartTime)
+	if err != nil {
+		return StoredBackup{}, errors.Wrap(err, "failed to parse the start time")
+	}
+
+	var finishTime time.Time
+	if activity.FinishTime != "" {
+		finishTime, err = time.Parse(timestampFormat, activity.FinishTime)
+		if err != nil {
+			return StoredBackup{}, errors.Wrap(err, "failed to parse the finish time")
+		}
+	}
+
+	artifacts := len(metadata.MetadataForEachArtifact)
+	for _, instance := range metadata.MetadataForEachInstance {
+		artifacts += len(instance.Artifacts)
+	}
+
+	_, err = os.Stat(filepath.Join(path, "manifest.yml"))
+	hasManifest := err == nil
+
+	size, err := directorySize(path)
+	if err != nil {
+		return StoredBackup{}, err
+	}
+
+	var directorUUID string
+	if metadata.Director != nil {
+		directorUUID = metadata.Director.UUID
+	}
+
+	return StoredBackup{
+		Path:            path,
+		Deployment:      deployment,
+		StartTime:       startTime,
+		FinishTime:      finishTime,
+		Status:          activity.Status,
+		IncrementalFrom: metadata.IncrementalFrom,
+		BBRVersion:      metadata.BBRVersion,
+		DirectorUUID:    directorUUID,
+		Instances:       len(metadata.MetadataForEachInstance),
+		Artifacts:       artifacts,
+		HasManifest:     hasManifest,
+		Size:            size,
+	}, nil
+}
+
+// HumanReadableSize is the size of the files in the backup directory. Chunks
+// in a deduplicating store and files in previous backups are not included.
+func (b StoredBackup) HumanReadableSize() string {
+	return humanReadableSize(b.Size)
+}
+
+func directorySize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, errors.Wrapf(err, "failed to calculate the size of %s", path)
+}
@@ -0,0 +1,103 @@
+package backup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FindBackups", func() {
+	var artifactPath string
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+
+	writeBackup := func(name, metadata string) {
+		Expect(os.Mkdir(filepath.Join(artifactPath, name), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(artifactPath, name, "metadata"), []byte(metadata), 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		artifactPath, err = ioutil.TempDir("", "catalog-artifacts")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeBackup("redis_20151022T010203Z", `---
+version: 2
+bbr_version: 1.2.3
+deployment: redis
+director:
+  uuid: director-uuid
+backup_activity:
+  start_time: 2015/10/22 01:02:03 UTC
+  status: in_progress
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    checksums: {}
+`)
+		writeBackup("redis_20151021T010203Z", `---
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  finish_time: 2015/10/21 01:05:03 UTC
+  status: complete
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    checksums: {}
+  - name: redis-metadata
+    checksums: {}
+- name: redis-server
+  index: "1"
+  artifacts: []
+custom_artifacts:
+- name: shared
+  checksums: {}
+`)
+		Expect(ioutil.WriteFile(filepath.Join(artifactPath, "redis_20151021T010203Z", "manifest.yml"), []byte("manifest"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(artifactPath, "redis_20151021T010203Z", "redis-server-0-redis.tar"), make([]byte, 2048), 0600)).To(Succeed())
+
+		Expect(os.Mkdir(filepath.Join(artifactPath, "not-a-backup"), 0700)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(artifactPath, "mysql_20151021T010203Z"), 0700)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(artifactPath)).To(Succeed())
+	})
+
+	It("describes each backup, oldest first", func() {
+		backups, err := FindBackups(artifactPath, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backups).To(HaveLen(2))
+
+		Expect(backups[0].Path).To(Equal(filepath.Join(artifactPath, "redis_20151021T010203Z")))
+		Expect(backups[0].Deployment).To(Equal("redis"))
+		Expect(backups[0].StartTime).To(Equal(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC)))
+		Expect(backups[0].FinishTime).To(Equal(time.Date(2015, 10, 21, 1, 5, 3, 0, time.UTC)))
+		Expect(backups[0].Complete()).To(BeTrue())
+		Expect(backups[0].Instances).To(Equal(2))
+		Expect(backups[0].Artifacts).To(Equal(3))
+		Expect(backups[0].HasManifest).To(BeTrue())
+		Expect(backups[0].Size).To(BeNumerically(">", 2048))
+
+		Expect(backups[1].Path).To(Equal(filepath.Join(artifactPath, "redis_20151022T010203Z")))
+		Expect(backups[1].Finished()).To(BeFalse())
+		Expect(backups[1].Complete()).To(BeFalse())
+		Expect(backups[1].HasManifest).To(BeFalse())
+		Expect(backups[1].BBRVersion).To(Equal("1.2.3"))
+		Expect(backups[1].DirectorUUID).To(Equal("director-uuid"))
+	})
+
+	It("fails when the directory cannot be read", func() {
+		_, err := FindBackups(filepath.Join(artifactPath, "not-there"), logger)
+		Expect(err).To(MatchError(ContainSubstring("failed to list backups")))
+	})
+})
@@ -0,0 +1,461 @@
+package backup
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	chunkSize               = 1024 * 1024
+	chunkManifestExtension  = ".chunks"
+	DefaultChunkGracePeriod = 24 * time.Hour
+)
+
+// ChunkStore is a local directory of content-addressed chunks shared by many
+// backups. The contents of every file in an artifact are split into chunks
+// named after their sha256, so unchanged files are only stored once no matter
+// how many backups contain them. Each backup keeps a chunk manifest per
+// artifact and registers itself with the store, so that chunks which are no
+// longer referenced by any backup can be garbage collected.
+type ChunkStore struct {
+	path string
+}
+
+type GarbageCollectionResult struct {
+	ReferencedChunks int
+	RemovedChunks    int
+	FreedBytes       int64
+	MissingBackups   []string
+}
+
+type chunkManifest struct {
+	Entries []chunkedEntry `yaml:"entries"`
+}
+
+type chunkedEntry struct {
+	Header chunkedHeader `yaml:"header"`
+	Chunks []string      `yaml:"chunks,omitempty"`
+}
+
+type chunkedHeader struct {
+	Typeflag   byte              `yaml:"type"`
+	Name       string            `yaml:"name"`
+	Linkname   string            `yaml:"linkname,omitempty"`
+	Size       int64             `yaml:"size"`
+	Mode       int64             `yaml:"mode"`
+	Uid        int               `yaml:"uid"`
+	Gid        int               `yaml:"gid"`
+	Uname      string            `yaml:"uname,omitempty"`
+	Gname      string            `yaml:"gname,omitempty"`
+	ModTime    int64             `yaml:"mod_time"`
+	Devmajor   int64             `yaml:"devmajor,omitempty"`
+	Devminor   int64             `yaml:"devminor,omitempty"`
+	PAXRecords map[string]string `yaml:"pax_records,omitempty"`
+}
+
+func NewChunkStore(path string) (ChunkStore, error) {
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return ChunkStore{}, errors.Wrapf(err, "failed to resolve chunk store path %s", path)
+	}
+
+	store := ChunkStore{path: absolutePath}
+	for _, dir := range []string{store.chunksDir(), store.backupsDir()} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return ChunkStore{}, errors.Wrapf(err, "failed to create chunk store at %s", absolutePath)
+		}
+	}
+	return store, nil
+}
+
+// OpenChunkStore opens an existing chunk store without creating it.
+func OpenChunkStore(path string) (ChunkStore, error) {
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return ChunkStore{}, errors.Wrapf(err, "failed to resolve chunk store path %s", path)
+	}
+
+	store := ChunkStore{path: absolutePath}
+	if _, err := os.Stat(store.chunksDir()); err != nil {
+		return ChunkStore{}, errors.Wrapf(err, "no chunk store found at %s", absolutePath)
+	}
+	return store, nil
+}
+
+func (store ChunkStore) Path() string {
+	return store.path
+}
+
+// register records a backup directory as a user of the store. Registrations
+// are one file per backup, named after its path, so concurrent backups never
+// write to the same file.
+func (store ChunkStore) register(backupPath string) error {
+	absolutePath, err := filepath.Abs(backupPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve backup path %s", backupPath)
+	}
+
+	return errors.Wrap(
+		writeFileAtomically(filepath.Join(store.backupsDir(), hashOf([]byte(absolutePath))), []byte(absolutePath)),
+		"failed to register backup with chunk store",
+	)
+}
+
+func (store ChunkStore) newArtifactWriter(manifestPath string) io.WriteCloser {
+	reader, writer := io.Pipe()
+	result := make(chan error, 1)
+
+	go func() {
+		err := store.writeArtifact(reader, manifestPath)
+		reader.CloseWithError(err)
+		result <- err
+	}()
+
+	return &chunkingWriter{PipeWriter: writer, result: result}
+}
+
+type chunkingWriter struct {
+	*io.PipeWriter
+	result chan error
+}
+
+func (w *chunkingWriter) Close() error {
+	w.PipeWriter.Close()
+	return <-w.result
+}
+
+func (store ChunkStore) writeArtifact(reader io.Reader, manifestPath string) error {
+	tarReader := tar.NewReader(reader)
+	manifest := chunkManifest{}
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read artifact tar")
+		}
+
+		chunks, err := store.putChunks(tarReader)
+		if err != nil {
+			return err
+		}
+
+		manifest.Entries = append(manifest.Entries, chunkedEntry{Header: newChunkedHeader(header), Chunks: chunks})
+	}
+
+	if _, err := io.Copy(ioutil.Discard, reader); err != nil {
+		return errors.Wrap(err, "failed to read artifact tar")
+	}
+
+	contents, err := yaml.Marshal(manifest)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chunk manifest")
+	}
+	return writeFileAtomically(manifestPath, contents)
+}
+
+func (store ChunkStore) putChunks(reader io.Reader) ([]string, error) {
+	var chunks []string
+	buffer := make([]byte, chunkSize)
+
+	for {
+		n, err := io.ReadFull(reader, buffer)
+		if n > 0 {
+			id := hashOf(buffer[:n])
+			if putErr := store.putChunk(id, buffer[:n]); putErr != nil {
+				return nil, putErr
+			}
+			chunks = append(chunks, id)
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return chunks, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read artifact tar")
+		}
+	}
+}
+
+// putChunk writes a chunk unless the store already holds it. Existing chunks
+// are touched instead, so that a garbage collection running while a backup is
+// in progress does not remove chunks the backup is about to reference.
+func (store ChunkStore) putChunk(id string, contents []byte) error {
+	chunkPath := store.chunkPath(id)
+
+	if _, err := os.Stat(chunkPath); err == nil {
+		now := time.Now()
+		return errors.Wrapf(os.Chtimes(chunkPath, now, now), "failed to update chunk %s", id)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(chunkPath), 0700); err != nil {
+		return errors.Wrapf(err, "failed to write chunk %s", id)
+	}
+	return errors.Wrapf(writeFileAtomically(chunkPath, contents), "failed to write chunk %s", id)
+}
+
+// openArtifact reassembles the tar stream of an artifact from its chunk
+// manifest, verifying every chunk against its name as it is read.
+func (store ChunkStore) openArtifact(manifestPath string) (io.ReadCloser, error) {
+	manifest, err := readChunkManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(store.writeTar(manifest, writer))
+	}()
+
+	return reader, nil
+}
+
+func (store ChunkStore) writeTar(manifest chunkManifest, writer io.Writer) error {
+	tarWriter := tar.NewWriter(writer)
+
+	for _, entry := range manifest.Entries {
+		if err := tarWriter.WriteHeader(entry.Header.tarHeader()); err != nil {
+			return errors.Wrapf(err, "failed to write tar header for %s", entry.Header.Name)
+		}
+
+		for _, id := range entry.Chunks {
+			contents, err := store.readChunk(id)
+			if err != nil {
+				return err
+			}
+			if _, err := tarWriter.Write(contents); err != nil {
+				return errors.Wrapf(err, "failed to write %s", entry.Header.Name)
+			}
+		}
+	}
+
+	return errors.Wrap(tarWriter.Close(), "failed to write artifact tar")
+}
+
+func (store ChunkStore) readChunk(id string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(store.chunkPath(id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read chunk %s", id)
+	}
+	if hashOf(contents) != id {
+		return nil, errors.Errorf("chunk %s is corrupted", id)
+	}
+	return contents, nil
+}
+
+// artifactSize is the size of the reassembled tar stream, ignoring any extended
+// headers the tar writer may add for long names.
+func (store ChunkStore) artifactSize(manifestPath string) (int64, error) {
+	manifest, err := readChunkManifest(manifestPath)
+	if err != nil {
+		return 0, err
+	}
+
+	size := int64(2 * tarBlockSize)
+	for _, entry := range manifest.Entries {
+		size += tarBlockSize + roundUpToTarBlock(entry.Header.Size)
+	}
+	return size, nil
+}
+
+// CollectGarbage removes chunks that are not referenced by any registered
+// backup. Chunks modified within the grace period are kept, as they may belong
+// to a backup that is still being drained.
+//
+// A registered backup that can no longer be found may have been moved rather
+// than deleted, and the chunks it references can not be known without its
+// manifests, so no chunks are removed while any registered backup is missing.
+// Registrations of missing backups are only dropped when forgetMissing is set.
+func (store ChunkStore) CollectGarbage(gracePeriod time.Duration, forgetMissing bool, logger orchestrator.Logger) (GarbageCollectionResult, error) {
+	result := GarbageCollectionResult{}
+	cutoff := time.Now().Add(-gracePeriod)
+
+	referenced, missing, err := store.referencedChunks()
+	if err != nil {
+		return result, err
+	}
+	result.ReferencedChunks = len(referenced)
+
+	for _, registration := range missing {
+		if forgetMissing {
+			logger.Info("bbr", "Backup %s no longer exists, forgetting it", registration.backupPath)
+			if err := os.Remove(registration.path); err != nil {
+				return result, errors.Wrapf(err, "failed to unregister backup %s", registration.backupPath)
+			}
+			continue
+		}
+
+		logger.Warn("bbr", "Backup %s no longer exists. If it has been moved its chunks are still needed, so no chunks will be removed. If it has been deleted, run again with --forget-missing", registration.backupPath)
+		result.MissingBackups = append(result.MissingBackups, registration.backupPath)
+	}
+	if len(result.MissingBackups) > 0 {
+		return result, nil
+	}
+
+	err = filepath.Walk(store.chunksDir(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.ModTime().After(cutoff) {
+			return nil
+		}
+		if _, found := referenced[info.Name()]; found {
+			return nil
+		}
+
+		logger.Debug("bbr", "Removing unreferenced chunk %s", info.Name())
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		result.RemovedChunks++
+		result.FreedBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return result, errors.Wrap(err, "failed to remove unreferenced chunks")
+	}
+
+	return result, nil
+}
+
+type backupRegistration struct {
+	path       string
+	backupPath string
+}
+
+// referencedChunks collects the chunks referenced by every registered backup
+// that still exists, and returns the registrations of the backups that do not.
+func (store ChunkStore) referencedChunks() (map[string]struct{}, []backupRegistration, error) {
+	registrations, err := ioutil.ReadDir(store.backupsDir())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list backups using the chunk store")
+	}
+
+	referenced := map[string]struct{}{}
+	var missing []backupRegistration
+	for _, registration := range registrations {
+		registrationPath := filepath.Join(store.backupsDir(), registration.Name())
+		if strings.HasPrefix(registration.Name(), ".") {
+			continue
+		}
+
+		contents, err := ioutil.ReadFile(registrationPath)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to list backups using the chunk store")
+		}
+		backupPath := string(contents)
+
+		if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+			missing = append(missing, backupRegistration{path: registrationPath, backupPath: backupPath})
+			continue
+		}
+
+		manifestPaths, err := filepath.Glob(filepath.Join(backupPath, "*"+chunkManifestExtension))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to list chunk manifests in %s", backupPath)
+		}
+
+		for _, manifestPath := range manifestPaths {
+			manifest, err := readChunkManifest(manifestPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, entry := range manifest.Entries {
+				for _, id := range entry.Chunks {
+					referenced[id] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return referenced, missing, nil
+}
+
+func (store ChunkStore) chunkPath(id string) string {
+	return filepath.Join(store.chunksDir(), id[:2], id)
+}
+
+func (store ChunkStore) chunksDir() string {
+	return filepath.Join(store.path, "chunks")
+}
+
+func (store ChunkStore) backupsDir() string {
+	return filepath.Join(store.path, "backups")
+}
+
+func readChunkManifest(manifestPath string) (chunkManifest, error) {
+	contents, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return chunkManifest{}, errors.Wrap(err, "failed to read chunk manifest")
+	}
+
+	manifest := chunkManifest{}
+	if err := yaml.Unmarshal(contents, &manifest); err != nil {
+		return chunkManifest{}, errors.Wrapf(err, "failed to unmarshal chunk manifest %s", manifestPath)
+	}
+	return manifest, nil
+}
+
+func chunkManifestFileName(artifactFileName string) string {
+	return strings.TrimSuffix(artifactFileName, ".tar") + chunkManifestExtension
+}
+
+func newChunkedHeader(header *tar.Header) chunkedHeader {
+	return chunkedHeader{
+		Typeflag:   header.Typeflag,
+		Name:       header.Name,
+		Linkname:   header.Linkname,
+		Size:       header.Size,
+		Mode:       header.Mode,
+		Uid:        header.Uid,
+		Gid:        header.Gid,
+		Uname:      header.Uname,
+		Gname:      header.Gname,
+		ModTime:    header.ModTime.Unix(),
+		Devmajor:   header.Devmajor,
+		Devminor:   header.Devminor,
+		PAXRecords: header.PAXRecords,
+	}
+}
+
+func (header chunkedHeader) tarHeader() *tar.Header {
+	return &tar.Header{
+		Typeflag:   header.Typeflag,
+		Name:       header.Name,
+		Linkname:   header.Linkname,
+		Size:       header.Size,
+		Mode:       header.Mode,
+		Uid:        header.Uid,
+		Gid:        header.Gid,
+		Uname:      header.Uname,
+		Gname:      header.Gname,
+		ModTime:    time.Unix(header.ModTime, 0),
+		Devmajor:   header.Devmajor,
+		Devminor:   header.Devminor,
+		PAXRecords: header.PAXRecords,
+	}
+}
+
+const tarBlockSize = 512
+
+func roundUpToTarBlock(size int64) int64 {
+	return (size + tarBlockSize - 1) / tarBlockSize * tarBlockSize
+}
+
+func hashOf(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
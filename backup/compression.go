@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	NoCompression   = "none"
+	GzipCompression = "gzip"
+)
+
+type compressor interface {
+	NewWriter(io.WriteCloser) io.WriteCloser
+	NewReader(io.ReadCloser) (io.ReadCloser, error)
+}
+
+var compressors = map[string]compressor{
+	GzipCompression: gzipCompressor{},
+}
+
+func ValidateCompression(compression string) error {
+	if compression == "" || compression == NoCompression {
+		return nil
+	}
+
+	if _, found := compressors[compression]; !found {
+		return errors.Errorf("unsupported compression %q, supported values are: %s, %s", compression, NoCompression, GzipCompression)
+	}
+	return nil
+}
+
+func compressorFor(compression string) (compressor, error) {
+	if compression == "" || compression == NoCompression {
+		return nil, nil
+	}
+
+	compressor, found := compressors[compression]
+	if !found {
+		return nil, errors.Errorf("unsupported compression %q", compression)
+	}
+	return compressor, nil
+}
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) NewWriter(file io.WriteCloser) io.WriteCloser {
+	return chainedWriteCloser{WriteCloser: gzip.NewWriter(file), underlying: file}
+}
+
+func (gzipCompressor) NewReader(file io.ReadCloser) (io.ReadCloser, error) {
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	return chainedReadCloser{ReadCloser: reader, underlying: file}, nil
+}
+
+type chainedWriteCloser struct {
+	io.WriteCloser
+	underlying io.Closer
+}
+
+func (w chainedWriteCloser) Close() error {
+	err := w.WriteCloser.Close()
+	underlyingErr := w.underlying.Close()
+	if err != nil {
+		return err
+	}
+	return underlyingErr
+}
+
+type chainedReadCloser struct {
+	io.ReadCloser
+	underlying io.Closer
+}
+
+func (r chainedReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	underlyingErr := r.underlying.Close()
+	if err != nil {
+		return err
+	}
+	return underlyingErr
+}
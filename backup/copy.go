@@ -0,0 +1,195 @@
+package backup
+
+import (
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// CopyBackup copies the complete backup at sourcePath to a new backup with the
+// same name, created by the destination manager under destinationPath. Every
+// artifact is read through the source and written through the destination, so
+// the copy is compressed, encrypted and signed the way the destination manager
+// is set up. The checksums of each copied artifact are recalculated at the
+// destination and compared with the source metadata, and the copy is only
+// marked as complete when they all match.
+func CopyBackup(source BackupDirectoryManager, sourcePath string, destination orchestrator.BackupManager, destinationPath string, logger orchestrator.Logger) error {
+	sourceMetadata, err := readMetadata(filepath.Join(sourcePath, "metadata"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", sourcePath)
+	}
+
+	// The key may only be meant for encrypting the copy
+	if sourceMetadata.Encryption == nil {
+		source.EncryptionKey = nil
+	}
+
+	sourceBackup, err := source.Open(sourcePath, logger)
+	if err != nil {
+		return err
+	}
+	if err := sourceMetadata.checkComplete(); err != nil {
+		return errors.Wrapf(err, "failed to copy %s", sourcePath)
+	}
+	if err := sourceBackup.VerifySignature(); err != nil {
+		return errors.Wrapf(err, "failed to copy %s", sourcePath)
+	}
+
+	startTime, finishTime, err := sourceMetadata.activityTimes()
+	if err != nil {
+		return errors.Wrapf(err, "failed to copy %s", sourcePath)
+	}
+
+	destinationBackup, err := destination.Create(destinationPath, filepath.Base(filepath.Clean(sourcePath)), logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the copy")
+	}
+
+	if err := destinationBackup.CreateMetadataFileWithStartTime(startTime); err != nil {
+		return err
+	}
+
+	if err := destinationBackup.AddDeploymentMetadata(sourceMetadata.deploymentMetadata()); err != nil {
+		return err
+	}
+
+	manifest, found, err := readManifest(sourcePath)
+	if err != nil {
+		return err
+	}
+	if found {
+		if err := destinationBackup.SaveManifest(manifest); err != nil {
+			return err
+		}
+	}
+
+	var copyErr error
+	for _, artifact := range sourceMetadata.artifacts() {
+		if copyErr = copyArtifact(sourceBackup, destinationBackup, artifact.identifier, artifact.checksum, logger); copyErr != nil {
+			break
+		}
+	}
+
+	if copyErr == nil {
+		if err := destinationBackup.MarkComplete(); err != nil {
+			return err
+		}
+	}
+
+	if err := destinationBackup.AddFinishTime(finishTime); err != nil {
+		return err
+	}
+
+	if copyErr != nil {
+		return copyErr
+	}
+
+	return destinationBackup.Sign()
+}
+
+func copyArtifact(source, destination orchestrator.Backup, artifactIdentifier orchestrator.ArtifactIdentifier, expected orchestrator.BackupChecksum, logger orchestrator.Logger) error {
+	logger.Info("bbr", "Copying %s...", fileName(artifactIdentifier))
+
+	reader, err := source.ReadArtifact(artifactIdentifier)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", fileName(artifactIdentifier))
+	}
+	defer reader.Close()
+
+	writer, err := destination.CreateArtifact(artifactIdentifier)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", fileName(artifactIdentifier))
+	}
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		orchestrator.AbortArtifact(writer, err)
+		return errors.Wrapf(err, "failed to copy %s", fileName(artifactIdentifier))
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrapf(err, "failed to copy %s", fileName(artifactIdentifier))
+	}
+
+	actual, err := destination.CalculateChecksum(artifactIdentifier)
+	if err != nil {
+		return errors.Wrapf(err, "failed to calculate the checksum of the copied %s", fileName(artifactIdentifier))
+	}
+
+	if match, mismatchedFiles := expected.Match(actual); !match {
+		sort.Strings(mismatchedFiles)
+		return errors.Errorf("copied %s does not match the source, mismatched files: %s", fileName(artifactIdentifier), strings.Join(mismatchedFiles, ", "))
+	}
+
+	return destination.AddChecksum(artifactIdentifier, actual)
+}
+
+type storedArtifact struct {
+	identifier artifactIdentifier
+	checksum   orchestrator.BackupChecksum
+}
+
+func (data *metadata) artifacts() []storedArtifact {
+	var artifacts []storedArtifact
+	for _, artifact := range data.MetadataForEachArtifact {
+		artifacts = append(artifacts, storedArtifact{identifier: makeCustomArtifactIdentifier(artifact), checksum: artifact.Checksum})
+	}
+	for _, inst := range data.MetadataForEachInstance {
+		for _, artifact := range inst.Artifacts {
+			identifier := makeDefaultArtifactIdentifier(artifact, inst)
+			identifier.instanceID = inst.ID
+			artifacts = append(artifacts, storedArtifact{identifier: identifier, checksum: artifact.Checksum})
+		}
+	}
+	return artifacts
+}
+
+func (data *metadata) activityTimes() (time.Time, time.Time, error) {
+	startTime, err := time.Parse(timestampFormat, data.MetadataForBackupActivity.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrap(err, "failed to parse the backup start time")
+	}
+
+	if data.MetadataForBackupActivity.FinishTime == "" {
+		return startTime, time.Now(), nil
+	}
+
+	finishTime, err := time.Parse(timestampFormat, data.MetadataForBackupActivity.FinishTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrap(err, "failed to parse the backup finish time")
+	}
+	return startTime, finishTime, nil
+}
+
+// deploymentMetadata is the inverse of addDeploymentMetadata.
+func (data *metadata) deploymentMetadata() orchestrator.DeploymentMetadata {
+	deploymentMetadata := orchestrator.DeploymentMetadata{
+		Name:       data.Deployment,
+		BBRVersion: data.BBRVersion,
+	}
+
+	if data.Director != nil {
+		deploymentMetadata.Director = orchestrator.DirectorInfo{
+			UUID:    data.Director.UUID,
+			Name:    data.Director.Name,
+			Version: data.Director.Version,
+		}
+	}
+
+	for _, inst := range data.MetadataForEachInstance {
+		instance := orchestrator.InstanceMetadata{
+			Name:      inst.Name,
+			Index:     inst.Index,
+			ID:        inst.ID,
+			Bootstrap: inst.Bootstrap,
+		}
+		for _, job := range inst.Jobs {
+			instance.Jobs = append(instance.Jobs, orchestrator.JobSpecifier{Name: job.Name, Release: job.Release})
+		}
+		deploymentMetadata.Instances = append(deploymentMetadata.Instances, instance)
+	}
+	return deploymentMetadata
+}
@@ -0,0 +1,144 @@
+package backup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CopyBackup", func() {
+	var sourceDir, destinationDir, backupPath, copyPath string
+	var source, destination BackupDirectoryManager
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var copyError error
+
+	files := map[string]string{"file1": "one", "file2": "two"}
+
+	BeforeEach(func() {
+		var err error
+		sourceDir, err = ioutil.TempDir("", "copy-source")
+		Expect(err).NotTo(HaveOccurred())
+		destinationDir, err = ioutil.TempDir("", "copy-destination")
+		Expect(err).NotTo(HaveOccurred())
+		backupPath = filepath.Join(sourceDir, "redis_20151021T010203Z")
+		copyPath = filepath.Join(destinationDir, "redis_20151021T010203Z")
+		source = BackupDirectoryManager{}
+		destination = BackupDirectoryManager{}
+
+		backup, err := BackupDirectoryManager{}.Create(sourceDir, "redis_20151021T010203Z", logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backup.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+		Expect(backup.SaveManifest("name: redis\n")).To(Succeed())
+
+		instance := new(fakes.FakeInstance)
+		instance.NameReturns("redis-server")
+		instance.IndexReturns("0")
+		instance.IDReturns("fake-id")
+		Expect(backup.AddDeploymentMetadata(orchestrator.DeploymentMetadata{
+			Name:      "redis",
+			Director:  orchestrator.DirectorInfo{UUID: "director-uuid"},
+			Instances: []orchestrator.InstanceMetadata{orchestrator.NewInstanceMetadata(instance)},
+		})).To(Succeed())
+
+		writeTarArtifact(backup, newFakeArtifact("redis-server", "0", "redis"), files)
+
+		Expect(backup.MarkComplete()).To(Succeed())
+		Expect(backup.AddFinishTime(time.Date(2015, 10, 21, 1, 5, 3, 0, time.UTC))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(sourceDir)).To(Succeed())
+		Expect(os.RemoveAll(destinationDir)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		copyError = CopyBackup(source, backupPath, destination, destinationDir, logger)
+	})
+
+	It("copies the backup and marks the copy complete", func() {
+		Expect(copyError).NotTo(HaveOccurred())
+
+		report, err := BackupDirectoryManager{}.Verify(copyPath, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Valid()).To(BeTrue())
+		Expect(report.Artifacts).To(HaveLen(1))
+
+		Expect(ioutil.ReadFile(filepath.Join(copyPath, "manifest.yml"))).To(Equal([]byte("name: redis\n")))
+
+		diff, err := DiffBackups(backupPath, copyPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeTrue())
+
+		catalog, err := FindBackups(destinationDir, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(catalog).To(HaveLen(1))
+		Expect(catalog[0].Status).To(Equal("complete"))
+		Expect(catalog[0].StartTime).To(Equal(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC)))
+		Expect(catalog[0].DirectorUUID).To(Equal("director-uuid"))
+	})
+
+	Context("when the destination compresses and encrypts artifacts", func() {
+		BeforeEach(func() {
+			destination = BackupDirectoryManager{Compression: "gzip", EncryptionKey: NewEncryptionKeyFromPassphrase("secret")}
+		})
+
+		It("writes a copy that can be read with the key", func() {
+			Expect(copyError).NotTo(HaveOccurred())
+
+			report, err := BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("secret")}.Verify(copyPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid()).To(BeTrue())
+		})
+
+		Context("and the key is also given for the unencrypted source", func() {
+			BeforeEach(func() {
+				source = BackupDirectoryManager{EncryptionKey: NewEncryptionKeyFromPassphrase("secret")}
+			})
+
+			It("reads the source without the key", func() {
+				Expect(copyError).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Context("when an artifact of the source is corrupted", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(filepath.Join(backupPath, "redis-server-0-redis.tar"), createTarWithContents(map[string]string{
+				"file1": "one",
+				"file2": "changed",
+			}), 0644)).To(Succeed())
+		})
+
+		It("fails and does not mark the copy complete", func() {
+			Expect(copyError).To(MatchError(ContainSubstring("copied redis-server-0-redis.tar does not match the source")))
+
+			catalog, err := FindBackups(destinationDir, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(catalog).To(HaveLen(1))
+			Expect(catalog[0].Status).To(Equal("failed"))
+		})
+	})
+
+	Context("when the source backup is not complete", func() {
+		BeforeEach(func() {
+			contents, err := ioutil.ReadFile(filepath.Join(backupPath, "metadata"))
+			Expect(err).NotTo(HaveOccurred())
+			contents = []byte(strings.Replace(string(contents), "status: complete", "status: in_progress", 1))
+			Expect(ioutil.WriteFile(filepath.Join(backupPath, "metadata"), contents, 0644)).To(Succeed())
+		})
+
+		It("refuses to copy it", func() {
+			Expect(copyError).To(MatchError(ContainSubstring("backup is not complete")))
+			Expect(copyPath).NotTo(BeADirectory())
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"crypto/ed25519"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// DedupBackupManager lays backups out like a BackupDirectoryManager, but the
+// artifacts in each backup directory are chunk manifests that refer to a
+// shared ChunkStore instead of full copies of the tar files.
+type DedupBackupManager struct {
+	Store           ChunkStore
+	IncrementalFrom string
+	SigningKey      ed25519.PrivateKey
+}
+
+func NewDedupBackupManager(storePath string) (DedupBackupManager, error) {
+	store, err := NewChunkStore(storePath)
+	if err != nil {
+		return DedupBackupManager{}, err
+	}
+	return DedupBackupManager{Store: store}, nil
+}
+
+func (manager DedupBackupManager) Create(path, directoryName string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+	backup, err := BackupDirectoryManager{IncrementalFrom: manager.IncrementalFrom, SigningKey: manager.SigningKey}.Create(path, directoryName, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	backupDirectory := backup.(*BackupDirectory)
+	if err := manager.Store.register(backupDirectory.baseDirName); err != nil {
+		return nil, errors.Wrap(err, "failed creating artifact directory")
+	}
+
+	backupDirectory.chunkStore = &manager.Store
+	return backupDirectory, nil
+}
+
+func (manager DedupBackupManager) Open(name string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+	backup, err := BackupDirectoryManager{SigningKey: manager.SigningKey}.Open(name, logger)
+	backup.(*BackupDirectory).chunkStore = &manager.Store
+	return backup, err
+}
@@ -0,0 +1,303 @@
+package backup_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DedupBackupManager", func() {
+	var storePath string
+	var artifactPath string
+	var backupManager DedupBackupManager
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var fakeBackupArtifact *fakes.FakeBackupArtifact
+	var blobContents string
+
+	BeforeEach(func() {
+		var err error
+		storePath, err = ioutil.TempDir("", "dedup-store")
+		Expect(err).NotTo(HaveOccurred())
+		artifactPath, err = ioutil.TempDir("", "dedup-artifacts")
+		Expect(err).NotTo(HaveOccurred())
+
+		backupManager, err = NewDedupBackupManager(storePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeBackupArtifact = new(fakes.FakeBackupArtifact)
+		fakeBackupArtifact.InstanceNameReturns("redis-server")
+		fakeBackupArtifact.InstanceIndexReturns("0")
+		fakeBackupArtifact.NameReturns("redis")
+
+		blobContents = strings.Repeat("a blob that rarely changes ", 100000)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(storePath)).To(Succeed())
+		Expect(os.RemoveAll(artifactPath)).To(Succeed())
+	})
+
+	createBackup := func(name string, files map[string]string) orchestrator.Backup {
+		backup, err := backupManager.Create(artifactPath, name, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backup.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+
+		writer, err := backup.CreateArtifact(fakeBackupArtifact)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = writer.Write(createTarWithContents(files))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		checksum, err := backup.CalculateChecksum(fakeBackupArtifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backup.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+		Expect(backup.MarkComplete()).To(Succeed())
+		Expect(backup.AddFinishTime(time.Date(2015, 10, 21, 1, 5, 3, 0, time.UTC))).To(Succeed())
+		return backup
+	}
+
+	chunkFiles := func() []string {
+		var chunks []string
+		filepath.Walk(filepath.Join(storePath, "chunks"), func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				chunks = append(chunks, path)
+			}
+			return nil
+		})
+		return chunks
+	}
+
+	Describe("backing up and restoring", func() {
+		var backup orchestrator.Backup
+
+		BeforeEach(func() {
+			backup = createBackup("my-deployment_20151021T010203Z", map[string]string{
+				"blob":  blobContents,
+				"empty": "",
+			})
+		})
+
+		It("only keeps a chunk manifest in the backup directory", func() {
+			files, err := filepath.Glob(filepath.Join(artifactPath, "my-deployment_20151021T010203Z", "*"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(ConsistOf(
+				filepath.Join(artifactPath, "my-deployment_20151021T010203Z", "metadata"),
+				filepath.Join(artifactPath, "my-deployment_20151021T010203Z", "redis-server-0-redis.chunks"),
+			))
+		})
+
+		It("splits the file contents into chunks", func() {
+			Expect(chunkFiles()).To(HaveLen(3))
+		})
+
+		It("records the chunk store in the metadata", func() {
+			metadata, err := ioutil.ReadFile(filepath.Join(artifactPath, "my-deployment_20151021T010203Z", "metadata"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(metadata)).To(ContainSubstring("chunk_store: " + backupManager.Store.Path()))
+		})
+
+		It("reassembles the tar stream", func() {
+			reader, err := backup.ReadArtifact(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			defer reader.Close()
+
+			Expect(filesInTar(reader)).To(Equal(map[string]string{
+				"blob":  blobContents,
+				"empty": "",
+			}))
+		})
+
+		It("reports the size of the reassembled tar stream", func() {
+			Expect(backup.GetArtifactByteSize(fakeBackupArtifact)).To(Equal(512 + 2700288 + 512 + 1024))
+			Expect(backup.GetArtifactSize(fakeBackupArtifact)).To(Equal("2.6M"))
+		})
+
+		It("can be opened for restore without naming the chunk store", func() {
+			restoreBackup, err := BackupDirectoryManager{}.Open(filepath.Join(artifactPath, "my-deployment_20151021T010203Z"), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(restoreBackup.Valid()).To(BeTrue())
+
+			reader, err := restoreBackup.ReadArtifact(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			defer reader.Close()
+			Expect(filesInTar(reader)).To(HaveKeyWithValue("blob", blobContents))
+		})
+
+		Context("when another backup contains the same files", func() {
+			BeforeEach(func() {
+				createBackup("my-deployment_20151022T010203Z", map[string]string{
+					"blob":  blobContents,
+					"empty": "",
+					"new":   "a new file",
+				})
+			})
+
+			It("only stores the new chunks", func() {
+				Expect(chunkFiles()).To(HaveLen(4))
+			})
+
+			Context("and the first backup is deleted", func() {
+				BeforeEach(func() {
+					Expect(os.RemoveAll(filepath.Join(artifactPath, "my-deployment_20151021T010203Z"))).To(Succeed())
+				})
+
+				It("keeps the chunks that are still referenced", func() {
+					result, err := backupManager.Store.CollectGarbage(0, true, logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RemovedChunks).To(Equal(0))
+					Expect(result.ReferencedChunks).To(Equal(4))
+				})
+			})
+		})
+
+		Context("when the backup is deleted", func() {
+			BeforeEach(func() {
+				Expect(os.RemoveAll(filepath.Join(artifactPath, "my-deployment_20151021T010203Z"))).To(Succeed())
+			})
+
+			It("keeps its chunks and reports it as missing", func() {
+				result, err := backupManager.Store.CollectGarbage(0, false, logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RemovedChunks).To(Equal(0))
+				Expect(result.MissingBackups).To(ConsistOf(filepath.Join(artifactPath, "my-deployment_20151021T010203Z")))
+				Expect(chunkFiles()).To(HaveLen(3))
+			})
+
+			Context("and missing backups are forgotten", func() {
+				It("garbage collects its chunks", func() {
+					result, err := backupManager.Store.CollectGarbage(0, true, logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RemovedChunks).To(Equal(3))
+					Expect(result.FreedBytes).To(Equal(int64(len(blobContents))))
+					Expect(result.MissingBackups).To(BeEmpty())
+					Expect(chunkFiles()).To(BeEmpty())
+				})
+
+				It("keeps chunks written within the grace period", func() {
+					result, err := backupManager.Store.CollectGarbage(time.Hour, true, logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RemovedChunks).To(Equal(0))
+					Expect(chunkFiles()).To(HaveLen(3))
+				})
+
+				It("does not report the backup as missing again", func() {
+					_, err := backupManager.Store.CollectGarbage(time.Hour, true, logger)
+					Expect(err).NotTo(HaveOccurred())
+
+					result, err := backupManager.Store.CollectGarbage(time.Hour, false, logger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.MissingBackups).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("when the backup directory is moved", func() {
+			var movedPath string
+
+			BeforeEach(func() {
+				var err error
+				movedPath, err = ioutil.TempDir("", "moved-dedup-artifacts")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.Rename(
+					filepath.Join(artifactPath, "my-deployment_20151021T010203Z"),
+					filepath.Join(movedPath, "my-deployment_20151021T010203Z"),
+				)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(movedPath)).To(Succeed())
+			})
+
+			It("keeps its chunks, so the moved backup can still be restored", func() {
+				result, err := backupManager.Store.CollectGarbage(0, false, logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RemovedChunks).To(Equal(0))
+				Expect(chunkFiles()).To(HaveLen(3))
+
+				movedBackup, err := BackupDirectoryManager{}.Open(filepath.Join(movedPath, "my-deployment_20151021T010203Z"), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(movedBackup.Valid()).To(BeTrue())
+			})
+
+			It("keeps its registration", func() {
+				_, err := backupManager.Store.CollectGarbage(0, false, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				registrations, err := ioutil.ReadDir(filepath.Join(storePath, "backups"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(registrations).To(HaveLen(1))
+			})
+		})
+
+		Context("when a chunk has been corrupted", func() {
+			BeforeEach(func() {
+				for _, chunk := range chunkFiles() {
+					Expect(ioutil.WriteFile(chunk, []byte("corrupted"), 0600)).To(Succeed())
+				}
+			})
+
+			It("fails to reassemble the artifact", func() {
+				reader, err := backup.ReadArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+
+				_, err = ioutil.ReadAll(reader)
+				Expect(err).To(MatchError(ContainSubstring("is corrupted")))
+			})
+
+			It("is not valid", func() {
+				valid, err := backup.Valid()
+				Expect(valid).To(BeFalse())
+				Expect(err).To(MatchError(ContainSubstring("is corrupted")))
+			})
+		})
+	})
+
+	It("preserves long file names", func() {
+		longName := strings.Repeat("directory/", 20) + "file"
+		backup := createBackup("my-deployment_20151021T010203Z", map[string]string{longName: "contents"})
+
+		checksum, err := backup.FetchChecksum(fakeBackupArtifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checksum).To(Equal(orchestrator.BackupChecksum{
+			longName: fmt.Sprintf("%x", sha256.Sum256([]byte("contents"))),
+		}))
+	})
+
+	It("fails when the chunk store cannot be created", func() {
+		_, err := NewDedupBackupManager("/dev/null/store")
+		Expect(err).To(MatchError(ContainSubstring("failed to create chunk store")))
+	})
+})
+
+func filesInTar(reader io.Reader) map[string]string {
+	files := map[string]string{}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		contents := bytes.NewBuffer(nil)
+		_, err = io.Copy(contents, tarReader)
+		Expect(err).NotTo(HaveOccurred())
+		files[header.Name] = contents.String()
+	}
+}
@@ -0,0 +1,242 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BackupDiff describes what changed between two backups, going by their
+// metadata and saved manifests only. Instances are named name/index and
+// artifacts of an instance name/index artifact. Replaced instances are in both
+// backups, but with different instance IDs.
+type BackupDiff struct {
+	AddedInstances    []string       `json:"added_instances,omitempty"`
+	RemovedInstances  []string       `json:"removed_instances,omitempty"`
+	ReplacedInstances []string       `json:"replaced_instances,omitempty"`
+	AddedArtifacts    []string       `json:"added_artifacts,omitempty"`
+	RemovedArtifacts  []string       `json:"removed_artifacts,omitempty"`
+	ChangedArtifacts  []ArtifactDiff `json:"changed_artifacts,omitempty"`
+	ManifestDiff      []string       `json:"manifest_diff,omitempty"`
+	ManifestCompared  bool           `json:"manifest_compared"`
+}
+
+type ArtifactDiff struct {
+	Artifact     string   `json:"artifact"`
+	AddedFiles   []string `json:"added_files,omitempty"`
+	RemovedFiles []string `json:"removed_files,omitempty"`
+	ChangedFiles []string `json:"changed_files,omitempty"`
+}
+
+func (diff BackupDiff) IsEmpty() bool {
+	return len(diff.AddedInstances) == 0 && len(diff.RemovedInstances) == 0 && len(diff.ReplacedInstances) == 0 &&
+		len(diff.AddedArtifacts) == 0 && len(diff.RemovedArtifacts) == 0 &&
+		len(diff.ChangedArtifacts) == 0 && len(diff.ManifestDiff) == 0
+}
+
+// DiffBackups compares the backup directory at fromPath with the one at toPath.
+func DiffBackups(fromPath, toPath string) (BackupDiff, error) {
+	from, err := readMetadata(filepath.Join(fromPath, "metadata"))
+	if err != nil {
+		return BackupDiff{}, errors.Wrapf(err, "failed to read %s", fromPath)
+	}
+	to, err := readMetadata(filepath.Join(toPath, "metadata"))
+	if err != nil {
+		return BackupDiff{}, errors.Wrapf(err, "failed to read %s", toPath)
+	}
+
+	var diff BackupDiff
+	diff.AddedInstances, diff.RemovedInstances = diffKeys(from.instanceNames(), to.instanceNames())
+	diff.ReplacedInstances = replacedInstances(from, to)
+
+	fromArtifacts, toArtifacts := from.artifactChecksums(), to.artifactChecksums()
+	diff.AddedArtifacts, diff.RemovedArtifacts = diffKeys(artifactNames(fromArtifacts), artifactNames(toArtifacts))
+
+	for _, artifact := range artifactNames(toArtifacts) {
+		fromChecksum, found := fromArtifacts[artifact]
+		if !found {
+			continue
+		}
+
+		artifactDiff := diffChecksums(artifact, fromChecksum, toArtifacts[artifact])
+		if len(artifactDiff.AddedFiles) > 0 || len(artifactDiff.RemovedFiles) > 0 || len(artifactDiff.ChangedFiles) > 0 {
+			diff.ChangedArtifacts = append(diff.ChangedArtifacts, artifactDiff)
+		}
+	}
+
+	fromManifest, fromFound, err := readManifest(fromPath)
+	if err != nil {
+		return BackupDiff{}, err
+	}
+	toManifest, toFound, err := readManifest(toPath)
+	if err != nil {
+		return BackupDiff{}, err
+	}
+	if fromFound && toFound {
+		diff.ManifestCompared = true
+		diff.ManifestDiff = diffLines(fromManifest, toManifest)
+	}
+
+	return diff, nil
+}
+
+func (data *metadata) instanceNames() []string {
+	var names []string
+	for _, instance := range data.MetadataForEachInstance {
+		names = append(names, instance.Name+"/"+instance.Index)
+	}
+	return names
+}
+
+// replacedInstances returns the instances whose ID differs between the backups.
+// Instances without a recorded ID were backed up by an older version of bbr and
+// are not compared.
+func replacedInstances(from, to metadata) []string {
+	var replaced []string
+	for _, toInstance := range to.MetadataForEachInstance {
+		for _, fromInstance := range from.MetadataForEachInstance {
+			if fromInstance.Name != toInstance.Name || fromInstance.Index != toInstance.Index {
+				continue
+			}
+			if fromInstance.ID != "" && toInstance.ID != "" && fromInstance.ID != toInstance.ID {
+				replaced = append(replaced, toInstance.Name+"/"+toInstance.Index)
+			}
+		}
+	}
+	sort.Strings(replaced)
+	return replaced
+}
+
+func (data *metadata) artifactChecksums() map[string]map[string]string {
+	checksums := map[string]map[string]string{}
+	for _, artifact := range data.MetadataForEachArtifact {
+		checksums[artifact.Name] = artifact.Checksum
+	}
+	for _, instance := range data.MetadataForEachInstance {
+		for _, artifact := range instance.Artifacts {
+			checksums[instance.Name+"/"+instance.Index+" "+artifact.Name] = artifact.Checksum
+		}
+	}
+	return checksums
+}
+
+func diffChecksums(artifact string, from, to map[string]string) ArtifactDiff {
+	artifactDiff := ArtifactDiff{Artifact: artifact}
+	artifactDiff.AddedFiles, artifactDiff.RemovedFiles = diffKeys(fileNames(from), fileNames(to))
+
+	for _, file := range fileNames(to) {
+		if fromSha, found := from[file]; found && fromSha != to[file] {
+			artifactDiff.ChangedFiles = append(artifactDiff.ChangedFiles, file)
+		}
+	}
+	return artifactDiff
+}
+
+// diffKeys returns the names that are only in to, and the names that are only
+// in from, in name order.
+func diffKeys(from, to []string) ([]string, []string) {
+	fromNames, toNames := map[string]bool{}, map[string]bool{}
+	for _, name := range from {
+		fromNames[name] = true
+	}
+	for _, name := range to {
+		toNames[name] = true
+	}
+
+	var added, removed []string
+	for _, name := range to {
+		if !fromNames[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range from {
+		if !toNames[name] {
+			removed = append(removed, name)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func fileNames(checksum map[string]string) []string {
+	var names []string
+	for name := range checksum {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func artifactNames(checksums map[string]map[string]string) []string {
+	var names []string
+	for name := range checksums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readManifest(backupPath string) (string, bool, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(backupPath, "manifest.yml"))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, errors.Wrapf(err, "failed to read the manifest of %s", backupPath)
+	}
+	return string(contents), true, nil
+}
+
+// diffLines returns the lines removed from and added to a text, prefixed with
+// "- " and "+ ", in the order they appear. It uses the longest common
+// subsequence of the lines between the common prefix and suffix, which is
+// cheap for the small changes manifests usually see between backups.
+func diffLines(from, to string) []string {
+	fromLines := strings.Split(strings.TrimSuffix(from, "\n"), "\n")
+	toLines := strings.Split(strings.TrimSuffix(to, "\n"), "\n")
+
+	for len(fromLines) > 0 && len(toLines) > 0 && fromLines[0] == toLines[0] {
+		fromLines, toLines = fromLines[1:], toLines[1:]
+	}
+	for len(fromLines) > 0 && len(toLines) > 0 && fromLines[len(fromLines)-1] == toLines[len(toLines)-1] {
+		fromLines, toLines = fromLines[:len(fromLines)-1], toLines[:len(toLines)-1]
+	}
+
+	common := make([][]int, len(fromLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(toLines)+1)
+	}
+	for i := len(fromLines) - 1; i >= 0; i-- {
+		for j := len(toLines) - 1; j >= 0; j-- {
+			if fromLines[i] == toLines[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(fromLines) || j < len(toLines) {
+		switch {
+		case i < len(fromLines) && j < len(toLines) && fromLines[i] == toLines[j]:
+			i, j = i+1, j+1
+		case j == len(toLines) || (i < len(fromLines) && common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, "- "+fromLines[i])
+			i++
+		default:
+			lines = append(lines, "+ "+toLines[j])
+			j++
+		}
+	}
+	return lines
+}
@@ -0,0 +1,180 @@
+package backup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffBackups", func() {
+	var fromPath, toPath string
+	var diff BackupDiff
+	var diffError error
+
+	BeforeEach(func() {
+		var err error
+		fromPath, err = ioutil.TempDir("", "diff-from")
+		Expect(err).NotTo(HaveOccurred())
+		toPath, err = ioutil.TempDir("", "diff-to")
+		Expect(err).NotTo(HaveOccurred())
+
+		createTestMetadata(fromPath, `---
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    checksums:
+      unchanged: aaaa
+      changed: bbbb
+      removed: cccc
+- name: redis-server
+  index: "1"
+  artifacts:
+  - name: redis
+    checksums:
+      file1: dddd
+custom_artifacts:
+- name: shared
+  checksums:
+    file1: eeee
+`)
+		createTestMetadata(toPath, `---
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    checksums:
+      unchanged: aaaa
+      changed: ffff
+      added: 1111
+  - name: redis-metadata
+    checksums:
+      file1: 2222
+- name: redis-server
+  index: "2"
+  artifacts:
+  - name: redis
+    checksums:
+      file1: dddd
+custom_artifacts:
+- name: shared
+  checksums:
+    file1: eeee
+`)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(fromPath)).To(Succeed())
+		Expect(os.RemoveAll(toPath)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		diff, diffError = DiffBackups(fromPath, toPath)
+	})
+
+	It("reports the added and removed instances and artifacts", func() {
+		Expect(diffError).NotTo(HaveOccurred())
+		Expect(diff.AddedInstances).To(Equal([]string{"redis-server/2"}))
+		Expect(diff.RemovedInstances).To(Equal([]string{"redis-server/1"}))
+		Expect(diff.AddedArtifacts).To(Equal([]string{"redis-server/0 redis-metadata", "redis-server/2 redis"}))
+		Expect(diff.RemovedArtifacts).To(Equal([]string{"redis-server/1 redis"}))
+	})
+
+	It("does not report replaced instances when no instance IDs were recorded", func() {
+		Expect(diff.ReplacedInstances).To(BeEmpty())
+	})
+
+	Context("when the instance IDs were recorded", func() {
+		BeforeEach(func() {
+			createTestMetadata(fromPath, `---
+version: 2
+instances:
+- name: redis-server
+  index: "0"
+  id: 11111111
+  artifacts: []
+- name: redis-server
+  index: "1"
+  id: 22222222
+  artifacts: []
+`)
+			createTestMetadata(toPath, `---
+version: 2
+instances:
+- name: redis-server
+  index: "0"
+  id: 11111111
+  artifacts: []
+- name: redis-server
+  index: "1"
+  id: 33333333
+  artifacts: []
+`)
+		})
+
+		It("reports the instances whose IDs changed as replaced", func() {
+			Expect(diffError).NotTo(HaveOccurred())
+			Expect(diff.ReplacedInstances).To(Equal([]string{"redis-server/1"}))
+			Expect(diff.IsEmpty()).To(BeFalse())
+		})
+	})
+
+	It("reports the files that changed in each artifact", func() {
+		Expect(diff.ChangedArtifacts).To(Equal([]ArtifactDiff{{
+			Artifact:     "redis-server/0 redis",
+			AddedFiles:   []string{"added"},
+			RemovedFiles: []string{"removed"},
+			ChangedFiles: []string{"changed"},
+		}}))
+	})
+
+	It("does not compare the manifests when they were not saved", func() {
+		Expect(diff.ManifestCompared).To(BeFalse())
+		Expect(diff.IsEmpty()).To(BeFalse())
+	})
+
+	Context("when both backups contain a manifest", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(filepath.Join(fromPath, "manifest.yml"), []byte("name: redis\ninstance_groups:\n- name: redis-server\n  instances: 2\nstemcells: []\n"), 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(toPath, "manifest.yml"), []byte("name: redis\ninstance_groups:\n- name: redis-server\n  instances: 3\n  azs: [z1]\nstemcells: []\n"), 0600)).To(Succeed())
+		})
+
+		It("diffs the manifests", func() {
+			Expect(diff.ManifestCompared).To(BeTrue())
+			Expect(diff.ManifestDiff).To(Equal([]string{
+				"-   instances: 2",
+				"+   instances: 3",
+				"+   azs: [z1]",
+			}))
+		})
+	})
+
+	Context("when the backups are the same", func() {
+		BeforeEach(func() {
+			metadata, err := ioutil.ReadFile(filepath.Join(fromPath, "metadata"))
+			Expect(err).NotTo(HaveOccurred())
+			createTestMetadata(toPath, string(metadata))
+		})
+
+		It("reports no differences", func() {
+			Expect(diffError).NotTo(HaveOccurred())
+			Expect(diff.IsEmpty()).To(BeTrue())
+		})
+	})
+
+	Context("when the metadata of a backup cannot be read", func() {
+		BeforeEach(func() {
+			Expect(os.Remove(filepath.Join(toPath, "metadata"))).To(Succeed())
+		})
+
+		It("fails", func() {
+			Expect(diffError).To(MatchError(ContainSubstring("failed to read " + toPath)))
+		})
+	})
+})
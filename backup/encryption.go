@@ -0,0 +1,268 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+const AES256GCMCipher = "aes-256-gcm"
+
+const (
+	encryptionChunkSize   = 64 * 1024
+	encryptionNoncePrefix = 7
+	encryptionSaltSize    = 16
+)
+
+// EncryptionKey is the secret supplied by the operator. Key files are hashed
+// into an AES-256 key; passphrases are stretched with scrypt using a salt that
+// is stored in the backup metadata.
+type EncryptionKey struct {
+	material     []byte
+	isPassphrase bool
+}
+
+func NewEncryptionKeyFromFile(keyFilePath string) (*EncryptionKey, error) {
+	material, err := ioutil.ReadFile(keyFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read encryption key file")
+	}
+	if len(material) == 0 {
+		return nil, errors.Errorf("encryption key file %s is empty", keyFilePath)
+	}
+	return &EncryptionKey{material: material}, nil
+}
+
+func NewEncryptionKeyFromPassphrase(passphrase string) *EncryptionKey {
+	return &EncryptionKey{material: []byte(passphrase), isPassphrase: true}
+}
+
+func (k *EncryptionKey) newSalt() ([]byte, error) {
+	if !k.isPassphrase {
+		return nil, nil
+	}
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate encryption salt")
+	}
+	return salt, nil
+}
+
+func (k *EncryptionKey) derive(salt []byte) ([]byte, error) {
+	if !k.isPassphrase {
+		key := sha256.Sum256(k.material)
+		return key[:], nil
+	}
+	key, err := scrypt.Key(k.material, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive encryption key from passphrase")
+	}
+	return key, nil
+}
+
+func keyFingerprint(key []byte) string {
+	fingerprint := sha256.Sum256(append([]byte("bbr-key-fingerprint:"), key...))
+	return hex.EncodeToString(fingerprint[:16])
+}
+
+// The encrypted stream is a random nonce prefix followed by length-prefixed
+// AES-GCM sealed chunks. Each chunk nonce is the prefix, a big-endian chunk
+// counter and a final-chunk flag, so reordered or truncated files fail to
+// decrypt.
+type encryptingWriter struct {
+	aead       cipher.AEAD
+	underlying io.WriteCloser
+	prefix     []byte
+	counter    uint32
+	buffer     []byte
+	started    bool
+}
+
+func newEncryptingWriter(key []byte, underlying io.WriteCloser) (io.WriteCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, encryptionNoncePrefix)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	return &encryptingWriter{
+		aead:       aead,
+		underlying: underlying,
+		prefix:     prefix,
+		buffer:     make([]byte, 0, encryptionChunkSize),
+	}, nil
+}
+
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if len(w.buffer) == encryptionChunkSize {
+			if err := w.flush(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(w.buffer[len(w.buffer):encryptionChunkSize], p)
+		w.buffer = w.buffer[:len(w.buffer)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (w *encryptingWriter) Close() error {
+	err := w.flush(true)
+	underlyingErr := w.underlying.Close()
+	if err != nil {
+		return err
+	}
+	return underlyingErr
+}
+
+func (w *encryptingWriter) flush(final bool) error {
+	if !w.started {
+		if _, err := w.underlying.Write(w.prefix); err != nil {
+			return err
+		}
+		w.started = true
+	}
+
+	sealed := w.aead.Seal(nil, chunkNonce(w.prefix, w.counter, final), w.buffer, nil)
+
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(sealed)))
+	if _, err := w.underlying.Write(length); err != nil {
+		return err
+	}
+	if _, err := w.underlying.Write(sealed); err != nil {
+		return err
+	}
+
+	w.counter++
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+type decryptingReader struct {
+	aead       cipher.AEAD
+	underlying io.ReadCloser
+	prefix     []byte
+	counter    uint32
+	nextLength []byte
+	plaintext  []byte
+	finished   bool
+}
+
+func newDecryptingReader(key []byte, underlying io.ReadCloser) (io.ReadCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, encryptionNoncePrefix)
+	if _, err := io.ReadFull(underlying, prefix); err != nil {
+		return nil, errors.Wrap(err, "failed to read encryption header")
+	}
+
+	reader := &decryptingReader{aead: aead, underlying: underlying, prefix: prefix}
+	if reader.nextLength, err = reader.readLength(); err != nil {
+		return nil, err
+	}
+	if reader.nextLength == nil {
+		return nil, errors.New("failed to decrypt artifact: encrypted stream is truncated")
+	}
+	return reader, nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.plaintext) == 0 {
+		if r.finished {
+			return 0, io.EOF
+		}
+		if err := r.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.plaintext)
+	r.plaintext = r.plaintext[n:]
+	return n, nil
+}
+
+func (r *decryptingReader) Close() error {
+	return r.underlying.Close()
+}
+
+// readChunk checks the length of the next chunk before reading it, as the
+// length is read before the chunk can be authenticated.
+func (r *decryptingReader) readChunk() error {
+	length := binary.BigEndian.Uint32(r.nextLength)
+	if length > uint32(encryptionChunkSize+r.aead.Overhead()) {
+		return errors.Errorf("failed to decrypt artifact: encrypted stream is corrupted, chunk of %d bytes is larger than the maximum of %d", length, encryptionChunkSize+r.aead.Overhead())
+	}
+
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(r.underlying, sealed); err != nil {
+		return errors.Wrap(err, "failed to decrypt artifact: encrypted stream is truncated")
+	}
+
+	nextLength, err := r.readLength()
+	if err != nil {
+		return err
+	}
+	final := nextLength == nil
+
+	plaintext, err := r.aead.Open(nil, chunkNonce(r.prefix, r.counter, final), sealed, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt artifact")
+	}
+
+	r.counter++
+	r.nextLength = nextLength
+	r.plaintext = plaintext
+	r.finished = final
+	return nil
+}
+
+func (r *decryptingReader) readLength() ([]byte, error) {
+	length := make([]byte, 4)
+	_, err := io.ReadFull(r.underlying, length)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt artifact: encrypted stream is truncated")
+	}
+	return length, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, counter uint32, final bool) []byte {
+	nonce := make([]byte, 0, encryptionNoncePrefix+5)
+	nonce = append(nonce, prefix...)
+	nonce = append(nonce, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(nonce[encryptionNoncePrefix:], counter)
+	if final {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
@@ -0,0 +1,155 @@
+package backup
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// Extract writes the files of one artifact of the backup in the directory at
+// path that match the glob pattern to the destination directory, and returns
+// their names. An empty pattern matches every file. Artifacts of an instance
+// are found by instance name and index, and custom artifacts by name alone.
+// Every extracted file is checked against the checksum recorded in the
+// metadata, and files that do not match are removed again.
+func (manager BackupDirectoryManager) Extract(path, instanceName, instanceIndex, artifactName, pattern, destination string, logger orchestrator.Logger) ([]string, error) {
+	backupDirectory := &BackupDirectory{
+		baseDirName:   path,
+		Logger:        logger,
+		encryptionKey: manager.EncryptionKey,
+		trustedKey:    manager.TrustedKey,
+	}
+
+	identifier := artifactIdentifier{name: artifactName, instanceName: instanceName, instanceIndex: instanceIndex, hasCustomName: instanceName == ""}
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to extract from %s", path)
+	}
+	artifact, found := metadata.findArtifactMetadata(identifier)
+	if !found {
+		return nil, errors.Errorf("backup %s does not contain %s", path, fileName(identifier))
+	}
+
+	if err := backupDirectory.VerifySignature(); err != nil {
+		return nil, err
+	}
+
+	if pattern != "" {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid file pattern %q", pattern)
+		}
+	}
+
+	if err := os.MkdirAll(destination, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s", destination)
+	}
+
+	reader, err := backupDirectory.ReadArtifact(identifier)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", fileName(identifier))
+	}
+	defer reader.Close()
+
+	extracted, mismatched, err := extractTarFiles(reader, pattern, destination, artifact.Checksum)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to extract from %s", fileName(identifier))
+	}
+
+	if len(mismatched) > 0 {
+		return extracted, errors.Errorf("extracted files do not match the checksums in the metadata and were removed: %s", strings.Join(mismatched, ", "))
+	}
+	if len(extracted) == 0 {
+		return nil, errors.Errorf("no files in %s match %q", fileName(identifier), pattern)
+	}
+	return extracted, nil
+}
+
+func extractTarFiles(reader io.Reader, pattern, destination string, checksum orchestrator.BackupChecksum) ([]string, []string, error) {
+	var extracted, mismatched []string
+
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "error reading tar")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := filepath.Clean(strings.TrimPrefix(header.Name, "./"))
+		if !matchesPattern(pattern, name) {
+			continue
+		}
+		if name == ".." || strings.HasPrefix(name, "../") || filepath.IsAbs(name) {
+			return nil, nil, errors.Errorf("refusing to extract %s outside of the destination", header.Name)
+		}
+
+		target := filepath.Join(destination, name)
+		sha, err := writeExtractedFile(target, tarReader, header.FileInfo().Mode().Perm())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if expected, found := checksum[header.Name]; !found || expected != sha {
+			os.Remove(target)
+			mismatched = append(mismatched, name)
+			continue
+		}
+		extracted = append(extracted, name)
+	}
+
+	sort.Strings(extracted)
+	sort.Strings(mismatched)
+	return extracted, mismatched, nil
+}
+
+// matchesPattern matches the pattern against the whole path of the file in the
+// artifact, and against its base name for patterns without a separator.
+func matchesPattern(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	if match, _ := filepath.Match(pattern, name); match {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		match, _ := filepath.Match(pattern, filepath.Base(name))
+		return match
+	}
+	return false
+}
+
+func writeExtractedFile(target string, reader io.Reader, mode os.FileMode) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", errors.Wrapf(err, "failed to create %s", filepath.Dir(target))
+	}
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create %s", target)
+	}
+
+	shasum := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, shasum), reader); err != nil {
+		file.Close()
+		return "", errors.Wrapf(err, "failed to write %s", target)
+	}
+	if err := file.Close(); err != nil {
+		return "", errors.Wrapf(err, "failed to write %s", target)
+	}
+
+	return fmt.Sprintf("%x", shasum.Sum(nil)), nil
+}
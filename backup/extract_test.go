@@ -0,0 +1,122 @@
+package backup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extract", func() {
+	var artifactPath, backupPath, destination string
+	var manager BackupDirectoryManager
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+
+	files := map[string]string{
+		"redis.conf":      "port 6379",
+		"dump/dump.rdb":   "rdb contents",
+		"dump/other.rdb":  "other contents",
+		"./logs/boot.log": "booted",
+	}
+
+	writeBackup := func() {
+		backup, err := manager.Create(artifactPath, "redis_20151021T010203Z", logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backup.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+
+		writeTarArtifact(backup, newFakeArtifact("redis-server", "0", "redis"), files)
+		writeTarArtifact(backup, newFakeArtifact("", "", "shared"), files)
+
+		Expect(backup.MarkComplete()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		artifactPath, err = ioutil.TempDir("", "extract-artifacts")
+		Expect(err).NotTo(HaveOccurred())
+		backupPath = filepath.Join(artifactPath, "redis_20151021T010203Z")
+		destination = filepath.Join(artifactPath, "extracted")
+		manager = BackupDirectoryManager{}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(artifactPath)).To(Succeed())
+	})
+
+	It("extracts every file of an instance artifact", func() {
+		writeBackup()
+
+		extracted, err := manager.Extract(backupPath, "redis-server", "0", "redis", "", destination, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(extracted).To(Equal([]string{"dump/dump.rdb", "dump/other.rdb", "logs/boot.log", "redis.conf"}))
+		Expect(ioutil.ReadFile(filepath.Join(destination, "dump", "dump.rdb"))).To(Equal([]byte("rdb contents")))
+		Expect(ioutil.ReadFile(filepath.Join(destination, "logs", "boot.log"))).To(Equal([]byte("booted")))
+	})
+
+	It("only extracts the files matching the pattern", func() {
+		writeBackup()
+
+		extracted, err := manager.Extract(backupPath, "redis-server", "0", "redis", "*.rdb", destination, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(extracted).To(Equal([]string{"dump/dump.rdb", "dump/other.rdb"}))
+
+		extracted, err = manager.Extract(backupPath, "redis-server", "0", "redis", "dump/d*", destination, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(extracted).To(Equal([]string{"dump/dump.rdb"}))
+
+		Expect(filepath.Join(destination, "redis.conf")).NotTo(BeAnExistingFile())
+	})
+
+	It("extracts files from custom artifacts", func() {
+		writeBackup()
+
+		extracted, err := manager.Extract(backupPath, "", "", "shared", "redis.conf", destination, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(extracted).To(Equal([]string{"redis.conf"}))
+	})
+
+	It("extracts files from compressed and encrypted artifacts", func() {
+		manager = BackupDirectoryManager{Compression: "gzip", EncryptionKey: NewEncryptionKeyFromPassphrase("secret")}
+		writeBackup()
+
+		extracted, err := manager.Extract(backupPath, "redis-server", "0", "redis", "redis.conf", destination, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(extracted).To(Equal([]string{"redis.conf"}))
+		Expect(ioutil.ReadFile(filepath.Join(destination, "redis.conf"))).To(Equal([]byte("port 6379")))
+
+		_, err = BackupDirectoryManager{}.Extract(backupPath, "redis-server", "0", "redis", "redis.conf", destination, logger)
+		Expect(err).To(MatchError(ContainSubstring("an encryption key must be provided")))
+	})
+
+	It("removes files that do not match the checksums in the metadata", func() {
+		writeBackup()
+		Expect(ioutil.WriteFile(filepath.Join(backupPath, "redis-server-0-redis.tar"), createTarWithContents(map[string]string{
+			"redis.conf":    "port 6380",
+			"dump/dump.rdb": "rdb contents",
+		}), 0644)).To(Succeed())
+
+		extracted, err := manager.Extract(backupPath, "redis-server", "0", "redis", "", destination, logger)
+		Expect(err).To(MatchError(ContainSubstring("do not match the checksums in the metadata and were removed: redis.conf")))
+		Expect(extracted).To(Equal([]string{"dump/dump.rdb"}))
+		Expect(filepath.Join(destination, "redis.conf")).NotTo(BeAnExistingFile())
+	})
+
+	It("fails when the backup does not contain the artifact", func() {
+		writeBackup()
+
+		_, err := manager.Extract(backupPath, "redis-server", "1", "redis", "", destination, logger)
+		Expect(err).To(MatchError(ContainSubstring("does not contain redis-server-1-redis.tar")))
+	})
+
+	It("fails when no files match the pattern", func() {
+		writeBackup()
+
+		_, err := manager.Extract(backupPath, "redis-server", "0", "redis", "*.sql", destination, logger)
+		Expect(err).To(MatchError(ContainSubstring(`no files in redis-server-0-redis.tar match "*.sql"`)))
+	})
+})
@@ -0,0 +1,205 @@
+package backup
+
+import (
+	"archive/tar"
+	"io"
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// An incremental backup only stores the files of each artifact that changed
+// since the backup it was taken from, but records the checksums of every file.
+// Reading an artifact fills in the unchanged files from the chain of previous
+// backups, so that the rest of bbr only ever sees complete artifacts.
+
+// checkPreviousBackup makes sure a backup can be incremented from, and returns
+// its absolute path for the metadata.
+func checkPreviousBackup(previousPath string, encryptionKey *EncryptionKey) (string, error) {
+	absolutePath, err := filepath.Abs(previousPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve the path of the previous backup %s", previousPath)
+	}
+
+	metadata, err := readMetadata(filepath.Join(absolutePath, "metadata"))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read the previous backup %s", previousPath)
+	}
+
+	if err := metadata.checkComplete(); err != nil {
+		return "", errors.Wrapf(err, "cannot take an incremental backup from %s", previousPath)
+	}
+
+	if _, err := metadata.resolveEncryptionKey(encryptionKey); err != nil {
+		return "", errors.Wrapf(err, "cannot take an incremental backup from %s", previousPath)
+	}
+
+	return absolutePath, nil
+}
+
+// FetchPreviousChecksum returns the checksum of the artifact in the backup
+// this one is incremented from, or nil if this is a full backup or the
+// artifact is new.
+func (backupDirectory *BackupDirectory) FetchPreviousChecksum(artifactIdentifier orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return nil, backupDirectory.logAndReturn(err, "Error reading metadata from %s", backupDirectory.metadataFilename())
+	}
+
+	if metadata.IncrementalFrom == "" {
+		return nil, nil
+	}
+
+	previousMetadata, err := readMetadata(filepath.Join(metadata.IncrementalFrom, "metadata"))
+	if err != nil {
+		return nil, backupDirectory.logAndReturn(err, "Error reading metadata of previous backup %s", metadata.IncrementalFrom)
+	}
+
+	if artifact, found := previousMetadata.findArtifactMetadata(artifactIdentifier); found {
+		return artifact.Checksum, nil
+	}
+	return nil, nil
+}
+
+func (backupDirectory *BackupDirectory) ReadArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.ReadCloser, error) {
+	stored, err := backupDirectory.readStoredArtifact(artifactIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return stored, nil
+	}
+
+	artifact, found := metadata.findArtifactMetadata(artifactIdentifier)
+	if !found {
+		return stored, nil
+	}
+
+	previousBackup, err := backupDirectory.previousBackupWith(artifactIdentifier)
+	if err != nil {
+		stored.Close()
+		return nil, err
+	}
+	if previousBackup == nil {
+		return stored, nil
+	}
+
+	previous, err := previousBackup.ReadArtifact(artifactIdentifier)
+	if err != nil {
+		stored.Close()
+		return nil, backupDirectory.logAndReturn(err, "Error reading previous backup %s", previousBackup.baseDirName)
+	}
+
+	backupDirectory.Debug("bbr", "Filling in unchanged files of %s from %s", logName(artifactIdentifier), previousBackup.baseDirName)
+	return mergeIncrementalArtifact(stored, previous, artifact.Checksum), nil
+}
+
+// previousArtifactByteSize is the size of the artifact in the chain of
+// previous backups. Added to the size of the changed files it is an upper
+// bound for the size of the complete artifact.
+func (backupDirectory *BackupDirectory) previousArtifactByteSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int, error) {
+	previousBackup, err := backupDirectory.previousBackupWith(artifactIdentifier)
+	if err != nil || previousBackup == nil {
+		return 0, err
+	}
+
+	return previousBackup.GetArtifactByteSize(artifactIdentifier)
+}
+
+// previousBackupWith opens the backup this one is incremented from, if it
+// contains the artifact.
+func (backupDirectory *BackupDirectory) previousBackupWith(artifactIdentifier orchestrator.ArtifactIdentifier) (*BackupDirectory, error) {
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil || metadata.IncrementalFrom == "" {
+		return nil, nil
+	}
+
+	previousBackup := &BackupDirectory{
+		baseDirName:   metadata.IncrementalFrom,
+		Logger:        backupDirectory.Logger,
+		encryptionKey: backupDirectory.encryptionKey,
+	}
+
+	drained, err := previousBackup.ArtifactDrained(artifactIdentifier)
+	if err != nil {
+		return nil, backupDirectory.logAndReturn(err, "Error reading previous backup %s", metadata.IncrementalFrom)
+	}
+	if !drained {
+		return nil, nil
+	}
+
+	return previousBackup, nil
+}
+
+func (backupDirectory *BackupDirectory) isIncremental() bool {
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	return err == nil && metadata.IncrementalFrom != ""
+}
+
+func mergeIncrementalArtifact(changed, previous io.ReadCloser, checksum map[string]string) io.ReadCloser {
+	reader, writer := io.Pipe()
+
+	go func() {
+		err := writeIncrementalArtifact(writer, changed, previous, checksum)
+		changed.Close()
+		previous.Close()
+		writer.CloseWithError(err)
+	}()
+
+	return reader
+}
+
+// writeIncrementalArtifact writes every entry of the changed files, followed
+// by the directories and the files that are still part of the artifact from
+// the previous backup.
+func writeIncrementalArtifact(writer io.Writer, changed, previous io.Reader, checksum map[string]string) error {
+	tarWriter := tar.NewWriter(writer)
+	written := map[string]bool{}
+
+	err := copyTarEntries(tarWriter, tar.NewReader(changed), func(header *tar.Header) bool {
+		return true
+	}, written)
+	if err != nil {
+		return errors.Wrap(err, "failed to read incremental artifact")
+	}
+
+	err = copyTarEntries(tarWriter, tar.NewReader(previous), func(header *tar.Header) bool {
+		if header.Typeflag == tar.TypeDir {
+			return true
+		}
+		_, stillPresent := checksum[header.Name]
+		return stillPresent
+	}, written)
+	if err != nil {
+		return errors.Wrap(err, "failed to read previous artifact")
+	}
+
+	return tarWriter.Close()
+}
+
+func copyTarEntries(tarWriter *tar.Writer, tarReader *tar.Reader, include func(*tar.Header) bool, written map[string]bool) error {
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if written[header.Name] || !include(header) {
+			continue
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return err
+		}
+		written[header.Name] = true
+	}
+}
@@ -0,0 +1,177 @@
+package backup_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Incremental backups", func() {
+	var artifactPath string
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var fakeBackupArtifact *fakes.FakeBackupArtifact
+
+	checksumOf := func(contents string) string {
+		return fmt.Sprintf("%x", sha256.Sum256([]byte(contents)))
+	}
+
+	writeArtifact := func(backup orchestrator.Backup, files map[string]string, checksum orchestrator.BackupChecksum) {
+		writer, err := backup.CreateArtifact(fakeBackupArtifact)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = writer.Write(createTarWithContents(files))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+		Expect(backup.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		artifactPath, err = ioutil.TempDir("", "incremental-artifacts")
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeBackupArtifact = new(fakes.FakeBackupArtifact)
+		fakeBackupArtifact.InstanceNameReturns("redis-server")
+		fakeBackupArtifact.InstanceIndexReturns("0")
+		fakeBackupArtifact.NameReturns("redis")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(artifactPath)).To(Succeed())
+	})
+
+	Context("when the previous backup is complete", func() {
+		var previousPath string
+		var backup orchestrator.Backup
+
+		BeforeEach(func() {
+			previous, err := BackupDirectoryManager{}.Create(artifactPath, "full", logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(previous.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+			writeArtifact(previous, map[string]string{
+				"unchanged": "the same contents",
+				"changed":   "old contents",
+				"deleted":   "a file that will be deleted",
+			}, orchestrator.BackupChecksum{
+				"unchanged": checksumOf("the same contents"),
+				"changed":   checksumOf("old contents"),
+				"deleted":   checksumOf("a file that will be deleted"),
+			})
+			Expect(previous.MarkComplete()).To(Succeed())
+			previousPath = filepath.Join(artifactPath, "full")
+
+			backup, err = BackupDirectoryManager{IncrementalFrom: previousPath}.Create(artifactPath, "incremental", logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+		})
+
+		It("records the previous backup in the metadata", func() {
+			metadata, err := ioutil.ReadFile(filepath.Join(artifactPath, "incremental", "metadata"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(metadata)).To(ContainSubstring("incremental_from: " + previousPath))
+		})
+
+		It("fetches the checksum of the previous backup of the artifact", func() {
+			Expect(backup.FetchPreviousChecksum(fakeBackupArtifact)).To(Equal(orchestrator.BackupChecksum{
+				"unchanged": checksumOf("the same contents"),
+				"changed":   checksumOf("old contents"),
+				"deleted":   checksumOf("a file that will be deleted"),
+			}))
+		})
+
+		It("does not return a previous checksum for new artifacts", func() {
+			newArtifact := new(fakes.FakeBackupArtifact)
+			newArtifact.InstanceNameReturns("redis-server")
+			newArtifact.InstanceIndexReturns("1")
+			newArtifact.NameReturns("redis")
+
+			Expect(backup.FetchPreviousChecksum(newArtifact)).To(BeNil())
+		})
+
+		Context("and only the changed files are copied", func() {
+			BeforeEach(func() {
+				writeArtifact(backup, map[string]string{
+					"changed": "new contents",
+				}, orchestrator.BackupChecksum{
+					"unchanged": checksumOf("the same contents"),
+					"changed":   checksumOf("new contents"),
+				})
+				Expect(backup.MarkComplete()).To(Succeed())
+			})
+
+			It("reads the complete artifact back", func() {
+				reader, err := backup.ReadArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+
+				Expect(filesInTar(reader)).To(Equal(map[string]string{
+					"unchanged": "the same contents",
+					"changed":   "new contents",
+				}))
+			})
+
+			It("is valid", func() {
+				Expect(backup.Valid()).To(BeTrue())
+			})
+
+			It("can be the previous backup of another incremental backup", func() {
+				next, err := BackupDirectoryManager{IncrementalFrom: filepath.Join(artifactPath, "incremental")}.Create(artifactPath, "next", logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(next.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+				writeArtifact(next, map[string]string{
+					"added": "a new file",
+				}, orchestrator.BackupChecksum{
+					"unchanged": checksumOf("the same contents"),
+					"changed":   checksumOf("new contents"),
+					"added":     checksumOf("a new file"),
+				})
+
+				reader, err := next.ReadArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+
+				Expect(filesInTar(reader)).To(Equal(map[string]string{
+					"unchanged": "the same contents",
+					"changed":   "new contents",
+					"added":     "a new file",
+				}))
+			})
+
+			Context("when the previous backup has been corrupted", func() {
+				BeforeEach(func() {
+					Expect(ioutil.WriteFile(filepath.Join(previousPath, "redis-server-0-redis.tar"), createTarWithContents(map[string]string{
+						"unchanged": "something else entirely",
+					}), 0600)).To(Succeed())
+				})
+
+				It("is not valid", func() {
+					Expect(backup.Valid()).To(BeFalse())
+				})
+			})
+		})
+	})
+
+	It("cannot be taken from an incomplete backup", func() {
+		previous, err := BackupDirectoryManager{}.Create(artifactPath, "incomplete", logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(previous.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+		_, err = BackupDirectoryManager{IncrementalFrom: filepath.Join(artifactPath, "incomplete")}.Create(artifactPath, "incremental", logger)
+		Expect(err).To(MatchError(ContainSubstring("cannot take an incremental backup from")))
+		Expect(filepath.Join(artifactPath, "incremental")).NotTo(BeADirectory())
+	})
+
+	It("cannot be taken from a backup that does not exist", func() {
+		_, err := BackupDirectoryManager{IncrementalFrom: filepath.Join(artifactPath, "not-there")}.Create(artifactPath, "incremental", logger)
+		Expect(err).To(MatchError(ContainSubstring("failed to read the previous backup")))
+	})
+})
@@ -1,55 +1,361 @@
 package backup
 
 import (
+	"encoding/hex"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
 
+// currentMetadataVersion is the version of the metadata schema that bbr writes.
+// Metadata files written before the schema was versioned have no version and
+// are treated as version 1.
+const currentMetadataVersion = 2
+
+// A backup that failed while its artifacts were being drained is recorded as
+// drain_failed rather than failed, as only such backups can be resumed.
+const (
+	statusInProgress  = "in_progress"
+	statusComplete    = "complete"
+	statusFailed      = "failed"
+	statusDrainFailed = "drain_failed"
+)
+
 type backupActivityMetadata struct {
 	StartTime  string `yaml:"start_time"`
 	FinishTime string `yaml:"finish_time,omitempty"`
+	Status     string `yaml:"status,omitempty"`
+}
+
+type directorMetadata struct {
+	UUID    string `yaml:"uuid"`
+	Name    string `yaml:"name,omitempty"`
+	Version string `yaml:"version,omitempty"`
 }
 
 type instanceMetadata struct {
 	Name      string             `yaml:"name"`
 	Index     string             `yaml:"index"`
+	ID        string             `yaml:"id,omitempty"`
+	Bootstrap bool               `yaml:"bootstrap,omitempty"`
+	Jobs      []jobMetadata      `yaml:"jobs,omitempty"`
 	Artifacts []artifactMetadata `yaml:"artifacts"`
 }
 
+type jobMetadata struct {
+	Name    string `yaml:"name"`
+	Release string `yaml:"release,omitempty"`
+}
+
 type artifactMetadata struct {
-	Name     string            `yaml:"name"`
-	Checksum map[string]string `yaml:"checksums"`
+	Name          string            `yaml:"name"`
+	Compression   string            `yaml:"compression,omitempty"`
+	Size          int64             `yaml:"size,omitempty"`
+	PlaintextSize int64             `yaml:"plaintext_size,omitempty"`
+	FileCount     int               `yaml:"file_count"`
+	Checksum      map[string]string `yaml:"checksums"`
+}
+
+type encryptionMetadata struct {
+	Cipher         string `yaml:"cipher"`
+	KeyFingerprint string `yaml:"key_fingerprint"`
+	Salt           string `yaml:"salt,omitempty"`
 }
 
 type metadata struct {
+	Version                   int                    `yaml:"version"`
+	BBRVersion                string                 `yaml:"bbr_version,omitempty"`
+	Deployment                string                 `yaml:"deployment,omitempty"`
+	Director                  *directorMetadata      `yaml:"director,omitempty"`
 	MetadataForEachInstance   []*instanceMetadata    `yaml:"instances,omitempty"`
 	MetadataForEachArtifact   []artifactMetadata     `yaml:"custom_artifacts,omitempty"`
 	MetadataForBackupActivity backupActivityMetadata `yaml:"backup_activity"`
+	Encryption                *encryptionMetadata    `yaml:"encryption,omitempty"`
+	ChunkStore                string                 `yaml:"chunk_store,omitempty"`
+	IncrementalFrom           string                 `yaml:"incremental_from,omitempty"`
 }
 
 func readMetadata(filename string) (metadata, error) {
-	metadata := metadata{}
-
 	contents, err := ioutil.ReadFile(filename)
 	if err != nil {
-		return metadata, errors.Wrap(err, "failed to read metadata")
+		return metadata{}, errors.Wrap(err, "failed to read metadata")
 	}
 
+	return parseMetadata(contents)
+}
+
+func parseMetadata(contents []byte) (metadata, error) {
+	metadata := metadata{}
+
 	if err := yaml.Unmarshal(contents, &metadata); err != nil {
 		return metadata, errors.Wrap(err, "failed to unmarshal metadata")
 	}
+
+	if err := metadata.migrate(); err != nil {
+		return metadata, err
+	}
 	return metadata, nil
 }
 
+// migrate brings metadata written by an older version of bbr up to the current
+// schema, so that the rest of bbr only has to deal with one version. The
+// migrated metadata is written back the next time the backup is saved.
+func (data *metadata) migrate() error {
+	if data.Version > currentMetadataVersion {
+		return errors.Errorf("metadata version %d is not supported by this version of bbr, which supports up to version %d", data.Version, currentMetadataVersion)
+	}
+
+	if data.Version < 2 {
+		for i := range data.MetadataForEachArtifact {
+			data.MetadataForEachArtifact[i].FileCount = len(data.MetadataForEachArtifact[i].Checksum)
+		}
+		for _, instance := range data.MetadataForEachInstance {
+			for i := range instance.Artifacts {
+				instance.Artifacts[i].FileCount = len(instance.Artifacts[i].Checksum)
+			}
+		}
+	}
+
+	data.Version = currentMetadataVersion
+	return nil
+}
+
 func (data *metadata) save(filename string) error {
+	contents, err := data.marshal()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(filename, contents)
+}
+
+// writeFileAtomically writes the contents to a temporary file next to filename
+// and renames it into place, so that an interrupted write never leaves a
+// truncated file behind.
+func writeFileAtomically(filename string, contents []byte) error {
+	name := filepath.Base(filename)
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(filename), "."+name+"-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary %s file", name)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(contents); err != nil {
+		tempFile.Close()
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	if err := tempFile.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	if err := os.Chmod(tempFile.Name(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+
+	return errors.Wrapf(os.Rename(tempFile.Name(), filename), "failed to write %s", name)
+}
+
+func (data *metadata) marshal() ([]byte, error) {
 	contents, err := yaml.Marshal(data)
 	if err != nil {
-		return errors.Wrap(err, "failed to marshal metadata")
+		return nil, errors.Wrap(err, "failed to marshal metadata")
+	}
+	return contents, nil
+}
+
+func (data *metadata) markFinished(finishTime string) {
+	data.MetadataForBackupActivity.FinishTime = finishTime
+	if data.MetadataForBackupActivity.Status == statusInProgress {
+		data.MetadataForBackupActivity.Status = statusFailed
 	}
+}
+
+// markResumed reopens a backup whose drain was interrupted so that the missing
+// artifacts can be added to it. Backups that failed in any other way may have
+// incomplete artifacts on the instances, and cannot be resumed. Backups without
+// a status predate status tracking and are treated as complete.
+func (data *metadata) markResumed() error {
+	status := data.MetadataForBackupActivity.Status
+	if status == "" || status == statusComplete {
+		return errors.New("backup is already complete, there is nothing to resume")
+	}
+	if status != statusDrainFailed {
+		return errors.Errorf("backup did not fail while copying its artifacts (status: %s), it cannot be resumed", status)
+	}
+
+	data.MetadataForBackupActivity.Status = statusInProgress
+	data.MetadataForBackupActivity.FinishTime = ""
+	return nil
+}
 
-	return ioutil.WriteFile(filename, contents, 0666)
+// checkComplete fails for backups that were interrupted or failed. Backups
+// written before the status was recorded are assumed to be complete.
+func (data *metadata) checkComplete() error {
+	status := data.MetadataForBackupActivity.Status
+	if status != "" && status != statusComplete {
+		return errors.Errorf("backup is not complete (status: %s), it cannot be used for a restore", status)
+	}
+	return nil
+}
+
+// addArtifact records an artifact and its checksum. The size is the number of
+// bytes the artifact takes up in this backup, after compression and encryption,
+// and the plaintext size that of its tar stream, if it differs.
+func (data *metadata) addArtifact(artifactIdentifier orchestrator.ArtifactIdentifier, compression string, shasum orchestrator.BackupChecksum, size, plaintextSize int64) {
+	if compression == NoCompression {
+		compression = ""
+	}
+
+	artifact := artifactMetadata{
+		Name:          artifactIdentifier.Name(),
+		Compression:   compression,
+		Size:          size,
+		PlaintextSize: plaintextSize,
+		FileCount:     len(shasum),
+		Checksum:      shasum,
+	}
+
+	if artifactIdentifier.HasCustomName() {
+		data.MetadataForEachArtifact = append(data.MetadataForEachArtifact, artifact)
+	} else {
+		instanceMetadata := data.findOrCreateInstanceMetadata(artifactIdentifier.InstanceName(), artifactIdentifier.InstanceIndex())
+		if instanceMetadata.ID == "" {
+			instanceMetadata.ID = artifactIdentifier.InstanceID()
+		}
+		instanceMetadata.Artifacts = append(instanceMetadata.Artifacts, artifact)
+	}
+}
+
+// addDeploymentMetadata records the deployment the backup was taken of, along
+// with the instances that are backed up.
+func (data *metadata) addDeploymentMetadata(deploymentMetadata orchestrator.DeploymentMetadata) {
+	data.Deployment = deploymentMetadata.Name
+	data.BBRVersion = deploymentMetadata.BBRVersion
+
+	if deploymentMetadata.Director != (orchestrator.DirectorInfo{}) {
+		data.Director = &directorMetadata{
+			UUID:    deploymentMetadata.Director.UUID,
+			Name:    deploymentMetadata.Director.Name,
+			Version: deploymentMetadata.Director.Version,
+		}
+	}
+
+	for _, instance := range deploymentMetadata.Instances {
+		instanceMetadata := data.findOrCreateInstanceMetadata(instance.Name, instance.Index)
+		instanceMetadata.ID = instance.ID
+		instanceMetadata.Bootstrap = instance.Bootstrap
+		instanceMetadata.Jobs = nil
+		for _, job := range instance.Jobs {
+			instanceMetadata.Jobs = append(instanceMetadata.Jobs, jobMetadata{Name: job.Name, Release: job.Release})
+		}
+	}
+}
+
+// instances describes every instance recorded in the backup, whether or not it
+// has artifacts.
+func (data *metadata) instances() []orchestrator.InstanceMetadata {
+	var instances []orchestrator.InstanceMetadata
+	for _, backupInstance := range data.MetadataForEachInstance {
+		instance := orchestrator.InstanceMetadata{
+			Name:      backupInstance.Name,
+			Index:     backupInstance.Index,
+			ID:        backupInstance.ID,
+			Bootstrap: backupInstance.Bootstrap,
+		}
+		for _, job := range backupInstance.Jobs {
+			instance.Jobs = append(instance.Jobs, orchestrator.JobSpecifier{Name: job.Name, Release: job.Release})
+		}
+		instances = append(instances, instance)
+	}
+	return instances
+}
+
+// instancesNotIn returns the name and index of each instance with artifacts in
+// the backup that is not one of the instances. Instances without artifacts are
+// only recorded to describe the deployment, and need not be restored to.
+func (data *metadata) instancesNotIn(instances []orchestrator.Instance) []string {
+	var missing []string
+	for _, backupInstance := range data.MetadataForEachInstance {
+		if len(backupInstance.Artifacts) == 0 {
+			continue
+		}
+
+		present := false
+		for _, inst := range instances {
+			if inst.Index() == backupInstance.Index && inst.Name() == backupInstance.Name {
+				present = true
+				break
+			}
+		}
+		if !present {
+			missing = append(missing, backupInstance.Name+"/"+backupInstance.Index)
+		}
+	}
+	return missing
+}
+
+func (data *metadata) setUpEncryption(encryptionKey *EncryptionKey) ([]byte, error) {
+	salt, err := encryptionKey.newSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := encryptionKey.derive(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Encryption = &encryptionMetadata{
+		Cipher:         AES256GCMCipher,
+		KeyFingerprint: keyFingerprint(key),
+		Salt:           hex.EncodeToString(salt),
+	}
+	return key, nil
+}
+
+// resolveEncryptionKey returns the key the artifacts are encrypted with, or nil
+// when the backup is not encrypted. It fails when the backup is encrypted and
+// the supplied key does not match the fingerprint recorded in the metadata, and
+// when a key is supplied for a backup that is not encrypted, so that artifacts
+// are never written unencrypted when encryption was asked for.
+func (data *metadata) resolveEncryptionKey(encryptionKey *EncryptionKey) ([]byte, error) {
+	if data.Encryption == nil {
+		if encryptionKey != nil {
+			return nil, errors.New("an encryption key was provided, but the backup is not encrypted")
+		}
+		return nil, nil
+	}
+
+	if data.Encryption.Cipher != AES256GCMCipher {
+		return nil, errors.Errorf("backup is encrypted with unsupported cipher %q", data.Encryption.Cipher)
+	}
+
+	if encryptionKey == nil {
+		return nil, errors.New("backup is encrypted, an encryption key must be provided")
+	}
+
+	salt, err := hex.DecodeString(data.Encryption.Salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode encryption salt")
+	}
+
+	key, err := encryptionKey.derive(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyFingerprint(key) != data.Encryption.KeyFingerprint {
+		return nil, errors.Errorf("the supplied encryption key does not match the key the backup was encrypted with (fingerprint %s)", data.Encryption.KeyFingerprint)
+	}
+	return key, nil
 }
 
 func (data *metadata) findOrCreateInstanceMetadata(name, index string) *instanceMetadata {
@@ -65,3 +371,25 @@ func (data *metadata) findOrCreateInstanceMetadata(name, index string) *instance
 	data.MetadataForEachInstance = append(data.MetadataForEachInstance, newInstanceMetadata)
 	return newInstanceMetadata
 }
+
+func (data *metadata) findArtifactMetadata(artifactIdentifier orchestrator.ArtifactIdentifier) (artifactMetadata, bool) {
+	if artifactIdentifier.HasCustomName() {
+		for _, customArtifactInMetadata := range data.MetadataForEachArtifact {
+			if customArtifactInMetadata.Name == artifactIdentifier.Name() {
+				return customArtifactInMetadata, true
+			}
+		}
+		return artifactMetadata{}, false
+	}
+
+	for _, instanceInMetadata := range data.MetadataForEachInstance {
+		if instanceInMetadata.Index == artifactIdentifier.InstanceIndex() && instanceInMetadata.Name == artifactIdentifier.InstanceName() {
+			for _, artifact := range instanceInMetadata.Artifacts {
+				if artifact.Name == artifactIdentifier.Name() {
+					return artifact, true
+				}
+			}
+		}
+	}
+	return artifactMetadata{}, false
+}
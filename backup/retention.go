@@ -0,0 +1,155 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// RetentionPolicy says which backups of a deployment to keep. The newest
+// complete backup in each of the last KeepDaily days, KeepWeekly weeks and
+// KeepMonthly months is kept, as well as the KeepLast newest complete backups.
+type RetentionPolicy struct {
+	KeepLast    int
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
+}
+
+func (policy RetentionPolicy) IsEmpty() bool {
+	return policy.KeepLast <= 0 && policy.KeepDaily <= 0 && policy.KeepWeekly <= 0 && policy.KeepMonthly <= 0
+}
+
+type PruneResult struct {
+	Kept    []StoredBackup
+	Removed []StoredBackup
+	Skipped []StoredBackup
+}
+
+// PruneBackups applies the retention policy to the backups of each deployment
+// under path and removes the ones it does not keep. The newest complete backup
+// of a deployment is always kept, as is every backup a kept incremental backup
+// depends on. Backups that have not finished are skipped, as they may still be
+// running or be resumed. With dryRun nothing is removed.
+func PruneBackups(path string, policy RetentionPolicy, dryRun bool, logger orchestrator.Logger) (PruneResult, error) {
+	if policy.IsEmpty() {
+		return PruneResult{}, errors.New("the retention policy must keep at least one backup")
+	}
+
+	backups, err := FindBackups(path, logger)
+	if err != nil {
+		return PruneResult{}, err
+	}
+
+	keep := map[string]bool{}
+	byDeployment := map[string][]StoredBackup{}
+	for _, backup := range backups {
+		byDeployment[backup.Deployment] = append(byDeployment[backup.Deployment], backup)
+	}
+	for _, deploymentBackups := range byDeployment {
+		for _, backup := range policy.selectBackups(deploymentBackups) {
+			keep[backup.Path] = true
+		}
+	}
+	for _, backup := range backups {
+		if !backup.Finished() {
+			keep[backup.Path] = true
+		}
+	}
+	keepIncrementalChains(backups, keep)
+
+	var result PruneResult
+	for _, backup := range backups {
+		switch {
+		case !backup.Finished():
+			logger.Info("bbr", "Skipping %s, it has not finished", backup.Path)
+			result.Skipped = append(result.Skipped, backup)
+		case keep[backup.Path]:
+			result.Kept = append(result.Kept, backup)
+		default:
+			if !dryRun {
+				logger.Info("bbr", "Removing %s", backup.Path)
+				if err := os.RemoveAll(backup.Path); err != nil {
+					return result, errors.Wrapf(err, "failed to remove %s", backup.Path)
+				}
+			}
+			result.Removed = append(result.Removed, backup)
+		}
+	}
+
+	return result, nil
+}
+
+// selectBackups returns the complete backups the policy keeps, given all the
+// backups of one deployment oldest first.
+func (policy RetentionPolicy) selectBackups(backups []StoredBackup) []StoredBackup {
+	var complete []StoredBackup
+	for i := len(backups) - 1; i >= 0; i-- {
+		if backups[i].Complete() {
+			complete = append(complete, backups[i])
+		}
+	}
+	if len(complete) == 0 {
+		return nil
+	}
+
+	selected := map[int]bool{0: true}
+	for i := 0; i < policy.KeepLast && i < len(complete); i++ {
+		selected[i] = true
+	}
+	selectNewestPerPeriod(complete, policy.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") }, selected)
+	selectNewestPerPeriod(complete, policy.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%02d", year, week)
+	}, selected)
+	selectNewestPerPeriod(complete, policy.KeepMonthly, func(t time.Time) string { return t.Format("2006-01") }, selected)
+
+	var kept []StoredBackup
+	for i, backup := range complete {
+		if selected[i] {
+			kept = append(kept, backup)
+		}
+	}
+	return kept
+}
+
+// selectNewestPerPeriod selects the newest backup in each of the count most
+// recent periods, given backups newest first.
+func selectNewestPerPeriod(backups []StoredBackup, count int, period func(time.Time) string, selected map[int]bool) {
+	seen := map[string]bool{}
+	for i, backup := range backups {
+		if len(seen) >= count {
+			return
+		}
+
+		key := period(backup.StartTime.UTC())
+		if !seen[key] {
+			seen[key] = true
+			selected[i] = true
+		}
+	}
+}
+
+// keepIncrementalChains keeps the backups that kept incremental backups are
+// incremented from, as they cannot be read without them.
+func keepIncrementalChains(backups []StoredBackup, keep map[string]bool) {
+	byPath := map[string]StoredBackup{}
+	for _, backup := range backups {
+		byPath[backup.Path] = backup
+	}
+
+	for _, backup := range backups {
+		if !keep[backup.Path] {
+			continue
+		}
+		for previous := backup.IncrementalFrom; previous != ""; previous = byPath[previous].IncrementalFrom {
+			if keep[previous] {
+				break
+			}
+			keep[previous] = true
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package backup_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PruneBackups", func() {
+	var artifactPath string
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var policy RetentionPolicy
+	var dryRun bool
+	var result PruneResult
+	var pruneError error
+
+	writeBackup := func(deployment string, startTime time.Time, extraMetadata string) string {
+		name := fmt.Sprintf("%s_%s", deployment, startTime.Format("20060102T150405Z"))
+		Expect(os.Mkdir(filepath.Join(artifactPath, name), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(artifactPath, name, "metadata"), []byte(fmt.Sprintf(`---
+backup_activity:
+  start_time: %s
+%s`, startTime.Format("2006/01/02 15:04:05 MST"), extraMetadata)), 0600)).To(Succeed())
+		return name
+	}
+
+	writeCompleteBackup := func(deployment string, startTime time.Time) string {
+		return writeBackup(deployment, startTime, fmt.Sprintf("  finish_time: %s\n  status: complete\n", startTime.Add(time.Minute).Format("2006/01/02 15:04:05 MST")))
+	}
+
+	remainingBackups := func() []string {
+		var names []string
+		entries, err := ioutil.ReadDir(artifactPath)
+		Expect(err).NotTo(HaveOccurred())
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	day := func(n int) time.Time {
+		return time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, n)
+	}
+
+	BeforeEach(func() {
+		var err error
+		artifactPath, err = ioutil.TempDir("", "prune-artifacts")
+		Expect(err).NotTo(HaveOccurred())
+		policy = RetentionPolicy{}
+		dryRun = false
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(artifactPath)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		result, pruneError = PruneBackups(artifactPath, policy, dryRun, logger)
+	})
+
+	Context("when keeping the last backups", func() {
+		BeforeEach(func() {
+			policy.KeepLast = 2
+			for i := 0; i < 4; i++ {
+				writeCompleteBackup("redis", day(i))
+			}
+			writeCompleteBackup("mysql", day(0))
+		})
+
+		It("removes the older backups of each deployment", func() {
+			Expect(pruneError).NotTo(HaveOccurred())
+			Expect(remainingBackups()).To(ConsistOf(
+				"mysql_20170301T120000Z",
+				"redis_20170303T120000Z",
+				"redis_20170304T120000Z",
+			))
+			Expect(result.Removed).To(HaveLen(2))
+			Expect(result.Kept).To(HaveLen(3))
+		})
+
+		Context("and it is a dry run", func() {
+			BeforeEach(func() {
+				dryRun = true
+			})
+
+			It("reports the backups it would remove without removing them", func() {
+				Expect(pruneError).NotTo(HaveOccurred())
+				Expect(remainingBackups()).To(HaveLen(5))
+				Expect(result.Removed).To(HaveLen(2))
+				Expect(result.Removed[0].Path).To(HaveSuffix("redis_20170301T120000Z"))
+				Expect(result.Removed[1].Path).To(HaveSuffix("redis_20170302T120000Z"))
+			})
+		})
+	})
+
+	Context("when keeping backups per period", func() {
+		BeforeEach(func() {
+			policy.KeepDaily = 2
+			policy.KeepMonthly = 2
+			writeCompleteBackup("redis", time.Date(2017, 1, 15, 12, 0, 0, 0, time.UTC))
+			writeCompleteBackup("redis", time.Date(2017, 2, 10, 12, 0, 0, 0, time.UTC))
+			writeCompleteBackup("redis", time.Date(2017, 2, 20, 12, 0, 0, 0, time.UTC))
+			writeCompleteBackup("redis", time.Date(2017, 3, 1, 6, 0, 0, 0, time.UTC))
+			writeCompleteBackup("redis", time.Date(2017, 3, 1, 18, 0, 0, 0, time.UTC))
+			writeCompleteBackup("redis", time.Date(2017, 3, 2, 12, 0, 0, 0, time.UTC))
+		})
+
+		It("keeps the newest backup of each period", func() {
+			Expect(pruneError).NotTo(HaveOccurred())
+			Expect(remainingBackups()).To(ConsistOf(
+				"redis_20170220T120000Z",
+				"redis_20170301T180000Z",
+				"redis_20170302T120000Z",
+			))
+		})
+
+		Context("and only weekly backups are kept", func() {
+			BeforeEach(func() {
+				policy = RetentionPolicy{KeepWeekly: 3}
+			})
+
+			It("keeps the newest backup of each week", func() {
+				Expect(pruneError).NotTo(HaveOccurred())
+				Expect(remainingBackups()).To(ConsistOf(
+					"redis_20170210T120000Z",
+					"redis_20170220T120000Z",
+					"redis_20170302T120000Z",
+				))
+			})
+		})
+	})
+
+	Context("when there are unfinished and failed backups", func() {
+		BeforeEach(func() {
+			policy.KeepLast = 1
+			writeCompleteBackup("redis", day(0))
+			writeBackup("redis", day(1), "  status: in_progress\n")
+			writeBackup("redis", day(2), fmt.Sprintf("  finish_time: %s\n  status: failed\n", day(2).Format("2006/01/02 15:04:05 MST")))
+		})
+
+		It("skips the unfinished backups and keeps the newest complete backup", func() {
+			Expect(pruneError).NotTo(HaveOccurred())
+			Expect(remainingBackups()).To(ConsistOf(
+				"redis_20170301T120000Z",
+				"redis_20170302T120000Z",
+			))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].Path).To(HaveSuffix("redis_20170302T120000Z"))
+		})
+	})
+
+	Context("when a kept backup is incremental", func() {
+		BeforeEach(func() {
+			policy.KeepLast = 1
+			full := writeCompleteBackup("redis", day(0))
+			writeCompleteBackup("redis", day(1))
+			writeBackup("redis", day(2), fmt.Sprintf("  finish_time: %s\n  status: complete\nincremental_from: %s\n",
+				day(2).Format("2006/01/02 15:04:05 MST"), filepath.Join(artifactPath, full)))
+		})
+
+		It("keeps the backups it was incremented from", func() {
+			Expect(pruneError).NotTo(HaveOccurred())
+			Expect(remainingBackups()).To(ConsistOf(
+				"redis_20170301T120000Z",
+				"redis_20170303T120000Z",
+			))
+		})
+	})
+
+	Context("when the directory contains other files", func() {
+		BeforeEach(func() {
+			policy.KeepLast = 1
+			writeCompleteBackup("redis", day(0))
+			Expect(os.Mkdir(filepath.Join(artifactPath, "not-a-backup"), 0700)).To(Succeed())
+			Expect(os.Mkdir(filepath.Join(artifactPath, "redis_20170101T000000Z"), 0700)).To(Succeed())
+		})
+
+		It("leaves them alone", func() {
+			Expect(pruneError).NotTo(HaveOccurred())
+			Expect(remainingBackups()).To(ConsistOf(
+				"not-a-backup",
+				"redis_20170101T000000Z",
+				"redis_20170301T120000Z",
+			))
+		})
+	})
+
+	Context("when the policy keeps nothing", func() {
+		It("fails", func() {
+			Expect(pruneError).To(MatchError(ContainSubstring("must keep at least one backup")))
+		})
+	})
+})
@@ -0,0 +1,565 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+type S3Backup struct {
+	orchestrator.Logger
+	client         s3iface.S3API
+	bucket         string
+	prefix         string
+	compression    string
+	encryptionKey  *EncryptionKey
+	derivedKey     []byte
+	signingKey     ed25519.PrivateKey
+	trustedKey     ed25519.PublicKey
+	plaintextSizes plaintextSizes
+	sync.Mutex
+}
+
+func (s3Backup *S3Backup) GetArtifactSize(artifactIdentifier orchestrator.ArtifactIdentifier) (string, error) {
+	size, err := s3Backup.GetArtifactByteSize(artifactIdentifier)
+	if err != nil {
+		return "", err
+	}
+	return humanReadableSize(int64(size)), nil
+}
+
+// GetArtifactByteSize is the size of the tar stream of the artifact. For
+// compressed or encrypted artifacts it is taken from the metadata, as the
+// object in the bucket is of a different size.
+func (s3Backup *S3Backup) GetArtifactByteSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int, error) {
+	if size, found := s3Backup.recordedPlaintextSize(artifactIdentifier); found {
+		return int(size), nil
+	}
+	return s3Backup.storedArtifactByteSize(artifactIdentifier)
+}
+
+func (s3Backup *S3Backup) recordedPlaintextSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int64, bool) {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return 0, false
+	}
+
+	artifact, found := metadata.findArtifactMetadata(artifactIdentifier)
+	return artifact.PlaintextSize, found && artifact.PlaintextSize > 0
+}
+
+func (s3Backup *S3Backup) storedArtifactByteSize(artifactIdentifier orchestrator.ArtifactIdentifier) (int, error) {
+	key := s3Backup.key(fileName(artifactIdentifier))
+
+	output, err := s3Backup.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s3Backup.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return 0, s3Backup.logAndReturn(err, "failed to determine size of object %s", s3Backup.location(key))
+	}
+	return int(aws.Int64Value(output.ContentLength)), nil
+}
+
+func (s3Backup *S3Backup) CreateArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.WriteCloser, error) {
+	key := s3Backup.key(fileName(artifactIdentifier))
+	s3Backup.Debug("bbr", "Trying to create object %s", s3Backup.location(key))
+
+	encryptionKey, err := s3Backup.artifactKey()
+	if err != nil {
+		return nil, s3Backup.logAndReturn(err, "Error creating object %s", s3Backup.location(key))
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	upload := &s3UploadWriter{PipeWriter: pipeWriter, done: make(chan error, 1)}
+
+	uploader := s3manager.NewUploaderWithClient(s3Backup.client)
+	go func() {
+		_, err := uploader.Upload(&s3manager.UploadInput{
+			Bucket: aws.String(s3Backup.bucket),
+			Key:    aws.String(key),
+			Body:   pipeReader,
+		})
+		pipeReader.CloseWithError(err)
+		upload.done <- err
+	}()
+
+	writer, err := encodeArtifact(upload, s3Backup.compression, encryptionKey)
+	if err != nil {
+		upload.CloseWithError(err)
+		return nil, s3Backup.logAndReturn(err, "Error creating object %s", s3Backup.location(key))
+	}
+
+	return &s3ArtifactWriter{
+		WriteCloser: s3Backup.plaintextSizes.count(artifactIdentifier, upload, writer),
+		upload:      upload,
+	}, nil
+}
+
+func (s3Backup *S3Backup) ReadArtifact(artifactIdentifier orchestrator.ArtifactIdentifier) (io.ReadCloser, error) {
+	key := s3Backup.key(fileName(artifactIdentifier))
+	s3Backup.Debug("bbr", "Trying to open %s", s3Backup.location(key))
+
+	encryptionKey, err := s3Backup.artifactKey()
+	if err != nil {
+		return nil, s3Backup.logAndReturn(err, "Error reading artifact object %s", s3Backup.location(key))
+	}
+
+	output, err := s3Backup.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s3Backup.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, s3Backup.logAndReturn(err, "Error reading artifact object %s", s3Backup.location(key))
+	}
+
+	reader, err := decodeArtifact(output.Body, s3Backup.artifactCompression(artifactIdentifier), encryptionKey)
+	if err != nil {
+		output.Body.Close()
+		return nil, s3Backup.logAndReturn(err, "Error reading artifact object %s", s3Backup.location(key))
+	}
+
+	return reader, nil
+}
+
+func (s3Backup *S3Backup) AddChecksum(artifactIdentifier orchestrator.ArtifactIdentifier, shasum orchestrator.BackupChecksum) error {
+	size, err := s3Backup.storedArtifactByteSize(artifactIdentifier)
+	if err != nil {
+		s3Backup.Warn("bbr", "Could not determine the size of %s, it will not be recorded in the metadata: %s", logName(artifactIdentifier), err)
+	}
+
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata")
+	}
+
+	metadata.addArtifact(artifactIdentifier, s3Backup.compression, shasum, int64(size), s3Backup.plaintextSizes.take(artifactIdentifier))
+
+	return s3Backup.saveMetadata(metadata)
+}
+
+func (s3Backup *S3Backup) CreateMetadataFileWithStartTime(startTime time.Time) error {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	exists, err := s3Backup.objectExists(s3Backup.key("metadata"))
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to check for metadata")
+	}
+	if exists {
+		return errors.New("metadata file already exists")
+	}
+
+	metadata := metadata{
+		Version: currentMetadataVersion,
+		MetadataForBackupActivity: backupActivityMetadata{
+			StartTime: startTime.Format(timestampFormat),
+			Status:    statusInProgress,
+		},
+	}
+
+	if s3Backup.encryptionKey != nil {
+		key, err := metadata.setUpEncryption(s3Backup.encryptionKey)
+		if err != nil {
+			return s3Backup.logAndReturn(err, "unable to set up encryption")
+		}
+		s3Backup.derivedKey = key
+	}
+
+	return s3Backup.saveMetadata(metadata)
+}
+
+func (s3Backup *S3Backup) AddDeploymentMetadata(deploymentMetadata orchestrator.DeploymentMetadata) error {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata")
+	}
+
+	metadata.addDeploymentMetadata(deploymentMetadata)
+	return s3Backup.saveMetadata(metadata)
+}
+
+func (s3Backup *S3Backup) AddFinishTime(finishTime time.Time) error {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata")
+	}
+
+	metadata.markFinished(finishTime.Format(timestampFormat))
+	return s3Backup.saveMetadata(metadata)
+}
+
+func (s3Backup *S3Backup) MarkComplete() error {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata")
+	}
+
+	metadata.MetadataForBackupActivity.Status = statusComplete
+	return s3Backup.saveMetadata(metadata)
+}
+
+func (s3Backup *S3Backup) MarkDrainFailed() error {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata")
+	}
+
+	metadata.MetadataForBackupActivity.Status = statusDrainFailed
+	return s3Backup.saveMetadata(metadata)
+}
+
+func (s3Backup *S3Backup) MarkResumed() error {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata")
+	}
+
+	if err := metadata.markResumed(); err != nil {
+		return s3Backup.logAndReturn(err, "unable to resume %s", s3Backup.location(s3Backup.prefix))
+	}
+
+	return s3Backup.saveMetadata(metadata)
+}
+
+func (s3Backup *S3Backup) FetchChecksum(artifactIdentifier orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return nil, s3Backup.logAndReturn(err, "Error reading metadata from %s", s3Backup.location(s3Backup.key("metadata")))
+	}
+
+	if artifact, found := metadata.findArtifactMetadata(artifactIdentifier); found {
+		return artifact.Checksum, nil
+	}
+
+	s3Backup.Warn("bbr", "Checksum for %s not found in artifact", logName(artifactIdentifier))
+	return nil, nil
+}
+
+// FetchPreviousChecksum always returns nil, as backups in object storage are
+// never incremental.
+func (s3Backup *S3Backup) FetchPreviousChecksum(artifactIdentifier orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
+	return nil, nil
+}
+
+func (s3Backup *S3Backup) ArtifactDrained(artifactIdentifier orchestrator.ArtifactIdentifier) (bool, error) {
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return false, s3Backup.logAndReturn(err, "Error reading metadata from %s", s3Backup.location(s3Backup.key("metadata")))
+	}
+
+	_, found := metadata.findArtifactMetadata(artifactIdentifier)
+	return found, nil
+}
+
+func (s3Backup *S3Backup) CalculateChecksum(artifactIdentifier orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
+	reader, err := s3Backup.ReadArtifact(artifactIdentifier)
+	if err != nil {
+		return nil, s3Backup.logAndReturn(err, "Error opening artifact %v", logName(artifactIdentifier))
+	}
+	defer reader.Close()
+
+	checksum, err := orchestrator.CalculateTarChecksum(reader, s3Backup.Logger)
+	if err != nil {
+		return nil, s3Backup.logAndReturn(err, "Error checksumming %s", logName(artifactIdentifier))
+	}
+	return checksum, nil
+}
+
+func (s3Backup *S3Backup) DeploymentMatches(deployment string, instances []orchestrator.Instance) (bool, error) {
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return false, s3Backup.logAndReturn(err, "Error reading metadata file")
+	}
+
+	if missing := metadata.instancesNotIn(instances); len(missing) > 0 {
+		return false, orchestrator.NewDeploymentMismatchError(fmt.Sprintf("the backup has instances that are not in deployment '%s'", deployment), missing)
+	}
+	return true, nil
+}
+
+func (s3Backup *S3Backup) BackedUpInstances() ([]orchestrator.InstanceMetadata, error) {
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return nil, s3Backup.logAndReturn(err, "Error reading metadata file")
+	}
+
+	return metadata.instances(), nil
+}
+
+func (s3Backup *S3Backup) SaveManifest(manifest string) error {
+	return errors.Wrap(s3Backup.putObject(s3Backup.key("manifest.yml"), []byte(manifest)), "failed to save manifest")
+}
+
+// Sign stores a detached signature of the metadata object next to it. It does
+// nothing when the backup was not given a signing key.
+func (s3Backup *S3Backup) Sign() error {
+	if s3Backup.signingKey == nil {
+		return nil
+	}
+
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	contents, err := s3Backup.getObject(s3Backup.key("metadata"))
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata")
+	}
+
+	return errors.Wrap(s3Backup.putObject(s3Backup.key(signatureFileName), signMetadata(s3Backup.signingKey, contents)), "failed to sign metadata")
+}
+
+// VerifySignature checks the metadata object against its detached signature.
+// It does nothing when the backup was not given a trusted key.
+func (s3Backup *S3Backup) VerifySignature() error {
+	if s3Backup.trustedKey == nil {
+		return nil
+	}
+
+	contents, err := s3Backup.getObject(s3Backup.key("metadata"))
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata")
+	}
+
+	exists, err := s3Backup.objectExists(s3Backup.key(signatureFileName))
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to check for metadata signature")
+	}
+	if !exists {
+		return errors.Errorf("backup is not signed, %s is missing", signatureFileName)
+	}
+
+	signature, err := s3Backup.getObject(s3Backup.key(signatureFileName))
+	if err != nil {
+		return s3Backup.logAndReturn(err, "unable to load metadata signature")
+	}
+
+	return verifyMetadataSignature(s3Backup.trustedKey, contents, signature)
+}
+
+func (s3Backup *S3Backup) Valid() (bool, error) {
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return false, s3Backup.logAndReturn(err, "Error reading metadata from %s", s3Backup.location(s3Backup.key("metadata")))
+	}
+
+	if err := metadata.checkComplete(); err != nil {
+		return false, s3Backup.logAndReturn(err, "Error validating %s", s3Backup.location(s3Backup.prefix))
+	}
+
+	if _, err := s3Backup.artifactKey(); err != nil {
+		return false, s3Backup.logAndReturn(err, "Error checking encryption key")
+	}
+
+	for _, artifact := range metadata.MetadataForEachArtifact {
+		if valid, err := s3Backup.artifactMatches(makeCustomArtifactIdentifier(artifact), artifact.Checksum); !valid {
+			return false, err
+		}
+	}
+
+	for _, inst := range metadata.MetadataForEachInstance {
+		for _, artifact := range inst.Artifacts {
+			if valid, err := s3Backup.artifactMatches(makeDefaultArtifactIdentifier(artifact, inst), artifact.Checksum); !valid {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func (s3Backup *S3Backup) artifactMatches(artifactIdentifier orchestrator.ArtifactIdentifier, expected orchestrator.BackupChecksum) (bool, error) {
+	actual, err := s3Backup.CalculateChecksum(artifactIdentifier)
+	if err != nil {
+		return false, s3Backup.logAndReturn(err, "Error calculating checksum for artifact")
+	}
+
+	if match, _ := actual.Match(expected); !match {
+		return false, s3Backup.logAndReturn(errors.New("checksum mismatch"), "Can't match checksums for %s, in metadata: %v, in actual file: %v", logName(artifactIdentifier), expected, actual)
+	}
+	return true, nil
+}
+
+func (s3Backup *S3Backup) artifactCompression(artifactIdentifier orchestrator.ArtifactIdentifier) string {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		return s3Backup.compression
+	}
+
+	artifact, found := metadata.findArtifactMetadata(artifactIdentifier)
+	if !found {
+		return s3Backup.compression
+	}
+	return artifact.Compression
+}
+
+func (s3Backup *S3Backup) artifactKey() ([]byte, error) {
+	defer s3Backup.Unlock()
+	s3Backup.Lock()
+
+	if s3Backup.derivedKey != nil {
+		return s3Backup.derivedKey, nil
+	}
+
+	metadata, err := s3Backup.readMetadata()
+	if err != nil {
+		if s3Backup.encryptionKey != nil {
+			return nil, errors.Wrap(err, "failed to read the encryption settings of the backup")
+		}
+		return nil, nil
+	}
+
+	key, err := metadata.resolveEncryptionKey(s3Backup.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s3Backup.derivedKey = key
+	return key, nil
+}
+
+func (s3Backup *S3Backup) readMetadata() (metadata, error) {
+	contents, err := s3Backup.getObject(s3Backup.key("metadata"))
+	if err != nil {
+		return metadata{}, errors.Wrap(err, "failed to read metadata")
+	}
+
+	return parseMetadata(contents)
+}
+
+func (s3Backup *S3Backup) getObject(key string) ([]byte, error) {
+	output, err := s3Backup.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s3Backup.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return ioutil.ReadAll(output.Body)
+}
+
+func (s3Backup *S3Backup) saveMetadata(metadata metadata) error {
+	contents, err := metadata.marshal()
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(s3Backup.putObject(s3Backup.key("metadata"), contents), "failed to save metadata")
+}
+
+func (s3Backup *S3Backup) putObject(key string, contents []byte) error {
+	_, err := s3Backup.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s3Backup.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(contents),
+	})
+	return err
+}
+
+func (s3Backup *S3Backup) objectExists(key string) (bool, error) {
+	_, err := s3Backup.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s3Backup.bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		return true, nil
+	}
+	if awsErr, ok := err.(awserr.RequestFailure); ok && awsErr.StatusCode() == 404 {
+		return false, nil
+	}
+	return false, err
+}
+
+func (s3Backup *S3Backup) key(name string) string {
+	return path.Join(s3Backup.prefix, name)
+}
+
+func (s3Backup *S3Backup) location(key string) string {
+	return fmt.Sprintf("s3://%s/%s", s3Backup.bucket, key)
+}
+
+func (s3Backup *S3Backup) logAndReturn(err error, message string, args ...interface{}) error {
+	message = fmt.Sprintf(message, args...)
+	s3Backup.Debug("bbr", "%s: %v", message, err)
+	return errors.Wrap(err, message)
+}
+
+// s3UploadWriter feeds an in-flight multipart upload. Close waits for the
+// upload to complete so that callers see upload failures.
+type s3UploadWriter struct {
+	*io.PipeWriter
+	done chan error
+}
+
+func (w *s3UploadWriter) Close() error {
+	if err := w.PipeWriter.Close(); err != nil {
+		return err
+	}
+	return <-w.done
+}
+
+// s3ArtifactWriter encodes an artifact into an upload. Aborting it fails the
+// upload, so that no truncated object is left in the bucket.
+type s3ArtifactWriter struct {
+	io.WriteCloser
+	upload *s3UploadWriter
+}
+
+func (w *s3ArtifactWriter) Abort(cause error) {
+	w.upload.CloseWithError(cause)
+	<-w.upload.done
+}
+
+func humanReadableSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	value := float64(size)
+	for _, suffix := range []string{"K", "M", "G", "T", "P"} {
+		value = value / unit
+		if value < unit {
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+	}
+	return fmt.Sprintf("%.1fE", value/unit)
+}
@@ -0,0 +1,129 @@
+package backup
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+const defaultS3Region = "us-east-1"
+
+// S3BackupManager stores backups as objects in an S3-compatible bucket. Each
+// backup lives under its own key prefix and is laid out like a BackupDirectory.
+type S3BackupManager struct {
+	Client        s3iface.S3API
+	Bucket        string
+	Prefix        string
+	Compression   string
+	EncryptionKey *EncryptionKey
+	SigningKey    ed25519.PrivateKey
+	TrustedKey    ed25519.PublicKey
+}
+
+// NewS3BackupManager parses an artifact URL of the form
+// s3://bucket/prefix?endpoint=https://host:port&region=region. The endpoint is
+// only needed for S3-compatible stores; credentials are taken from the standard
+// AWS environment variables and configuration files.
+func NewS3BackupManager(artifactURL, compression string, encryptionKey *EncryptionKey) (S3BackupManager, error) {
+	parsedURL, err := url.Parse(artifactURL)
+	if err != nil {
+		return S3BackupManager{}, errors.Wrap(err, "failed to parse artifact url")
+	}
+
+	if parsedURL.Scheme != "s3" || parsedURL.Host == "" {
+		return S3BackupManager{}, errors.Errorf("unsupported artifact url %s, expected s3://bucket/prefix", artifactURL)
+	}
+
+	config := aws.NewConfig()
+	region := parsedURL.Query().Get("region")
+	if region == "" {
+		region = defaultS3Region
+	}
+	config = config.WithRegion(region)
+
+	if endpoint := parsedURL.Query().Get("endpoint"); endpoint != "" {
+		config = config.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+
+	awsSession, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return S3BackupManager{}, errors.Wrap(err, "failed to create s3 session")
+	}
+
+	return S3BackupManager{
+		Client:        s3.New(awsSession),
+		Bucket:        parsedURL.Host,
+		Prefix:        strings.Trim(parsedURL.Path, "/"),
+		Compression:   compression,
+		EncryptionKey: encryptionKey,
+	}, nil
+}
+
+func (manager S3BackupManager) Create(_, directoryName string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+	backupPrefix := path.Join(manager.Prefix, directoryName)
+
+	exists, err := manager.prefixExists(backupPrefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating artifact directory")
+	}
+	if exists {
+		return nil, errors.Errorf("failed creating artifact directory: %s already exists", manager.location(backupPrefix))
+	}
+
+	return manager.newBackup(backupPrefix, logger), nil
+}
+
+func (manager S3BackupManager) Open(name string, logger orchestrator.Logger) (orchestrator.Backup, error) {
+	backupPrefix := path.Join(manager.Prefix, name)
+
+	exists, err := manager.prefixExists(backupPrefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed opening the directory")
+	}
+	if !exists {
+		return nil, errors.Errorf("failed opening the directory: no backup found at %s", manager.location(backupPrefix))
+	}
+
+	return manager.newBackup(backupPrefix, logger), nil
+}
+
+func (manager S3BackupManager) newBackup(backupPrefix string, logger orchestrator.Logger) *S3Backup {
+	return &S3Backup{
+		Logger:        logger,
+		client:        manager.Client,
+		bucket:        manager.Bucket,
+		prefix:        backupPrefix,
+		compression:   manager.Compression,
+		encryptionKey: manager.EncryptionKey,
+		signingKey:    manager.SigningKey,
+		trustedKey:    manager.TrustedKey,
+	}
+}
+
+func (manager S3BackupManager) prefixExists(backupPrefix string) (bool, error) {
+	output, err := manager.Client.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:  aws.String(manager.Bucket),
+		Prefix:  aws.String(backupPrefix + "/"),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list %s", manager.location(backupPrefix))
+	}
+	return len(output.Contents) > 0, nil
+}
+
+func (manager S3BackupManager) location(backupPrefix string) string {
+	return fmt.Sprintf("s3://%s/%s", manager.Bucket, backupPrefix)
+}
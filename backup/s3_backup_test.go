@@ -0,0 +1,344 @@
+package backup_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("S3Backup", func() {
+	var server *httptest.Server
+	var objectStore *fakeObjectStore
+	var backupManager S3BackupManager
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var fakeBackupArtifact *fakes.FakeBackupArtifact
+	var tarContents []byte
+
+	BeforeEach(func() {
+		objectStore = newFakeObjectStore()
+		server = httptest.NewServer(objectStore)
+
+		os.Setenv("AWS_ACCESS_KEY_ID", "access-key")
+		os.Setenv("AWS_SECRET_ACCESS_KEY", "secret-key")
+
+		var err error
+		backupManager, err = NewS3BackupManager(fmt.Sprintf("s3://my-bucket/backups?endpoint=%s", server.URL), "", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeBackupArtifact = new(fakes.FakeBackupArtifact)
+		fakeBackupArtifact.InstanceNameReturns("redis-server")
+		fakeBackupArtifact.InstanceIndexReturns("0")
+		fakeBackupArtifact.NameReturns("redis")
+
+		tarContents = createTarWithContents(map[string]string{
+			"file1": "This archive contains some text files.",
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+		os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+	})
+
+	Describe("NewS3BackupManager", func() {
+		It("parses the bucket and prefix from the artifact url", func() {
+			Expect(backupManager.Bucket).To(Equal("my-bucket"))
+			Expect(backupManager.Prefix).To(Equal("backups"))
+		})
+
+		It("rejects urls that are not s3 urls", func() {
+			_, err := NewS3BackupManager("https://my-bucket/backups", "", nil)
+			Expect(err).To(MatchError(ContainSubstring("unsupported artifact url")))
+		})
+	})
+
+	Describe("backing up and restoring", func() {
+		var backup orchestrator.Backup
+
+		BeforeEach(func() {
+			var err error
+			backup, err = backupManager.Create("", "my-deployment_20151021T010203Z", logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+			Expect(backup.SaveManifest("manifest-contents")).To(Succeed())
+
+			writer, err := backup.CreateArtifact(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = writer.Write(tarContents)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writer.Close()).To(Succeed())
+
+			checksum, err := backup.CalculateChecksum(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+			Expect(backup.MarkComplete()).To(Succeed())
+			Expect(backup.AddFinishTime(time.Date(2015, 10, 21, 1, 5, 3, 0, time.UTC))).To(Succeed())
+		})
+
+		It("streams the artifact, manifest and metadata to the bucket", func() {
+			Expect(objectStore.keys()).To(ConsistOf(
+				"my-bucket/backups/my-deployment_20151021T010203Z/metadata",
+				"my-bucket/backups/my-deployment_20151021T010203Z/manifest.yml",
+				"my-bucket/backups/my-deployment_20151021T010203Z/redis-server-0-redis.tar",
+			))
+			Expect(objectStore.get("my-bucket/backups/my-deployment_20151021T010203Z/redis-server-0-redis.tar")).To(Equal(tarContents))
+			Expect(objectStore.get("my-bucket/backups/my-deployment_20151021T010203Z/metadata")).To(MatchYAML(fmt.Sprintf(`---
+version: 2
+backup_activity:
+  start_time: 2015/10/21 01:02:03 UTC
+  finish_time: 2015/10/21 01:05:03 UTC
+  status: complete
+instances:
+- name: redis-server
+  index: "0"
+  artifacts:
+  - name: redis
+    size: %d
+    file_count: 1
+    checksums:
+      file1: %x
+`, len(tarContents), sha256.Sum256([]byte("This archive contains some text files.")))))
+		})
+
+		It("reports the size of the artifact", func() {
+			Expect(backup.GetArtifactByteSize(fakeBackupArtifact)).To(Equal(len(tarContents)))
+			Expect(backup.GetArtifactSize(fakeBackupArtifact)).To(Equal("2.0K"))
+		})
+
+		It("fails to create the same backup twice", func() {
+			_, err := backupManager.Create("", "my-deployment_20151021T010203Z", logger)
+			Expect(err).To(MatchError(ContainSubstring("failed creating artifact directory")))
+		})
+
+		Context("when the backup is opened for restore", func() {
+			var restoreManager S3BackupManager
+
+			BeforeEach(func() {
+				var err error
+				restoreManager, err = NewS3BackupManager(fmt.Sprintf("s3://my-bucket/backups/my-deployment_20151021T010203Z?endpoint=%s", server.URL), "", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				backup, err = restoreManager.Open("", logger)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("is valid", func() {
+				Expect(backup.Valid()).To(BeTrue())
+			})
+
+			It("matches the deployment", func() {
+				instance := new(fakes.FakeInstance)
+				instance.NameReturns("redis-server")
+				instance.IndexReturns("0")
+
+				Expect(backup.DeploymentMatches("my-deployment", []orchestrator.Instance{instance})).To(BeTrue())
+			})
+
+			It("reads the artifact back", func() {
+				reader, err := backup.ReadArtifact(fakeBackupArtifact)
+				Expect(err).NotTo(HaveOccurred())
+				defer reader.Close()
+
+				Expect(ioutil.ReadAll(reader)).To(Equal(tarContents))
+			})
+
+			It("fetches the stored checksum", func() {
+				Expect(backup.FetchChecksum(fakeBackupArtifact)).To(Equal(orchestrator.BackupChecksum{
+					"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+				}))
+			})
+
+			Context("when the artifact object has been corrupted", func() {
+				BeforeEach(func() {
+					objectStore.put("my-bucket/backups/my-deployment_20151021T010203Z/redis-server-0-redis.tar", createTarWithContents(map[string]string{
+						"file1": "Something else entirely.",
+					}))
+				})
+
+				It("is not valid", func() {
+					valid, err := backup.Valid()
+					Expect(valid).To(BeFalse())
+					Expect(err).To(MatchError(ContainSubstring("Can't match checksums")))
+				})
+			})
+		})
+	})
+
+	Describe("aborting an artifact", func() {
+		It("leaves no object behind", func() {
+			backup, err := backupManager.Create("", "my-deployment_20151021T010203Z", logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+			writer, err := backup.CreateArtifact(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = writer.Write(tarContents[:10])
+			Expect(err).NotTo(HaveOccurred())
+
+			orchestrator.AbortArtifact(writer, fmt.Errorf("stream error"))
+
+			Expect(objectStore.keys()).To(ConsistOf("my-bucket/backups/my-deployment_20151021T010203Z/metadata"))
+		})
+	})
+
+	Describe("compression", func() {
+		var backup orchestrator.Backup
+
+		BeforeEach(func() {
+			backupManager.Compression = GzipCompression
+
+			var err error
+			backup, err = backupManager.Create("", "my-deployment_20151021T010203Z", logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+
+			writer, err := backup.CreateArtifact(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = writer.Write(tarContents)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writer.Close()).To(Succeed())
+
+			checksum, err := backup.CalculateChecksum(fakeBackupArtifact)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.AddChecksum(fakeBackupArtifact, checksum)).To(Succeed())
+		})
+
+		It("reports the size of the tar stream, not of the compressed object", func() {
+			Expect(len(objectStore.get("my-bucket/backups/my-deployment_20151021T010203Z/redis-server-0-redis.tar"))).To(BeNumerically("<", len(tarContents)))
+			Expect(backup.GetArtifactByteSize(fakeBackupArtifact)).To(Equal(len(tarContents)))
+		})
+	})
+
+	Describe("encryption", func() {
+		var encryptedManager S3BackupManager
+
+		BeforeEach(func() {
+			var err error
+			encryptedManager, err = NewS3BackupManager(fmt.Sprintf("s3://my-bucket/backups?endpoint=%s", server.URL), "", NewEncryptionKeyFromPassphrase("secret"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the metadata can not be read", func() {
+			It("fails to create artifacts rather than uploading them unencrypted", func() {
+				backup, err := encryptedManager.Create("", "my-deployment_20151021T010203Z", logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = backup.CreateArtifact(fakeBackupArtifact)
+				Expect(err).To(MatchError(ContainSubstring("failed to read the encryption settings of the backup")))
+				Expect(objectStore.keys()).To(BeEmpty())
+			})
+		})
+
+		Context("when an unencrypted backup is opened with a key", func() {
+			It("fails to create artifacts rather than uploading them unencrypted", func() {
+				backup, err := backupManager.Create("", "my-deployment_20151021T010203Z", logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(backup.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+
+				backup, err = encryptedManager.Open("my-deployment_20151021T010203Z", logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = backup.CreateArtifact(fakeBackupArtifact)
+				Expect(err).To(MatchError(ContainSubstring("an encryption key was provided, but the backup is not encrypted")))
+				Expect(objectStore.keys()).To(ConsistOf("my-bucket/backups/my-deployment_20151021T010203Z/metadata"))
+			})
+		})
+	})
+
+	Describe("Open", func() {
+		It("fails when there is no backup at the url", func() {
+			_, err := backupManager.Open("not-there", logger)
+			Expect(err).To(MatchError(ContainSubstring("no backup found at s3://my-bucket/backups/not-there")))
+		})
+	})
+})
+
+type fakeObjectStore struct {
+	sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeObjectStore() *fakeObjectStore {
+	return &fakeObjectStore{objects: map[string][]byte{}}
+}
+
+func (store *fakeObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer GinkgoRecover()
+	key := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		store.list(w, key, r.URL.Query().Get("prefix"))
+	case r.Method == http.MethodPut:
+		contents, err := ioutil.ReadAll(r.Body)
+		Expect(err).NotTo(HaveOccurred())
+		store.put(key, contents)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		contents, found := store.lookup(key)
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(contents)))
+		if r.Method == http.MethodGet {
+			w.Write(contents)
+		}
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func (store *fakeObjectStore) list(w http.ResponseWriter, bucket, prefix string) {
+	var contents string
+	for _, key := range store.keys() {
+		if strings.HasPrefix(key, bucket+"/"+prefix) {
+			contents += fmt.Sprintf("<Contents><Key>%s</Key></Contents>", strings.TrimPrefix(key, bucket+"/"))
+		}
+	}
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Name>%s</Name>%s</ListBucketResult>`, bucket, contents)
+}
+
+func (store *fakeObjectStore) put(key string, contents []byte) {
+	store.Lock()
+	defer store.Unlock()
+	store.objects[key] = contents
+}
+
+func (store *fakeObjectStore) lookup(key string) ([]byte, bool) {
+	store.Lock()
+	defer store.Unlock()
+	contents, found := store.objects[key]
+	return contents, found
+}
+
+func (store *fakeObjectStore) get(key string) []byte {
+	contents, _ := store.lookup(key)
+	return contents
+}
+
+func (store *fakeObjectStore) keys() []string {
+	store.Lock()
+	defer store.Unlock()
+	var keys []string
+	for key := range store.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const signatureFileName = "metadata.sig"
+
+// LoadSigningKey reads an ed25519 private key from a PEM encoded PKCS #8 file,
+// such as the one written by `openssl genpkey -algorithm ed25519`.
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEMBlock(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read signing key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signing key")
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("failed to parse signing key: not an ed25519 private key")
+	}
+	return privateKey, nil
+}
+
+// LoadTrustedKey reads an ed25519 public key from a PEM encoded PKIX file, such
+// as the one written by `openssl pkey -pubout`.
+func LoadTrustedKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEMBlock(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read trusted key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse trusted key")
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("failed to parse trusted key: not an ed25519 public key")
+	}
+	return publicKey, nil
+}
+
+func readPEMBlock(path, blockType string) (*pem.Block, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != blockType {
+		return nil, errors.Errorf("%s does not contain a PEM encoded %s", path, strings.ToLower(blockType))
+	}
+	return block, nil
+}
+
+// signMetadata returns the detached signature of the metadata contents. As the
+// metadata holds the checksum of every file in every artifact, the signature
+// covers the artifacts as well.
+func signMetadata(key ed25519.PrivateKey, metadataContents []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, metadataContents)) + "\n")
+}
+
+func verifyMetadataSignature(key ed25519.PublicKey, metadataContents, signature []byte) error {
+	decodedSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return errors.Wrap(err, "failed to decode the metadata signature")
+	}
+
+	if !ed25519.Verify(key, metadataContents, decodedSignature) {
+		return errors.New("the metadata signature does not match the trusted key, the backup may have been tampered with")
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package backup_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signing", func() {
+	var keyDir string
+	var publicKey ed25519.PublicKey
+	var privateKey ed25519.PrivateKey
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+
+	writePEM := func(name, blockType string, bytes []byte) string {
+		path := filepath.Join(keyDir, name)
+		Expect(ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes}), 0600)).To(Succeed())
+		return path
+	}
+
+	writeKeys := func() (string, string) {
+		privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		Expect(err).NotTo(HaveOccurred())
+		publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+		Expect(err).NotTo(HaveOccurred())
+		return writePEM("signing.pem", "PRIVATE KEY", privateKeyBytes), writePEM("trusted.pem", "PUBLIC KEY", publicKeyBytes)
+	}
+
+	BeforeEach(func() {
+		var err error
+		keyDir, err = ioutil.TempDir("", "signing-keys")
+		Expect(err).NotTo(HaveOccurred())
+		publicKey, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(keyDir)).To(Succeed())
+	})
+
+	Describe("LoadSigningKey and LoadTrustedKey", func() {
+		It("load PEM encoded ed25519 keys", func() {
+			signingKeyPath, trustedKeyPath := writeKeys()
+
+			Expect(LoadSigningKey(signingKeyPath)).To(Equal(privateKey))
+			Expect(LoadTrustedKey(trustedKeyPath)).To(Equal(publicKey))
+		})
+
+		It("reject files that do not contain the expected key", func() {
+			signingKeyPath, trustedKeyPath := writeKeys()
+
+			_, err := LoadSigningKey(trustedKeyPath)
+			Expect(err).To(MatchError(ContainSubstring("does not contain a PEM encoded private key")))
+			_, err = LoadTrustedKey(signingKeyPath)
+			Expect(err).To(MatchError(ContainSubstring("does not contain a PEM encoded public key")))
+		})
+
+		It("reject missing files", func() {
+			_, err := LoadSigningKey(filepath.Join(keyDir, "missing.pem"))
+			Expect(err).To(MatchError(ContainSubstring("failed to read signing key")))
+		})
+	})
+
+	Describe("signing and verifying a backup", func() {
+		var backupPath string
+		var signingManager, verifyingManager BackupDirectoryManager
+
+		BeforeEach(func() {
+			backupPath = filepath.Join(keyDir, "redis_20151021T010203Z")
+			signingManager = BackupDirectoryManager{SigningKey: privateKey}
+			verifyingManager = BackupDirectoryManager{TrustedKey: publicKey}
+
+			backup, err := signingManager.Create("", backupPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+			Expect(backup.MarkComplete()).To(Succeed())
+			Expect(backup.Sign()).To(Succeed())
+		})
+
+		It("writes a detached signature next to the metadata", func() {
+			Expect(filepath.Join(backupPath, "metadata.sig")).To(BeARegularFile())
+		})
+
+		It("verifies the signature with the trusted key", func() {
+			backup, err := verifyingManager.Open(backupPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.VerifySignature()).To(Succeed())
+		})
+
+		It("fails when the metadata was changed after signing", func() {
+			contents, err := ioutil.ReadFile(filepath.Join(backupPath, "metadata"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(backupPath, "metadata"), append(contents, []byte("incremental_from: /elsewhere\n")...), 0644)).To(Succeed())
+
+			backup, err := verifyingManager.Open(backupPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.VerifySignature()).To(MatchError(ContainSubstring("does not match the trusted key")))
+		})
+
+		It("fails when the backup was signed with another key", func() {
+			otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			backup, err := BackupDirectoryManager{TrustedKey: otherPublicKey}.Open(backupPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.VerifySignature()).To(MatchError(ContainSubstring("does not match the trusted key")))
+		})
+
+		It("fails when the backup is not signed", func() {
+			Expect(os.Remove(filepath.Join(backupPath, "metadata.sig"))).To(Succeed())
+
+			backup, err := verifyingManager.Open(backupPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.VerifySignature()).To(MatchError(ContainSubstring("backup is not signed")))
+		})
+
+		It("does not check the signature without a trusted key", func() {
+			Expect(os.Remove(filepath.Join(backupPath, "metadata.sig"))).To(Succeed())
+
+			backup, err := BackupDirectoryManager{}.Open(backupPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.VerifySignature()).To(Succeed())
+		})
+
+		It("reports a bad signature when verifying the backup", func() {
+			Expect(ioutil.WriteFile(filepath.Join(backupPath, "metadata.sig"), []byte("bm90IGEgc2lnbmF0dXJl\n"), 0644)).To(Succeed())
+
+			report, err := verifyingManager.Verify(backupPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid()).To(BeFalse())
+			Expect(report.Problems).To(ConsistOf(ContainSubstring("does not match the trusted key")))
+		})
+	})
+
+	Context("when the backup manager has no signing key", func() {
+		It("does not sign the backup", func() {
+			backupPath := filepath.Join(keyDir, "redis_20151021T010203Z")
+			backup, err := BackupDirectoryManager{}.Create("", backupPath, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.CreateMetadataFileWithStartTime(time.Now())).To(Succeed())
+			Expect(backup.Sign()).To(Succeed())
+
+			Expect(filepath.Join(backupPath, "metadata.sig")).NotTo(BeAnExistingFile())
+		})
+	})
+})
@@ -0,0 +1,176 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// VerificationReport lists every problem found in a backup directory, rather
+// than stopping at the first one like Valid does.
+type VerificationReport struct {
+	Path            string                 `json:"path"`
+	Problems        []string               `json:"problems,omitempty"`
+	UnexpectedFiles []string               `json:"unexpected_files,omitempty"`
+	Artifacts       []ArtifactVerification `json:"artifacts"`
+}
+
+type ArtifactVerification struct {
+	Name           string   `json:"name"`
+	InstanceName   string   `json:"instance_name,omitempty"`
+	InstanceIndex  string   `json:"instance_index,omitempty"`
+	File           string   `json:"file"`
+	Problem        string   `json:"problem,omitempty"`
+	MissingFiles   []string `json:"missing_files,omitempty"`
+	ExtraFiles     []string `json:"extra_files,omitempty"`
+	CorruptedFiles []string `json:"corrupted_files,omitempty"`
+}
+
+func (report VerificationReport) Valid() bool {
+	if len(report.Problems) > 0 || len(report.UnexpectedFiles) > 0 {
+		return false
+	}
+	for _, artifact := range report.Artifacts {
+		if !artifact.Valid() {
+			return false
+		}
+	}
+	return true
+}
+
+func (artifact ArtifactVerification) Valid() bool {
+	return artifact.Problem == "" && len(artifact.MissingFiles) == 0 && len(artifact.ExtraFiles) == 0 && len(artifact.CorruptedFiles) == 0
+}
+
+// Verify checksums every artifact of the backup in the directory against its
+// metadata and checks that there are no artifact files the metadata does not
+// describe. When the manager has a trusted key, the metadata signature is
+// checked as well. It only fails when the metadata cannot be read.
+func (manager BackupDirectoryManager) Verify(path string, logger orchestrator.Logger) (VerificationReport, error) {
+	backupDirectory := &BackupDirectory{
+		baseDirName:   path,
+		Logger:        logger,
+		encryptionKey: manager.EncryptionKey,
+		trustedKey:    manager.TrustedKey,
+	}
+
+	metadata, err := readMetadata(backupDirectory.metadataFilename())
+	if err != nil {
+		return VerificationReport{}, errors.Wrapf(err, "failed to verify %s", path)
+	}
+
+	report := VerificationReport{Path: path, Artifacts: []ArtifactVerification{}}
+
+	if err := metadata.checkComplete(); err != nil {
+		report.Problems = append(report.Problems, err.Error())
+	}
+
+	if err := backupDirectory.VerifySignature(); err != nil {
+		report.Problems = append(report.Problems, err.Error())
+	}
+
+	unexpectedFiles, err := backupDirectory.unexpectedArtifactFiles(metadata)
+	if err != nil {
+		report.Problems = append(report.Problems, err.Error())
+	}
+	report.UnexpectedFiles = unexpectedFiles
+
+	if _, err := backupDirectory.artifactKey(); err != nil {
+		report.Problems = append(report.Problems, err.Error())
+		return report, nil
+	}
+
+	for _, artifact := range metadata.MetadataForEachArtifact {
+		report.Artifacts = append(report.Artifacts, backupDirectory.verifyArtifact(makeCustomArtifactIdentifier(artifact), artifact.Checksum))
+	}
+	for _, inst := range metadata.MetadataForEachInstance {
+		for _, artifact := range inst.Artifacts {
+			report.Artifacts = append(report.Artifacts, backupDirectory.verifyArtifact(makeDefaultArtifactIdentifier(artifact, inst), artifact.Checksum))
+		}
+	}
+
+	return report, nil
+}
+
+func (backupDirectory *BackupDirectory) verifyArtifact(artifactIdentifier orchestrator.ArtifactIdentifier, expected orchestrator.BackupChecksum) ArtifactVerification {
+	verification := ArtifactVerification{
+		Name: artifactIdentifier.Name(),
+		File: fileName(artifactIdentifier),
+	}
+	if !artifactIdentifier.HasCustomName() {
+		verification.InstanceName = artifactIdentifier.InstanceName()
+		verification.InstanceIndex = artifactIdentifier.InstanceIndex()
+	}
+
+	if backupDirectory.isChunked(artifactIdentifier) {
+		verification.File = chunkManifestFileName(verification.File)
+	} else if _, err := os.Stat(backupDirectory.instanceFilename(artifactIdentifier)); err != nil {
+		verification.Problem = "artifact file is missing"
+		return verification
+	}
+
+	actual, err := backupDirectory.CalculateChecksum(artifactIdentifier)
+	if err != nil {
+		verification.Problem = err.Error()
+		return verification
+	}
+
+	for file, sha := range expected {
+		actualSha, found := actual[file]
+		if !found {
+			verification.MissingFiles = append(verification.MissingFiles, file)
+		} else if actualSha != sha {
+			verification.CorruptedFiles = append(verification.CorruptedFiles, file)
+		}
+	}
+	for file := range actual {
+		if _, found := expected[file]; !found {
+			verification.ExtraFiles = append(verification.ExtraFiles, file)
+		}
+	}
+
+	sort.Strings(verification.MissingFiles)
+	sort.Strings(verification.CorruptedFiles)
+	sort.Strings(verification.ExtraFiles)
+	return verification
+}
+
+// unexpectedArtifactFiles returns the artifact files and chunk manifests in the
+// directory that the metadata does not describe.
+func (backupDirectory *BackupDirectory) unexpectedArtifactFiles(metadata metadata) ([]string, error) {
+	expected := map[string]bool{}
+	for _, artifact := range metadata.MetadataForEachArtifact {
+		expected[customArtifactFileName(artifact.Name)] = true
+	}
+	for _, inst := range metadata.MetadataForEachInstance {
+		for _, artifact := range inst.Artifacts {
+			expected[instanceArtifactFileName(inst.Name, inst.Index, artifact.Name)] = true
+		}
+	}
+
+	entries, err := ioutil.ReadDir(backupDirectory.baseDirName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s", backupDirectory.baseDirName)
+	}
+
+	var unexpected []string
+	for _, entry := range entries {
+		name := entry.Name()
+		switch filepath.Ext(name) {
+		case ".tar":
+			if !expected[name] {
+				unexpected = append(unexpected, name)
+			}
+		case chunkManifestExtension:
+			if !expected[strings.TrimSuffix(name, chunkManifestExtension)+".tar"] {
+				unexpected = append(unexpected, name)
+			}
+		}
+	}
+	return unexpected, nil
+}
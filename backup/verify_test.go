@@ -0,0 +1,115 @@
+package backup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verify", func() {
+	var artifactPath, backupPath string
+	var logger = boshlog.NewWriterLogger(boshlog.LevelDebug, GinkgoWriter)
+	var report VerificationReport
+	var verifyError error
+
+	BeforeEach(func() {
+		var err error
+		artifactPath, err = ioutil.TempDir("", "verify-artifacts")
+		Expect(err).NotTo(HaveOccurred())
+		backupPath = filepath.Join(artifactPath, "redis_20151021T010203Z")
+
+		backup, err := BackupDirectoryManager{}.Create(artifactPath, "redis_20151021T010203Z", logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backup.CreateMetadataFileWithStartTime(time.Date(2015, 10, 21, 1, 2, 3, 0, time.UTC))).To(Succeed())
+
+		files := map[string]string{"file1": "one", "file2": "two", "file3": "three"}
+		writeTarArtifact(backup, newFakeArtifact("redis-server", "0", "redis"), files)
+		writeTarArtifact(backup, newFakeArtifact("redis-server", "1", "redis"), files)
+		writeTarArtifact(backup, newFakeArtifact("", "", "shared"), files)
+
+		Expect(backup.MarkComplete()).To(Succeed())
+		Expect(backup.AddFinishTime(time.Date(2015, 10, 21, 1, 5, 3, 0, time.UTC))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(artifactPath)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		report, verifyError = BackupDirectoryManager{}.Verify(backupPath, logger)
+	})
+
+	It("reports a valid backup", func() {
+		Expect(verifyError).NotTo(HaveOccurred())
+		Expect(report.Valid()).To(BeTrue())
+		Expect(report.Artifacts).To(HaveLen(3))
+		Expect(report.Problems).To(BeEmpty())
+	})
+
+	Context("when several artifacts are broken", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(filepath.Join(backupPath, "redis-server-0-redis.tar"), createTarWithContents(map[string]string{
+				"file1": "one",
+				"file2": "changed",
+				"file4": "extra",
+			}), 0600)).To(Succeed())
+			Expect(os.Remove(filepath.Join(backupPath, "redis-server-1-redis.tar"))).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(backupPath, "shared.tar"), []byte("not a tar"), 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(backupPath, "leftover.tar"), createTarWithContents(map[string]string{}), 0600)).To(Succeed())
+		})
+
+		It("reports every problem", func() {
+			Expect(verifyError).NotTo(HaveOccurred())
+			Expect(report.Valid()).To(BeFalse())
+			Expect(report.UnexpectedFiles).To(ConsistOf("leftover.tar"))
+
+			Expect(report.Artifacts).To(HaveLen(3))
+			var byFile = map[string]ArtifactVerification{}
+			for _, artifact := range report.Artifacts {
+				byFile[artifact.File] = artifact
+			}
+
+			Expect(byFile["redis-server-0-redis.tar"].MissingFiles).To(Equal([]string{"file3"}))
+			Expect(byFile["redis-server-0-redis.tar"].CorruptedFiles).To(Equal([]string{"file2"}))
+			Expect(byFile["redis-server-0-redis.tar"].ExtraFiles).To(Equal([]string{"file4"}))
+			Expect(byFile["redis-server-0-redis.tar"].InstanceName).To(Equal("redis-server"))
+
+			Expect(byFile["redis-server-1-redis.tar"].Problem).To(Equal("artifact file is missing"))
+
+			Expect(byFile["shared.tar"].Problem).NotTo(BeEmpty())
+			Expect(byFile["shared.tar"].InstanceName).To(BeEmpty())
+		})
+	})
+
+	Context("when the backup is not complete", func() {
+		BeforeEach(func() {
+			metadata, err := ioutil.ReadFile(filepath.Join(backupPath, "metadata"))
+			Expect(err).NotTo(HaveOccurred())
+			metadata = []byte(strings.Replace(string(metadata), "status: complete", "status: failed", 1))
+			Expect(ioutil.WriteFile(filepath.Join(backupPath, "metadata"), metadata, 0600)).To(Succeed())
+		})
+
+		It("reports the status as a problem", func() {
+			Expect(verifyError).NotTo(HaveOccurred())
+			Expect(report.Valid()).To(BeFalse())
+			Expect(report.Problems).To(ConsistOf(ContainSubstring("backup is not complete")))
+		})
+	})
+
+	Context("when the metadata cannot be read", func() {
+		BeforeEach(func() {
+			Expect(os.Remove(filepath.Join(backupPath, "metadata"))).To(Succeed())
+		})
+
+		It("fails", func() {
+			Expect(verifyError).To(MatchError(ContainSubstring("failed to verify")))
+		})
+	})
+})
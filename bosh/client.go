@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
@@ -17,8 +18,9 @@ import (
 
 //go:generate counterfeiter -o fakes/fake_bosh_client.go . BoshClient
 type BoshClient interface {
-	FindInstances(deploymentName string) ([]orchestrator.Instance, error)
+	FindInstances(ctx context.Context, deploymentName string) ([]orchestrator.Instance, error)
 	GetManifest(deploymentName string) (string, error)
+	DirectorInfo() (orchestrator.DirectorInfo, error)
 }
 
 func NewClient(boshDirector director.Director,
@@ -54,7 +56,7 @@ type Logger interface {
 	Error(tag, msg string, args ...interface{})
 }
 
-func (c Client) FindInstances(deploymentName string) ([]orchestrator.Instance, error) {
+func (c Client) FindInstances(ctx context.Context, deploymentName string) ([]orchestrator.Instance, error) {
 	deployment, err := c.Director.FindDeployment(deploymentName)
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't find deployment "+deploymentName)
@@ -131,7 +133,7 @@ func (c Client) FindInstances(deploymentName string) ([]orchestrator.Instance, e
 				continue
 			}
 
-			jobs, err := c.jobFinder.FindJobs(instanceIdentifier, remoteRunner, manifestQuerier) //TODO: here
+			jobs, err := c.jobFinder.FindJobs(ctx, instanceIdentifier, remoteRunner, manifestQuerier) //TODO: here
 
 			if err != nil {
 				cleanupAlreadyMadeConnections(deployment, slugs, sshOpts)
@@ -152,6 +154,7 @@ func (c Client) FindInstances(deploymentName string) ([]orchestrator.Instance, e
 					remoteRunner,
 					deployment,
 					false,
+					isBootstrap,
 					c.Logger,
 					jobs,
 				),
@@ -191,6 +194,14 @@ func (c Client) GetManifest(deploymentName string) (string, error) {
 	return deployment.Manifest()
 }
 
+func (c Client) DirectorInfo() (orchestrator.DirectorInfo, error) {
+	info, err := c.Director.Info()
+	if err != nil {
+		return orchestrator.DirectorInfo{}, errors.Wrap(err, "failed to get director info")
+	}
+	return orchestrator.DirectorInfo{UUID: info.UUID, Name: info.Name, Version: info.Version}, nil
+}
+
 type JobVMInfo struct {
 	JobName string
 	VMInfo  director.VMInfo
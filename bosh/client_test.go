@@ -1,6 +1,7 @@
 package bosh_test
 
 import (
+	"context"
 	"log"
 
 	"bytes"
@@ -77,7 +78,7 @@ var _ = Describe("Director", func() {
 		)
 
 		JustBeforeEach(func() {
-			actualInstances, actualError = b.FindInstances(deploymentName)
+			actualInstances, actualError = b.FindInstances(context.Background(), deploymentName)
 		})
 
 		Context("finds instances for the deployment", func() {
@@ -128,6 +129,7 @@ var _ = Describe("Director", func() {
 					remoteRunner,
 					boshDeployment,
 					false,
+					false,
 					boshLogger,
 					expectedJobs,
 				)}))
@@ -156,7 +158,7 @@ var _ = Describe("Director", func() {
 
 			It("finds the jobs with the job finder", func() {
 				Expect(fakeJobFinder.FindJobsCallCount()).To(Equal(1))
-				_, _, manifestQuerier := fakeJobFinder.FindJobsArgsForCall(0)
+				_, _, _, manifestQuerier := fakeJobFinder.FindJobsArgsForCall(0)
 				Expect(manifestQuerier).To(Equal(manifestQuerier))
 			})
 
@@ -265,7 +267,7 @@ var _ = Describe("Director", func() {
 						false,
 					),
 				}
-				fakeJobFinder.FindJobsStub = func(instanceIdentifier instance.InstanceIdentifier, remoteRunner ssh.RemoteRunner, manifestQuerier instance.ManifestQuerier) (orchestrator.Jobs, error) {
+				fakeJobFinder.FindJobsStub = func(_ context.Context, instanceIdentifier instance.InstanceIdentifier, remoteRunner ssh.RemoteRunner, manifestQuerier instance.ManifestQuerier) (orchestrator.Jobs, error) {
 					if instanceIdentifier.InstanceId == "id1" {
 						return instance0Jobs, nil
 					} else {
@@ -285,6 +287,7 @@ var _ = Describe("Director", func() {
 						remoteRunner,
 						boshDeployment,
 						false,
+						false,
 						boshLogger,
 						instance0Jobs,
 					),
@@ -295,6 +298,7 @@ var _ = Describe("Director", func() {
 						remoteRunner,
 						boshDeployment,
 						false,
+						false,
 						boshLogger,
 						instance1Jobs,
 					),
@@ -403,7 +407,7 @@ var _ = Describe("Director", func() {
 					),
 				}
 
-				fakeJobFinder.FindJobsStub = func(instanceIdentifier instance.InstanceIdentifier, remoteRunner ssh.RemoteRunner, manifestQuerier instance.ManifestQuerier) (orchestrator.Jobs, error) {
+				fakeJobFinder.FindJobsStub = func(_ context.Context, instanceIdentifier instance.InstanceIdentifier, remoteRunner ssh.RemoteRunner, manifestQuerier instance.ManifestQuerier) (orchestrator.Jobs, error) {
 					if instanceIdentifier.InstanceId == "linux1" {
 						return instance0Jobs, nil
 					} else {
@@ -423,6 +427,7 @@ var _ = Describe("Director", func() {
 						remoteRunner,
 						boshDeployment,
 						false,
+						false,
 						boshLogger,
 						instance0Jobs,
 					),
@@ -510,7 +515,7 @@ var _ = Describe("Director", func() {
 					}
 				}
 				remoteRunnerFactory.Returns(remoteRunner, nil)
-				fakeJobFinder.FindJobsStub = func(instanceIdentifier instance.InstanceIdentifier,
+				fakeJobFinder.FindJobsStub = func(_ context.Context, instanceIdentifier instance.InstanceIdentifier,
 					remoteRunner ssh.RemoteRunner, manifestQuerier instance.ManifestQuerier) (orchestrator.Jobs, error) {
 					if instanceIdentifier.InstanceGroupName == "job2" {
 						return []orchestrator.Job{
@@ -541,6 +546,7 @@ var _ = Describe("Director", func() {
 						remoteRunner,
 						boshDeployment,
 						false,
+						true,
 						boshLogger,
 						[]orchestrator.Job{},
 					),
@@ -551,6 +557,7 @@ var _ = Describe("Director", func() {
 						remoteRunner,
 						boshDeployment,
 						false,
+						true,
 						boshLogger,
 						[]orchestrator.Job{
 							instance.NewJob(
@@ -572,6 +579,7 @@ var _ = Describe("Director", func() {
 						remoteRunner,
 						boshDeployment,
 						false,
+						false,
 						boshLogger,
 						[]orchestrator.Job{
 							instance.NewJob(
@@ -650,17 +658,17 @@ var _ = Describe("Director", func() {
 			It("for each remote runner, it finds the jobs with the job finder", func() {
 				Expect(fakeJobFinder.FindJobsCallCount()).To(Equal(3))
 
-				actualInstanceIdentifier, actualRemoteRunner, actualManifestQuerier := fakeJobFinder.FindJobsArgsForCall(0)
+				_, actualInstanceIdentifier, actualRemoteRunner, actualManifestQuerier := fakeJobFinder.FindJobsArgsForCall(0)
 				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{InstanceGroupName: "job1", InstanceId: "id1", Bootstrap: true}))
 				Expect(actualRemoteRunner).To(Equal(remoteRunner))
 				Expect(actualManifestQuerier).To(Equal(manifestQuerier))
 
-				actualInstanceIdentifier, actualRemoteRunner, actualManifestQuerier = fakeJobFinder.FindJobsArgsForCall(1)
+				_, actualInstanceIdentifier, actualRemoteRunner, actualManifestQuerier = fakeJobFinder.FindJobsArgsForCall(1)
 				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{InstanceGroupName: "job2", InstanceId: "id3", Bootstrap: true}))
 				Expect(actualRemoteRunner).To(Equal(remoteRunner))
 				Expect(actualManifestQuerier).To(Equal(manifestQuerier))
 
-				actualInstanceIdentifier, actualRemoteRunner, actualManifestQuerier = fakeJobFinder.FindJobsArgsForCall(2)
+				_, actualInstanceIdentifier, actualRemoteRunner, actualManifestQuerier = fakeJobFinder.FindJobsArgsForCall(2)
 				Expect(actualInstanceIdentifier).To(Equal(instance.InstanceIdentifier{InstanceGroupName: "job2", InstanceId: "id4", Bootstrap: false}))
 				Expect(actualRemoteRunner).To(Equal(remoteRunner))
 				Expect(actualManifestQuerier).To(Equal(manifestQuerier))
@@ -11,6 +11,7 @@ import (
 type BoshDeployedInstance struct {
 	Deployment director.Deployment
 	*instance.DeployedInstance
+	bootstrap bool
 }
 
 func NewBoshDeployedInstance(instanceGroupName,
@@ -19,15 +20,21 @@ func NewBoshDeployedInstance(instanceGroupName,
 	remoteRunner ssh.RemoteRunner,
 	deployment director.Deployment,
 	artifactDirectoryCreated bool,
+	bootstrap bool,
 	logger Logger,
 	jobs orchestrator.Jobs,
 ) orchestrator.Instance {
 	return &BoshDeployedInstance{
 		Deployment:       deployment,
 		DeployedInstance: instance.NewDeployedInstance(instanceIndex, instanceGroupName, instanceID, artifactDirectoryCreated, remoteRunner, logger, jobs),
+		bootstrap:        bootstrap,
 	}
 }
 
+func (i *BoshDeployedInstance) IsBootstrap() bool {
+	return i.bootstrap
+}
+
 func (i *BoshDeployedInstance) Cleanup() error {
 	var errs []error
 
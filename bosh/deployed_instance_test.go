@@ -23,7 +23,7 @@ var _ = Describe("BoshDeployedInstance", func() {
 	var boshLogger boshlog.Logger
 	var stdout *gbytes.Buffer
 	var jobName, jobIndex, jobID string
-	var artifactDirCreated bool
+	var artifactDirCreated, bootstrap bool
 	var backuperInstance orchestrator.Instance
 
 	BeforeEach(func() {
@@ -35,6 +35,7 @@ var _ = Describe("BoshDeployedInstance", func() {
 		stdout = gbytes.NewBuffer()
 		boshLogger = boshlog.New(boshlog.LevelDebug, log.New(stdout, "[bosh-package] ", log.Lshortfile))
 		artifactDirCreated = true
+		bootstrap = false
 	})
 
 	JustBeforeEach(func() {
@@ -46,6 +47,7 @@ var _ = Describe("BoshDeployedInstance", func() {
 			remoteRunner,
 			boshDeployment,
 			artifactDirCreated,
+			bootstrap,
 			boshLogger,
 			[]orchestrator.Job{},
 		)
@@ -244,4 +246,20 @@ var _ = Describe("BoshDeployedInstance", func() {
 			})
 		})
 	})
+
+	Describe("IsBootstrap", func() {
+		It("is false when the instance is not the bootstrap node", func() {
+			Expect(backuperInstance.IsBootstrap()).To(BeFalse())
+		})
+
+		Context("when the instance is the bootstrap node", func() {
+			BeforeEach(func() {
+				bootstrap = true
+			})
+
+			It("is true", func() {
+				Expect(backuperInstance.IsBootstrap()).To(BeTrue())
+			})
+		})
+	})
 })
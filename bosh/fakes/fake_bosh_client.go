@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
@@ -9,10 +10,23 @@ import (
 )
 
 type FakeBoshClient struct {
-	FindInstancesStub        func(string) ([]orchestrator.Instance, error)
+	DirectorInfoStub        func() (orchestrator.DirectorInfo, error)
+	directorInfoMutex       sync.RWMutex
+	directorInfoArgsForCall []struct {
+	}
+	directorInfoReturns struct {
+		result1 orchestrator.DirectorInfo
+		result2 error
+	}
+	directorInfoReturnsOnCall map[int]struct {
+		result1 orchestrator.DirectorInfo
+		result2 error
+	}
+	FindInstancesStub        func(context.Context, string) ([]orchestrator.Instance, error)
 	findInstancesMutex       sync.RWMutex
 	findInstancesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	findInstancesReturns struct {
 		result1 []orchestrator.Instance
@@ -39,16 +53,72 @@ type FakeBoshClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBoshClient) FindInstances(arg1 string) ([]orchestrator.Instance, error) {
+func (fake *FakeBoshClient) DirectorInfo() (orchestrator.DirectorInfo, error) {
+	fake.directorInfoMutex.Lock()
+	ret, specificReturn := fake.directorInfoReturnsOnCall[len(fake.directorInfoArgsForCall)]
+	fake.directorInfoArgsForCall = append(fake.directorInfoArgsForCall, struct {
+	}{})
+	fake.recordInvocation("DirectorInfo", []interface{}{})
+	fake.directorInfoMutex.Unlock()
+	if fake.DirectorInfoStub != nil {
+		return fake.DirectorInfoStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.directorInfoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBoshClient) DirectorInfoCallCount() int {
+	fake.directorInfoMutex.RLock()
+	defer fake.directorInfoMutex.RUnlock()
+	return len(fake.directorInfoArgsForCall)
+}
+
+func (fake *FakeBoshClient) DirectorInfoCalls(stub func() (orchestrator.DirectorInfo, error)) {
+	fake.directorInfoMutex.Lock()
+	defer fake.directorInfoMutex.Unlock()
+	fake.DirectorInfoStub = stub
+}
+
+func (fake *FakeBoshClient) DirectorInfoReturns(result1 orchestrator.DirectorInfo, result2 error) {
+	fake.directorInfoMutex.Lock()
+	defer fake.directorInfoMutex.Unlock()
+	fake.DirectorInfoStub = nil
+	fake.directorInfoReturns = struct {
+		result1 orchestrator.DirectorInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) DirectorInfoReturnsOnCall(i int, result1 orchestrator.DirectorInfo, result2 error) {
+	fake.directorInfoMutex.Lock()
+	defer fake.directorInfoMutex.Unlock()
+	fake.DirectorInfoStub = nil
+	if fake.directorInfoReturnsOnCall == nil {
+		fake.directorInfoReturnsOnCall = make(map[int]struct {
+			result1 orchestrator.DirectorInfo
+			result2 error
+		})
+	}
+	fake.directorInfoReturnsOnCall[i] = struct {
+		result1 orchestrator.DirectorInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) FindInstances(arg1 context.Context, arg2 string) ([]orchestrator.Instance, error) {
 	fake.findInstancesMutex.Lock()
 	ret, specificReturn := fake.findInstancesReturnsOnCall[len(fake.findInstancesArgsForCall)]
 	fake.findInstancesArgsForCall = append(fake.findInstancesArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("FindInstances", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("FindInstances", []interface{}{arg1, arg2})
 	fake.findInstancesMutex.Unlock()
 	if fake.FindInstancesStub != nil {
-		return fake.FindInstancesStub(arg1)
+		return fake.FindInstancesStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -63,17 +133,17 @@ func (fake *FakeBoshClient) FindInstancesCallCount() int {
 	return len(fake.findInstancesArgsForCall)
 }
 
-func (fake *FakeBoshClient) FindInstancesCalls(stub func(string) ([]orchestrator.Instance, error)) {
+func (fake *FakeBoshClient) FindInstancesCalls(stub func(context.Context, string) ([]orchestrator.Instance, error)) {
 	fake.findInstancesMutex.Lock()
 	defer fake.findInstancesMutex.Unlock()
 	fake.FindInstancesStub = stub
 }
 
-func (fake *FakeBoshClient) FindInstancesArgsForCall(i int) string {
+func (fake *FakeBoshClient) FindInstancesArgsForCall(i int) (context.Context, string) {
 	fake.findInstancesMutex.RLock()
 	defer fake.findInstancesMutex.RUnlock()
 	argsForCall := fake.findInstancesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBoshClient) FindInstancesReturns(result1 []orchestrator.Instance, result2 error) {
@@ -168,6 +238,8 @@ func (fake *FakeBoshClient) GetManifestReturnsOnCall(i int, result1 string, resu
 func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.directorInfoMutex.RLock()
+	defer fake.directorInfoMutex.RUnlock()
 	fake.findInstancesMutex.RLock()
 	defer fake.findInstancesMutex.RUnlock()
 	fake.getManifestMutex.RLock()
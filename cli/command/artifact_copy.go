@@ -0,0 +1,94 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/urfave/cli"
+)
+
+type ArtifactCopyCommand struct {
+}
+
+func NewArtifactCopyCommand() ArtifactCopyCommand {
+	return ArtifactCopyCommand{}
+}
+
+func (a ArtifactCopyCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:   "copy",
+		Usage:  "Copy a backup to another directory or S3 bucket, checking every artifact once it is copied",
+		Action: a.Action,
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  "from",
+				Usage: "Path to the backup directory to copy",
+			},
+			cli.StringFlag{
+				Name:  "to",
+				Usage: "Directory, or s3://bucket/prefix URL, to copy the backup into",
+			},
+			cli.StringFlag{
+				Name:  "compression",
+				Value: backup.NoCompression,
+				Usage: "Compression to apply to the copied artifacts: none or gzip",
+			},
+			cli.StringFlag{
+				Name:  "signing-key",
+				Usage: "Path to a PEM encoded ed25519 private key to sign the copied metadata with",
+			},
+			cli.StringFlag{
+				Name:  "verify-signature",
+				Usage: "Path to a PEM encoded ed25519 public key to check the source metadata signature against",
+			},
+		}, encryptionFlags()...),
+	}
+}
+
+func (a ArtifactCopyCommand) Action(c *cli.Context) error {
+	if err := flags.Validate([]string{"from", "to"}, c); err != nil {
+		return err
+	}
+
+	logger := factory.BuildBoshLogger(c.GlobalBool("debug"))
+
+	encryptionKey, err := factory.BuildEncryptionKey(c.String("encryption-key-file"), c.String("encryption-passphrase"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	source := backup.BackupDirectoryManager{EncryptionKey: encryptionKey}
+	if c.String("verify-signature") != "" {
+		source.TrustedKey, err = backup.LoadTrustedKey(c.String("verify-signature"))
+		if err != nil {
+			return processError(orchestrator.NewError(err))
+		}
+	}
+
+	artifactURL, artifactPath := "", c.String("to")
+	if strings.HasPrefix(artifactPath, "s3://") {
+		artifactURL, artifactPath = artifactPath, ""
+	}
+
+	destination, err := factory.BuildBackupManager(factory.BackupManagerOptions{
+		ArtifactURL:          artifactURL,
+		Compression:          c.String("compression"),
+		EncryptionKeyFile:    c.String("encryption-key-file"),
+		EncryptionPassphrase: c.String("encryption-passphrase"),
+		SigningKeyFile:       c.String("signing-key"),
+	})
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	if err := backup.CopyBackup(source, c.String("from"), destination, artifactPath, logger); err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	fmt.Printf("Copied %s to %s\n", c.String("from"), c.String("to"))
+	return nil
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+type ArtifactDiffCommand struct {
+}
+
+func NewArtifactDiffCommand() ArtifactDiffCommand {
+	return ArtifactDiffCommand{}
+}
+
+func (a ArtifactDiffCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:      "diff",
+		Usage:     "Show what changed between two backups of the same deployment",
+		ArgsUsage: "<backup-a> <backup-b>",
+		Action:    a.Action,
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the differences as JSON",
+			},
+		},
+	}
+}
+
+func (a ArtifactDiffCommand) Action(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return processError(orchestrator.NewError(errors.New("two backup directories must be provided")))
+	}
+
+	diff, err := backup.DiffBackups(c.Args().Get(0), c.Args().Get(1))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	if c.Bool("json") {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+
+	printBackupDiff(diff)
+	return nil
+}
+
+func printBackupDiff(diff backup.BackupDiff) {
+	if diff.IsEmpty() {
+		fmt.Println("The backups do not differ.")
+	}
+
+	printNames("Added instances", diff.AddedInstances)
+	printNames("Removed instances", diff.RemovedInstances)
+	printNames("Replaced instances", diff.ReplacedInstances)
+	printNames("Added artifacts", diff.AddedArtifacts)
+	printNames("Removed artifacts", diff.RemovedArtifacts)
+
+	for _, artifact := range diff.ChangedArtifacts {
+		fmt.Printf("Changed artifact %s:\n", artifact.Artifact)
+		printFiles("added", artifact.AddedFiles)
+		printFiles("removed", artifact.RemovedFiles)
+		printFiles("changed", artifact.ChangedFiles)
+	}
+
+	if !diff.ManifestCompared {
+		fmt.Println("The manifests were not compared, as only one or neither of the backups contains a manifest.")
+	} else if len(diff.ManifestDiff) > 0 {
+		fmt.Println("Manifest:")
+		for _, line := range diff.ManifestDiff {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+func printNames(description string, names []string) {
+	if len(names) > 0 {
+		fmt.Printf("%s: %s\n", description, strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+type ArtifactExtractCommand struct {
+}
+
+func NewArtifactExtractCommand() ArtifactExtractCommand {
+	return ArtifactExtractCommand{}
+}
+
+func (a ArtifactExtractCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:   "extract",
+		Usage:  "Extract files from a single artifact of a backup, checking them against the backup metadata",
+		Action: a.Action,
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  "path",
+				Usage: "Path to the backup directory",
+			},
+			cli.StringFlag{
+				Name:  "instance",
+				Usage: "Instance group and index of the artifact, as <group>/<index>. Omit for custom artifacts",
+			},
+			cli.StringFlag{
+				Name:  "job",
+				Usage: "Name of the job, or custom artifact, to extract files from",
+			},
+			cli.StringFlag{
+				Name:  "file",
+				Usage: "Only extract the files matching this glob pattern",
+			},
+			cli.StringFlag{
+				Name:  "to",
+				Usage: "Directory to extract the files to",
+			},
+			cli.StringFlag{
+				Name:  "verify-signature",
+				Usage: "Path to a PEM encoded ed25519 public key to check the backup metadata signature against",
+			},
+		}, encryptionFlags()...),
+	}
+}
+
+func (a ArtifactExtractCommand) Action(c *cli.Context) error {
+	if err := flags.Validate([]string{"path", "job", "to"}, c); err != nil {
+		return err
+	}
+
+	var instanceName, instanceIndex string
+	if c.String("instance") != "" {
+		parts := strings.Split(c.String("instance"), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return processError(orchestrator.NewError(errors.Errorf("invalid instance %q, expected <group>/<index>", c.String("instance"))))
+		}
+		instanceName, instanceIndex = parts[0], parts[1]
+	}
+
+	logger := factory.BuildBoshLogger(c.GlobalBool("debug"))
+
+	encryptionKey, err := factory.BuildEncryptionKey(c.String("encryption-key-file"), c.String("encryption-passphrase"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	manager := backup.BackupDirectoryManager{EncryptionKey: encryptionKey}
+	if c.String("verify-signature") != "" {
+		manager.TrustedKey, err = backup.LoadTrustedKey(c.String("verify-signature"))
+		if err != nil {
+			return processError(orchestrator.NewError(err))
+		}
+	}
+
+	extracted, err := manager.Extract(c.String("path"), instanceName, instanceIndex, c.String("job"), c.String("file"), c.String("to"), logger)
+	for _, file := range extracted {
+		fmt.Printf("Extracted %s\n", file)
+	}
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/urfave/cli"
+)
+
+type ArtifactGarbageCollectCommand struct {
+}
+
+func NewArtifactGarbageCollectCommand() ArtifactGarbageCollectCommand {
+	return ArtifactGarbageCollectCommand{}
+}
+
+func (a ArtifactGarbageCollectCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:    "garbage-collect",
+		Aliases: []string{"gc"},
+		Usage:   "Remove chunks that are no longer referenced by any backup from a deduplicating store",
+		Action:  a.Action,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "dedup-store",
+				Usage: "Path to the chunk store to collect",
+			},
+			cli.DurationFlag{
+				Name:  "grace-period",
+				Value: backup.DefaultChunkGracePeriod,
+				Usage: "Keep unreferenced chunks written more recently than this, as they may belong to a backup that is still running",
+			},
+			cli.BoolFlag{
+				Name:  "forget-missing",
+				Usage: "Forget registered backups that no longer exist, so that their chunks can be removed. Only use this once missing backups have been deleted rather than moved",
+			},
+		},
+	}
+}
+
+func (a ArtifactGarbageCollectCommand) Action(c *cli.Context) error {
+	if err := flags.Validate([]string{"dedup-store"}, c); err != nil {
+		return err
+	}
+
+	logger := factory.BuildBoshLogger(c.GlobalBool("debug"))
+
+	store, err := backup.OpenChunkStore(c.String("dedup-store"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	result, err := store.CollectGarbage(c.Duration("grace-period"), c.Bool("forget-missing"), logger)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	if len(result.MissingBackups) > 0 {
+		fmt.Printf("No chunks were removed, as %d registered backups could not be found:\n", len(result.MissingBackups))
+		for _, missingBackup := range result.MissingBackups {
+			fmt.Printf("  %s\n", missingBackup)
+		}
+		fmt.Println("Move them back, or run again with --forget-missing if they have been deleted.")
+		return nil
+	}
+
+	fmt.Printf("Removed %d unreferenced chunks, freeing %d bytes. %d chunks are still referenced.\n", result.RemovedChunks, result.FreedBytes, result.ReferencedChunks)
+	return nil
+}
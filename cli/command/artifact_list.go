@@ -0,0 +1,137 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/urfave/cli"
+)
+
+type ArtifactListCommand struct {
+}
+
+func NewArtifactListCommand() ArtifactListCommand {
+	return ArtifactListCommand{}
+}
+
+func (a ArtifactListCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:    "list",
+		Aliases: []string{"ls"},
+		Usage:   "List the backups in a directory",
+		Action:  a.Action,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "path",
+				Usage: "Path to the directory containing the backups",
+			},
+			cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the backups as JSON",
+			},
+		},
+	}
+}
+
+type listedBackup struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Deployment   string `json:"deployment"`
+	StartTime    string `json:"start_time"`
+	FinishTime   string `json:"finish_time,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Complete     bool   `json:"complete"`
+	SizeInBytes  int64  `json:"size_in_bytes"`
+	Instances    int    `json:"instances"`
+	Artifacts    int    `json:"artifacts"`
+	HasManifest  bool   `json:"manifest"`
+	BBRVersion   string `json:"bbr_version,omitempty"`
+	DirectorUUID string `json:"director_uuid,omitempty"`
+}
+
+func (a ArtifactListCommand) Action(c *cli.Context) error {
+	if err := flags.Validate([]string{"path"}, c); err != nil {
+		return err
+	}
+
+	logger := factory.BuildBoshLogger(c.GlobalBool("debug"))
+
+	backups, err := backup.FindBackups(c.String("path"), logger)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	if c.Bool("json") {
+		return printBackupsAsJSON(backups)
+	}
+
+	printBackupsAsTable(backups)
+	return nil
+}
+
+func printBackupsAsJSON(backups []backup.StoredBackup) error {
+	listedBackups := []listedBackup{}
+	for _, storedBackup := range backups {
+		listed := listedBackup{
+			Name:         filepath.Base(storedBackup.Path),
+			Path:         storedBackup.Path,
+			Deployment:   storedBackup.Deployment,
+			StartTime:    storedBackup.StartTime.Format(time.RFC3339),
+			Status:       storedBackup.Status,
+			Complete:     storedBackup.Complete(),
+			SizeInBytes:  storedBackup.Size,
+			Instances:    storedBackup.Instances,
+			Artifacts:    storedBackup.Artifacts,
+			HasManifest:  storedBackup.HasManifest,
+			BBRVersion:   storedBackup.BBRVersion,
+			DirectorUUID: storedBackup.DirectorUUID,
+		}
+		if storedBackup.Finished() {
+			listed.FinishTime = storedBackup.FinishTime.Format(time.RFC3339)
+		}
+		listedBackups = append(listedBackups, listed)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(listedBackups)
+}
+
+func printBackupsAsTable(backups []backup.StoredBackup) {
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tDEPLOYMENT\tSTARTED\tFINISHED\tSIZE\tINSTANCES\tARTIFACTS\tMANIFEST\tCOMPLETE")
+	for _, storedBackup := range backups {
+		finishTime := "-"
+		if storedBackup.Finished() {
+			finishTime = storedBackup.FinishTime.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			filepath.Base(storedBackup.Path),
+			storedBackup.Deployment,
+			storedBackup.StartTime.Format(time.RFC3339),
+			finishTime,
+			storedBackup.HumanReadableSize(),
+			storedBackup.Instances,
+			storedBackup.Artifacts,
+			yesOrNo(storedBackup.HasManifest),
+			yesOrNo(storedBackup.Complete()),
+		)
+	}
+	table.Flush()
+}
+
+func yesOrNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/urfave/cli"
+)
+
+type ArtifactPruneCommand struct {
+}
+
+func NewArtifactPruneCommand() ArtifactPruneCommand {
+	return ArtifactPruneCommand{}
+}
+
+func (a ArtifactPruneCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:   "prune",
+		Usage:  "Remove old backups according to a retention policy",
+		Action: a.Action,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "path",
+				Usage: "Path to the directory containing the backups",
+			},
+			cli.IntFlag{
+				Name:  "keep-last",
+				Usage: "Keep the last n backups of each deployment",
+			},
+			cli.IntFlag{
+				Name:  "keep-daily",
+				Usage: "Keep the last backup of each of the last n days",
+			},
+			cli.IntFlag{
+				Name:  "keep-weekly",
+				Usage: "Keep the last backup of each of the last n weeks",
+			},
+			cli.IntFlag{
+				Name:  "keep-monthly",
+				Usage: "Keep the last backup of each of the last n months",
+			},
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show which backups would be removed without removing them",
+			},
+		},
+	}
+}
+
+func (a ArtifactPruneCommand) Action(c *cli.Context) error {
+	if err := flags.Validate([]string{"path"}, c); err != nil {
+		return err
+	}
+
+	policy := backup.RetentionPolicy{
+		KeepLast:    c.Int("keep-last"),
+		KeepDaily:   c.Int("keep-daily"),
+		KeepWeekly:  c.Int("keep-weekly"),
+		KeepMonthly: c.Int("keep-monthly"),
+	}
+	if policy.IsEmpty() {
+		return processError(orchestrator.NewError(fmt.Errorf("at least one of '--keep-last', '--keep-daily', '--keep-weekly' or '--keep-monthly' must be provided")))
+	}
+
+	logger := factory.BuildBoshLogger(c.GlobalBool("debug"))
+	dryRun := c.Bool("dry-run")
+
+	result, err := backup.PruneBackups(c.String("path"), policy, dryRun, logger)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	for _, prunedBackup := range result.Removed {
+		if dryRun {
+			fmt.Printf("Would remove %s\n", prunedBackup.Path)
+		} else {
+			fmt.Printf("Removed %s\n", prunedBackup.Path)
+		}
+	}
+	if dryRun {
+		fmt.Printf("Would keep %d backups and remove %d backups. %d unfinished backups would be skipped.\n", len(result.Kept), len(result.Removed), len(result.Skipped))
+	} else {
+		fmt.Printf("Kept %d backups and removed %d backups. %d unfinished backups were skipped.\n", len(result.Kept), len(result.Removed), len(result.Skipped))
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/urfave/cli"
+)
+
+type ArtifactVerifyCommand struct {
+}
+
+func NewArtifactVerifyCommand() ArtifactVerifyCommand {
+	return ArtifactVerifyCommand{}
+}
+
+func (a ArtifactVerifyCommand) Cli() cli.Command {
+	return cli.Command{
+		Name:   "verify",
+		Usage:  "Check every artifact of a backup against the checksums in its metadata",
+		Action: a.Action,
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  "path",
+				Usage: "Path to the backup directory",
+			},
+			cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the verification report as JSON",
+			},
+			cli.StringFlag{
+				Name:  "verify-signature",
+				Usage: "Path to a PEM encoded ed25519 public key to check the backup metadata signature against",
+			},
+		}, encryptionFlags()...),
+	}
+}
+
+type verificationOutput struct {
+	backup.VerificationReport
+	Valid bool `json:"valid"`
+}
+
+func (a ArtifactVerifyCommand) Action(c *cli.Context) error {
+	if err := flags.Validate([]string{"path"}, c); err != nil {
+		return err
+	}
+
+	logger := factory.BuildBoshLogger(c.GlobalBool("debug"))
+
+	encryptionKey, err := factory.BuildEncryptionKey(c.String("encryption-key-file"), c.String("encryption-passphrase"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	manager := backup.BackupDirectoryManager{EncryptionKey: encryptionKey}
+	if c.String("verify-signature") != "" {
+		manager.TrustedKey, err = backup.LoadTrustedKey(c.String("verify-signature"))
+		if err != nil {
+			return processError(orchestrator.NewError(err))
+		}
+	}
+
+	report, err := manager.Verify(c.String("path"), logger)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	if c.Bool("json") {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(verificationOutput{VerificationReport: report, Valid: report.Valid()}); err != nil {
+			return err
+		}
+	} else {
+		printVerificationReport(report)
+	}
+
+	if !report.Valid() {
+		return cli.NewExitError(fmt.Sprintf("Backup %s failed verification", report.Path), 1)
+	}
+	return nil
+}
+
+func printVerificationReport(report backup.VerificationReport) {
+	for _, problem := range report.Problems {
+		fmt.Printf("Problem: %s\n", problem)
+	}
+	for _, file := range report.UnexpectedFiles {
+		fmt.Printf("Unexpected file: %s is not described by the metadata\n", file)
+	}
+
+	for _, artifact := range report.Artifacts {
+		if artifact.Valid() {
+			fmt.Printf("%s: OK\n", artifact.File)
+			continue
+		}
+
+		fmt.Printf("%s: FAILED\n", artifact.File)
+		if artifact.Problem != "" {
+			fmt.Printf("  %s\n", artifact.Problem)
+		}
+		printFiles("missing", artifact.MissingFiles)
+		printFiles("extra", artifact.ExtraFiles)
+		printFiles("corrupted", artifact.CorruptedFiles)
+	}
+}
+
+func printFiles(description string, files []string) {
+	if len(files) > 0 {
+		fmt.Printf("  %d %s files: %s\n", len(files), description, strings.Join(files, ", "))
+	}
+}
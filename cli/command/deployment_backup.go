@@ -1,12 +1,18 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/deployment"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
 
@@ -20,39 +26,157 @@ func NewDeploymentBackupCommand() DeploymentBackupCommand {
 }
 
 func (d DeploymentBackupCommand) Cli() cli.Command {
+	cliFlags := []cli.Flag{
+		cli.BoolFlag{
+			Name:  "with-manifest",
+			Usage: "Download the deployment manifest",
+		},
+		cli.StringFlag{
+			Name:  "artifact-path, a",
+			Usage: "Specify an optional path to save the backup artifacts to",
+		},
+		cli.StringFlag{
+			Name:  "artifact-url",
+			Usage: "Specify an optional s3://bucket/prefix URL to stream the backup artifacts to, instead of a local path",
+		},
+		cli.StringFlag{
+			Name:  "compression",
+			Value: backup.NoCompression,
+			Usage: "Compression to apply to the backup artifacts: none or gzip",
+		},
+		cli.StringFlag{
+			Name:  "dedup-store",
+			Usage: "Specify an optional path to a chunk store shared between backups, so that unchanged files are only stored once",
+		},
+		cli.StringFlag{
+			Name:  "incremental-from",
+			Usage: "Only copy the files that changed since the given backup artifact, which the new backup will depend on",
+		},
+		cli.StringFlag{
+			Name:  "resume",
+			Usage: "Finish copying the artifacts of an interrupted backup into the given backup artifact, without locking the deployment again",
+		},
+		cli.BoolFlag{
+			Name:  "verify-drained",
+			Usage: "With --resume, checksum the artifacts that were already copied again, instead of trusting the checksums recorded for them",
+		},
+		cli.DurationFlag{
+			Name:  "max-lock-duration",
+			Usage: "Abort the backup scripts and unlock the deployment if it is still locked for backup after this long, e.g. 15m. 0 means no limit",
+		},
+		cli.StringFlag{
+			Name:  "signing-key",
+			Usage: "Path to a PEM encoded ed25519 private key to sign the backup metadata with",
+		},
+	}
+	cliFlags = append(cliFlags, dryRunFlags()...)
+	cliFlags = append(cliFlags, onInterruptFlag())
+	cliFlags = append(cliFlags, eventsFlag())
+	cliFlags = append(cliFlags, scriptTimeoutFlags(backupScriptNames)...)
+	cliFlags = append(cliFlags, retryFlags()...)
+	cliFlags = append(cliFlags, encryptionFlags()...)
+
 	return cli.Command{
 		Name:    "backup",
 		Aliases: []string{"b"},
 		Usage:   "Backup a deployment",
 		Action:  d.Action,
-		Flags: []cli.Flag{
-			cli.BoolFlag{
-				Name:  "with-manifest",
-				Usage: "Download the deployment manifest",
-			},
-			cli.StringFlag{
-				Name:  "artifact-path, a",
-				Usage: "Specify an optional path to save the backup artifacts to",
-			},
-		},
+		Flags:   cliFlags,
 	}
 }
 
 func (d DeploymentBackupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	ctx, err := trapInterrupt(true, c.String("on-interrupt"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
 
 	username, password, target, caCert, bbrVersion, debug, deployment, allDeployments := getDeploymentParams(c)
 	withManifest := c.Bool("with-manifest")
 	artifactPath := c.String("artifact-path")
 
+	if err := validateArtifactLocation(c); err != nil {
+		return err
+	}
+
+	resumePath := c.String("resume")
+	if resumePath != "" && (allDeployments || artifactPath != "") {
+		return processError(orchestrator.NewError(errors.New("'--resume' cannot be used with '--all-deployments' or '--artifact-path'")))
+	}
+
+	verifyDrained := c.Bool("verify-drained")
+	if verifyDrained && resumePath == "" {
+		return processError(orchestrator.NewError(errors.New("'--verify-drained' can only be used with '--resume'")))
+	}
+
+	incrementalFrom := c.String("incremental-from")
+	if incrementalFrom != "" && (allDeployments || resumePath != "") {
+		return processError(orchestrator.NewError(errors.New("'--incremental-from' cannot be used with '--all-deployments' or '--resume'")))
+	}
+
+	if c.Bool("dry-run") {
+		if allDeployments || resumePath != "" {
+			return processError(orchestrator.NewError(errors.New("'--dry-run' cannot be used with '--all-deployments' or '--resume'")))
+		}
+		if err := validateDryRunFlags(c); err != nil {
+			return processError(orchestrator.NewError(err))
+		}
+		return planBackup(ctx, c, deployment, target, username, password, caCert, bbrVersion, debug)
+	}
+
+	backupManager, err := factory.BuildBackupManager(factory.BackupManagerOptions{
+		ArtifactURL:          c.String("artifact-url"),
+		DedupStorePath:       c.String("dedup-store"),
+		IncrementalFrom:      incrementalFrom,
+		Compression:          c.String("compression"),
+		EncryptionKeyFile:    c.String("encryption-key-file"),
+		EncryptionPassphrase: c.String("encryption-passphrase"),
+		SigningKeyFile:       c.String("signing-key"),
+	})
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	timeouts, err := scriptTimeouts(c, backupScriptNames)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	retries, err := retryPolicy(c)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	maxLockDuration := c.Duration("max-lock-duration")
+	if maxLockDuration < 0 {
+		return processError(orchestrator.NewError(errors.New("'--max-lock-duration' must not be negative")))
+	}
+	if maxLockDuration != 0 && resumePath != "" {
+		return processError(orchestrator.NewError(errors.New("'--max-lock-duration' cannot be used with '--resume'")))
+	}
+
+	observer, closeEvents, err := eventObserver(c)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+	defer closeEvents()
+
+	if allDeployments && eventsToStdout(c) {
+		return processError(orchestrator.NewError(errors.New("'--events=-' cannot be used with '--all-deployments', write the events to a file instead")))
+	}
+
+	if resumePath != "" {
+		return resumeSingleDeployment(ctx, deployment, target, username, password, caCert, resumePath, verifyDrained, withManifest, backupManager, bbrVersion, timeouts, retries, observer, buildWorkflowLogger(c, debug))
+	}
+
 	if allDeployments {
-		return backupAll(target, username, password, caCert, artifactPath, withManifest, bbrVersion, debug)
+		return backupAll(ctx, target, username, password, caCert, artifactPath, withManifest, backupManager, bbrVersion, timeouts, maxLockDuration, retries, observer, debug)
 	}
 
-	return backupSingleDeployment(deployment, target, username, password, caCert, artifactPath, withManifest, bbrVersion, debug)
+	return backupSingleDeployment(ctx, deployment, target, username, password, caCert, artifactPath, withManifest, backupManager, bbrVersion, timeouts, maxLockDuration, retries, observer, buildWorkflowLogger(c, debug))
 }
 
-func backupAll(target, username, password, caCert, artifactPath string, withManifest bool, bbrVersion string, debug bool) error {
+func backupAll(ctx context.Context, target, username, password, caCert, artifactPath string, withManifest bool, backupManager orchestrator.BackupManager, bbrVersion string, scriptTimeouts instance.ScriptTimeouts, maxLockDuration time.Duration, retryPolicy retry.Policy, observer orchestrator.Observer, debug bool) error {
 	backupAction := func(deploymentName string) orchestrator.Error {
 		timestamp := time.Now().UTC().Format(artifactTimeStampFormat)
 		logFilePath, buffer, logger := createLogger(timestamp, artifactPath, deploymentName, debug)
@@ -63,7 +187,12 @@ func backupAll(target, username, password, caCert, artifactPath string, withMani
 			password,
 			caCert,
 			withManifest,
+			backupManager,
 			bbrVersion,
+			scriptTimeouts,
+			maxLockDuration,
+			retryPolicy,
+			observer,
 			logger,
 			timestamp,
 		)
@@ -72,7 +201,7 @@ func backupAll(target, username, password, caCert, artifactPath string, withMani
 		}
 
 		printlnWithTimestamp(fmt.Sprintf("Starting backup of %s, log file: %s", deploymentName, logFilePath))
-		err := backuper.Backup(deploymentName, artifactPath)
+		err := backuper.Backup(ctx, deploymentName, artifactPath)
 
 		if err != nil {
 			printlnWithTimestamp(fmt.Sprintf("ERROR: failed to backup %s", deploymentName))
@@ -94,7 +223,7 @@ func backupAll(target, username, password, caCert, artifactPath string, withMani
 	fmt.Println("Starting backup...")
 
 	logger, _ := factory.BuildBoshLoggerWithCustomBuffer(debug)
-	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, bbrVersion, logger)
+	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, bbrVersion, nil, retry.Policy{}, logger)
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
@@ -107,16 +236,15 @@ func backupAll(target, username, password, caCert, artifactPath string, withMani
 		deployment.NewParallelExecutor())
 }
 
-func backupSingleDeployment(deployment, target, username, password, caCert, artifactPath string, withManifest bool, bbrVersion string, debug bool) error {
-	logger := factory.BuildBoshLogger(debug)
+func backupSingleDeployment(ctx context.Context, deployment, target, username, password, caCert, artifactPath string, withManifest bool, backupManager orchestrator.BackupManager, bbrVersion string, scriptTimeouts instance.ScriptTimeouts, maxLockDuration time.Duration, retryPolicy retry.Policy, observer orchestrator.Observer, logger boshlog.Logger) error {
 	timeStamp := time.Now().UTC().Format(artifactTimeStampFormat)
 
-	backuper, err := factory.BuildDeploymentBackuper(target, username, password, caCert, withManifest, bbrVersion, logger, timeStamp)
+	backuper, err := factory.BuildDeploymentBackuper(target, username, password, caCert, withManifest, backupManager, bbrVersion, scriptTimeouts, maxLockDuration, retryPolicy, observer, logger, timeStamp)
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
 
-	backupErr := backuper.Backup(deployment, artifactPath)
+	backupErr := backuper.Backup(ctx, deployment, artifactPath)
 	if backupErr.ContainsUnlockOrCleanupOrArtifactDirExists() {
 		return processErrorWithFooter(backupErr, backupCleanupAdvisedNotice)
 	}
@@ -124,6 +252,38 @@ func backupSingleDeployment(deployment, target, username, password, caCert, arti
 	return processError(backupErr)
 }
 
+func resumeSingleDeployment(ctx context.Context, deployment, target, username, password, caCert, backupPath string, verifyDrained, withManifest bool, backupManager orchestrator.BackupManager, bbrVersion string, scriptTimeouts instance.ScriptTimeouts, retryPolicy retry.Policy, observer orchestrator.Observer, logger boshlog.Logger) error {
+	timeStamp := time.Now().UTC().Format(artifactTimeStampFormat)
+
+	backuper, err := factory.BuildDeploymentBackuper(target, username, password, caCert, withManifest, backupManager, bbrVersion, scriptTimeouts, 0, retryPolicy, observer, logger, timeStamp)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	resumeErr := backuper.Resume(ctx, deployment, backupPath, verifyDrained)
+	if resumeErr.ContainsUnlockOrCleanupOrArtifactDirExists() {
+		return processErrorWithFooter(resumeErr, backupCleanupAdvisedNotice)
+	}
+
+	return processError(resumeErr)
+}
+
+func planBackup(ctx context.Context, c *cli.Context, deployment, target, username, password, caCert, bbrVersion string, debug bool) error {
+	logger := buildPlanLogger(c, debug)
+
+	planner, err := factory.BuildDeploymentBackupPlanner(target, username, password, caCert, bbrVersion, logger)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	plan, planErr := planner.Plan(ctx, deployment, "")
+	if planErr != nil {
+		return processError(planErr)
+	}
+
+	return printPlan(plan, c.Bool("json"))
+}
+
 func printlnWithTimestamp(str string) {
 	fmt.Printf("[%s] %s\n", time.Now().UTC().Format("15:04:05"), str)
 }
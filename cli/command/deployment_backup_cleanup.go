@@ -8,6 +8,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	"github.com/urfave/cli"
 )
 
@@ -23,11 +24,14 @@ func (d DeploymentBackupCleanupCommand) Cli() cli.Command {
 		Name:   "backup-cleanup",
 		Usage:  "Cleanup a deployment after a backup was interrupted",
 		Action: d.Action,
+		Flags:  []cli.Flag{cleanupOnInterruptFlag()},
 	}
 }
 
 func (d DeploymentBackupCleanupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	if err := trapSigint(true, c.String("on-interrupt")); err != nil {
+		return processError(orchestrator.NewError(err))
+	}
 
 	username, password, target, caCert, bbrVersion, debug, deployment, allDeployments := getDeploymentParams(c)
 
@@ -90,7 +94,7 @@ func cleanupAllDeployments(target, username, password, caCert, bbrVersion string
 
 	logger, _ := factory.BuildBoshLoggerWithCustomBuffer(debug)
 
-	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, bbrVersion, logger)
+	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, bbrVersion, nil, retry.Policy{}, logger)
 	if err != nil {
 		return err
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	"github.com/urfave/cli"
 )
 
@@ -35,7 +36,7 @@ func (d DeploymentPreBackupCheck) Action(c *cli.Context) error {
 	} else {
 		logger = factory.BuildBoshLogger(debug)
 	}
-	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, bbrVersion, logger)
+	boshClient, err := factory.BuildBoshClient(target, username, password, caCert, bbrVersion, nil, retry.Policy{}, logger)
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
@@ -1,9 +1,12 @@
 package command
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
 
@@ -15,39 +18,167 @@ func NewDeploymentRestoreCommand() DeploymentRestoreCommand {
 }
 
 func (d DeploymentRestoreCommand) Cli() cli.Command {
+	cliFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "artifact-path, a",
+			Usage: "Path to the artifact to restore",
+		},
+		cli.StringFlag{
+			Name:  "artifact-url",
+			Usage: "s3://bucket/prefix URL of the artifact to restore. Omit if '--artifact-path' is provided",
+		},
+		cli.StringFlag{
+			Name:  "verify-signature",
+			Usage: "Path to a PEM encoded ed25519 public key. The restore fails unless the backup metadata was signed with the matching private key",
+		},
+		cli.StringSliceFlag{
+			Name:  "instance-group",
+			Usage: "Only restore the jobs of this instance group. Can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "job",
+			Usage: "Only restore this job. Can be repeated",
+		},
+		cli.StringFlag{
+			Name:  "instance-mapping",
+			Usage: "Path to a YAML file mapping the instance groups and indices of the backup to those of the deployment",
+		},
+		cli.BoolFlag{
+			Name:  "lock-dependents",
+			Usage: "Also lock the jobs that declare they should be locked before the selected jobs",
+		},
+	}
+	cliFlags = append(cliFlags, dryRunFlags()...)
+	cliFlags = append(cliFlags, onInterruptFlag())
+	cliFlags = append(cliFlags, eventsFlag())
+	cliFlags = append(cliFlags, scriptTimeoutFlags(restoreScriptNames)...)
+	cliFlags = append(cliFlags, retryFlags()...)
+	cliFlags = append(cliFlags, encryptionFlags()...)
+
 	return cli.Command{
 		Name:    "restore",
 		Aliases: []string{"r"},
 		Usage:   "Restore a deployment from backup",
 		Action:  d.Action,
-		Flags: []cli.Flag{cli.StringFlag{
-			Name:  "artifact-path, a",
-			Usage: "Path to the artifact to restore",
-		}},
+		Flags:   cliFlags,
 	}
 }
 
 func (d DeploymentRestoreCommand) Action(c *cli.Context) error {
-	trapSigint(false)
+	ctx, err := trapInterrupt(false, c.String("on-interrupt"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
 
-	if err := flags.Validate([]string{"artifact-path"}, c); err != nil {
-		return err
+	if c.String("artifact-url") == "" {
+		if err := flags.Validate([]string{"artifact-path"}, c); err != nil {
+			return err
+		}
 	}
 
 	deployment := c.Parent().String("deployment")
 	artifactPath := c.String("artifact-path")
 
+	if err := validateArtifactLocation(c); err != nil {
+		return err
+	}
+
+	if c.Bool("dry-run") {
+		if err := validateDryRunFlags(c); err != nil {
+			return processError(orchestrator.NewError(err))
+		}
+	}
+
+	backupManager, err := factory.BuildBackupManager(factory.BackupManagerOptions{
+		ArtifactURL:          c.String("artifact-url"),
+		EncryptionKeyFile:    c.String("encryption-key-file"),
+		EncryptionPassphrase: c.String("encryption-passphrase"),
+		TrustedKeyFile:       c.String("verify-signature"),
+	})
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	instanceMapping, err := factory.BuildInstanceMapping(c.String("instance-mapping"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+	if !instanceMapping.IsEmpty() {
+		backupManager = orchestrator.NewMappedBackupManager(backupManager, instanceMapping)
+	}
+
+	selection := orchestrator.RestoreSelection{
+		InstanceGroups: c.StringSlice("instance-group"),
+		Jobs:           c.StringSlice("job"),
+		LockDependents: c.Bool("lock-dependents"),
+	}
+	if selection.IsEmpty() && selection.LockDependents {
+		return processError(orchestrator.NewError(errors.New("'--lock-dependents' can only be used with '--instance-group' or '--job'")))
+	}
+
+	if c.Bool("dry-run") {
+		return planRestore(ctx, c, deployment, artifactPath, backupManager, selection)
+	}
+
+	timeouts, err := scriptTimeouts(c, restoreScriptNames)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	retries, err := retryPolicy(c)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	observer, closeEvents, err := eventObserver(c)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+	defer closeEvents()
+
 	restorer, err := factory.BuildDeploymentRestorer(c.Parent().String("target"),
 		c.Parent().String("username"),
 		c.Parent().String("password"),
 		c.Parent().String("ca-cert"),
+		backupManager,
+		selection,
 		c.App.Version,
-		c.GlobalBool("debug"))
+		timeouts,
+		retries,
+		observer,
+		buildWorkflowLogger(c, c.GlobalBool("debug")))
 
 	if err != nil {
 		return processError(orchestrator.NewError(err))
 	}
 
-	restoreErr := restorer.Restore(deployment, artifactPath)
+	restoreErr := restorer.Restore(ctx, deployment, artifactPath)
+	if restoreErr.IsPostRestoreValidation() {
+		return processErrorWithFooter(restoreErr, restoreValidationFailedNotice)
+	}
+
 	return processError(restoreErr)
 }
+
+func planRestore(ctx context.Context, c *cli.Context, deployment, artifactPath string, backupManager orchestrator.BackupManager, selection orchestrator.RestoreSelection) error {
+	logger := buildPlanLogger(c, c.GlobalBool("debug"))
+
+	planner, err := factory.BuildDeploymentRestorePlanner(c.Parent().String("target"),
+		c.Parent().String("username"),
+		c.Parent().String("password"),
+		c.Parent().String("ca-cert"),
+		backupManager,
+		selection,
+		c.App.Version,
+		logger)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	plan, planErr := planner.Plan(ctx, deployment, artifactPath)
+	if planErr != nil {
+		return processError(planErr)
+	}
+
+	return printPlan(plan, c.Bool("json"))
+}
@@ -18,11 +18,14 @@ func (d DeploymentRestoreCleanupCommand) Cli() cli.Command {
 		Name:   "restore-cleanup",
 		Usage:  "Cleanup a deployment after a restore was interrupted",
 		Action: d.Action,
+		Flags:  []cli.Flag{cleanupOnInterruptFlag()},
 	}
 }
 
 func (d DeploymentRestoreCleanupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	if err := trapSigint(true, c.String("on-interrupt")); err != nil {
+		return processError(orchestrator.NewError(err))
+	}
 
 	cleaner, err := factory.BuildDeploymentRestoreCleanuper(c.Parent().String("target"),
 		c.Parent().String("username"),
@@ -3,7 +3,9 @@ package command
 import (
 	"time"
 
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/urfave/cli"
 )
 
@@ -15,36 +17,98 @@ func NewDirectorBackupCommand() DirectorBackupCommand {
 }
 
 func (checkCommand DirectorBackupCommand) Cli() cli.Command {
+	cliFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "artifact-path, a",
+			Usage: "Specify an optional path to save the backup artifacts to",
+		},
+		cli.StringFlag{
+			Name:  "artifact-url",
+			Usage: "Specify an optional s3://bucket/prefix URL to stream the backup artifacts to, instead of a local path",
+		},
+		cli.StringFlag{
+			Name:  "compression",
+			Value: backup.NoCompression,
+			Usage: "Compression to apply to the backup artifacts: none or gzip",
+		},
+		cli.StringFlag{
+			Name:  "dedup-store",
+			Usage: "Specify an optional path to a chunk store shared between backups, so that unchanged files are only stored once",
+		},
+		cli.StringFlag{
+			Name:  "signing-key",
+			Usage: "Path to a PEM encoded ed25519 private key to sign the backup metadata with",
+		},
+	}
+	cliFlags = append(cliFlags, onInterruptFlag())
+	cliFlags = append(cliFlags, eventsFlag())
+	cliFlags = append(cliFlags, scriptTimeoutFlags(backupScriptNames)...)
+	cliFlags = append(cliFlags, retryFlags()...)
+	cliFlags = append(cliFlags, encryptionFlags()...)
+
 	return cli.Command{
 		Name:    "backup",
 		Aliases: []string{"b"},
 		Usage:   "Backup a BOSH Director",
 		Action:  checkCommand.Action,
-		Flags: []cli.Flag{
-			cli.StringFlag{
-				Name:  "artifact-path, a",
-				Usage: "Specify an optional path to save the backup artifacts to",
-			},
-		},
+		Flags:   cliFlags,
 	}
-
 }
 
 func (checkCommand DirectorBackupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	ctx, err := trapInterrupt(true, c.String("on-interrupt"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
 
 	directorName := extractNameFromAddress(c.Parent().String("host"))
 	timeStamp := time.Now().UTC().Format(artifactTimeStampFormat)
 
+	if err := validateArtifactLocation(c); err != nil {
+		return err
+	}
+
+	backupManager, err := factory.BuildBackupManager(factory.BackupManagerOptions{
+		ArtifactURL:          c.String("artifact-url"),
+		DedupStorePath:       c.String("dedup-store"),
+		Compression:          c.String("compression"),
+		EncryptionKeyFile:    c.String("encryption-key-file"),
+		EncryptionPassphrase: c.String("encryption-passphrase"),
+		SigningKeyFile:       c.String("signing-key"),
+	})
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	timeouts, err := scriptTimeouts(c, backupScriptNames)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	retries, err := retryPolicy(c)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	observer, closeEvents, err := eventObserver(c)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+	defer closeEvents()
+
 	backuper := factory.BuildDirectorBackuper(
 		c.Parent().String("host"),
 		c.Parent().String("username"),
 		c.Parent().String("private-key-path"),
+		backupManager,
 		c.App.Version,
-		c.GlobalBool("debug"),
+		timeouts,
+		retries,
+		observer,
+		buildWorkflowLogger(c, c.GlobalBool("debug")),
 		timeStamp)
 
-	backupErr := backuper.Backup(directorName, c.String("artifact-path"))
+	backupErr := backuper.Backup(ctx, directorName, c.String("artifact-path"))
 
 	if backupErr.ContainsUnlockOrCleanupOrArtifactDirExists() {
 		return processErrorWithFooter(backupErr, backupCleanupAdvisedNotice)
@@ -2,6 +2,7 @@ package command
 
 import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/urfave/cli"
 )
 
@@ -16,11 +17,14 @@ func (d DirectorBackupCleanupCommand) Cli() cli.Command {
 		Name:   "backup-cleanup",
 		Usage:  "Cleanup a director after a backup was interrupted",
 		Action: d.Action,
+		Flags:  []cli.Flag{cleanupOnInterruptFlag()},
 	}
 }
 
 func (d DirectorBackupCleanupCommand) Action(c *cli.Context) error {
-	trapSigint(true)
+	if err := trapSigint(true, c.String("on-interrupt")); err != nil {
+		return processError(orchestrator.NewError(err))
+	}
 
 	directorName := extractNameFromAddress(c.Parent().String("host"))
 
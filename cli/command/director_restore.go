@@ -3,6 +3,7 @@ package command
 import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/cli/flags"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/urfave/cli"
 )
 
@@ -14,38 +15,96 @@ func NewDirectorRestoreCommand() DirectorRestoreCommand {
 }
 
 func (cmd DirectorRestoreCommand) Cli() cli.Command {
+	cliFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "artifact-path, a",
+			Usage: "Path to the artifact to restore",
+		},
+		cli.StringFlag{
+			Name:  "artifact-url",
+			Usage: "s3://bucket/prefix URL of the artifact to restore. Omit if '--artifact-path' is provided",
+		},
+		cli.StringFlag{
+			Name:  "verify-signature",
+			Usage: "Path to a PEM encoded ed25519 public key. The restore fails unless the backup metadata was signed with the matching private key",
+		},
+	}
+	cliFlags = append(cliFlags, onInterruptFlag())
+	cliFlags = append(cliFlags, eventsFlag())
+	cliFlags = append(cliFlags, scriptTimeoutFlags(restoreScriptNames)...)
+	cliFlags = append(cliFlags, retryFlags()...)
+	cliFlags = append(cliFlags, encryptionFlags()...)
+
 	return cli.Command{
 		Name:    "restore",
 		Aliases: []string{"r"},
 		Usage:   "Restore a deployment from backup",
 		Action:  cmd.Action,
-		Flags: []cli.Flag{
-			cli.StringFlag{
-				Name:  "artifact-path, a",
-				Usage: "Path to the artifact to restore",
-			},
-		},
+		Flags:   cliFlags,
 	}
 }
 
 func (cmd DirectorRestoreCommand) Action(c *cli.Context) error {
-	trapSigint(false)
+	ctx, err := trapInterrupt(false, c.String("on-interrupt"))
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
 
-	if err := flags.Validate([]string{"artifact-path"}, c); err != nil {
-		return err
+	if c.String("artifact-url") == "" {
+		if err := flags.Validate([]string{"artifact-path"}, c); err != nil {
+			return err
+		}
 	}
 
 	directorName := extractNameFromAddress(c.Parent().String("host"))
 	artifactPath := c.String("artifact-path")
 
+	if err := validateArtifactLocation(c); err != nil {
+		return err
+	}
+
+	backupManager, err := factory.BuildBackupManager(factory.BackupManagerOptions{
+		ArtifactURL:          c.String("artifact-url"),
+		EncryptionKeyFile:    c.String("encryption-key-file"),
+		EncryptionPassphrase: c.String("encryption-passphrase"),
+		TrustedKeyFile:       c.String("verify-signature"),
+	})
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	timeouts, err := scriptTimeouts(c, restoreScriptNames)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	retries, err := retryPolicy(c)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+
+	observer, closeEvents, err := eventObserver(c)
+	if err != nil {
+		return processError(orchestrator.NewError(err))
+	}
+	defer closeEvents()
+
 	restorer := factory.BuildDirectorRestorer(
 		c.Parent().String("host"),
 		c.Parent().String("username"),
 		c.Parent().String("private-key-path"),
+		backupManager,
 		c.App.Version,
-		c.GlobalBool("debug"),
+		timeouts,
+		retries,
+		observer,
+		buildWorkflowLogger(c, c.GlobalBool("debug")),
 	)
 
-	restoreErr := restorer.Restore(directorName, artifactPath)
+	restoreErr := restorer.Restore(ctx, directorName, artifactPath)
+	if restoreErr.IsPostRestoreValidation() {
+		return processErrorWithFooter(restoreErr, restoreValidationFailedNotice)
+	}
+
 	return processError(restoreErr)
 }
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func eventsFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "events",
+		Usage: "Write the steps, job scripts, transfers and checksum results as newline-delimited JSON to this file, or to stdout with '--events=-', which moves the log to stderr",
+	}
+}
+
+// eventObserver builds the observer asked for with '--events'. It returns a nil
+// observer when no events were asked for, and a function to call once the
+// workflows have finished, which closes the events file and reports any failure
+// to write the events.
+func eventObserver(c *cli.Context) (orchestrator.Observer, func(), error) {
+	path := c.String("events")
+	if path == "" {
+		return nil, func() {}, nil
+	}
+
+	if eventsToStdout(c) {
+		observer := orchestrator.NewNDJSONObserver(os.Stdout, time.Now)
+		return observer, func() { reportEventsError(observer.Err()) }, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create events file")
+	}
+
+	observer := orchestrator.NewNDJSONObserver(file, time.Now)
+	return observer, func() {
+		writeErr := observer.Err()
+		if closeErr := file.Close(); writeErr == nil {
+			writeErr = closeErr
+		}
+		reportEventsError(writeErr)
+	}, nil
+}
+
+func reportEventsError(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: the event stream is incomplete, failed to write events: %s\n", err)
+	}
+}
+
+func eventsToStdout(c *cli.Context) bool {
+	return c.String("events") == "-"
+}
+
+// buildWorkflowLogger keeps the log out of stdout when the events are streamed
+// to stdout, so that the stream can be parsed.
+func buildWorkflowLogger(c *cli.Context, debug bool) boshlog.Logger {
+	if eventsToStdout(c) {
+		return factory.BuildBoshStderrLogger(debug)
+	}
+	return factory.BuildBoshLogger(debug)
+}
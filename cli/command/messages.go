@@ -3,8 +3,13 @@ package command
 const backupSigintQuestion = "Stopping a backup can leave the system in bad state. Are you sure you want to cancel? [yes/no]"
 const backupStdinErrorMessage = "Couldn't read from Stdin, if you still want to stop the backup send SIGTERM."
 const backupCleanupAdvisedNotice = "It is recommended that you run `bbr backup-cleanup` to ensure that any temp files are cleaned up and all jobs are unlocked."
+const backupAbortNotice = "Stopping the backup. The running scripts are being aborted, then the jobs will be unlocked and cleaned up. Interrupt again to exit straight away."
+const backupInterruptIgnoredNotice = "Ignoring the interrupt as '--on-interrupt' is ignore. Send SIGTERM to stop the backup."
 const backupCleanupAllDeploymentsAdvisedNotice = "It is recommended that you run `bbr deployment --all-deployments backup-cleanup` to ensure that any temp files are cleaned up and all jobs are unlocked."
 
 const restoreSigintQuestion = "Stopping a restore can leave the system in bad state. Are you sure you want to cancel? [yes/no]"
 const restoreStdinErrorMessage = "Couldn't read from Stdin, if you still want to stop the restore send SIGTERM."
 const restoreCleanupAdvisedNotice = "It is recommended that you run `bbr restore-cleanup` to ensure that any temp files are cleaned up and all jobs are unlocked."
+const restoreAbortNotice = "Stopping the restore. The running scripts are being aborted, then the jobs will be unlocked and cleaned up. Interrupt again to exit straight away."
+const restoreInterruptIgnoredNotice = "Ignoring the interrupt as '--on-interrupt' is ignore. Send SIGTERM to stop the restore."
+const restoreValidationFailedNotice = "The restore completed and all jobs were unlocked, but the post-restore-validate scripts reported that the restored data is not healthy."
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func dryRunFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Print what bbr would do, in order, without running any scripts",
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "Print the dry-run plan as JSON",
+		},
+	}
+}
+
+// validateDryRunFlags rejects the flags that have no effect on a dry run, as
+// no scripts run and so no events are emitted.
+func validateDryRunFlags(c *cli.Context) error {
+	if c.String("events") != "" {
+		return errors.New("'--events' cannot be used with '--dry-run'")
+	}
+	return nil
+}
+
+// buildPlanLogger keeps the log out of stdout when the plan is printed as
+// JSON, so that the output can be parsed.
+func buildPlanLogger(c *cli.Context, debug bool) boshlog.Logger {
+	if c.Bool("json") {
+		return factory.BuildBoshLoggerWithCustomWriter(os.Stderr, debug)
+	}
+	return factory.BuildBoshLogger(debug)
+}
+
+func printPlan(plan orchestrator.Plan, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+
+	fmt.Printf("Dry run of %s of %s, no scripts were run.\n", plan.Operation, plan.Deployment)
+
+	fmt.Println("Lock order:")
+	printJobBatches(plan.LockOrder)
+
+	fmt.Printf("Jobs with %s scripts:\n", plan.Operation)
+	for _, instance := range plan.Scripts {
+		fmt.Printf("  %s (%s): %s\n", instance.Instance, instance.ID, strings.Join(instance.Jobs, ", "))
+	}
+
+	if plan.Operation == "restore" {
+		fmt.Println("Artifacts to copy to the instances:")
+	} else {
+		fmt.Println("Artifacts to drain:")
+	}
+	for _, artifact := range plan.Artifacts {
+		name := artifact.Name
+		if artifact.Instance != "" {
+			name = fmt.Sprintf("%s %s", artifact.Instance, artifact.Name)
+		}
+		size := artifact.Size
+		if size == "" {
+			size = "size unknown"
+		}
+		fmt.Printf("  %s (%s)\n", name, size)
+	}
+
+	fmt.Println("Unlock order:")
+	printJobBatches(plan.UnlockOrder)
+	return nil
+}
+
+func printJobBatches(batches [][]orchestrator.PlannedJob) {
+	for i, batch := range batches {
+		var jobs []string
+		for _, job := range batch {
+			jobs = append(jobs, fmt.Sprintf("%s %s", job.Instance, job.Job))
+		}
+		fmt.Printf("  %d. %s\n", i+1, strings.Join(jobs, ", "))
+	}
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
+	"github.com/urfave/cli"
+)
+
+func retryFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.IntFlag{
+			Name:  "retry-attempts",
+			Value: 1,
+			Usage: "How many times to try copying each artifact, and running read-only commands on the instances, before giving up",
+		},
+		cli.DurationFlag{
+			Name:  "retry-backoff",
+			Value: 5 * time.Second,
+			Usage: "How long to wait before the first retry. The wait doubles for each retry after it",
+		},
+		cli.StringFlag{
+			Name:  "retry-on",
+			Value: string(retry.ConnectionErrors),
+			Usage: "Comma separated classes of error to retry: connection, checksum",
+		},
+	}
+}
+
+func retryPolicy(c *cli.Context) (retry.Policy, error) {
+	var errorClasses []string
+	for _, errorClass := range strings.Split(c.String("retry-on"), ",") {
+		if errorClass = strings.TrimSpace(errorClass); errorClass != "" {
+			errorClasses = append(errorClasses, errorClass)
+		}
+	}
+
+	return retry.NewPolicy(c.Int("retry-attempts"), c.Duration("retry-backoff"), errorClasses)
+}
@@ -0,0 +1,41 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/urfave/cli"
+)
+
+var (
+	backupScriptNames  = []string{"pre-backup-lock", "backup", "post-backup-unlock"}
+	restoreScriptNames = []string{"pre-restore-lock", "restore", "post-restore-unlock", "post-restore-validate"}
+)
+
+func scriptTimeoutFlags(scriptNames []string) []cli.Flag {
+	var flags []cli.Flag
+	for _, scriptName := range scriptNames {
+		flags = append(flags, cli.DurationFlag{
+			Name:  scriptName + "-timeout",
+			Usage: fmt.Sprintf("Kill %s scripts that run for longer than this, e.g. 30m, unless the job's metadata sets its own timeout. 0 means no timeout", scriptName),
+		})
+	}
+	return flags
+}
+
+// scriptTimeouts reads the timeouts set by scriptTimeoutFlags. Scripts whose
+// flag was not set are left out, so that they are not timed out.
+func scriptTimeouts(c *cli.Context, scriptNames []string) (instance.ScriptTimeouts, error) {
+	timeouts := instance.ScriptTimeouts{}
+	for _, scriptName := range scriptNames {
+		if timeout := c.Duration(scriptName + "-timeout"); timeout != 0 {
+			timeouts[scriptName] = timeout
+		}
+	}
+
+	if err := timeouts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return timeouts, nil
+}
@@ -2,10 +2,12 @@ package command
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"strings"
+	"syscall"
 
 	"io/ioutil"
 	"time"
@@ -14,43 +16,169 @@ import (
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/factory"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
 
 const defaultLogfilePermissions = 0644
 
-func trapSigint(backup bool) {
-	sigintChan := make(chan os.Signal, 1)
-	signal.Notify(sigintChan, os.Interrupt)
+const (
+	onInterruptPrompt = "prompt"
+	onInterruptAbort  = "abort"
+	onInterruptIgnore = "ignore"
+)
+
+const onInterruptUsage = "What to do on SIGINT: prompt to confirm stopping, abort straight away or ignore it."
+
+func onInterruptFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "on-interrupt",
+		Value: onInterruptPrompt,
+		Usage: onInterruptUsage + " Stopping aborts the running scripts, then unlocks and cleans up",
+	}
+}
+
+func cleanupOnInterruptFlag() cli.Flag {
+	return cli.StringFlag{
+		Name:  "on-interrupt",
+		Value: onInterruptPrompt,
+		Usage: onInterruptUsage + " Stopping exits straight away",
+	}
+}
+
+type interruptNotices struct {
+	question       string
+	stdinError     string
+	cleanupAdvised string
+	abort          string
+	ignored        string
+}
 
-	var sigintQuestion, stdInErrorMessage, cleanupAdvisedNotice string
+func interruptNoticesFor(backup bool) interruptNotices {
 	if backup {
-		sigintQuestion = backupSigintQuestion
-		stdInErrorMessage = backupStdinErrorMessage
-		cleanupAdvisedNotice = backupCleanupAdvisedNotice
-	} else {
-		sigintQuestion = restoreSigintQuestion
-		stdInErrorMessage = restoreStdinErrorMessage
-		cleanupAdvisedNotice = restoreCleanupAdvisedNotice
+		return interruptNotices{
+			question:       backupSigintQuestion,
+			stdinError:     backupStdinErrorMessage,
+			cleanupAdvised: backupCleanupAdvisedNotice,
+			abort:          backupAbortNotice,
+			ignored:        backupInterruptIgnoredNotice,
+		}
+	}
+	return interruptNotices{
+		question:       restoreSigintQuestion,
+		stdinError:     restoreStdinErrorMessage,
+		cleanupAdvised: restoreCleanupAdvisedNotice,
+		abort:          restoreAbortNotice,
+		ignored:        restoreInterruptIgnoredNotice,
+	}
+}
+
+// trapInterrupt returns a context that is cancelled when the backup or restore
+// should stop. SIGTERM always cancels it, while SIGINT prompts, cancels or is
+// ignored depending on onInterrupt. Once the context is cancelled the workflow
+// unlocks and cleans up, and a further signal exits straight away.
+func trapInterrupt(backup bool, onInterrupt string) (context.Context, error) {
+	notices := interruptNoticesFor(backup)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := trapSignals(notices, onInterrupt, func() {
+		fmt.Println("\n" + notices.abort)
+		cancel()
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return ctx, nil
+}
+
+// trapSigint is trapInterrupt for the commands that cannot be aborted part way
+// through, such as the cleanups: stopping them exits straight away.
+func trapSigint(backup bool, onInterrupt string) error {
+	notices := interruptNoticesFor(backup)
+
+	return trapSignals(notices, onInterrupt, func() {
+		fmt.Println("\n" + notices.cleanupAdvised)
+		os.Exit(1)
+	})
+}
+
+// trapSignals calls stop on SIGTERM, or on SIGINT as onInterrupt decides. Any
+// signal after that exits straight away.
+func trapSignals(notices interruptNotices, onInterrupt string, stop func()) error {
+	switch onInterrupt {
+	case onInterruptPrompt, onInterruptAbort, onInterruptIgnore:
+	default:
+		return errors.Errorf("'--on-interrupt' must be one of %s, %s or %s", onInterruptPrompt, onInterruptAbort, onInterruptIgnore)
 	}
 
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+
 	go func() {
-		for range sigintChan {
-			stdinReader := bufio.NewReader(os.Stdin)
-			factory.ApplicationLoggerStdout.Pause()
-			factory.ApplicationLoggerStderr.Pause()
-			fmt.Fprintln(os.Stdout, "\n"+sigintQuestion)
-			input, err := stdinReader.ReadString('\n')
-			if err != nil {
-				fmt.Println("\n" + stdInErrorMessage)
-			} else if strings.ToLower(strings.TrimSpace(input)) == "yes" {
-				fmt.Println(cleanupAdvisedNotice)
+		stopped := false
+		for sig := range signalChan {
+			if stopped {
+				fmt.Println("\n" + notices.cleanupAdvised)
 				os.Exit(1)
 			}
-			factory.ApplicationLoggerStdout.Resume()
-			factory.ApplicationLoggerStderr.Resume()
+
+			if sig == os.Interrupt {
+				switch onInterrupt {
+				case onInterruptIgnore:
+					fmt.Println("\n" + notices.ignored)
+					continue
+				case onInterruptPrompt:
+					if !confirmInterrupt(notices.question, notices.stdinError) {
+						continue
+					}
+				}
+			}
+
+			stopped = true
+			stop()
 		}
 	}()
+
+	return nil
+}
+
+func confirmInterrupt(question, stdInErrorMessage string) bool {
+	factory.ApplicationLoggerStdout.Pause()
+	factory.ApplicationLoggerStderr.Pause()
+	defer factory.ApplicationLoggerStdout.Resume()
+	defer factory.ApplicationLoggerStderr.Resume()
+
+	stdinReader := bufio.NewReader(os.Stdin)
+	fmt.Fprintln(os.Stdout, "\n"+question)
+	input, err := stdinReader.ReadString('\n')
+	if err != nil {
+		fmt.Println("\n" + stdInErrorMessage)
+		return false
+	}
+	return strings.ToLower(strings.TrimSpace(input)) == "yes"
+}
+
+func encryptionFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  "encryption-key-file",
+			Usage: "Path to a file containing the key used to encrypt and decrypt the backup artifacts",
+		},
+		cli.StringFlag{
+			Name:   "encryption-passphrase",
+			EnvVar: "BBR_ENCRYPTION_PASSPHRASE",
+			Usage:  "Passphrase used to encrypt and decrypt the backup artifacts",
+		},
+	}
+}
+
+func validateArtifactLocation(c *cli.Context) error {
+	if c.String("artifact-path") != "" && c.String("artifact-url") != "" {
+		return processError(orchestrator.NewError(errors.New("only one of '--artifact-path' and '--artifact-url' can be provided")))
+	}
+	return nil
 }
 
 func processError(err orchestrator.Error) error {
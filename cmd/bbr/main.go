@@ -53,6 +53,25 @@ func main() {
 				command.NewDirectorRestoreCleanupCommand().Cli(),
 			},
 		},
+		{
+			Name:  "artifact",
+			Usage: "Manage backup artifacts",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "debug",
+					Usage: "Enable debug logs",
+				},
+			},
+			Subcommands: []cli.Command{
+				command.NewArtifactGarbageCollectCommand().Cli(),
+				command.NewArtifactPruneCommand().Cli(),
+				command.NewArtifactListCommand().Cli(),
+				command.NewArtifactVerifyCommand().Cli(),
+				command.NewArtifactDiffCommand().Cli(),
+				command.NewArtifactCopyCommand().Cli(),
+				command.NewArtifactExtractCommand().Cli(),
+			},
+		},
 		{
 			Name:    "help",
 			Aliases: []string{"h"},
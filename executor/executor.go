@@ -1,11 +1,13 @@
 package executor
 
+import "context"
+
 //go:generate counterfeiter -o fakes/fake_executor.go . Executor
 type Executor interface {
-	Run([][]Executable) []error
+	Run(context.Context, [][]Executable) []error
 }
 
 //go:generate counterfeiter -o fakes/fake_executable.go . Executable
 type Executable interface {
-	Execute() error
+	Execute(context.Context) error
 }
@@ -1,6 +1,8 @@
 package executor_test
 
 import (
+	"context"
+
 	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/fakes"
@@ -15,35 +17,38 @@ var _ = Describe("Executor", func() {
 			var errs []error
 			var executable1, executable2, executable3, executable4 *fakes.FakeExecutable
 			var orderOfExecution []string
+			var ctx context.Context
 
 			BeforeEach(func() {
+				ctx = context.Background()
+
 				executable1 = new(fakes.FakeExecutable)
-				executable1.ExecuteStub = func() error {
+				executable1.ExecuteStub = func(context.Context) error {
 					orderOfExecution = append(orderOfExecution, "executable1")
 					return nil
 				}
 
 				executable2 = new(fakes.FakeExecutable)
-				executable2.ExecuteStub = func() error {
+				executable2.ExecuteStub = func(context.Context) error {
 					orderOfExecution = append(orderOfExecution, "executable2")
 					return nil
 				}
 
 				executable3 = new(fakes.FakeExecutable)
-				executable3.ExecuteStub = func() error {
+				executable3.ExecuteStub = func(context.Context) error {
 					orderOfExecution = append(orderOfExecution, "executable3")
 					return nil
 				}
 
 				executable4 = new(fakes.FakeExecutable)
-				executable4.ExecuteStub = func() error {
+				executable4.ExecuteStub = func(context.Context) error {
 					orderOfExecution = append(orderOfExecution, "executable4")
 					return nil
 				}
 			})
 
 			JustBeforeEach(func() {
-				errs = executor.Run([][]Executable{
+				errs = executor.Run(ctx, [][]Executable{
 					{executable1},
 					{executable2, executable3},
 					{executable4},
@@ -81,6 +86,29 @@ var _ = Describe("Executor", func() {
 					Expect(executable4.ExecuteCallCount()).To(Equal(1))
 				})
 			})
+
+			Context("when the context is cancelled while an executable runs", func() {
+				BeforeEach(func() {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithCancel(context.Background())
+					executable1.ExecuteStub = func(executableCtx context.Context) error {
+						cancel()
+						return executableCtx.Err()
+					}
+				})
+
+				It("passes the context on and does not start the remaining executables", func() {
+					Expect(errs).To(ConsistOf(
+						MatchError(context.Canceled),
+						MatchError(context.Canceled),
+					))
+
+					Expect(executable1.ExecuteCallCount()).To(Equal(1))
+					Expect(executable2.ExecuteCallCount()).To(Equal(0))
+					Expect(executable3.ExecuteCallCount()).To(Equal(0))
+					Expect(executable4.ExecuteCallCount()).To(Equal(0))
+				})
+			})
 		})
 	}
 
@@ -2,15 +2,17 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 )
 
 type FakeExecutable struct {
-	ExecuteStub        func() error
+	ExecuteStub        func(context.Context) error
 	executeMutex       sync.RWMutex
 	executeArgsForCall []struct {
+		arg1 context.Context
 	}
 	executeReturns struct {
 		result1 error
@@ -22,15 +24,16 @@ type FakeExecutable struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeExecutable) Execute() error {
+func (fake *FakeExecutable) Execute(arg1 context.Context) error {
 	fake.executeMutex.Lock()
 	ret, specificReturn := fake.executeReturnsOnCall[len(fake.executeArgsForCall)]
 	fake.executeArgsForCall = append(fake.executeArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Execute", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Execute", []interface{}{arg1})
 	fake.executeMutex.Unlock()
 	if fake.ExecuteStub != nil {
-		return fake.ExecuteStub()
+		return fake.ExecuteStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -45,12 +48,19 @@ func (fake *FakeExecutable) ExecuteCallCount() int {
 	return len(fake.executeArgsForCall)
 }
 
-func (fake *FakeExecutable) ExecuteCalls(stub func() error) {
+func (fake *FakeExecutable) ExecuteCalls(stub func(context.Context) error) {
 	fake.executeMutex.Lock()
 	defer fake.executeMutex.Unlock()
 	fake.ExecuteStub = stub
 }
 
+func (fake *FakeExecutable) ExecuteArgsForCall(i int) context.Context {
+	fake.executeMutex.RLock()
+	defer fake.executeMutex.RUnlock()
+	argsForCall := fake.executeArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeExecutable) ExecuteReturns(result1 error) {
 	fake.executeMutex.Lock()
 	defer fake.executeMutex.Unlock()
@@ -1,5 +1,7 @@
 package executor
 
+import "context"
+
 func NewParallelExecutor() ParallelExecutor {
 	return ParallelExecutor{
 		maxInFlight: 10,
@@ -14,26 +16,44 @@ func (s ParallelExecutor) SetMaxInFlight(maxInFlight int) {
 	s.maxInFlight = maxInFlight
 }
 
-func (s ParallelExecutor) Run(executablesList [][]Executable) []error {
+// Run runs each list of executables in parallel, one list after the other.
+// Once the context is cancelled no more executables are started; the ones that
+// are already running are left to return.
+func (s ParallelExecutor) Run(ctx context.Context, executablesList [][]Executable) []error {
 	var errors []error
 	for _, executables := range executablesList {
+		if ctx.Err() != nil {
+			return append(errors, ctx.Err())
+		}
+
 		guard := make(chan bool, s.maxInFlight)
 		errs := make(chan error, len(executables))
 
+		started := 0
 		for _, executable := range executables {
 			guard <- true
+			if ctx.Err() != nil {
+				<-guard
+				break
+			}
+
+			started++
 			go func(executable Executable) {
-				errs <- executable.Execute()
+				errs <- executable.Execute(ctx)
 				<-guard
 			}(executable)
 		}
 
-		for range executables {
+		for i := 0; i < started; i++ {
 			err := <-errs
 			if err != nil {
 				errors = append(errors, err)
 			}
 		}
+
+		if started < len(executables) {
+			return append(errors, ctx.Err())
+		}
 	}
 
 	return errors
@@ -0,0 +1,106 @@
+package factory
+
+import (
+	"crypto/ed25519"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// BackupManagerOptions are the command line flags that decide where backups
+// are stored and how. Commands leave the ones they do not support empty.
+type BackupManagerOptions struct {
+	ArtifactURL          string
+	DedupStorePath       string
+	IncrementalFrom      string
+	Compression          string
+	EncryptionKeyFile    string
+	EncryptionPassphrase string
+	SigningKeyFile       string
+	TrustedKeyFile       string
+}
+
+func BuildBackupManager(options BackupManagerOptions) (orchestrator.BackupManager, error) {
+	if err := backup.ValidateCompression(options.Compression); err != nil {
+		return nil, err
+	}
+
+	var signingKey ed25519.PrivateKey
+	if options.SigningKeyFile != "" {
+		var err error
+		if signingKey, err = backup.LoadSigningKey(options.SigningKeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var trustedKey ed25519.PublicKey
+	if options.TrustedKeyFile != "" {
+		var err error
+		if trustedKey, err = backup.LoadTrustedKey(options.TrustedKeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.IncrementalFrom != "" && options.ArtifactURL != "" {
+		return nil, errors.New("incremental backups cannot be used with an artifact url")
+	}
+
+	if options.DedupStorePath != "" {
+		if options.ArtifactURL != "" {
+			return nil, errors.New("a deduplicating store cannot be used with an artifact url")
+		}
+		if (options.Compression != "" && options.Compression != backup.NoCompression) || options.EncryptionKeyFile != "" || options.EncryptionPassphrase != "" {
+			return nil, errors.New("a deduplicating store cannot be used with compression or encryption")
+		}
+		if options.TrustedKeyFile != "" {
+			return nil, errors.New("a deduplicating store cannot verify backup signatures")
+		}
+		manager, err := backup.NewDedupBackupManager(options.DedupStorePath)
+		if err != nil {
+			return nil, err
+		}
+		manager.IncrementalFrom = options.IncrementalFrom
+		manager.SigningKey = signingKey
+		return manager, nil
+	}
+
+	encryptionKey, err := BuildEncryptionKey(options.EncryptionKeyFile, options.EncryptionPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ArtifactURL != "" {
+		manager, err := backup.NewS3BackupManager(options.ArtifactURL, options.Compression, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		manager.SigningKey = signingKey
+		manager.TrustedKey = trustedKey
+		return manager, nil
+	}
+
+	return backup.BackupDirectoryManager{
+		Compression:     options.Compression,
+		EncryptionKey:   encryptionKey,
+		IncrementalFrom: options.IncrementalFrom,
+		SigningKey:      signingKey,
+		TrustedKey:      trustedKey,
+	}, nil
+}
+
+// BuildEncryptionKey returns nil when neither a key file nor a passphrase is
+// provided.
+func BuildEncryptionKey(encryptionKeyFile, encryptionPassphrase string) (*backup.EncryptionKey, error) {
+	if encryptionKeyFile != "" && encryptionPassphrase != "" {
+		return nil, errors.New("only one of encryption key file and encryption passphrase can be provided")
+	}
+
+	if encryptionKeyFile != "" {
+		return backup.NewEncryptionKeyFromFile(encryptionKeyFile)
+	}
+	if encryptionPassphrase != "" {
+		return backup.NewEncryptionKeyFromPassphrase(encryptionPassphrase), nil
+	}
+	return nil, nil
+}
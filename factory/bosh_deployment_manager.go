@@ -2,13 +2,15 @@ package factory
 
 import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 
 	boshcmd "github.com/cloudfoundry/bosh-cli/cmd/opts"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 )
 
-func BuildBoshClient(targetUrl, username, password, caCertPathOrValue, bbrVersion string, logger boshlog.Logger) (bosh.Client, error) {
+func BuildBoshClient(targetUrl, username, password, caCertPathOrValue, bbrVersion string, scriptTimeouts instance.ScriptTimeouts, retryPolicy retry.Policy, logger boshlog.Logger) (bosh.Client, error) {
 	var boshClient bosh.Client
 	var err error
 	fs := boshsys.NewOsFileSystem(logger)
@@ -20,7 +22,7 @@ func BuildBoshClient(targetUrl, username, password, caCertPathOrValue, bbrVersio
 		return boshClient, err
 	}
 
-	boshClient, err = bosh.BuildClient(targetUrl, username, password, caCertArg.Content, bbrVersion, logger)
+	boshClient, err = bosh.BuildClient(targetUrl, username, password, caCertArg.Content, bbrVersion, scriptTimeouts, retryPolicy, logger)
 	if err != nil {
 		return boshClient, err
 	}
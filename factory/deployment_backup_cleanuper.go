@@ -5,6 +5,7 @@ import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	"github.com/cloudfoundry/bosh-utils/logger"
 )
 
@@ -17,7 +18,7 @@ func BuildDeploymentBackupCleanuper(
 	logger logger.Logger,
 ) (*orchestrator.BackupCleaner, error) {
 
-	boshClient, err := BuildBoshClient(target, username, password, caCert, bbrVersion, logger)
+	boshClient, err := BuildBoshClient(target, username, password, caCert, bbrVersion, nil, retry.Policy{}, logger)
 
 	if err != nil {
 		return nil, err
@@ -3,11 +3,12 @@ package factory
 import (
 	"time"
 
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 )
 
@@ -17,11 +18,16 @@ func BuildDeploymentBackuper(
 	password,
 	caCert string,
 	withManifest bool,
+	backupManager orchestrator.BackupManager,
 	bbrVersion string,
+	scriptTimeouts instance.ScriptTimeouts,
+	maxLockDuration time.Duration,
+	retryPolicy retry.Policy,
+	observer orchestrator.Observer,
 	logger boshlog.Logger,
 	timestamp string,
 ) (*orchestrator.Backuper, error) {
-	boshClient, err := BuildBoshClient(target, username, password, caCert, bbrVersion, logger)
+	boshClient, err := BuildBoshClient(target, username, password, caCert, bbrVersion, scriptTimeouts, retryPolicy, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -29,13 +35,16 @@ func BuildDeploymentBackuper(
 	execr := executor.NewParallelExecutor()
 
 	return orchestrator.NewBackuper(
-		backup.BackupDirectoryManager{},
+		backupManager,
 		logger,
 		bosh.NewDeploymentManager(boshClient, logger, withManifest),
 		orderer.NewKahnBackupLockOrderer(),
 		execr,
 		time.Now,
-		orchestrator.NewArtifactCopier(execr, logger),
+		orchestrator.NewArtifactCopier(execr, retryPolicy, logger),
 		timestamp,
+		bbrVersion,
+		maxLockDuration,
+		observer,
 	), nil
 }
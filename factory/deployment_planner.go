@@ -0,0 +1,27 @@
+package factory
+
+import (
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+)
+
+func BuildDeploymentBackupPlanner(target, username, password, caCert, bbrVersion string, logger boshlog.Logger) (*orchestrator.Planner, error) {
+	boshClient, err := BuildBoshClient(target, username, password, caCert, bbrVersion, nil, retry.Policy{}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return orchestrator.NewBackupPlanner(logger, bosh.NewDeploymentManager(boshClient, logger, false), orderer.NewKahnBackupLockOrderer()), nil
+}
+
+func BuildDeploymentRestorePlanner(target, username, password, caCert string, backupManager orchestrator.BackupManager, selection orchestrator.RestoreSelection, bbrVersion string, logger boshlog.Logger) (*orchestrator.Planner, error) {
+	boshClient, err := BuildBoshClient(target, username, password, caCert, bbrVersion, nil, retry.Policy{}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return orchestrator.NewRestorePlanner(logger, backupManager, bosh.NewDeploymentManager(boshClient, logger, false), orderer.NewKahnRestoreLockOrderer(), selection), nil
+}
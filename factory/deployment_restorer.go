@@ -1,21 +1,24 @@
 package factory
 
 import (
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/bosh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 )
 
-func BuildDeploymentRestorer(target, username, password, caCert, bbrVersion string, debug bool) (*orchestrator.Restorer, error) {
-	logger := BuildLogger(debug)
+func BuildDeploymentRestorer(target, username, password, caCert string, backupManager orchestrator.BackupManager, selection orchestrator.RestoreSelection, bbrVersion string, scriptTimeouts instance.ScriptTimeouts, retryPolicy retry.Policy, observer orchestrator.Observer, logger boshlog.Logger) (*orchestrator.Restorer, error) {
 	boshClient, err := BuildBoshClient(
 		target,
 		username,
 		password,
 		caCert,
 		bbrVersion,
+		scriptTimeouts,
+		retryPolicy,
 		logger,
 	)
 	if err != nil {
@@ -23,11 +26,13 @@ func BuildDeploymentRestorer(target, username, password, caCert, bbrVersion stri
 	}
 
 	return orchestrator.NewRestorer(
-		backup.BackupDirectoryManager{},
+		backupManager,
 		logger,
 		bosh.NewDeploymentManager(boshClient, logger, false),
 		orderer.NewKahnRestoreLockOrderer(),
 		executor.NewSerialExecutor(),
-		orchestrator.NewArtifactCopier(executor.NewParallelExecutor(), logger),
+		orchestrator.NewArtifactCopier(executor.NewParallelExecutor(), retryPolicy, logger),
+		selection,
+		observer,
 	), nil
 }
@@ -14,7 +14,7 @@ func BuildDirectorBackupChecker(host, username, privateKeyPath, bbrVersion strin
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinderOmitMetadataReleases(bbrVersion, logger),
+		instance.NewJobFinderOmitMetadataReleases(bbrVersion, nil, logger),
 		ssh.NewSshRemoteRunner,
 	)
 
@@ -20,7 +20,7 @@ func BuildDirectorBackupCleaner(host,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinderOmitMetadataReleases(bbrVersion, logger),
+		instance.NewJobFinderOmitMetadataReleases(bbrVersion, nil, logger),
 		ssh.NewSshRemoteRunner,
 	)
 
@@ -3,34 +3,37 @@ package factory
 import (
 	"time"
 
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/standalone"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 )
 
-func BuildDirectorBackuper(host, username, privateKeyPath, bbrVersion string, hasDebug bool, timeStamp string) *orchestrator.Backuper {
-	logger := BuildLogger(hasDebug)
+func BuildDirectorBackuper(host, username, privateKeyPath string, backupManager orchestrator.BackupManager, bbrVersion string, scriptTimeouts instance.ScriptTimeouts, retryPolicy retry.Policy, observer orchestrator.Observer, logger boshlog.Logger, timeStamp string) *orchestrator.Backuper {
 	deploymentManager := standalone.NewDeploymentManager(logger,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinderOmitMetadataReleases(bbrVersion, logger),
-		ssh.NewSshRemoteRunner,
+		instance.NewJobFinderOmitMetadataReleases(bbrVersion, scriptTimeouts, logger),
+		ssh.NewRetryingRemoteRunnerFactory(ssh.NewSshRemoteRunner, retryPolicy),
 	)
 	execr := executor.NewParallelExecutor()
 
 	return orchestrator.NewBackuper(
-		backup.BackupDirectoryManager{},
+		backupManager,
 		logger,
 		deploymentManager,
 		orderer.NewKahnBackupLockOrderer(),
 		execr,
 		time.Now,
-		orchestrator.NewArtifactCopier(execr, logger),
+		orchestrator.NewArtifactCopier(execr, retryPolicy, logger),
 		timeStamp,
+		bbrVersion,
+		0,
+		observer,
 	)
 }
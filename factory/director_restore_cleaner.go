@@ -21,7 +21,7 @@ func BuildDirectorRestoreCleaner(host,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinderOmitMetadataReleases(bbrVersion, logger),
+		instance.NewJobFinderOmitMetadataReleases(bbrVersion, nil, logger),
 		ssh.NewSshRemoteRunner,
 	)
 
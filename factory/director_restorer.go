@@ -1,31 +1,33 @@
 package factory
 
 import (
-	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/backup"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/standalone"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 )
 
-func BuildDirectorRestorer(host, username, privateKeyPath, bbrVersion string, hasDebug bool) *orchestrator.Restorer {
-	logger := BuildLogger(hasDebug)
+func BuildDirectorRestorer(host, username, privateKeyPath string, backupManager orchestrator.BackupManager, bbrVersion string, scriptTimeouts instance.ScriptTimeouts, retryPolicy retry.Policy, observer orchestrator.Observer, logger boshlog.Logger) *orchestrator.Restorer {
 	deploymentManager := standalone.NewDeploymentManager(logger,
 		host,
 		username,
 		privateKeyPath,
-		instance.NewJobFinderOmitMetadataReleases(bbrVersion, logger),
-		ssh.NewSshRemoteRunner,
+		instance.NewJobFinderOmitMetadataReleases(bbrVersion, scriptTimeouts, logger),
+		ssh.NewRetryingRemoteRunnerFactory(ssh.NewSshRemoteRunner, retryPolicy),
 	)
 
 	return orchestrator.NewRestorer(
-		backup.BackupDirectoryManager{},
+		backupManager,
 		logger,
 		deploymentManager,
 		orderer.NewKahnRestoreLockOrderer(),
 		executor.NewSerialExecutor(),
-		orchestrator.NewArtifactCopier(executor.NewParallelExecutor(), logger),
+		orchestrator.NewArtifactCopier(executor.NewParallelExecutor(), retryPolicy, logger),
+		orchestrator.RestoreSelection{},
+		observer,
 	)
 }
@@ -0,0 +1,22 @@
+package factory
+
+import (
+	"io/ioutil"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/pkg/errors"
+)
+
+// BuildInstanceMapping returns an empty mapping when no mapping file is
+// provided.
+func BuildInstanceMapping(instanceMappingFile string) (orchestrator.InstanceMapping, error) {
+	if instanceMappingFile == "" {
+		return orchestrator.InstanceMapping{}, nil
+	}
+
+	contents, err := ioutil.ReadFile(instanceMappingFile)
+	if err != nil {
+		return orchestrator.InstanceMapping{}, errors.Wrap(err, "failed to read instance mapping")
+	}
+	return orchestrator.ParseInstanceMapping(contents)
+}
@@ -23,6 +23,15 @@ func BuildBoshLogger(debug bool) boshlog.Logger {
 	return boshlog.NewWriterLogger(boshlog.LevelInfo, ApplicationLoggerStdout)
 }
 
+// BuildBoshStderrLogger logs to stderr instead of stdout, for when stdout is
+// used for output that must be parseable.
+func BuildBoshStderrLogger(debug bool) boshlog.Logger {
+	if debug {
+		return boshlog.NewWriterLogger(boshlog.LevelDebug, ApplicationLoggerStderr)
+	}
+	return boshlog.NewWriterLogger(boshlog.LevelInfo, ApplicationLoggerStderr)
+}
+
 func BuildBoshLoggerWithCustomBuffer(debug bool) (boshlog.Logger, *bytes.Buffer) {
 	buffer := new(bytes.Buffer)
 	if debug {
@@ -1,8 +1,11 @@
 package instance
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
@@ -24,7 +27,7 @@ func NewBackupArtifact(job orchestrator.Job, instance orchestrator.InstanceIdent
 	} else {
 		name = job.Name()
 	}
-	return &Artifact{
+	artifact := &Artifact{
 		isNamed:           job.HasNamedBackupArtifact(),
 		artifactDirectory: job.BackupArtifactDirectory(),
 		name:              name,
@@ -32,6 +35,10 @@ func NewBackupArtifact(job orchestrator.Job, instance orchestrator.InstanceIdent
 		remoteRunner:      remoteRunner,
 		Logger:            logger,
 	}
+	if job, ok := job.(Job); ok {
+		artifact.previousChecksums = job.previousChecksums
+	}
+	return artifact
 }
 
 func NewRestoreArtifact(job orchestrator.Job, instance orchestrator.InstanceIdentifer, remoteRunner ssh.RemoteRunner, logger Logger) *Artifact {
@@ -58,12 +65,13 @@ type Artifact struct {
 	name              string
 	instance          orchestrator.InstanceIdentifer
 	Logger
-	remoteRunner ssh.RemoteRunner
+	remoteRunner      ssh.RemoteRunner
+	previousChecksums *previousChecksumsUpload
 }
 
-func (b *Artifact) StreamFromRemote(writer io.Writer) error {
+func (b *Artifact) StreamFromRemote(ctx context.Context, writer io.Writer) error {
 	b.Logger.Debug("bbr", "Streaming backup from instance %s/%s", b.instance.Name(), b.instance.ID())
-	err := b.remoteRunner.ArchiveAndDownload(b.artifactDirectory, writer)
+	err := b.remoteRunner.ArchiveAndDownload(ctx, b.artifactDirectory, writer)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Error streaming backup from remote instance. Error: %s", err.Error()))
 	}
@@ -71,20 +79,58 @@ func (b *Artifact) StreamFromRemote(writer io.Writer) error {
 	return nil
 }
 
-func (b *Artifact) StreamToRemote(reader io.Reader) error {
+func (b *Artifact) StreamFilesFromRemote(ctx context.Context, files []string, writer io.Writer) error {
+	b.Logger.Debug("bbr", "Streaming %d changed files from instance %s/%s", len(files), b.instance.Name(), b.instance.ID())
+	err := b.remoteRunner.ArchiveAndDownloadFiles(ctx, b.artifactDirectory, files, writer)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error streaming backup from remote instance. Error: %s", err.Error()))
+	}
+
+	return nil
+}
+
+// UploadPreviousChecksums writes the checksums of the previous backup of this
+// artifact to the instance, in shasum format, for the backup script to use.
+func (b *Artifact) UploadPreviousChecksums(checksum orchestrator.BackupChecksum) error {
+	b.Logger.Debug("bbr", "Uploading previous checksums to instance %s/%s", b.instance.Name(), b.instance.ID())
+
+	var files []string
+	for file := range checksum {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var contents bytes.Buffer
+	for _, file := range files {
+		fmt.Fprintf(&contents, "%s  %s\n", checksum[file], file)
+	}
+
+	err := b.remoteRunner.WriteFile(previousChecksumsFile(b.artifactDirectory), contents.Bytes())
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to upload previous checksums to instance %s/%s", b.instance.Name(), b.instance.ID()))
+	}
+
+	if b.previousChecksums != nil {
+		b.previousChecksums.uploaded = true
+	}
+
+	return nil
+}
+
+func (b *Artifact) StreamToRemote(ctx context.Context, reader io.Reader) error {
 	err := b.remoteRunner.CreateDirectory(b.artifactDirectory)
 	if err != nil {
 		return errors.Wrap(err, "Creating backup directory on the remote failed")
 	}
 
 	b.Logger.Debug("bbr", "Streaming backup to instance %s/%s", b.instance.Name(), b.instance.ID())
-	return b.remoteRunner.ExtractAndUpload(reader, b.artifactDirectory)
+	return b.remoteRunner.ExtractAndUpload(ctx, reader, b.artifactDirectory)
 }
 
-func (b *Artifact) Size() (string, error) {
+func (b *Artifact) Size(ctx context.Context) (string, error) {
 	b.Logger.Debug("bbr", "Calculating size of backup on %s/%s", b.instance.Name(), b.instance.ID())
 
-	size, err := b.remoteRunner.SizeOf(b.artifactDirectory)
+	size, err := b.remoteRunner.SizeOf(ctx, b.artifactDirectory)
 	if err != nil {
 		return "", errors.Wrap(err, fmt.Sprintf("Unable to check size of %s", b.artifactDirectory))
 	}
@@ -92,18 +138,18 @@ func (b *Artifact) Size() (string, error) {
 	return size, nil
 }
 
-func (b *Artifact) SizeInBytes() (int, error) {
-	size, err := b.remoteRunner.SizeInBytes(b.artifactDirectory)
+func (b *Artifact) SizeInBytes(ctx context.Context) (int, error) {
+	size, err := b.remoteRunner.SizeInBytes(ctx, b.artifactDirectory)
 	if err != nil {
 		return 0, errors.Wrap(err, fmt.Sprintf("Unable to check size of %s", b.artifactDirectory))
 	}
 	return size, nil
 }
 
-func (b *Artifact) Checksum() (orchestrator.BackupChecksum, error) {
+func (b *Artifact) Checksum(ctx context.Context) (orchestrator.BackupChecksum, error) {
 	b.Logger.Debug("bbr", "Calculating shasum for remote files on %s/%s", b.instance.Name(), b.instance.ID())
 
-	backupChecksum, err := b.remoteRunner.ChecksumDirectory(b.artifactDirectory)
+	backupChecksum, err := b.remoteRunner.ChecksumDirectory(ctx, b.artifactDirectory)
 	if err != nil {
 		return nil, errors.Wrap(err, "Unable to calculate backup checksum")
 	}
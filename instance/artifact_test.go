@@ -2,6 +2,7 @@ package instance_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 
@@ -40,7 +41,7 @@ var _ = Describe("artifact", func() {
 			var writer = bytes.NewBufferString("dave")
 
 			JustBeforeEach(func() {
-				err = backupArtifact.StreamFromRemote(writer)
+				err = backupArtifact.StreamFromRemote(context.Background(), writer)
 			})
 
 			Describe("when successful", func() {
@@ -51,7 +52,7 @@ var _ = Describe("artifact", func() {
 				It("uses the remote runner to tar the backup and download it to the local machine", func() {
 					Expect(remoteRunner.ArchiveAndDownloadCallCount()).To(Equal(1))
 
-					dir, returnedWriter := remoteRunner.ArchiveAndDownloadArgsForCall(0)
+					_, dir, returnedWriter := remoteRunner.ArchiveAndDownloadArgsForCall(0)
 					Expect(dir).To(Equal(artifactDirectory))
 					Expect(returnedWriter).To(Equal(writer))
 				})
@@ -69,7 +70,7 @@ var _ = Describe("artifact", func() {
 				It("uses the remote runner to tar the backup and download it to the local machine", func() {
 					Expect(remoteRunner.ArchiveAndDownloadCallCount()).To(Equal(1))
 
-					dir, returnedWriter := remoteRunner.ArchiveAndDownloadArgsForCall(0)
+					_, dir, returnedWriter := remoteRunner.ArchiveAndDownloadArgsForCall(0)
 					Expect(dir).To(Equal(artifactDirectory))
 					Expect(returnedWriter).To(Equal(writer))
 				})
@@ -80,12 +81,80 @@ var _ = Describe("artifact", func() {
 			})
 		})
 
+		Describe("StreamFilesFromRemote", func() {
+			var err error
+			var writer = bytes.NewBufferString("dave")
+
+			JustBeforeEach(func() {
+				err = backupArtifact.StreamFilesFromRemote(context.Background(), []string{"file1", "file3/file4"}, writer)
+			})
+
+			Describe("when successful", func() {
+				It("uses the remote runner to tar only the given files and download them to the local machine", func() {
+					Expect(remoteRunner.ArchiveAndDownloadFilesCallCount()).To(Equal(1))
+
+					_, dir, files, returnedWriter := remoteRunner.ArchiveAndDownloadFilesArgsForCall(0)
+					Expect(dir).To(Equal(artifactDirectory))
+					Expect(files).To(Equal([]string{"file1", "file3/file4"}))
+					Expect(returnedWriter).To(Equal(writer))
+				})
+
+				It("does not fail", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Describe("when there is an error in archive and download", func() {
+				BeforeEach(func() {
+					remoteRunner.ArchiveAndDownloadFilesReturns(fmt.Errorf("oh no, it broke"))
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError(ContainSubstring("oh no, it broke")))
+				})
+			})
+		})
+
+		Describe("UploadPreviousChecksums", func() {
+			var err error
+
+			JustBeforeEach(func() {
+				err = backupArtifact.UploadPreviousChecksums(map[string]string{
+					"file3/file4": "n87fc29fb3aacd99f7f7b81df9c43b13e71c56a1e",
+					"file1":       "07fc29fb3aacd99f7f7b81df9c43b13e71c56a1e",
+				})
+			})
+
+			It("writes the checksums in shasum format next to the artifact directory", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(remoteRunner.WriteFileCallCount()).To(Equal(1))
+
+				path, contents := remoteRunner.WriteFileArgsForCall(0)
+				Expect(path).To(Equal(artifactDirectory + ".previous-checksums"))
+				Expect(string(contents)).To(Equal(
+					"07fc29fb3aacd99f7f7b81df9c43b13e71c56a1e  file1\n" +
+						"n87fc29fb3aacd99f7f7b81df9c43b13e71c56a1e  file3/file4\n",
+				))
+			})
+
+			Context("when the file cannot be written", func() {
+				BeforeEach(func() {
+					remoteRunner.WriteFileReturns(fmt.Errorf("disk full"))
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError(ContainSubstring("disk full")))
+					Expect(err).To(MatchError(ContainSubstring("Unable to upload previous checksums")))
+				})
+			})
+		})
+
 		Describe("BackupChecksum", func() {
 			var actualChecksum map[string]string
 			var actualChecksumError error
 
 			JustBeforeEach(func() {
-				actualChecksum, actualChecksumError = backupArtifact.Checksum()
+				actualChecksum, actualChecksumError = backupArtifact.Checksum(context.Background())
 			})
 
 			Context("can calculate checksum", func() {
@@ -100,7 +169,8 @@ var _ = Describe("artifact", func() {
 				})
 
 				It("generates the correct request", func() {
-					Expect(remoteRunner.ChecksumDirectoryArgsForCall(0)).To(Equal(artifactDirectory))
+					_, path := remoteRunner.ChecksumDirectoryArgsForCall(0)
+					Expect(path).To(Equal(artifactDirectory))
 				})
 
 				It("returns the checksum", func() {
@@ -157,11 +227,12 @@ var _ = Describe("artifact", func() {
 			})
 			Context("when the remoteRunner can determine the size", func() {
 				It("delegates to the remoteRunner", func() {
-					size, err := backupArtifact.SizeInBytes()
+					size, err := backupArtifact.SizeInBytes(context.Background())
 					Expect(err).NotTo(HaveOccurred())
 					Expect(size).To(Equal(65537))
 					Expect(remoteRunner.SizeInBytesCallCount()).To(Equal(1))
-					Expect(remoteRunner.SizeInBytesArgsForCall(0)).To(Equal("some path"))
+					_, path := remoteRunner.SizeInBytesArgsForCall(0)
+					Expect(path).To(Equal("some path"))
 				})
 			})
 
@@ -171,7 +242,7 @@ var _ = Describe("artifact", func() {
 					backupArtifact.SetArtifactDirectory("my cool thing")
 				})
 				It("wraps the error and returns it", func() {
-					_, err := backupArtifact.SizeInBytes()
+					_, err := backupArtifact.SizeInBytes(context.Background())
 					Expect(err).To(MatchError(ContainSubstring("Unable to check size of my cool thing: I am completely broken")))
 				})
 			})
@@ -186,12 +257,13 @@ var _ = Describe("artifact", func() {
 				})
 
 				JustBeforeEach(func() {
-					size, _ = backupArtifact.Size()
+					size, _ = backupArtifact.Size(context.Background())
 				})
 
 				It("returns the size of the backup according to the root user, as a string", func() {
 					Expect(remoteRunner.SizeOfCallCount()).To(Equal(1))
-					Expect(remoteRunner.SizeOfArgsForCall(0)).To(Equal(artifactDirectory))
+					_, path := remoteRunner.SizeOfArgsForCall(0)
+					Expect(path).To(Equal(artifactDirectory))
 					Expect(size).To(Equal("4.1G"))
 				})
 			})
@@ -204,12 +276,13 @@ var _ = Describe("artifact", func() {
 				})
 
 				JustBeforeEach(func() {
-					_, err = backupArtifact.Size()
+					_, err = backupArtifact.Size(context.Background())
 				})
 
 				It("returns the size of the backup according to the root user, as a string", func() {
 					Expect(remoteRunner.SizeOfCallCount()).To(Equal(1))
-					Expect(remoteRunner.SizeOfArgsForCall(0)).To(Equal(artifactDirectory))
+					_, path := remoteRunner.SizeOfArgsForCall(0)
+					Expect(path).To(Equal(artifactDirectory))
 					Expect(err).To(SatisfyAll(
 						MatchError(ContainSubstring("Unable to check size of "+artifactDirectory)),
 						MatchError(ContainSubstring("no backup directory or something")),
@@ -223,7 +296,7 @@ var _ = Describe("artifact", func() {
 			var reader = bytes.NewBufferString("dave")
 
 			JustBeforeEach(func() {
-				err = backupArtifact.StreamToRemote(reader)
+				err = backupArtifact.StreamToRemote(context.Background(), reader)
 			})
 
 			Describe("when successful", func() {
@@ -235,7 +308,7 @@ var _ = Describe("artifact", func() {
 
 				It("uses the remote runner to stream files to the remote machine", func() {
 					Expect(remoteRunner.ExtractAndUploadCallCount()).To(Equal(1))
-					sentReader, dir := remoteRunner.ExtractAndUploadArgsForCall(0)
+					_, sentReader, dir := remoteRunner.ExtractAndUploadArgsForCall(0)
 					Expect(dir).To(Equal(artifactDirectory))
 					Expect(reader).To(Equal(sentReader))
 				})
@@ -1,6 +1,7 @@
 package instance
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -30,8 +31,8 @@ func NewDeployedInstance(instanceIndex string, instanceGroupName string, instanc
 	}
 }
 
-func (i *DeployedInstance) ArtifactDirExists() (bool, error) {
-	return i.remoteRunner.DirectoryExists(orchestrator.ArtifactDirectory)
+func (i *DeployedInstance) ArtifactDirExists(ctx context.Context) (bool, error) {
+	return i.remoteRunner.DirectoryExists(ctx, orchestrator.ArtifactDirectory)
 }
 
 func (i *DeployedInstance) RemoveArtifactDir() error {
@@ -50,6 +51,12 @@ func (i *DeployedInstance) MarkArtifactDirCreated() {
 	i.artifactDirCreated = true
 }
 
+// RetainArtifactDir stops Cleanup from removing the artifact directory, so that
+// artifacts which could not be drained are left for a resumed backup.
+func (i *DeployedInstance) RetainArtifactDir() {
+	i.artifactDirCreated = false
+}
+
 func (i *DeployedInstance) HasMetadataRestoreNames() bool {
 	return i.jobs.HasMetadataRestoreNames()
 }
@@ -58,10 +65,10 @@ func (i *DeployedInstance) Jobs() []orchestrator.Job {
 	return i.jobs
 }
 
-func (i *DeployedInstance) Backup() error {
+func (i *DeployedInstance) Backup(ctx context.Context) error {
 	var backupErrors []error
 	for _, job := range i.jobs {
-		if err := job.Backup(); err != nil {
+		if err := job.Backup(ctx); err != nil {
 			backupErrors = append(backupErrors, err)
 		}
 	}
@@ -73,6 +80,18 @@ func (i *DeployedInstance) Backup() error {
 	return orchestrator.ConvertErrors(backupErrors)
 }
 
+// previousChecksumsFile is where the checksums of the previous backup of an
+// artifact are written for incremental backups. It is next to the artifact
+// directory, so that it is not copied back as part of the artifact.
+//
+// Backup scripts are given its path in BBR_PREVIOUS_CHECKSUMS_FILE, but must
+// still write every file of the artifact to the artifact directory: bbr only
+// skips downloading the files whose checksum is unchanged, and records any
+// file that is missing from the directory as deleted.
+func previousChecksumsFile(artifactDirectory string) string {
+	return artifactDirectory + ".previous-checksums"
+}
+
 func artifactDirectoryVariables(artifactDirectory string) map[string]string {
 	return map[string]string{
 		"BBR_ARTIFACT_DIRECTORY": artifactDirectory + "/",
@@ -80,10 +99,10 @@ func artifactDirectoryVariables(artifactDirectory string) map[string]string {
 	}
 }
 
-func (i *DeployedInstance) Restore() error {
+func (i *DeployedInstance) Restore(ctx context.Context) error {
 	var restoreErrors []error
 	for _, job := range i.jobs {
-		if err := job.Restore(); err != nil {
+		if err := job.Restore(ctx); err != nil {
 			restoreErrors = append(restoreErrors, err)
 		}
 	}
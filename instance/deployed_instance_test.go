@@ -1,6 +1,7 @@
 package instance_test
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -102,12 +103,13 @@ var _ = Describe("DeployedInstance", func() {
 		var dirExists bool
 
 		JustBeforeEach(func() {
-			dirExists, _ = deployedInstance.ArtifactDirExists()
+			dirExists, _ = deployedInstance.ArtifactDirExists(context.Background())
 		})
 
 		It("queries whether the artifact directory is present", func() {
 			Expect(remoteRunner.DirectoryExistsCallCount()).To(Equal(1))
-			Expect(remoteRunner.DirectoryExistsArgsForCall(0)).To(Equal("/var/vcap/store/bbr-backup"))
+			_, dir := remoteRunner.DirectoryExistsArgsForCall(0)
+			Expect(dir).To(Equal("/var/vcap/store/bbr-backup"))
 		})
 
 		Context("when artifact directory does not exist", func() {
@@ -264,7 +266,7 @@ var _ = Describe("DeployedInstance", func() {
 		var err error
 
 		JustBeforeEach(func() {
-			err = deployedInstance.Backup()
+			err = deployedInstance.Backup(context.Background())
 		})
 
 		Context("when there are multiple backup scripts in multiple job directories", func() {
@@ -323,21 +325,21 @@ var _ = Describe("DeployedInstance", func() {
 					"/var/vcap/store/bbr-backup/baz",
 				))
 
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/foo/bin/bbr/backup"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/foo/",
 					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/foo/",
 				}))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
+				_, specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/bar/bin/bbr/backup"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/bar/",
 					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/bar/",
 				}))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
+				_, specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/baz/bin/bbr/backup"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/baz/",
@@ -421,14 +423,14 @@ var _ = Describe("DeployedInstance", func() {
 					"/var/vcap/store/bbr-backup/foo",
 					"/var/vcap/store/bbr-backup/baz-dave-backup-one-restore-all",
 				))
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/foo/bin/bbr/backup"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/foo/",
 					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/foo/",
 				}))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
+				_, specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/baz/bin/bbr/backup"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/baz-dave-backup-one-restore-all/",
@@ -513,7 +515,7 @@ var _ = Describe("DeployedInstance", func() {
 					),
 				})
 
-				remoteRunner.RunScriptWithEnvStub = func(cmd string, envVars map[string]string, label string) (string, error) {
+				remoteRunner.RunScriptWithEnvStub = func(_ context.Context, cmd string, envVars map[string]string, label string) (string, error) {
 					if strings.Contains(cmd, "jobs/bar") {
 						return "", fmt.Errorf("no space left on device")
 					} else if strings.Contains(cmd, "jobs/baz") {
@@ -547,7 +549,7 @@ var _ = Describe("DeployedInstance", func() {
 		var actualError error
 
 		JustBeforeEach(func() {
-			actualError = deployedInstance.Restore()
+			actualError = deployedInstance.Restore(context.Background())
 		})
 
 		Context("when there are multiple restore scripts in multiple job directories", func() {
@@ -595,21 +597,21 @@ var _ = Describe("DeployedInstance", func() {
 			It("uses the remote runner to run each restore script providing the correct ARTIFACT_DIRECTORY", func() {
 				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(3))
 
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/foo/bin/bbr/restore"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/foo/",
 					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/foo/",
 				}))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
+				_, specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/bar/bin/bbr/restore"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/bar/",
 					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/bar/",
 				}))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
+				_, specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/baz/bin/bbr/restore"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/baz/",
@@ -697,21 +699,21 @@ var _ = Describe("DeployedInstance", func() {
 			It("uses the remote runner to create each job's backup folder and run each backup script providing the correct BBR_ARTIFACT_DIRECTORY and ARTIFACT_DIRECTORY", func() {
 				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(3))
 
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/foo/bin/bbr/restore"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/foo/",
 					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/foo/",
 				}))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
+				_, specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(1)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/bar/bin/bbr/restore"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/bar/",
 					"BBR_ARTIFACT_DIRECTORY": "/var/vcap/store/bbr-backup/bar/",
 				}))
 
-				specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
+				_, specifiedScriptPath, specifiedEnvVars, _ = remoteRunner.RunScriptWithEnvArgsForCall(2)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/baz/bin/bbr/restore"))
 				Expect(specifiedEnvVars).To(Equal(map[string]string{
 					"ARTIFACT_DIRECTORY":     "/var/vcap/store/bbr-backup/special-backup/",
@@ -761,7 +763,7 @@ var _ = Describe("DeployedInstance", func() {
 					),
 				})
 
-				remoteRunner.RunScriptWithEnvStub = func(cmd string, envVars map[string]string, label string) (string, error) {
+				remoteRunner.RunScriptWithEnvStub = func(_ context.Context, cmd string, envVars map[string]string, label string) (string, error) {
 					if strings.Contains(cmd, "jobs/bar") {
 						return "", fmt.Errorf("no space left on device")
 					} else if strings.Contains(cmd, "jobs/baz") {
@@ -791,6 +793,15 @@ var _ = Describe("DeployedInstance", func() {
 		})
 	})
 
+	Describe("RetainArtifactDir", func() {
+		It("stops the artifact directory from being treated as created", func() {
+			deployedInstance.MarkArtifactDirCreated()
+			deployedInstance.RetainArtifactDir()
+
+			Expect(deployedInstance.ArtifactDirCreated()).To(BeFalse())
+		})
+	})
+
 	Describe("Name", func() {
 		It("returns the instance name", func() {
 			Expect(deployedInstance.Name()).To(Equal("instance-group-name"))
@@ -1048,7 +1059,7 @@ var _ = Describe("DeployedInstance", func() {
 			It("only returns artifacts for the jobs with restore scripts", func() {
 				Expect(restoreArtifacts).To(Equal(
 					[]orchestrator.BackupArtifact{
-						instance.NewBackupArtifact(
+						instance.NewRestoreArtifact(
 							jobWithRestoreScript1,
 							deployedInstance,
 							remoteRunner,
@@ -1,8 +1,10 @@
 package instance
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
@@ -24,11 +26,28 @@ func NewJob(remoteRunner ssh.RemoteRunner, instanceIdentifier string, logger Log
 		preRestoreScript:    jobScripts.PreRestoreLockOnly().firstOrBlank(),
 		postBackupScript:    jobScripts.PostBackupUnlockOnly().firstOrBlank(),
 		postRestoreScript:   jobScripts.SinglePostRestoreUnlockScript(),
+		validateScript:      jobScripts.PostRestoreValidateOnly().firstOrBlank(),
 		backupOneRestoreAll: backupOneRestoreAll,
 		onBootstrapNode:     onBootstrapNode,
+		lockWindow:          &lockWindow{},
+		previousChecksums:   &previousChecksumsUpload{},
 	}
 }
 
+// lockWindow records when a job started locking for backup, so that the time
+// it spent locked can be logged once it has been unlocked. It is shared by
+// every copy of the Job.
+type lockWindow struct {
+	lockedAt time.Time
+}
+
+// previousChecksumsUpload records whether the checksums of the previous backup
+// were written to the instance for the backup script, which they only are for
+// incremental backups. It is shared by every copy of the Job.
+type previousChecksumsUpload struct {
+	uploaded bool
+}
+
 type Job struct {
 	Logger              Logger
 	name                string
@@ -40,10 +59,13 @@ type Job struct {
 	preRestoreScript    Script
 	restoreScript       Script
 	postRestoreScript   Script
+	validateScript      Script
 	remoteRunner        ssh.RemoteRunner
 	instanceIdentifier  string
 	backupOneRestoreAll bool
 	onBootstrapNode     bool
+	lockWindow          *lockWindow
+	previousChecksums   *previousChecksumsUpload
 }
 
 func (j Job) Name() string {
@@ -113,7 +135,7 @@ func (j Job) HasNamedRestoreArtifact() bool {
 	return j.backupOneRestoreAll || j.metadata.RestoreName != ""
 }
 
-func (j Job) Backup() error {
+func (j Job) Backup(ctx context.Context) error {
 	if j.backupScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.backupScript)
 		j.Logger.Info("bbr", "Backing up %s on %s...", j.name, j.instanceIdentifier)
@@ -124,11 +146,19 @@ func (j Job) Backup() error {
 		}
 
 		env := artifactDirectoryVariables(j.BackupArtifactDirectory())
-		_, err = j.remoteRunner.RunScriptWithEnv(
-			string(j.backupScript),
-			env,
-			fmt.Sprintf("backup %s on %s", j.name, j.instanceIdentifier),
-		)
+		if j.previousChecksums.uploaded {
+			// The script must still write the complete artifact, see previousChecksumsFile.
+			env["BBR_PREVIOUS_CHECKSUMS_FILE"] = previousChecksumsFile(j.BackupArtifactDirectory())
+		}
+		err = j.runScript(ctx, j.backupScript, func(ctx context.Context, command string) error {
+			_, err := j.remoteRunner.RunScriptWithEnv(
+				ctx,
+				command,
+				env,
+				fmt.Sprintf("backup %s on %s", j.name, j.instanceIdentifier),
+			)
+			return err
+		})
 
 		if err != nil {
 			j.Logger.Error("bbr", "Error backing up %s on %s.", j.name, j.instanceIdentifier)
@@ -146,15 +176,20 @@ func (j Job) Backup() error {
 	return nil
 }
 
-func (j Job) PreBackupLock() error {
+func (j Job) PreBackupLock(ctx context.Context) error {
 	if j.preBackupScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.preBackupScript)
 		j.Logger.Info("bbr", "Locking %s on %s for backup...", j.name, j.instanceIdentifier)
-
-		_, err := j.remoteRunner.RunScript(
-			string(j.preBackupScript),
-			fmt.Sprintf("pre-backup lock %s on %s", j.name, j.instanceIdentifier),
-		)
+		j.lockWindow.lockedAt = time.Now()
+
+		err := j.runScript(ctx, j.preBackupScript, func(ctx context.Context, command string) error {
+			_, err := j.remoteRunner.RunScript(
+				ctx,
+				command,
+				fmt.Sprintf("pre-backup lock %s on %s", j.name, j.instanceIdentifier),
+			)
+			return err
+		})
 		if err != nil {
 			j.Logger.Error("bbr", "Error locking %s on %s.", j.name, j.instanceIdentifier)
 
@@ -171,18 +206,24 @@ func (j Job) PreBackupLock() error {
 	return nil
 }
 
-func (j Job) PostBackupUnlock(afterSuccessfulBackup bool) error {
+func (j Job) PostBackupUnlock(ctx context.Context, afterSuccessfulBackup bool) error {
+	defer j.logLockWindow()
+
 	if j.postBackupScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.postBackupScript)
 		j.Logger.Info("bbr", "Unlocking %s on %s...", j.name, j.instanceIdentifier)
 		env := map[string]string{
 			"BBR_AFTER_BACKUP_SCRIPTS_SUCCESSFUL": strconv.FormatBool(afterSuccessfulBackup),
 		}
-		_, err := j.remoteRunner.RunScriptWithEnv(
-			string(j.postBackupScript),
-			env,
-			fmt.Sprintf("post-backup unlock %s on %s", j.name, j.instanceIdentifier),
-		)
+		err := j.runScript(ctx, j.postBackupScript, func(ctx context.Context, command string) error {
+			_, err := j.remoteRunner.RunScriptWithEnv(
+				ctx,
+				command,
+				env,
+				fmt.Sprintf("post-backup unlock %s on %s", j.name, j.instanceIdentifier),
+			)
+			return err
+		})
 		if err != nil {
 			j.Logger.Error("bbr", "Error unlocking %s on %s.", j.name, j.instanceIdentifier)
 
@@ -199,15 +240,31 @@ func (j Job) PostBackupUnlock(afterSuccessfulBackup bool) error {
 	return nil
 }
 
-func (j Job) PreRestoreLock() error {
+// logLockWindow logs how long a job that was locked for backup stayed locked,
+// until it was unlocked. Jobs without a post-backup-unlock script stay locked
+// until the unlock reaches them, so it is logged for them as well.
+func (j Job) logLockWindow() {
+	if j.lockWindow.lockedAt.IsZero() {
+		return
+	}
+
+	j.Logger.Info("bbr", "%s on %s was locked for %s.", j.name, j.instanceIdentifier, time.Since(j.lockWindow.lockedAt).Round(time.Millisecond))
+	j.lockWindow.lockedAt = time.Time{}
+}
+
+func (j Job) PreRestoreLock(ctx context.Context) error {
 	if j.preRestoreScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.preRestoreScript)
 		j.Logger.Info("bbr", "Locking %s on %s for restore...", j.name, j.instanceIdentifier)
 
-		_, err := j.remoteRunner.RunScript(
-			string(j.preRestoreScript),
-			fmt.Sprintf("pre-restore lock %s on %s", j.name, j.instanceIdentifier),
-		)
+		err := j.runScript(ctx, j.preRestoreScript, func(ctx context.Context, command string) error {
+			_, err := j.remoteRunner.RunScript(
+				ctx,
+				command,
+				fmt.Sprintf("pre-restore lock %s on %s", j.name, j.instanceIdentifier),
+			)
+			return err
+		})
 		if err != nil {
 			j.Logger.Error("bbr", "Error locking %s on %s.", j.name, j.instanceIdentifier)
 
@@ -224,16 +281,20 @@ func (j Job) PreRestoreLock() error {
 	return nil
 }
 
-func (j Job) Restore() error {
+func (j Job) Restore(ctx context.Context) error {
 	if j.restoreScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.restoreScript)
 		j.Logger.Info("bbr", "Restoring %s on %s...", j.name, j.instanceIdentifier)
 
 		env := artifactDirectoryVariables(j.RestoreArtifactDirectory())
-		_, err := j.remoteRunner.RunScriptWithEnv(
-			string(j.restoreScript), env,
-			fmt.Sprintf("restore %s on %s", j.name, j.instanceIdentifier),
-		)
+		err := j.runScript(ctx, j.restoreScript, func(ctx context.Context, command string) error {
+			_, err := j.remoteRunner.RunScriptWithEnv(
+				ctx,
+				command, env,
+				fmt.Sprintf("restore %s on %s", j.name, j.instanceIdentifier),
+			)
+			return err
+		})
 		if err != nil {
 			j.Logger.Error("bbr", "Error restoring %s on %s.", j.name, j.instanceIdentifier)
 
@@ -250,15 +311,19 @@ func (j Job) Restore() error {
 	return nil
 }
 
-func (j Job) PostRestoreUnlock() error {
+func (j Job) PostRestoreUnlock(ctx context.Context) error {
 	if j.postRestoreScript != "" {
 		j.Logger.Debug("bbr", "> %s", j.postRestoreScript)
 		j.Logger.Info("bbr", "Unlocking %s on %s...", j.name, j.instanceIdentifier)
 
-		_, err := j.remoteRunner.RunScript(
-			string(j.postRestoreScript),
-			fmt.Sprintf("post-restore unlock %s on %s", j.name, j.instanceIdentifier),
-		)
+		err := j.runScript(ctx, j.postRestoreScript, func(ctx context.Context, command string) error {
+			_, err := j.remoteRunner.RunScript(
+				ctx,
+				command,
+				fmt.Sprintf("post-restore unlock %s on %s", j.name, j.instanceIdentifier),
+			)
+			return err
+		})
 		if err != nil {
 			j.Logger.Error("bbr", "Error unlocking %s on %s.", j.name, j.instanceIdentifier)
 
@@ -275,6 +340,97 @@ func (j Job) PostRestoreUnlock() error {
 	return nil
 }
 
+// PostRestoreValidate runs the job's post-restore-validate script, which checks
+// that the restored data is healthy once the job has been unlocked.
+func (j Job) PostRestoreValidate(ctx context.Context) error {
+	if j.validateScript != "" {
+		j.Logger.Debug("bbr", "> %s", j.validateScript)
+		j.Logger.Info("bbr", "Validating restore of %s on %s...", j.name, j.instanceIdentifier)
+
+		err := j.runScript(ctx, j.validateScript, func(ctx context.Context, command string) error {
+			_, err := j.remoteRunner.RunScript(
+				ctx,
+				command,
+				fmt.Sprintf("post-restore validate %s on %s", j.name, j.instanceIdentifier),
+			)
+			return err
+		})
+		if err != nil {
+			j.Logger.Error("bbr", "Error validating restore of %s on %s.", j.name, j.instanceIdentifier)
+
+			return errors.Wrap(err, fmt.Sprintf(
+				"Error attempting to run post-restore-validate for job %s on %s",
+				j.Name(),
+				j.instanceIdentifier,
+			))
+		}
+
+		j.Logger.Info("bbr", "Finished validating restore of %s on %s.", j.name, j.instanceIdentifier)
+	}
+
+	return nil
+}
+
+// runScript runs a script, telling the observer of the workflow, if there is
+// one, when it starts and when it finishes.
+func (j Job) runScript(ctx context.Context, script Script, run func(ctx context.Context, command string) error) error {
+	orchestrator.NotifyObserver(ctx, orchestrator.JobScriptStarted{
+		Instance: j.instanceIdentifier,
+		Job:      j.name,
+		Script:   script.Name(),
+	})
+	startedAt := time.Now()
+
+	err := j.runWithTimeout(ctx, script, run)
+
+	finished := orchestrator.JobScriptFinished{
+		Instance: j.instanceIdentifier,
+		Job:      j.name,
+		Script:   script.Name(),
+		Duration: time.Since(startedAt),
+	}
+	if err != nil {
+		finished.Error = err.Error()
+	}
+	orchestrator.NotifyObserver(ctx, finished)
+
+	return err
+}
+
+// runWithTimeout runs a script under the timeout configured for it. When the
+// timeout fires the script's context is cancelled, which aborts the SSH session,
+// and a ScriptTimeoutError is returned in place of the cancellation. As not
+// every SSH server passes on the signal of an aborted session, the script is
+// also run under timeout(1) on the instance, which kills its process group.
+func (j Job) runWithTimeout(ctx context.Context, script Script, run func(ctx context.Context, command string) error) error {
+	timeout, ok := j.metadata.Timeouts[script.Name()]
+	if !ok {
+		return run(ctx, string(script))
+	}
+
+	scriptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := run(scriptCtx, remoteTimeoutCommand(script, timeout))
+	if err != nil && scriptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return orchestrator.NewScriptTimeoutError(script.Name(), timeout)
+	}
+	return err
+}
+
+// remoteKillGrace is how long a timed out script has to exit after it was sent
+// SIGTERM on the instance, before it is sent SIGKILL.
+const remoteKillGrace = 10 * time.Second
+
+func remoteTimeoutCommand(script Script, timeout time.Duration) string {
+	return fmt.Sprintf("timeout --kill-after=%s %s %s", remoteDuration(remoteKillGrace), remoteDuration(timeout), script)
+}
+
+// remoteDuration formats a duration as an argument to timeout(1).
+func remoteDuration(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'f', -1, 64) + "s"
+}
+
 func (j Job) backupArtifactOrJobName() string {
 	if j.HasNamedBackupArtifact() {
 		return j.BackupArtifactName()
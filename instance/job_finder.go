@@ -1,6 +1,7 @@
 package instance
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
@@ -21,35 +22,38 @@ func (i InstanceIdentifier) String() string {
 
 //go:generate counterfeiter -o fakes/fake_job_finder.go . JobFinder
 type JobFinder interface {
-	FindJobs(instanceIdentifier InstanceIdentifier, remoteRunner ssh.RemoteRunner, manifestQuerier ManifestQuerier) (orchestrator.Jobs, error)
+	FindJobs(ctx context.Context, instanceIdentifier InstanceIdentifier, remoteRunner ssh.RemoteRunner, manifestQuerier ManifestQuerier) (orchestrator.Jobs, error)
 }
 
 type JobFinderFromScripts struct {
 	bbrVersion       string
+	scriptTimeouts   ScriptTimeouts
 	Logger           Logger
 	parseJobMetadata MetadataParserFunc
 }
 
-func NewJobFinder(bbrVersion string, logger Logger) *JobFinderFromScripts {
+func NewJobFinder(bbrVersion string, scriptTimeouts ScriptTimeouts, logger Logger) *JobFinderFromScripts {
 	return &JobFinderFromScripts{
 		bbrVersion:       bbrVersion,
+		scriptTimeouts:   scriptTimeouts,
 		Logger:           logger,
 		parseJobMetadata: ParseJobMetadata,
 	}
 }
 
-func NewJobFinderOmitMetadataReleases(bbrVersion string, logger Logger) *JobFinderFromScripts {
+func NewJobFinderOmitMetadataReleases(bbrVersion string, scriptTimeouts ScriptTimeouts, logger Logger) *JobFinderFromScripts {
 	return &JobFinderFromScripts{
 		bbrVersion:       bbrVersion,
+		scriptTimeouts:   scriptTimeouts,
 		Logger:           logger,
 		parseJobMetadata: ParseJobMetadataOmitReleases,
 	}
 }
 
-func (j *JobFinderFromScripts) FindJobs(instanceIdentifier InstanceIdentifier, remoteRunner ssh.RemoteRunner,
+func (j *JobFinderFromScripts) FindJobs(ctx context.Context, instanceIdentifier InstanceIdentifier, remoteRunner ssh.RemoteRunner,
 	manifestQuerier ManifestQuerier) (orchestrator.Jobs, error) {
 
-	findOutput, err := j.findBBRScripts(instanceIdentifier, remoteRunner)
+	findOutput, err := j.findBBRScripts(ctx, instanceIdentifier, remoteRunner)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +61,7 @@ func (j *JobFinderFromScripts) FindJobs(instanceIdentifier InstanceIdentifier, r
 	scripts := NewBackupAndRestoreScripts(findOutput)
 	for _, script := range scripts {
 		if script.isMetadata() {
-			jobMetadata, err := j.findMetadata(instanceIdentifier, script, remoteRunner)
+			jobMetadata, err := j.findMetadata(ctx, instanceIdentifier, script, remoteRunner)
 
 			if err != nil {
 				return nil, err
@@ -83,16 +87,20 @@ func (j *JobFinderFromScripts) logMetadata(jobMetadata *Metadata, jobName string
 		j.Logger.Info("bbr", "Detected order: %s should be locked before %s during restore", jobName, filepath.Join(lockBefore.Release, lockBefore.JobName))
 	}
 
+	for scriptName, timeout := range jobMetadata.Timeouts {
+		j.Logger.Info("bbr", "Detected timeout: %s %s script will time out after %s", jobName, scriptName, timeout)
+	}
+
 	if jobMetadata.BackupName != "" {
 		j.Logger.Warn("bbr", "discontinued metadata keys backup_name/restore_name found in job %s. bbr will not be able to restore this backup artifact.", jobName)
 	}
 }
 
-func (j *JobFinderFromScripts) findBBRScripts(instanceIdentifierForLogging InstanceIdentifier,
+func (j *JobFinderFromScripts) findBBRScripts(ctx context.Context, instanceIdentifierForLogging InstanceIdentifier,
 	remoteRunner ssh.RemoteRunner) ([]string, error) {
 	j.Logger.Debug("bbr", "Attempting to find scripts on %s", instanceIdentifierForLogging)
 
-	scripts, err := remoteRunner.FindFiles("/var/vcap/jobs/*/bin/bbr/*")
+	scripts, err := remoteRunner.FindFiles(ctx, "/var/vcap/jobs/*/bin/bbr/*")
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("finding scripts failed on %s", instanceIdentifierForLogging))
 	}
@@ -100,8 +108,9 @@ func (j *JobFinderFromScripts) findBBRScripts(instanceIdentifierForLogging Insta
 	return scripts, nil
 }
 
-func (j *JobFinderFromScripts) findMetadata(instanceIdentifier InstanceIdentifier, script Script, remoteRunner ssh.RemoteRunner) (*Metadata, error) {
+func (j *JobFinderFromScripts) findMetadata(ctx context.Context, instanceIdentifier InstanceIdentifier, script Script, remoteRunner ssh.RemoteRunner) (*Metadata, error) {
 	metadataContent, err := remoteRunner.RunScriptWithEnv(
+		ctx,
 		string(script),
 		map[string]string{"BBR_VERSION": j.bbrVersion},
 		fmt.Sprintf("find metadata for %s on %s", script.JobName(), instanceIdentifier),
@@ -157,13 +166,16 @@ func (j *JobFinderFromScripts) buildJobs(remoteRunner ssh.RemoteRunner,
 
 		backupOneRestoreAll, _ := manifestQuerier.IsJobBackupOneRestoreAll(instanceIdentifier.InstanceGroupName, jobName)
 
+		jobMetadata := metadata[jobName]
+		jobMetadata.Timeouts = jobMetadata.Timeouts.withDefaults(j.scriptTimeouts)
+
 		jobs = append(jobs, NewJob(
 			remoteRunner,
 			instanceIdentifier.String(),
 			logger,
 			releaseName,
 			jobScripts,
-			metadata[jobName],
+			jobMetadata,
 			backupOneRestoreAll,
 			instanceIdentifier.Bootstrap,
 		))
@@ -1,6 +1,7 @@
 package instance_test
 
 import (
+	"context"
 	"strings"
 
 	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
@@ -13,6 +14,7 @@ import (
 	"bytes"
 	"io"
 	"log"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	. "github.com/onsi/ginkgo"
@@ -34,7 +36,7 @@ var _ = Describe("JobFinderFromScripts", func() {
 		combinedLog := log.New(io.MultiWriter(GinkgoWriter, logStream), "[instance-test] ", log.Lshortfile)
 		logger = boshlog.New(boshlog.LevelDebug, combinedLog)
 
-		jobFinder = NewJobFinder(bbrVersion, logger)
+		jobFinder = NewJobFinder(bbrVersion, nil, logger)
 	})
 
 	Describe("FindJobs", func() {
@@ -62,13 +64,14 @@ var _ = Describe("JobFinderFromScripts", func() {
 		})
 
 		JustBeforeEach(func() {
-			jobs, jobsError = jobFinder.FindJobs(instanceIdentifier, remoteRunner, manifestQuerier)
+			jobs, jobsError = jobFinder.FindJobs(context.Background(), instanceIdentifier, remoteRunner, manifestQuerier)
 		})
 
 		It("finds the jobs", func() {
-			jobs, jobsError = jobFinder.FindJobs(instanceIdentifier, remoteRunner, manifestQuerier)
+			jobs, jobsError = jobFinder.FindJobs(context.Background(), instanceIdentifier, remoteRunner, manifestQuerier)
 			By("finding the scripts", func() {
-				Expect(remoteRunner.FindFilesArgsForCall(0)).To(Equal("/var/vcap/jobs/*/bin/bbr/*"))
+				_, pattern := remoteRunner.FindFilesArgsForCall(0)
+				Expect(pattern).To(Equal("/var/vcap/jobs/*/bin/bbr/*"))
 			})
 
 			By("logging the scripts found", func() {
@@ -128,7 +131,7 @@ var _ = Describe("JobFinderFromScripts", func() {
 			})
 
 			It("finds the jobs", func() {
-				jobs, _ = jobFinder.FindJobs(instanceIdentifier, remoteRunner, manifestQuerier)
+				jobs, _ = jobFinder.FindJobs(context.Background(), instanceIdentifier, remoteRunner, manifestQuerier)
 				Expect(jobs).To(ConsistOf(
 					NewJob(
 						remoteRunner,
@@ -157,7 +160,8 @@ var _ = Describe("JobFinderFromScripts", func() {
 
 			It("ignores them", func() {
 				By("finding the scripts", func() {
-					Expect(remoteRunner.FindFilesArgsForCall(0)).To(Equal("/var/vcap/jobs/*/bin/bbr/*"))
+					_, pattern := remoteRunner.FindFilesArgsForCall(0)
+					Expect(pattern).To(Equal("/var/vcap/jobs/*/bin/bbr/*"))
 				})
 
 				By("not returning an error", func() {
@@ -262,7 +266,7 @@ backup_should_be_locked_before:
 
 				It("attaches the metadata to the corresponding jobs", func() {
 					By("executing the metadata scripts passing the correct arguments", func() {
-						cmd, env, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+						_, cmd, env, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 						Expect(cmd).To(Equal("/var/vcap/jobs/consul_agent/bin/bbr/metadata"))
 						Expect(env).To(Equal(map[string]string{"BBR_VERSION": bbrVersion}))
 					})
@@ -295,12 +299,12 @@ backup_should_be_locked_before:
 
 				Context("and the jobFinder is configured to omit releases", func() {
 					BeforeEach(func() {
-						jobFinder = NewJobFinderOmitMetadataReleases(bbrVersion, logger)
+						jobFinder = NewJobFinderOmitMetadataReleases(bbrVersion, nil, logger)
 					})
 
 					It("attaches the metadata to the corresponding jobs", func() {
 						By("executing the metadata scripts passing the correct arguments", func() {
-							cmd, env, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+							_, cmd, env, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 							Expect(cmd).To(Equal("/var/vcap/jobs/consul_agent/bin/bbr/metadata"))
 							Expect(env).To(Equal(map[string]string{"BBR_VERSION": bbrVersion}))
 						})
@@ -333,6 +337,42 @@ backup_should_be_locked_before:
 				})
 			})
 
+			Context("when the metadata overrides the script timeouts", func() {
+				BeforeEach(func() {
+					jobFinder = NewJobFinder(bbrVersion, ScriptTimeouts{
+						"backup":  10 * time.Minute,
+						"restore": 20 * time.Minute,
+					}, logger)
+					remoteRunner.FindFilesReturns([]string{"/var/vcap/jobs/consul_agent/bin/bbr/metadata"}, nil)
+					remoteRunner.RunScriptWithEnvReturns(`---
+timeouts:
+  backup: 1h`, nil)
+				})
+
+				It("uses the job's timeouts in place of the defaults", func() {
+					Expect(jobsError).NotTo(HaveOccurred())
+					Expect(jobs).To(ConsistOf(
+						NewJob(
+							remoteRunner,
+							"identifier/0",
+							logger,
+							consulAgentReleaseName,
+							BackupAndRestoreScripts{
+								"/var/vcap/jobs/consul_agent/bin/bbr/metadata",
+							},
+							Metadata{
+								Timeouts: ScriptTimeouts{
+									"backup":  time.Hour,
+									"restore": 20 * time.Minute,
+								},
+							},
+							true,
+							true),
+					))
+					Expect(logStream.String()).To(ContainSubstring("Detected timeout: consul_agent backup script will time out after 1h0m0s"))
+				})
+			})
+
 			Context("when finding the scripts fails", func() {
 				BeforeEach(func() {
 					remoteRunner.FindFilesReturns(nil, fmt.Errorf("ERROR"))
@@ -390,7 +430,7 @@ skip_bbr_scripts: true
 
 				It("ignores the job", func() {
 					By("executing the metadata scripts passing the correct arguments", func() {
-						cmd, env, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+						_, cmd, env, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 						Expect(cmd).To(Equal("/var/vcap/jobs/consul_agent/bin/bbr/metadata"))
 						Expect(env).To(Equal(map[string]string{"BBR_VERSION": bbrVersion}))
 					})
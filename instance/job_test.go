@@ -1,13 +1,19 @@
 package instance_test
 
 import (
+	"context"
+	"time"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/pkg/errors"
 
 	"log"
 
 	"fmt"
 
+	backuperfakes "github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
 	sshfakes "github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh/fakes"
 
 	. "github.com/onsi/ginkgo"
@@ -343,9 +349,20 @@ var _ = Describe("Job", func() {
 
 	Describe("Backup", func() {
 		var backupError error
+		var ctx context.Context
+		var previousChecksum orchestrator.BackupChecksum
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			previousChecksum = nil
+		})
 
 		JustBeforeEach(func() {
-			backupError = job.Backup()
+			if previousChecksum != nil {
+				artifact := instance.NewBackupArtifact(job, new(backuperfakes.FakeInstance), remoteRunner, logger)
+				Expect(artifact.UploadPreviousChecksums(previousChecksum)).To(Succeed())
+			}
+			backupError = job.Backup(ctx)
 		})
 
 		Context("job has no backup script", func() {
@@ -372,7 +389,7 @@ var _ = Describe("Job", func() {
 				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
 
 				Expect(remoteRunner.CreateDirectoryArgsForCall(0)).To(Equal("/var/vcap/store/bbr-backup/jobname"))
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/jobname/bin/bbr/backup"))
 				Expect(specifiedEnvVars).To(SatisfyAll(
 					HaveLen(2),
@@ -381,6 +398,23 @@ var _ = Describe("Job", func() {
 				))
 			})
 
+			It("does not point a full backup at previous checksums", func() {
+				_, _, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				Expect(specifiedEnvVars).NotTo(HaveKey("BBR_PREVIOUS_CHECKSUMS_FILE"))
+			})
+
+			Context("and the checksums of the previous backup have been uploaded", func() {
+				BeforeEach(func() {
+					previousChecksum = orchestrator.BackupChecksum{"file1": "abc"}
+				})
+
+				It("tells the backup script where to find them", func() {
+					Expect(remoteRunner.WriteFileCallCount()).To(Equal(1))
+					_, _, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+					Expect(specifiedEnvVars).To(HaveKeyWithValue("BBR_PREVIOUS_CHECKSUMS_FILE", "/var/vcap/store/bbr-backup/jobname.previous-checksums"))
+				})
+			})
+
 			Context("backup script runs successfully", func() {
 				BeforeEach(func() {
 					remoteRunner.RunScriptWithEnvReturns("stdout", nil)
@@ -400,6 +434,35 @@ var _ = Describe("Job", func() {
 					Expect(backupError).To(MatchError(ContainSubstring("some weird error")))
 				})
 			})
+
+			Context("and the workflow has an observer", func() {
+				var observer *backuperfakes.FakeObserver
+
+				BeforeEach(func() {
+					observer = new(backuperfakes.FakeObserver)
+					ctx = orchestrator.ContextWithObserver(context.Background(), "redis", observer)
+					remoteRunner.RunScriptWithEnvReturns("", fmt.Errorf("some weird error"))
+				})
+
+				It("tells the observer when the backup script starts and finishes", func() {
+					Expect(observer.NotifyCallCount()).To(Equal(2))
+
+					deploymentName, started := observer.NotifyArgsForCall(0)
+					Expect(deploymentName).To(Equal("redis"))
+					Expect(started).To(Equal(orchestrator.JobScriptStarted{
+						Instance: "instance/identifier",
+						Job:      "jobname",
+						Script:   "backup",
+					}))
+
+					_, event := observer.NotifyArgsForCall(1)
+					finished := event.(orchestrator.JobScriptFinished)
+					Expect(finished.Instance).To(Equal("instance/identifier"))
+					Expect(finished.Job).To(Equal("jobname"))
+					Expect(finished.Script).To(Equal("backup"))
+					Expect(finished.Error).To(Equal("some weird error"))
+				})
+			})
 		})
 	})
 
@@ -407,7 +470,7 @@ var _ = Describe("Job", func() {
 		var restoreError error
 
 		JustBeforeEach(func() {
-			restoreError = job.Restore()
+			restoreError = job.Restore(context.Background())
 		})
 
 		Context("job has no restore script", func() {
@@ -432,7 +495,7 @@ var _ = Describe("Job", func() {
 			It("uses the remote runner to run the script", func() {
 				Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
 
-				specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+				_, specifiedScriptPath, specifiedEnvVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 				Expect(specifiedScriptPath).To(Equal("/var/vcap/jobs/jobname/bin/bbr/restore"))
 				Expect(specifiedEnvVars).To(SatisfyAll(
 					HaveLen(2),
@@ -468,7 +531,7 @@ var _ = Describe("Job", func() {
 		var preBackupLockError error
 
 		JustBeforeEach(func() {
-			preBackupLockError = job.PreBackupLock()
+			preBackupLockError = job.PreBackupLock(context.Background())
 		})
 
 		Context("job has no pre-backup-lock script", func() {
@@ -493,7 +556,7 @@ var _ = Describe("Job", func() {
 			It("runs the script", func() {
 				By("calling the remote runner", func() {
 					Expect(remoteRunner.RunScriptCallCount()).To(Equal(1))
-					cmd, _ := remoteRunner.RunScriptArgsForCall(0)
+					_, cmd, _ := remoteRunner.RunScriptArgsForCall(0)
 					Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/pre-backup-lock"))
 				})
 
@@ -534,6 +597,32 @@ var _ = Describe("Job", func() {
 					})
 				})
 			})
+
+			Context("pre-backup-lock script runs for longer than its timeout", func() {
+				BeforeEach(func() {
+					metadata = instance.Metadata{
+						Timeouts: instance.ScriptTimeouts{"pre-backup-lock": 10 * time.Millisecond},
+					}
+					remoteRunner.RunScriptStub = func(ctx context.Context, _, _ string) (string, error) {
+						<-ctx.Done()
+						return "", ctx.Err()
+					}
+				})
+
+				It("cancels the script and fails with a timeout error", func() {
+					Expect(preBackupLockError).To(MatchError(ContainSubstring(
+						"Error attempting to run pre-backup-lock for job jobname on instance/identifier: pre-backup-lock script timed out after 10ms",
+					)))
+					Expect(errors.Cause(preBackupLockError)).To(Equal(
+						orchestrator.NewScriptTimeoutError("pre-backup-lock", 10*time.Millisecond),
+					))
+				})
+
+				It("kills the script on the instance as well, in case the SSH server ignores the aborted session", func() {
+					_, cmd, _ := remoteRunner.RunScriptArgsForCall(0)
+					Expect(cmd).To(Equal("timeout --kill-after=10s 0.01s /var/vcap/jobs/jobname/bin/bbr/pre-backup-lock"))
+				})
+			})
 		})
 	})
 
@@ -548,7 +637,7 @@ var _ = Describe("Job", func() {
 		})
 
 		JustBeforeEach(func() {
-			postBackupUnlockError = job.PostBackupUnlock(afterSuccessfulBackup)
+			postBackupUnlockError = job.PostBackupUnlock(context.Background(), afterSuccessfulBackup)
 		})
 
 		Context("job has no post-backup-unlock script", func() {
@@ -561,6 +650,23 @@ var _ = Describe("Job", func() {
 			It("should not run anything on the remote runner", func() {
 				Expect(remoteRunner.Invocations()).To(HaveLen(0))
 			})
+
+			Context("and the job was locked for backup", func() {
+				BeforeEach(func() {
+					jobScripts = instance.BackupAndRestoreScripts{
+						"/var/vcap/jobs/jobname/bin/bbr/pre-backup-lock",
+					}
+				})
+
+				It("logs how long the job was locked for", func() {
+					Expect(job.PreBackupLock(context.Background())).To(Succeed())
+					Expect(job.PostBackupUnlock(context.Background(), true)).To(Succeed())
+
+					Expect(string(logOutput.Contents())).To(MatchRegexp(
+						"INFO - jobname on instance/identifier was locked for [0-9.]+m?s",
+					))
+				})
+			})
 		})
 
 		Context("job has a post-backup-unlock script", func() {
@@ -573,7 +679,7 @@ var _ = Describe("Job", func() {
 
 				It("uses remote runner to run the script", func() {
 					Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
-					cmd, envVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+					_, cmd, envVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 					Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/post-backup-unlock"))
 					Expect(envVars).To(HaveKeyWithValue("BBR_AFTER_BACKUP_SCRIPTS_SUCCESSFUL", "true"))
 				})
@@ -587,6 +693,26 @@ var _ = Describe("Job", func() {
 						Expect(postBackupUnlockError).NotTo(HaveOccurred())
 					})
 				})
+
+				Context("and the job was locked for backup", func() {
+					BeforeEach(func() {
+						jobScripts = instance.BackupAndRestoreScripts{
+							"/var/vcap/jobs/jobname/bin/bbr/pre-backup-lock",
+							"/var/vcap/jobs/jobname/bin/bbr/post-backup-unlock",
+						}
+					})
+
+					It("logs how long the job was locked for", func() {
+						Expect(string(logOutput.Contents())).NotTo(ContainSubstring("was locked for"))
+
+						Expect(job.PreBackupLock(context.Background())).To(Succeed())
+						Expect(job.PostBackupUnlock(context.Background(), true)).To(Succeed())
+
+						Expect(string(logOutput.Contents())).To(MatchRegexp(
+							"INFO - jobname on instance/identifier was locked for [0-9.]+m?s",
+						))
+					})
+				})
 			})
 
 			Context("and is called after a failed backup", func() {
@@ -599,7 +725,7 @@ var _ = Describe("Job", func() {
 
 				It("uses remote runner to run the script", func() {
 					Expect(remoteRunner.RunScriptWithEnvCallCount()).To(Equal(1))
-					cmd, envVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
+					_, cmd, envVars, _ := remoteRunner.RunScriptWithEnvArgsForCall(0)
 					Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/post-backup-unlock"))
 					Expect(envVars).To(HaveKeyWithValue("BBR_AFTER_BACKUP_SCRIPTS_SUCCESSFUL", "false"))
 				})
@@ -614,6 +740,24 @@ var _ = Describe("Job", func() {
 				It("fails", func() {
 					Expect(postBackupUnlockError).To(MatchError(ContainSubstring("it failed")))
 				})
+
+				Context("and the job was locked for backup", func() {
+					BeforeEach(func() {
+						jobScripts = instance.BackupAndRestoreScripts{
+							"/var/vcap/jobs/jobname/bin/bbr/pre-backup-lock",
+							"/var/vcap/jobs/jobname/bin/bbr/post-backup-unlock",
+						}
+					})
+
+					It("still logs how long the job was locked for", func() {
+						Expect(job.PreBackupLock(context.Background())).To(Succeed())
+						Expect(job.PostBackupUnlock(context.Background(), true)).To(MatchError(ContainSubstring("it failed")))
+
+						Expect(string(logOutput.Contents())).To(MatchRegexp(
+							"INFO - jobname on instance/identifier was locked for [0-9.]+m?s",
+						))
+					})
+				})
 			})
 		})
 	})
@@ -628,7 +772,7 @@ var _ = Describe("Job", func() {
 		var preRestoreLockError error
 
 		JustBeforeEach(func() {
-			preRestoreLockError = job.PreRestoreLock()
+			preRestoreLockError = job.PreRestoreLock(context.Background())
 		})
 
 		Context("job has no pre-restore-lock script", func() {
@@ -653,7 +797,7 @@ var _ = Describe("Job", func() {
 			It("runs the script", func() {
 				By("using the remote runner", func() {
 					Expect(remoteRunner.RunScriptCallCount()).To(Equal(1))
-					cmd, _ := remoteRunner.RunScriptArgsForCall(0)
+					_, cmd, _ := remoteRunner.RunScriptArgsForCall(0)
 					Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/pre-restore-lock"))
 				})
 
@@ -701,7 +845,7 @@ var _ = Describe("Job", func() {
 		var postRestoreUnlockError error
 
 		JustBeforeEach(func() {
-			postRestoreUnlockError = job.PostRestoreUnlock()
+			postRestoreUnlockError = job.PostRestoreUnlock(context.Background())
 		})
 
 		Context("job has no post-restore-unlock script", func() {
@@ -725,7 +869,7 @@ var _ = Describe("Job", func() {
 
 			It("uses the remote runner to run the script", func() {
 				Expect(remoteRunner.RunScriptCallCount()).To(Equal(1))
-				cmd, _ := remoteRunner.RunScriptArgsForCall(0)
+				_, cmd, _ := remoteRunner.RunScriptArgsForCall(0)
 				Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/post-restore-unlock"))
 			})
 
@@ -750,4 +894,70 @@ var _ = Describe("Job", func() {
 			})
 		})
 	})
+
+	Describe("PostRestoreValidate", func() {
+		var postRestoreValidateError error
+
+		JustBeforeEach(func() {
+			postRestoreValidateError = job.PostRestoreValidate(context.Background())
+		})
+
+		Context("job has no post-restore-validate script", func() {
+			BeforeEach(func() {
+				jobScripts = instance.BackupAndRestoreScripts{
+					"/var/vcap/jobs/jobname/bin/bbr/restore",
+					"/var/vcap/jobs/jobname/bin/bbr/post-restore-unlock",
+				}
+			})
+
+			It("should not run anything on the remote runner", func() {
+				Expect(postRestoreValidateError).NotTo(HaveOccurred())
+				Expect(remoteRunner.Invocations()).To(HaveLen(0))
+			})
+		})
+
+		Context("job has a post-restore-validate script", func() {
+			BeforeEach(func() {
+				jobScripts = instance.BackupAndRestoreScripts{
+					"/var/vcap/jobs/jobname/bin/bbr/restore",
+					"/var/vcap/jobs/jobname/bin/bbr/post-restore-validate",
+				}
+			})
+
+			It("uses the remote runner to run the script", func() {
+				Expect(postRestoreValidateError).NotTo(HaveOccurred())
+				Expect(remoteRunner.RunScriptCallCount()).To(Equal(1))
+				_, cmd, _ := remoteRunner.RunScriptArgsForCall(0)
+				Expect(cmd).To(Equal("/var/vcap/jobs/jobname/bin/bbr/post-restore-validate"))
+			})
+
+			Context("post-restore-validate script fails", func() {
+				BeforeEach(func() {
+					remoteRunner.RunScriptReturns("", fmt.Errorf("data is corrupt"))
+				})
+
+				It("fails", func() {
+					Expect(postRestoreValidateError).To(MatchError(
+						"Error attempting to run post-restore-validate for job jobname on instance/identifier: data is corrupt",
+					))
+				})
+			})
+
+			Context("post-restore-validate script runs for longer than its timeout", func() {
+				BeforeEach(func() {
+					metadata = instance.Metadata{Timeouts: instance.ScriptTimeouts{"post-restore-validate": 10 * time.Millisecond}}
+					remoteRunner.RunScriptStub = func(ctx context.Context, _, _ string) (string, error) {
+						<-ctx.Done()
+						return "", ctx.Err()
+					}
+				})
+
+				It("fails with a timeout error", func() {
+					Expect(errors.Cause(postRestoreValidateError)).To(Equal(
+						orchestrator.NewScriptTimeoutError("post-restore-validate", 10*time.Millisecond),
+					))
+				})
+			})
+		})
+	})
 })
@@ -13,11 +13,12 @@ type LockBefore struct {
 }
 
 type Metadata struct {
-	BackupName                  string       `yaml:"backup_name"`
-	RestoreName                 string       `yaml:"restore_name"`
-	BackupShouldBeLockedBefore  []LockBefore `yaml:"backup_should_be_locked_before"`
-	RestoreShouldBeLockedBefore []LockBefore `yaml:"restore_should_be_locked_before"`
-	SkipBBRScripts              bool         `yaml:"skip_bbr_scripts"`
+	BackupName                  string         `yaml:"backup_name"`
+	RestoreName                 string         `yaml:"restore_name"`
+	BackupShouldBeLockedBefore  []LockBefore   `yaml:"backup_should_be_locked_before"`
+	RestoreShouldBeLockedBefore []LockBefore   `yaml:"restore_should_be_locked_before"`
+	SkipBBRScripts              bool           `yaml:"skip_bbr_scripts"`
+	Timeouts                    ScriptTimeouts `yaml:"timeouts"`
 }
 
 func ParseJobMetadata(data string) (*Metadata, error) {
@@ -34,6 +35,11 @@ func ParseJobMetadata(data string) (*Metadata, error) {
 		}
 	}
 
+	err = metadata.Timeouts.Validate()
+	if err != nil {
+		return nil, err
+	}
+
 	return metadata, nil
 }
 
@@ -1,6 +1,8 @@
 package instance_test
 
 import (
+	"time"
+
 	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 
 	. "github.com/onsi/ginkgo"
@@ -116,6 +118,31 @@ restore_should_be_locked_before:
 		Expect(m.RestoreShouldBeLockedBefore).To(ConsistOf(expectedLockBefores))
 	})
 
+	It("has an optional `timeouts` field", func() {
+		rawMetadata := `---
+timeouts:
+  pre-backup-lock: 5m
+  backup: 2h`
+
+		m, err := metadataParserFunc(rawMetadata)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.Timeouts).To(Equal(ScriptTimeouts{
+			"pre-backup-lock": 5 * time.Minute,
+			"backup":          2 * time.Hour,
+		}))
+	})
+
+	It("errors if a timeout is set for an unknown script", func() {
+		rawMetadata := `---
+timeouts:
+  metadata: 5m`
+
+		_, err := metadataParserFunc(rawMetadata)
+
+		Expect(err).To(MatchError(ContainSubstring("timeouts can not be set for unknown script metadata")))
+	})
+
 	It("errors if either the job name or release are missing", func() {
 		rawMetadata := `---
 backup_name: foo
@@ -8,25 +8,27 @@ import (
 type Script string
 
 const (
-	backupScriptName            = "backup"
-	restoreScriptName           = "restore"
-	metadataScriptName          = "metadata"
-	preBackupLockScriptName     = "pre-backup-lock"
-	preRestoreLockScriptName    = "pre-restore-lock"
-	postBackupUnlockScriptName  = "post-backup-unlock"
-	postRestoreUnlockScriptName = "post-restore-unlock"
-
-	jobBaseDirectory               = "/var/vcap/jobs/"
-	jobDirectoryMatcher            = jobBaseDirectory + "*/bin/bbr/"
-	mySQLBackupScriptMatcher       = jobBaseDirectory + "mysql-backup/bin/bbr/*"
-	mySQLRestoreScriptMatcher      = jobBaseDirectory + "mysql-restore/bin/bbr/*"
-	backupScriptMatcher            = jobDirectoryMatcher + backupScriptName
-	restoreScriptMatcher           = jobDirectoryMatcher + restoreScriptName
-	metadataScriptMatcher          = jobDirectoryMatcher + metadataScriptName
-	preBackupLockScriptMatcher     = jobDirectoryMatcher + preBackupLockScriptName
-	preRestoreLockScriptMatcher    = jobDirectoryMatcher + preRestoreLockScriptName
-	postBackupUnlockScriptMatcher  = jobDirectoryMatcher + postBackupUnlockScriptName
-	postRestoreUnlockScriptMatcher = jobDirectoryMatcher + postRestoreUnlockScriptName
+	backupScriptName              = "backup"
+	restoreScriptName             = "restore"
+	metadataScriptName            = "metadata"
+	preBackupLockScriptName       = "pre-backup-lock"
+	preRestoreLockScriptName      = "pre-restore-lock"
+	postBackupUnlockScriptName    = "post-backup-unlock"
+	postRestoreUnlockScriptName   = "post-restore-unlock"
+	postRestoreValidateScriptName = "post-restore-validate"
+
+	jobBaseDirectory                 = "/var/vcap/jobs/"
+	jobDirectoryMatcher              = jobBaseDirectory + "*/bin/bbr/"
+	mySQLBackupScriptMatcher         = jobBaseDirectory + "mysql-backup/bin/bbr/*"
+	mySQLRestoreScriptMatcher        = jobBaseDirectory + "mysql-restore/bin/bbr/*"
+	backupScriptMatcher              = jobDirectoryMatcher + backupScriptName
+	restoreScriptMatcher             = jobDirectoryMatcher + restoreScriptName
+	metadataScriptMatcher            = jobDirectoryMatcher + metadataScriptName
+	preBackupLockScriptMatcher       = jobDirectoryMatcher + preBackupLockScriptName
+	preRestoreLockScriptMatcher      = jobDirectoryMatcher + preRestoreLockScriptName
+	postBackupUnlockScriptMatcher    = jobDirectoryMatcher + postBackupUnlockScriptName
+	postRestoreUnlockScriptMatcher   = jobDirectoryMatcher + postRestoreUnlockScriptName
+	postRestoreValidateScriptMatcher = jobDirectoryMatcher + postRestoreValidateScriptName
 )
 
 func (s Script) isBackup() bool {
@@ -64,6 +66,11 @@ func (s Script) isPostRestoreUnlock() bool {
 	return match
 }
 
+func (s Script) isPostRestoreValidate() bool {
+	match, _ := filepath.Match(postRestoreValidateScriptMatcher, string(s))
+	return match
+}
+
 func (s Script) isMySQLScript() bool {
 	backupMatch, _ := filepath.Match(mySQLBackupScriptMatcher, string(s))
 	restoreMatch, _ := filepath.Match(mySQLRestoreScriptMatcher, string(s))
@@ -81,6 +88,7 @@ func (s Script) isPlatformScript() bool {
 		s.isPreRestoreLock() ||
 		s.isPostBackupUnlock() ||
 		s.isPostRestoreUnlock() ||
+		s.isPostRestoreValidate() ||
 		s.isMetadata()
 }
 
@@ -0,0 +1,59 @@
+package instance
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ScriptTimeouts maps a lifecycle script name, such as "pre-backup-lock", to
+// the longest time that script may run for. Scripts without an entry are not
+// timed out.
+type ScriptTimeouts map[string]time.Duration
+
+var timeoutableScriptNames = []string{
+	preBackupLockScriptName,
+	backupScriptName,
+	postBackupUnlockScriptName,
+	preRestoreLockScriptName,
+	restoreScriptName,
+	postRestoreUnlockScriptName,
+	postRestoreValidateScriptName,
+}
+
+func (t ScriptTimeouts) Validate() error {
+	for scriptName, timeout := range t {
+		if !isTimeoutableScriptName(scriptName) {
+			return errors.Errorf("timeouts can not be set for unknown script %s", scriptName)
+		}
+		if timeout <= 0 {
+			return errors.Errorf("timeout for %s script must be positive, got %s", scriptName, timeout)
+		}
+	}
+	return nil
+}
+
+func (t ScriptTimeouts) withDefaults(defaults ScriptTimeouts) ScriptTimeouts {
+	merged := ScriptTimeouts{}
+	for scriptName, timeout := range defaults {
+		if timeout > 0 {
+			merged[scriptName] = timeout
+		}
+	}
+	for scriptName, timeout := range t {
+		merged[scriptName] = timeout
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+func isTimeoutableScriptName(scriptName string) bool {
+	for _, name := range timeoutableScriptNames {
+		if name == scriptName {
+			return true
+		}
+	}
+	return false
+}
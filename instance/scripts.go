@@ -84,6 +84,16 @@ func (s BackupAndRestoreScripts) PostBackupUnlockOnly() BackupAndRestoreScripts
 	return scripts
 }
 
+func (s BackupAndRestoreScripts) PostRestoreValidateOnly() BackupAndRestoreScripts {
+	scripts := BackupAndRestoreScripts{}
+	for _, script := range s {
+		if script.isPostRestoreValidate() {
+			scripts = append(scripts, script)
+		}
+	}
+	return scripts
+}
+
 func (s BackupAndRestoreScripts) SinglePostRestoreUnlockScript() Script {
 	for _, script := range s {
 		if script.isPostRestoreUnlock() {
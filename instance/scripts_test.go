@@ -135,6 +135,19 @@ var _ = Describe("Backup and Restore Scripts", func() {
 			})
 		})
 
+		Context("PostRestoreValidate", func() {
+			It("returns the matching scripts", func() {
+				var allScripts = []string{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
+					"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-restore-unlock",
+					"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-restore-validate",
+					"/var/vcap/jobs/cloud_controller_clock/bin/pre-start"}
+				Expect(NewBackupAndRestoreScripts(allScripts)).To(Equal(BackupAndRestoreScripts{
+					"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-restore-unlock",
+					"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-restore-validate",
+				}))
+			})
+		})
+
 		Context("Metadata", func() {
 			It("returns the matching scripts", func() {
 				var allScripts = []string{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
@@ -314,6 +327,24 @@ var _ = Describe("Backup and Restore Scripts", func() {
 		})
 	})
 
+	Describe("PostRestoreValidateOnly", func() {
+		It("returns the post-restore-validate scripts when it only has one", func() {
+			s := BackupAndRestoreScripts{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
+				"/var/vcap/jobs/cloud_controller_clock/bin/bbr/restore",
+				"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-restore-unlock",
+				"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-restore-validate",
+				"/var/vcap/jobs/cloud_controller_clock/bin/pre-start"}
+			Expect(s.PostRestoreValidateOnly()).To(Equal(BackupAndRestoreScripts{"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-restore-validate"}))
+		})
+
+		It("returns empty when it has none", func() {
+			s := BackupAndRestoreScripts{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
+				"/var/vcap/jobs/cloud_controller_clock/bin/bbr/post-restore-unlock",
+				"/var/vcap/jobs/cloud_controller_clock/bin/pre-start"}
+			Expect(s.PostRestoreValidateOnly()).To(Equal(BackupAndRestoreScripts{}))
+		})
+	})
+
 	Describe("PostBackupUnlockOnly", func() {
 		It("returns the post-backup-unlock scripts when it only has one", func() {
 			s := BackupAndRestoreScripts{"/var/vcap/jobs/cloud_controller_clock/bin/baz",
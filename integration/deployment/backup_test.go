@@ -298,11 +298,11 @@ printf "backupcontent2" > $BBR_ARTIFACT_DIRECTORY/backupdump2
 						Expect(session.Out).To(gbytes.Say(`INFO - Copying backup for job redis on redis-dedicated-node/fake-uuid -- \d\d\d?% complete`))
 						Expect(session.Out).To(gbytes.Say("INFO - Finished copying backup -- for job redis on redis-dedicated-node/fake-uuid..."))
 						Expect(session.Out).To(gbytes.Say("INFO - Starting validity checks -- for job redis on redis-dedicated-node/fake-uuid..."))
-						Expect(session.Out).To(gbytes.Say(`DEBUG - Calculating shasum for local file ./backupdump[12]`))
-						Expect(session.Out).To(gbytes.Say(`DEBUG - Calculating shasum for local file ./backupdump[12]`))
 						Expect(session.Out).To(gbytes.Say("DEBUG - Calculating shasum for remote files"))
 						Expect(session.Out).To(gbytes.Say("DEBUG - Comparing shasums"))
 						Expect(session.Out).To(gbytes.Say("INFO - Finished validity checks -- for job redis on redis-dedicated-node/fake-uuid..."))
+						Expect(string(session.Out.Contents())).To(ContainSubstring("DEBUG - Calculating shasum for local file ./backupdump1"))
+						Expect(string(session.Out.Contents())).To(ContainSubstring("DEBUG - Calculating shasum for local file ./backupdump2"))
 
 						Expect(string(session.Out.Contents())).NotTo(ContainSubstring("Skipping disabled jobs:"))
 					})
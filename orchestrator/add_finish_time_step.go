@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"time"
 )
 
@@ -14,7 +15,7 @@ func NewAddFinishTimeStep(nowFunc func() time.Time) Step {
 	}
 }
 
-func (s *AddFinishTimeStep) Run(session *Session) error {
+func (s *AddFinishTimeStep) Run(ctx context.Context, session *Session) error {
 	if session.CurrentArtifact() != nil {
 		return session.CurrentArtifact().AddFinishTime(s.nowFunc())
 	}
@@ -20,9 +20,66 @@ type Backup interface {
 	AddChecksum(ArtifactIdentifier, BackupChecksum) error
 	CreateMetadataFileWithStartTime(time.Time) error
 	AddFinishTime(time.Time) error
+	MarkComplete() error
+	MarkDrainFailed() error
+	MarkResumed() error
 	FetchChecksum(ArtifactIdentifier) (BackupChecksum, error)
+	FetchPreviousChecksum(ArtifactIdentifier) (BackupChecksum, error)
+	ArtifactDrained(ArtifactIdentifier) (bool, error)
 	CalculateChecksum(ArtifactIdentifier) (BackupChecksum, error)
 	DeploymentMatches(string, []Instance) (bool, error)
+	BackedUpInstances() ([]InstanceMetadata, error)
 	SaveManifest(manifest string) error
+	AddDeploymentMetadata(DeploymentMetadata) error
+	Sign() error
+	VerifySignature() error
 	Valid() (bool, error)
 }
+
+// AbortableWriter is an artifact writer that can discard what was written to
+// it, rather than storing a truncated artifact.
+type AbortableWriter interface {
+	io.WriteCloser
+	Abort(cause error)
+}
+
+// AbortArtifact gives up on an artifact after a failed write. Writers that
+// can not be aborted are closed, and the artifact is overwritten by the next
+// attempt.
+func AbortArtifact(writer io.WriteCloser, cause error) {
+	if abortable, ok := writer.(AbortableWriter); ok {
+		abortable.Abort(cause)
+		return
+	}
+	writer.Close()
+}
+
+// DeploymentMetadata describes the deployment a backup was taken of, and the
+// director that deployed it, for recording alongside the artifacts.
+type DeploymentMetadata struct {
+	Name       string
+	BBRVersion string
+	Director   DirectorInfo
+	Instances  []InstanceMetadata
+}
+
+type InstanceMetadata struct {
+	Name      string
+	Index     string
+	ID        string
+	Bootstrap bool
+	Jobs      []JobSpecifier
+}
+
+func NewInstanceMetadata(instance Instance) InstanceMetadata {
+	instanceMetadata := InstanceMetadata{
+		Name:      instance.Name(),
+		Index:     instance.Index(),
+		ID:        instance.ID(),
+		Bootstrap: instance.IsBootstrap(),
+	}
+	for _, job := range instance.Jobs() {
+		instanceMetadata.Jobs = append(instanceMetadata.Jobs, JobSpecifier{Name: job.Name(), Release: job.Release()})
+	}
+	return instanceMetadata
+}
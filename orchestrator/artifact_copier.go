@@ -1,53 +1,58 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 )
 
 //go:generate counterfeiter -o fakes/fake_artifact_copier.go . ArtifactCopier
 type ArtifactCopier interface {
-	DownloadBackupFromDeployment(Backup, Deployment) error
-	UploadBackupToDeployment(Backup, Deployment) error
+	DownloadBackupFromDeployment(context.Context, Backup, Deployment, bool) error
+	UploadBackupToDeployment(context.Context, Backup, Deployment) error
 }
 
 type artifactCopier struct {
 	Logger
-	executor executor.Executor
+	executor    executor.Executor
+	retryPolicy retry.Policy
 }
 
-func NewArtifactCopier(executor executor.Executor, logger Logger) ArtifactCopier {
+func NewArtifactCopier(executor executor.Executor, retryPolicy retry.Policy, logger Logger) ArtifactCopier {
 	return artifactCopier{
-		Logger:   logger,
-		executor: executor,
+		Logger:      logger,
+		executor:    executor,
+		retryPolicy: retryPolicy,
 	}
 }
 
-func (c artifactCopier) DownloadBackupFromDeployment(localBackup Backup, deployment Deployment) error {
+func (c artifactCopier) DownloadBackupFromDeployment(ctx context.Context, localBackup Backup, deployment Deployment, verifyDrained bool) error {
 	instances := deployment.BackupableInstances()
 
 	var executables []executor.Executable
 	for _, instance := range instances {
 		for _, remoteBackupArtifact := range instance.ArtifactsToBackup() {
-			executables = append(executables, NewBackupDownloadExecutable(localBackup, remoteBackupArtifact, c.Logger))
+			executables = append(executables, NewBackupDownloadExecutable(localBackup, remoteBackupArtifact, c.retryPolicy, verifyDrained, c.Logger))
 		}
 	}
 
-	errs := c.executor.Run([][]executor.Executable{executables})
+	errs := c.executor.Run(ctx, [][]executor.Executable{executables})
 
 	return ConvertErrors(errs)
 }
 
-func (c artifactCopier) UploadBackupToDeployment(localBackup Backup, deployment Deployment) error {
+func (c artifactCopier) UploadBackupToDeployment(ctx context.Context, localBackup Backup, deployment Deployment) error {
 	instances := deployment.RestorableInstances()
 
 	var executables []executor.Executable
 	for _, instance := range instances {
 		for _, remoteBackupArtifact := range instance.ArtifactsToRestore() {
-			executables = append(executables, NewBackupUploadExecutable(localBackup, remoteBackupArtifact, instance, c.Logger))
+			executables = append(executables, NewBackupUploadExecutable(localBackup, remoteBackupArtifact, instance, c.retryPolicy, c.Logger))
 		}
 	}
 
-	errs := c.executor.Run([][]executor.Executable{executables})
+	errs := c.executor.Run(ctx, [][]executor.Executable{executables})
 
 	return ConvertErrors(errs)
 }
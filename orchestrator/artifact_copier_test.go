@@ -1,11 +1,13 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	executorFakes "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor/fakes"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -24,6 +26,8 @@ var _ = Describe("ArtifactCopier", func() {
 
 		remoteBackup1 *fakes.FakeBackupArtifact
 		remoteBackup2 *fakes.FakeBackupArtifact
+
+		retryPolicy retry.Policy
 	)
 
 	BeforeEach(func() {
@@ -40,7 +44,9 @@ var _ = Describe("ArtifactCopier", func() {
 		remoteBackup1 = new(fakes.FakeBackupArtifact)
 		remoteBackup2 = new(fakes.FakeBackupArtifact)
 
-		artifactCopier = orchestrator.NewArtifactCopier(fakeExecutor, logger)
+		retryPolicy = retry.Policy{Attempts: 3, ErrorClasses: []retry.ErrorClass{retry.ConnectionErrors}}
+
+		artifactCopier = orchestrator.NewArtifactCopier(fakeExecutor, retryPolicy, logger)
 	})
 
 	Context("DownloadBackupFromDeployment", func() {
@@ -51,7 +57,7 @@ var _ = Describe("ArtifactCopier", func() {
 		})
 
 		JustBeforeEach(func() {
-			err = artifactCopier.DownloadBackupFromDeployment(localBackup, deployment)
+			err = artifactCopier.DownloadBackupFromDeployment(context.Background(), localBackup, deployment, true)
 		})
 
 		It("downloads the backup from deployment", func() {
@@ -70,9 +76,10 @@ var _ = Describe("ArtifactCopier", func() {
 
 			By("running the executor with the executables", func() {
 				Expect(fakeExecutor.RunCallCount()).To(Equal(1))
-				Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{{
-					orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup1, logger),
-					orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup2, logger),
+				_, executables := fakeExecutor.RunArgsForCall(0)
+				Expect(executables).To(Equal([][]executor.Executable{{
+					orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup1, retryPolicy, true, logger),
+					orchestrator.NewBackupDownloadExecutable(localBackup, remoteBackup2, retryPolicy, true, logger),
 				}}))
 			})
 		})
@@ -96,7 +103,7 @@ var _ = Describe("ArtifactCopier", func() {
 		})
 
 		JustBeforeEach(func() {
-			err = artifactCopier.UploadBackupToDeployment(localBackup, deployment)
+			err = artifactCopier.UploadBackupToDeployment(context.Background(), localBackup, deployment)
 		})
 
 		It("uploads the backup to the deployment", func() {
@@ -115,9 +122,10 @@ var _ = Describe("ArtifactCopier", func() {
 
 			By("running the executor with the executables", func() {
 				Expect(fakeExecutor.RunCallCount()).To(Equal(1))
-				Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{{
-					orchestrator.NewBackupUploadExecutable(localBackup, remoteBackup1, instance1, logger),
-					orchestrator.NewBackupUploadExecutable(localBackup, remoteBackup2, instance2, logger),
+				_, executables := fakeExecutor.RunArgsForCall(0)
+				Expect(executables).To(Equal([][]executor.Executable{{
+					orchestrator.NewBackupUploadExecutable(localBackup, remoteBackup1, instance1, retryPolicy, logger),
+					orchestrator.NewBackupUploadExecutable(localBackup, remoteBackup2, instance2, retryPolicy, logger),
 				}}))
 			})
 		})
@@ -1,5 +1,7 @@
 package orchestrator
 
+import "context"
+
 type BackupChecker struct {
 	*Workflow
 }
@@ -22,7 +24,7 @@ func NewBackupChecker(logger Logger, deploymentManager DeploymentManager, lockOr
 func (b BackupChecker) Check(deploymentName string) Error {
 	session := NewSession(deploymentName)
 
-	err := b.Workflow.Run(session)
+	err := b.Workflow.Run(context.Background(), session)
 
 	return err
 }
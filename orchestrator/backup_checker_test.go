@@ -45,7 +45,8 @@ var _ = Describe("Check", func() {
 
 		It("finds the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
-			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+			_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
 		})
 
 		It("checks if the deployment is backupable", func() {
@@ -74,7 +75,8 @@ var _ = Describe("Check", func() {
 
 		It("attempts to find the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
-			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+			_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
 		})
 	})
 })
@@ -1,6 +1,10 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 func NewBackupCleaner(logger Logger, deploymentManager DeploymentManager, lockOrderer LockOrderer,
 	executor executor.Executor) *BackupCleaner {
@@ -27,7 +31,7 @@ type BackupCleaner struct {
 
 func (c BackupCleaner) Cleanup(deploymentName string) Error {
 	session := NewSession(deploymentName)
-	currentError := c.Workflow.Run(session)
+	currentError := c.Workflow.Run(context.Background(), session)
 
 	if len(currentError) == 0 {
 		c.Logger.Info("bbr", "'%s' cleaned up\n", deploymentName)
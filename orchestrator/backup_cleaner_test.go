@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -42,7 +43,8 @@ var _ = Describe("Cleanup", func() {
 
 		It("finds the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
-			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+			_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
 		})
 
 		It("ensures that deployment is cleaned up", func() {
@@ -51,7 +53,7 @@ var _ = Describe("Cleanup", func() {
 
 		It("ensures that deployment is unlocked using the provided lockOrderer", func() {
 			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
-			actualAfterSuccessfulBackup, actualLockOrderer, _ := deployment.PostBackupUnlockArgsForCall(0)
+			_, actualAfterSuccessfulBackup, actualLockOrderer, _ := deployment.PostBackupUnlockArgsForCall(0)
 			Expect(actualAfterSuccessfulBackup).To(BeFalse())
 			Expect(actualLockOrderer).To(Equal(lockOrderer))
 		})
@@ -61,7 +63,7 @@ var _ = Describe("Cleanup", func() {
 		var currentSequenceNumber, unlockCallIndex, cleanupCallIndex int
 		BeforeEach(func() {
 			deploymentManager.FindReturns(deployment, nil)
-			deployment.PostBackupUnlockStub = func(afterSuccessfulBackup bool, orderer orchestrator.LockOrderer, runner executor.Executor) error {
+			deployment.PostBackupUnlockStub = func(_ context.Context, afterSuccessfulBackup bool, orderer orchestrator.LockOrderer, runner executor.Executor) error {
 				unlockCallIndex = currentSequenceNumber
 				currentSequenceNumber = currentSequenceNumber + 1
 				return nil
@@ -87,7 +89,8 @@ var _ = Describe("Cleanup", func() {
 
 		It("attempts to find the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
-			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+			_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
 		})
 
 		It("fails", func() {
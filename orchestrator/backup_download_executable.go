@@ -1,33 +1,58 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
+	"io"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/readwriter"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	"github.com/pkg/errors"
 )
 
 type BackupDownloadExecutable struct {
 	localBackup    Backup
 	remoteArtifact BackupArtifact
+	retryPolicy    retry.Policy
+	verifyDrained  bool
 	Logger
 }
 
-func NewBackupDownloadExecutable(localBackup Backup, remoteArtifact BackupArtifact, logger Logger) BackupDownloadExecutable {
+func NewBackupDownloadExecutable(localBackup Backup, remoteArtifact BackupArtifact, retryPolicy retry.Policy, verifyDrained bool, logger Logger) BackupDownloadExecutable {
 	return BackupDownloadExecutable{
 		localBackup:    localBackup,
 		remoteArtifact: remoteArtifact,
+		retryPolicy:    retryPolicy,
+		verifyDrained:  verifyDrained,
 		Logger:         logger,
 	}
 }
 
-func (e BackupDownloadExecutable) Execute() error {
-	err := e.downloadBackupArtifact(e.localBackup, e.remoteArtifact)
+func (e BackupDownloadExecutable) Execute(ctx context.Context) error {
+	drained, err := e.localBackup.ArtifactDrained(e.remoteArtifact)
 	if err != nil {
 		return err
 	}
 
-	checksum, err := e.compareChecksums(e.localBackup, e.remoteArtifact)
+	if drained && e.verifyDrained {
+		return e.verifyDrainedArtifact(ctx, e.localBackup, e.remoteArtifact)
+	} else if drained {
+		e.Logger.Info("bbr", "Backup for job %s on %s/%s was already copied, skipping it...", e.remoteArtifact.Name(), e.remoteArtifact.InstanceName(), e.remoteArtifact.InstanceID())
+		return nil
+	}
+
+	previousChecksum, err := e.localBackup.FetchPreviousChecksum(e.remoteArtifact)
+	if err != nil {
+		return err
+	}
+
+	var checksum BackupChecksum
+	description := fmt.Sprintf("Copying backup for job %s on %s/%s", e.remoteArtifact.Name(), e.remoteArtifact.InstanceName(), e.remoteArtifact.InstanceID())
+	err = e.retryPolicy.Do(ctx, e.Logger, description, func() error {
+		var err error
+		checksum, err = e.copyArtifact(ctx, previousChecksum)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -46,66 +71,175 @@ func (e BackupDownloadExecutable) Execute() error {
 	return nil
 }
 
-func (e BackupDownloadExecutable) downloadBackupArtifact(localBackup Backup, remoteBackupArtifact BackupArtifact) error {
+// copyArtifact makes a single attempt at copying the remote artifact and
+// checking it against the remote checksums. Each attempt creates the local
+// artifact afresh, truncating whatever a failed attempt had written to it.
+func (e BackupDownloadExecutable) copyArtifact(ctx context.Context, previousChecksum BackupChecksum) (BackupChecksum, error) {
+	if previousChecksum != nil {
+		return e.downloadChangedFiles(ctx, e.localBackup, e.remoteArtifact, previousChecksum)
+	}
+
+	localChecksum, err := e.downloadBackupArtifact(ctx, e.localBackup, e.remoteArtifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.compareChecksums(ctx, localChecksum, e.remoteArtifact)
+}
+
+// downloadBackupArtifact streams the remote artifact into the local backup and
+// returns the checksum of the tar stream, calculated as it is copied.
+func (e BackupDownloadExecutable) downloadBackupArtifact(ctx context.Context, localBackup Backup, remoteBackupArtifact BackupArtifact) (BackupChecksum, error) {
 	localBackupArtifactWriter, err := localBackup.CreateArtifact(remoteBackupArtifact)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	size, err := remoteBackupArtifact.Size()
+	size, err := remoteBackupArtifact.Size(ctx)
 	if err != nil {
-		return err
+		AbortArtifact(localBackupArtifactWriter, err)
+		return nil, err
 	}
 
-	sizeInBytes, err := remoteBackupArtifact.SizeInBytes()
+	sizeInBytes, err := remoteBackupArtifact.SizeInBytes(ctx)
 	if err != nil {
-		return err
+		AbortArtifact(localBackupArtifactWriter, err)
+		return nil, err
 	}
 
+	checksumWriter := newTarChecksumWriter(e.Logger)
+	defer checksumWriter.Close()
+
 	percentageMessage := fmt.Sprintf("Copying backup for job %s on %s/%s -- %%d%%%% complete", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
-	percentageLogger := readwriter.NewLogPercentageWriter(localBackupArtifactWriter, e.Logger, sizeInBytes, "bbr", percentageMessage)
+	percentageLogger := readwriter.NewLogPercentageWriter(io.MultiWriter(localBackupArtifactWriter, checksumWriter), e.Logger, sizeInBytes, "bbr", percentageMessage)
+	percentageLogger.OnProgress(notifyTransferProgress(ctx, remoteBackupArtifact, Download))
 
 	e.Logger.Info("bbr", "Copying backup -- %s uncompressed -- for job %s on %s/%s...", size, remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
-	err = remoteBackupArtifact.StreamFromRemote(percentageLogger)
+	err = remoteBackupArtifact.StreamFromRemote(ctx, percentageLogger)
 	if err != nil {
-		return err
+		AbortArtifact(localBackupArtifactWriter, err)
+		return nil, err
 	}
 
 	err = localBackupArtifactWriter.Close()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	localChecksum, err := checksumWriter.Checksum()
+	if err != nil {
+		return nil, err
 	}
 
 	e.Logger.Info("bbr", "Finished copying backup -- for job %s on %s/%s...", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
-	return nil
+	return localChecksum, nil
 }
 
-func (e BackupDownloadExecutable) compareChecksums(localBackup Backup, remoteBackupArtifact BackupArtifact) (BackupChecksum, error) {
-	e.Logger.Info("bbr", "Starting validity checks -- for job %s on %s/%s...", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
+// downloadChangedFiles only streams the files whose checksum differs from the
+// previous backup of the artifact. It returns the checksum of every remote
+// file, as the unchanged files are still part of the artifact.
+func (e BackupDownloadExecutable) downloadChangedFiles(ctx context.Context, localBackup Backup, remoteBackupArtifact BackupArtifact, previousChecksum BackupChecksum) (BackupChecksum, error) {
+	remoteChecksum, err := remoteBackupArtifact.Checksum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changedFiles := remoteChecksum.ChangedSince(previousChecksum)
 
-	localChecksum, err := localBackup.CalculateChecksum(remoteBackupArtifact)
+	localBackupArtifactWriter, err := localBackup.CreateArtifact(remoteBackupArtifact)
 	if err != nil {
 		return nil, err
 	}
 
-	remoteChecksum, err := remoteBackupArtifact.Checksum()
+	checksumWriter := newTarChecksumWriter(e.Logger)
+	defer checksumWriter.Close()
+
+	e.Logger.Info("bbr", "Copying %d of %d files changed since the previous backup -- for job %s on %s/%s...", len(changedFiles), len(remoteChecksum), remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
+	err = remoteBackupArtifact.StreamFilesFromRemote(ctx, changedFiles, io.MultiWriter(localBackupArtifactWriter, checksumWriter))
 	if err != nil {
+		AbortArtifact(localBackupArtifactWriter, err)
 		return nil, err
 	}
 
+	err = localBackupArtifactWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	localChecksum, err := checksumWriter.Checksum()
+	if err != nil {
+		return nil, err
+	}
+
+	e.Logger.Info("bbr", "Finished copying backup -- for job %s on %s/%s...", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
+
+	e.Logger.Info("bbr", "Starting validity checks -- for job %s on %s/%s...", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
+	if err := e.matchChecksums(ctx, localChecksum, remoteChecksum.Only(changedFiles), remoteBackupArtifact); err != nil {
+		return nil, err
+	}
+
+	return remoteChecksum, nil
+}
+
+func (e BackupDownloadExecutable) compareChecksums(ctx context.Context, localChecksum BackupChecksum, remoteBackupArtifact BackupArtifact) (BackupChecksum, error) {
+	e.Logger.Info("bbr", "Starting validity checks -- for job %s on %s/%s...", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
+
+	remoteChecksum, err := remoteBackupArtifact.Checksum(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.matchChecksums(ctx, localChecksum, remoteChecksum, remoteBackupArtifact); err != nil {
+		return nil, err
+	}
+
+	return localChecksum, nil
+}
+
+func (e BackupDownloadExecutable) matchChecksums(ctx context.Context, localChecksum, remoteChecksum BackupChecksum, remoteBackupArtifact BackupArtifact) error {
 	e.Logger.Debug("bbr", "Comparing shasums")
 
 	match, mismatchedFiles := localChecksum.Match(remoteChecksum)
+	notifyChecksumResult(ctx, remoteBackupArtifact, match, mismatchedFiles)
 	if !match {
 		e.Logger.Debug("bbr", "Checksums didn't match for:")
 		e.Logger.Debug("bbr", fmt.Sprintf("%v\n", mismatchedFiles))
 
-		err = errors.Errorf(
+		return retry.NewChecksumError(errors.Errorf(
 			"Backup is corrupted, checksum failed for %s/%s %s - checksums don't match for %v. "+
 				"Checksum failed for %d files in total",
+			remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID(), remoteBackupArtifact.Name(), getFirstTen(mismatchedFiles), len(mismatchedFiles)))
+	}
+
+	return nil
+}
+
+// verifyDrainedArtifact checks an artifact that was drained before a resumed
+// backup against the checksum recorded for it, instead of downloading it again.
+// It reads the whole artifact, so it is only done when asked for.
+func (e BackupDownloadExecutable) verifyDrainedArtifact(ctx context.Context, localBackup Backup, remoteBackupArtifact BackupArtifact) error {
+	e.Logger.Info("bbr", "Backup for job %s on %s/%s was already copied, checking it is intact...", remoteBackupArtifact.Name(), remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID())
+
+	recordedChecksum, err := localBackup.FetchChecksum(remoteBackupArtifact)
+	if err != nil {
+		return err
+	}
+
+	localChecksum, err := localBackup.CalculateChecksum(remoteBackupArtifact)
+	if err != nil {
+		return err
+	}
+
+	match, mismatchedFiles := localChecksum.Match(recordedChecksum)
+	notifyChecksumResult(ctx, remoteBackupArtifact, match, mismatchedFiles)
+	if !match {
+		e.Logger.Debug("bbr", "Checksums didn't match for:")
+		e.Logger.Debug("bbr", fmt.Sprintf("%v\n", mismatchedFiles))
+
+		return errors.Errorf(
+			"Backup is corrupted, checksum failed for previously copied %s/%s %s - checksums don't match for %v. "+
+				"Checksum failed for %d files in total",
 			remoteBackupArtifact.InstanceName(), remoteBackupArtifact.InstanceID(), remoteBackupArtifact.Name(), getFirstTen(mismatchedFiles), len(mismatchedFiles))
-		return nil, err
 	}
 
-	return localChecksum, nil
+	return nil
 }
@@ -1,12 +1,20 @@
 package orchestrator_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/readwriter"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -18,6 +26,10 @@ var _ = Describe("BackupDownloadExecutable", func() {
 		remoteArtifact            *fakes.FakeBackupArtifact
 		logger                    *fakes.FakeLogger
 		localBackupArtifactWriter *fakes.FakeWriteCloser
+		tarContents               []byte
+		retryPolicy               retry.Policy
+		verifyDrained             bool
+		ctx                       context.Context
 		actualError               error
 	)
 	BeforeEach(func() {
@@ -25,13 +37,33 @@ var _ = Describe("BackupDownloadExecutable", func() {
 		remoteArtifact = new(fakes.FakeBackupArtifact)
 		logger = new(fakes.FakeLogger)
 		localBackupArtifactWriter = new(fakes.FakeWriteCloser)
+		retryPolicy = retry.Policy{}
+		verifyDrained = false
+		ctx = context.Background()
 
+		localBackupArtifactWriter.WriteStub = func(p []byte) (int, error) {
+			return len(p), nil
+		}
 		localBackup.CreateArtifactReturns(localBackupArtifactWriter, nil)
+
+		tarContents = createTarWithContents(map[string]string{
+			"file1": "This archive contains some text files.",
+			"file2": "Gopher names:\nGeorge\nGeoffrey\nGonzo",
+		})
+		remoteArtifact.SizeInBytesReturns(len(tarContents), nil)
+		remoteArtifact.StreamFromRemoteStub = func(_ context.Context, writer io.Writer) error {
+			_, err := writer.Write(tarContents)
+			return err
+		}
+		remoteArtifact.ChecksumReturns(orchestrator.BackupChecksum{
+			"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+			"file2": fmt.Sprintf("%x", sha256.Sum256([]byte("Gopher names:\nGeorge\nGeoffrey\nGonzo"))),
+		}, nil)
 	})
 
 	JustBeforeEach(func() {
-		executable = orchestrator.NewBackupDownloadExecutable(localBackup, remoteArtifact, logger)
-		actualError = executable.Execute()
+		executable = orchestrator.NewBackupDownloadExecutable(localBackup, remoteArtifact, retryPolicy, verifyDrained, logger)
+		actualError = executable.Execute(ctx)
 	})
 
 	It("downloads the artifact", func() {
@@ -48,20 +80,30 @@ var _ = Describe("BackupDownloadExecutable", func() {
 			Expect(remoteArtifact.SizeCallCount()).To(Equal(1))
 		})
 
-		By("streaming from the remote artifact", func() {
+		By("streaming from the remote artifact into the local artifact", func() {
 			Expect(remoteArtifact.StreamFromRemoteCallCount()).To(Equal(1))
-			streamWriter := remoteArtifact.StreamFromRemoteArgsForCall(0)
+			_, streamWriter := remoteArtifact.StreamFromRemoteArgsForCall(0)
 			Expect(streamWriter).To(BeAssignableToTypeOf(&readwriter.LogPercentageWriter{}))
-			logPercentageWriter := streamWriter.(*readwriter.LogPercentageWriter)
-			Expect(logPercentageWriter.Writer).To(Equal(localBackupArtifactWriter))
+			Expect(localBackupArtifactWriter.WriteCallCount()).To(Equal(1))
+			Expect(localBackupArtifactWriter.WriteArgsForCall(0)).To(Equal(tarContents))
 		})
 
 		By("closing the local backup artifact writer", func() {
 			Expect(localBackupArtifactWriter.CloseCallCount()).To(Equal(1))
 		})
 
-		By("calculating the local checksum", func() {
-			Expect(localBackup.CalculateChecksumCallCount()).To(Equal(1))
+		By("calculating the local checksum while streaming, without reading the artifact back", func() {
+			Expect(localBackup.CalculateChecksumCallCount()).To(BeZero())
+			Expect(localBackup.ReadArtifactCallCount()).To(BeZero())
+		})
+
+		By("recording the checksum of the streamed files", func() {
+			Expect(localBackup.AddChecksumCallCount()).To(Equal(1))
+			_, checksum := localBackup.AddChecksumArgsForCall(0)
+			Expect(checksum).To(Equal(orchestrator.BackupChecksum{
+				"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+				"file2": fmt.Sprintf("%x", sha256.Sum256([]byte("Gopher names:\nGeorge\nGeoffrey\nGonzo"))),
+			}))
 		})
 
 		By("calculating the remote checksum", func() {
@@ -73,6 +115,62 @@ var _ = Describe("BackupDownloadExecutable", func() {
 		})
 	})
 
+	Context("When the workflow has an observer", func() {
+		var observer *fakes.FakeObserver
+
+		BeforeEach(func() {
+			observer = new(fakes.FakeObserver)
+			ctx = orchestrator.ContextWithObserver(context.Background(), "redis", observer)
+
+			remoteArtifact.NameReturns("redis-server")
+			remoteArtifact.InstanceNameReturns("redis")
+			remoteArtifact.InstanceIDReturns("f1b2c3")
+		})
+
+		It("tells the observer how the download progressed and whether the checksums matched", func() {
+			Expect(actualError).NotTo(HaveOccurred())
+			Expect(observer.NotifyCallCount()).To(Equal(2))
+
+			deploymentName, progress := observer.NotifyArgsForCall(0)
+			Expect(deploymentName).To(Equal("redis"))
+			Expect(progress).To(Equal(orchestrator.TransferProgress{
+				Instance:         "redis/f1b2c3",
+				Artifact:         "redis-server",
+				Direction:        orchestrator.Download,
+				BytesTransferred: len(tarContents),
+				TotalBytes:       len(tarContents),
+			}))
+
+			_, event := observer.NotifyArgsForCall(1)
+			checksumResult := event.(orchestrator.ChecksumResult)
+			Expect(checksumResult.Instance).To(Equal("redis/f1b2c3"))
+			Expect(checksumResult.Artifact).To(Equal("redis-server"))
+			Expect(checksumResult.Match).To(BeTrue())
+			Expect(checksumResult.MismatchedFiles).To(BeEmpty())
+		})
+
+		Context("and the checksums are mismatched", func() {
+			BeforeEach(func() {
+				remoteArtifact.ChecksumReturns(orchestrator.BackupChecksum{
+					"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+					"file2": "not matching",
+				}, nil)
+			})
+
+			It("tells the observer which files did not match", func() {
+				Expect(actualError).To(HaveOccurred())
+
+				_, checksumResult := observer.NotifyArgsForCall(observer.NotifyCallCount() - 1)
+				Expect(checksumResult).To(Equal(orchestrator.ChecksumResult{
+					Instance:        "redis/f1b2c3",
+					Artifact:        "redis-server",
+					Match:           false,
+					MismatchedFiles: []string{"file2"},
+				}))
+			})
+		})
+	})
+
 	Context("When the local artifact cannot be created", func() {
 		BeforeEach(func() {
 			localBackup.CreateArtifactReturns(nil, fmt.Errorf("create artifact error"))
@@ -101,6 +199,80 @@ var _ = Describe("BackupDownloadExecutable", func() {
 		It("should fail", func() {
 			Expect(actualError).To(MatchError("stream error"))
 		})
+
+		It("closes the local backup artifact writer", func() {
+			Expect(localBackupArtifactWriter.CloseCallCount()).To(Equal(1))
+		})
+
+		Context("and the local backup artifact can be aborted", func() {
+			var abortableWriter *abortableWriteCloser
+
+			BeforeEach(func() {
+				abortableWriter = &abortableWriteCloser{FakeWriteCloser: localBackupArtifactWriter}
+				localBackup.CreateArtifactReturns(abortableWriter, nil)
+			})
+
+			It("aborts the artifact rather than storing what was streamed", func() {
+				Expect(abortableWriter.abortedWith).To(MatchError("stream error"))
+				Expect(localBackupArtifactWriter.CloseCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("When streaming the remote artifact drops the connection and connection errors are retried", func() {
+		var firstAttemptWriter *fakes.FakeWriteCloser
+
+		BeforeEach(func() {
+			retryPolicy = retry.Policy{Attempts: 3, Backoff: time.Millisecond, ErrorClasses: []retry.ErrorClass{retry.ConnectionErrors}}
+
+			firstAttemptWriter = new(fakes.FakeWriteCloser)
+			firstAttemptWriter.WriteStub = func(p []byte) (int, error) {
+				return len(p), nil
+			}
+			localBackup.CreateArtifactReturnsOnCall(0, firstAttemptWriter, nil)
+			localBackup.CreateArtifactReturnsOnCall(1, localBackupArtifactWriter, nil)
+
+			remoteArtifact.StreamFromRemoteStub = func(_ context.Context, writer io.Writer) error {
+				if remoteArtifact.StreamFromRemoteCallCount() == 1 {
+					_, _ = writer.Write(tarContents[:10])
+					return io.ErrUnexpectedEOF
+				}
+				_, err := writer.Write(tarContents)
+				return err
+			}
+		})
+
+		It("downloads the artifact on the next attempt", func() {
+			Expect(actualError).NotTo(HaveOccurred())
+
+			By("re-creating the local artifact, discarding the partial download", func() {
+				Expect(localBackup.CreateArtifactCallCount()).To(Equal(2))
+				Expect(firstAttemptWriter.CloseCallCount()).To(Equal(1))
+				Expect(localBackupArtifactWriter.WriteArgsForCall(0)).To(Equal(tarContents))
+			})
+
+			By("recording the checksum once", func() {
+				Expect(localBackup.AddChecksumCallCount()).To(Equal(1))
+			})
+
+			By("logging the retry", func() {
+				Expect(logger.WarnCallCount()).To(Equal(1))
+				_, msg, args := logger.WarnArgsForCall(0)
+				Expect(fmt.Sprintf(msg, args...)).To(ContainSubstring("failed on attempt 1 of 3"))
+			})
+		})
+	})
+
+	Context("When streaming the remote artifact keeps failing with a non-retried error", func() {
+		BeforeEach(func() {
+			retryPolicy = retry.Policy{Attempts: 3, ErrorClasses: []retry.ErrorClass{retry.ConnectionErrors}}
+			remoteArtifact.StreamFromRemoteReturns(fmt.Errorf("stream error"))
+		})
+
+		It("fails without retrying", func() {
+			Expect(actualError).To(MatchError("stream error"))
+			Expect(remoteArtifact.StreamFromRemoteCallCount()).To(Equal(1))
+		})
 	})
 
 	Context("When the local backup writer cannot be closed", func() {
@@ -113,13 +285,26 @@ var _ = Describe("BackupDownloadExecutable", func() {
 		})
 	})
 
-	Context("When the local backup checksum cannot be calculated", func() {
+	Context("When the streamed artifact is not a valid tar", func() {
+		BeforeEach(func() {
+			remoteArtifact.StreamFromRemoteStub = func(_ context.Context, writer io.Writer) error {
+				_, err := writer.Write([]byte(strings.Repeat("not a tar file", 1000)))
+				return err
+			}
+		})
+
+		It("should fail", func() {
+			Expect(actualError).To(MatchError(ContainSubstring("Error reading tar")))
+		})
+	})
+
+	Context("When the local artifact cannot be written", func() {
 		BeforeEach(func() {
-			localBackup.CalculateChecksumReturns(nil, fmt.Errorf("local checksum error"))
+			localBackupArtifactWriter.WriteReturns(0, fmt.Errorf("disk full"))
 		})
 
 		It("should fail", func() {
-			Expect(actualError).To(MatchError("local checksum error"))
+			Expect(actualError).To(MatchError("disk full"))
 		})
 	})
 
@@ -135,13 +320,28 @@ var _ = Describe("BackupDownloadExecutable", func() {
 
 	Context("When the checksums are mismatched", func() {
 		BeforeEach(func() {
-			localBackup.CalculateChecksumReturns(orchestrator.BackupChecksum{"file1": "abcd", "file2": "not matching"}, nil)
-			remoteArtifact.ChecksumReturns(orchestrator.BackupChecksum{"file1": "abcd", "file2": "efgh"}, nil)
+			remoteArtifact.ChecksumReturns(orchestrator.BackupChecksum{
+				"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+				"file2": "not matching",
+			}, nil)
 		})
 
 		It("should fail", func() {
 			Expect(actualError).To(MatchError(ContainSubstring("Backup is corrupted, checksum failed")))
 		})
+
+		Context("and checksum errors are retried", func() {
+			BeforeEach(func() {
+				retryPolicy = retry.Policy{Attempts: 2, ErrorClasses: []retry.ErrorClass{retry.ChecksumErrors}}
+			})
+
+			It("copies the artifact again before failing", func() {
+				Expect(actualError).To(MatchError(ContainSubstring("Backup is corrupted, checksum failed")))
+				Expect(remoteArtifact.StreamFromRemoteCallCount()).To(Equal(2))
+				Expect(localBackup.CreateArtifactCallCount()).To(Equal(2))
+				Expect(localBackup.AddChecksumCallCount()).To(BeZero())
+			})
+		})
 	})
 
 	Context("When the checksum cannot be added to the local backup", func() {
@@ -163,4 +363,190 @@ var _ = Describe("BackupDownloadExecutable", func() {
 			Expect(actualError).To(MatchError("remote artifact deletion error"))
 		})
 	})
+
+	Context("When it cannot be determined whether the artifact was already drained", func() {
+		BeforeEach(func() {
+			localBackup.ArtifactDrainedReturns(false, fmt.Errorf("metadata error"))
+		})
+
+		It("should fail", func() {
+			Expect(actualError).To(MatchError("metadata error"))
+		})
+	})
+
+	Context("When the artifact was already drained by an interrupted backup", func() {
+		BeforeEach(func() {
+			localBackup.ArtifactDrainedReturns(true, nil)
+			localBackup.FetchChecksumReturns(orchestrator.BackupChecksum{"file1": "abcd"}, nil)
+			localBackup.CalculateChecksumReturns(orchestrator.BackupChecksum{"file1": "abcd"}, nil)
+		})
+
+		It("trusts the recorded checksum instead of downloading the artifact again", func() {
+			By("not failing", func() {
+				Expect(actualError).NotTo(HaveOccurred())
+			})
+
+			By("not reading the local artifact", func() {
+				Expect(localBackup.CalculateChecksumCallCount()).To(BeZero())
+			})
+
+			By("not copying the artifact", func() {
+				Expect(localBackup.CreateArtifactCallCount()).To(BeZero())
+				Expect(remoteArtifact.StreamFromRemoteCallCount()).To(BeZero())
+			})
+
+			By("not recording the checksum again", func() {
+				Expect(localBackup.AddChecksumCallCount()).To(BeZero())
+			})
+		})
+
+		Context("and the drained artifacts should be verified", func() {
+			BeforeEach(func() {
+				verifyDrained = true
+			})
+
+			It("verifies the local artifact instead of downloading it again", func() {
+				By("not failing", func() {
+					Expect(actualError).NotTo(HaveOccurred())
+				})
+
+				By("checking the local artifact against the recorded checksum", func() {
+					Expect(localBackup.FetchChecksumArgsForCall(0)).To(Equal(remoteArtifact))
+					Expect(localBackup.CalculateChecksumArgsForCall(0)).To(Equal(remoteArtifact))
+				})
+
+				By("not copying the artifact", func() {
+					Expect(localBackup.CreateArtifactCallCount()).To(BeZero())
+					Expect(remoteArtifact.StreamFromRemoteCallCount()).To(BeZero())
+				})
+
+				By("not recording the checksum again", func() {
+					Expect(localBackup.AddChecksumCallCount()).To(BeZero())
+				})
+			})
+
+			Context("and the local artifact no longer matches the recorded checksum", func() {
+				BeforeEach(func() {
+					localBackup.CalculateChecksumReturns(orchestrator.BackupChecksum{"file1": "not matching"}, nil)
+				})
+
+				It("should fail", func() {
+					Expect(actualError).To(MatchError(ContainSubstring("Backup is corrupted, checksum failed for previously copied")))
+				})
+			})
+
+			Context("and the local checksum cannot be calculated", func() {
+				BeforeEach(func() {
+					localBackup.CalculateChecksumReturns(nil, fmt.Errorf("local checksum error"))
+				})
+
+				It("should fail", func() {
+					Expect(actualError).To(MatchError("local checksum error"))
+				})
+			})
+		})
+	})
+
+	Context("When the backup is incremental", func() {
+		BeforeEach(func() {
+			localBackup.FetchPreviousChecksumReturns(orchestrator.BackupChecksum{
+				"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+				"file2": "an old checksum",
+			}, nil)
+			tarContents = createTarWithContents(map[string]string{
+				"file2": "Gopher names:\nGeorge\nGeoffrey\nGonzo",
+			})
+			remoteArtifact.StreamFilesFromRemoteStub = func(_ context.Context, files []string, writer io.Writer) error {
+				_, err := writer.Write(tarContents)
+				return err
+			}
+		})
+
+		It("only downloads the files that changed since the previous backup", func() {
+			By("not failing", func() {
+				Expect(actualError).NotTo(HaveOccurred())
+			})
+
+			By("fetching the previous checksum of the artifact", func() {
+				Expect(localBackup.FetchPreviousChecksumArgsForCall(0)).To(Equal(remoteArtifact))
+			})
+
+			By("streaming only the changed files into the local artifact", func() {
+				Expect(remoteArtifact.StreamFromRemoteCallCount()).To(BeZero())
+				Expect(remoteArtifact.StreamFilesFromRemoteCallCount()).To(Equal(1))
+				_, files, _ := remoteArtifact.StreamFilesFromRemoteArgsForCall(0)
+				Expect(files).To(Equal([]string{"file2"}))
+				Expect(localBackupArtifactWriter.WriteArgsForCall(0)).To(Equal(tarContents))
+				Expect(localBackupArtifactWriter.CloseCallCount()).To(Equal(1))
+			})
+
+			By("recording the checksum of every remote file", func() {
+				_, checksum := localBackup.AddChecksumArgsForCall(0)
+				Expect(checksum).To(Equal(orchestrator.BackupChecksum{
+					"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+					"file2": fmt.Sprintf("%x", sha256.Sum256([]byte("Gopher names:\nGeorge\nGeoffrey\nGonzo"))),
+				}))
+			})
+		})
+
+		Context("and the changed files do not match the remote checksum", func() {
+			BeforeEach(func() {
+				tarContents = createTarWithContents(map[string]string{
+					"file2": "Something else entirely.",
+				})
+			})
+
+			It("should fail", func() {
+				Expect(actualError).To(MatchError(ContainSubstring("Backup is corrupted")))
+			})
+		})
+
+		Context("and streaming the changed files fails", func() {
+			BeforeEach(func() {
+				remoteArtifact.StreamFilesFromRemoteStub = nil
+				remoteArtifact.StreamFilesFromRemoteReturns(fmt.Errorf("stream error"))
+			})
+
+			It("should fail", func() {
+				Expect(actualError).To(MatchError("stream error"))
+			})
+		})
+	})
+
+	Context("When the previous checksum cannot be read", func() {
+		BeforeEach(func() {
+			localBackup.FetchPreviousChecksumReturns(nil, fmt.Errorf("previous backup error"))
+		})
+
+		It("should fail", func() {
+			Expect(actualError).To(MatchError("previous backup error"))
+		})
+	})
 })
+
+func createTarWithContents(files map[string]string) []byte {
+	bytesBuffer := bytes.NewBuffer([]byte{})
+	tarFile := tar.NewWriter(bytesBuffer)
+
+	for filename, contents := range files {
+		Expect(tarFile.WriteHeader(&tar.Header{
+			Name: filename,
+			Mode: 0600,
+			Size: int64(len(contents)),
+		})).To(Succeed())
+		_, err := tarFile.Write([]byte(contents))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Expect(tarFile.Close()).To(Succeed())
+	return bytesBuffer.Bytes()
+}
+
+type abortableWriteCloser struct {
+	*fakes.FakeWriteCloser
+	abortedWith error
+}
+
+func (w *abortableWriteCloser) Abort(cause error) {
+	w.abortedWith = cause
+}
@@ -1,5 +1,7 @@
 package orchestrator
 
+import "context"
+
 type BackupExecutable struct {
 	Job
 }
@@ -8,6 +10,6 @@ func NewBackupExecutable(j Job) BackupExecutable {
 	return BackupExecutable{j}
 }
 
-func (e BackupExecutable) Execute() error {
-	return e.Job.Backup()
+func (e BackupExecutable) Execute(ctx context.Context) error {
+	return e.Job.Backup(ctx)
 }
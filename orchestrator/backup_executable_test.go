@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
@@ -26,7 +27,7 @@ var _ = Describe("BackupExecutables", func() {
 			executable = orchestrator.NewBackupExecutable(fakeJob)
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes backup", func() {
@@ -1,21 +1,52 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type BackupStep struct {
-	executor executor.Executor
+	executor        executor.Executor
+	maxLockDuration time.Duration
+	logger          Logger
 }
 
-func (s *BackupStep) Run(session *Session) error {
-	err := session.CurrentDeployment().Backup(s.executor)
+// Run runs the backup scripts. The deployment has just been locked when this
+// step starts, so when a maximum lock duration is set the backup scripts are
+// aborted once it has passed, leaving the failure path to unlock the jobs.
+func (s *BackupStep) Run(ctx context.Context, session *Session) error {
+	if s.maxLockDuration <= 0 {
+		err := session.CurrentDeployment().Backup(ctx, s.executor)
+		if err != nil {
+			return WrapBackupError(err)
+		}
+		return nil
+	}
+
+	watchdogCtx, cancel := context.WithTimeout(ctx, s.maxLockDuration)
+	defer cancel()
+
+	err := session.CurrentDeployment().Backup(watchdogCtx, s.executor)
+	if err != nil && watchdogCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		s.logger.Error("bbr", "Backup scripts did not finish within the maximum lock duration of %s, aborting.", s.maxLockDuration)
+		return NewLockDurationError(fmt.Sprintf(
+			"backup scripts were aborted because the deployment was locked for longer than the maximum lock duration of %s",
+			s.maxLockDuration,
+		))
+	}
 	if err != nil {
-		return NewBackupError(err.Error())
+		return WrapBackupError(err)
 	}
 	return nil
 }
 
-func NewBackupStep(executor executor.Executor) Step {
+func NewBackupStep(executor executor.Executor, maxLockDuration time.Duration, logger Logger) Step {
 	return &BackupStep{
-		executor: executor,
+		executor:        executor,
+		maxLockDuration: maxLockDuration,
+		logger:          logger,
 	}
 }
@@ -1,8 +1,10 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/readwriter"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 
 	"github.com/pkg/errors"
 )
@@ -11,23 +13,35 @@ type BackupUploadExecutable struct {
 	localBackup    Backup
 	remoteArtifact BackupArtifact
 	instance       Instance
+	retryPolicy    retry.Policy
 	Logger
 }
 
-func NewBackupUploadExecutable(localBackup Backup, remoteArtifact BackupArtifact, instance Instance, logger Logger) BackupUploadExecutable {
+func NewBackupUploadExecutable(localBackup Backup, remoteArtifact BackupArtifact, instance Instance, retryPolicy retry.Policy, logger Logger) BackupUploadExecutable {
 	return BackupUploadExecutable{
 		localBackup:    localBackup,
 		remoteArtifact: remoteArtifact,
 		instance:       instance,
+		retryPolicy:    retryPolicy,
 		Logger:         logger,
 	}
 }
 
-func (e BackupUploadExecutable) Execute() error {
+func (e BackupUploadExecutable) Execute(ctx context.Context) error {
+	description := fmt.Sprintf("Copying backup for job %s on %s/%s", e.remoteArtifact.Name(), e.instance.Name(), e.instance.Index())
+	return e.retryPolicy.Do(ctx, e.Logger, description, func() error {
+		return e.uploadArtifact(ctx)
+	})
+}
+
+// uploadArtifact makes a single attempt at streaming the local artifact to the
+// instance and checking it against the recorded checksums.
+func (e BackupUploadExecutable) uploadArtifact(ctx context.Context) error {
 	localBackupArtifactReader, err := e.localBackup.ReadArtifact(e.remoteArtifact)
 	if err != nil {
 		return err
 	}
+	defer localBackupArtifactReader.Close()
 
 	size, err := e.localBackup.GetArtifactSize(e.remoteArtifact)
 	if err != nil {
@@ -41,9 +55,10 @@ func (e BackupUploadExecutable) Execute() error {
 
 	percentageMessage := fmt.Sprintf("Copying backup for job %s on %s/%s -- %%d%%%% complete", e.remoteArtifact.Name(), e.remoteArtifact.InstanceName(), e.remoteArtifact.InstanceID())
 	percentageLogger := readwriter.NewLogPercentageReader(localBackupArtifactReader, e.Logger, sizeInBytes, "bbr", percentageMessage)
+	percentageLogger.OnProgress(notifyTransferProgress(ctx, e.remoteArtifact, Upload))
 
 	e.Logger.Info("bbr", "Copying backup -- %s uncompressed -- for job %s on %s/%s...", size, e.remoteArtifact.Name(), e.instance.Name(), e.instance.Index())
-	err = e.remoteArtifact.StreamToRemote(percentageLogger)
+	err = e.remoteArtifact.StreamToRemote(ctx, percentageLogger)
 	if err != nil {
 		return err
 	}
@@ -55,22 +70,23 @@ func (e BackupUploadExecutable) Execute() error {
 		return err
 	}
 
-	remoteChecksum, err := e.remoteArtifact.Checksum()
+	remoteChecksum, err := e.remoteArtifact.Checksum(ctx)
 	if err != nil {
 		return err
 	}
 
 	match, mismatchedFiles := localChecksum.Match(remoteChecksum)
+	notifyChecksumResult(ctx, e.remoteArtifact, match, mismatchedFiles)
 	if !match {
 		e.Logger.Debug("bbr", "Checksums didn't match for:")
 		e.Logger.Debug("bbr", fmt.Sprintf("%v\n", mismatchedFiles))
-		return errors.Errorf("Backup couldn't be transferred, checksum failed for %s/%s %s - checksums don't match for %v. Checksum failed for %d files in total",
+		return retry.NewChecksumError(errors.Errorf("Backup couldn't be transferred, checksum failed for %s/%s %s - checksums don't match for %v. Checksum failed for %d files in total",
 			e.instance.Name(),
 			e.instance.ID(),
 			e.remoteArtifact.Name(),
 			getFirstTen(mismatchedFiles),
 			len(mismatchedFiles),
-		)
+		))
 	}
 	e.Logger.Info("bbr", "Finished copying backup for job %s on %s/%s.", e.remoteArtifact.Name(), e.instance.Name(), e.instance.Index())
 
@@ -1,14 +1,18 @@
 package orchestrator_test
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/readwriter"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
 	"github.com/pkg/errors"
 
 	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
@@ -23,6 +27,7 @@ var _ = Describe("BackupUploadExecutable", func() {
 		remoteArtifact            *fakes.FakeBackupArtifact
 		instance                  *fakes.FakeInstance
 		logger                    *fakes.FakeLogger
+		retryPolicy               retry.Policy
 		actualError               error
 		localBackupArtifactReader io.ReadCloser
 	)
@@ -31,6 +36,7 @@ var _ = Describe("BackupUploadExecutable", func() {
 		remoteArtifact = new(fakes.FakeBackupArtifact)
 		instance = new(fakes.FakeInstance)
 		logger = new(fakes.FakeLogger)
+		retryPolicy = retry.Policy{}
 
 		localBackupArtifactReader = ioutil.NopCloser(bytes.NewBufferString("this-is-some-backup-data"))
 		backup.ReadArtifactReturns(localBackupArtifactReader, nil)
@@ -39,8 +45,8 @@ var _ = Describe("BackupUploadExecutable", func() {
 	})
 
 	JustBeforeEach(func() {
-		executable = orchestrator.NewBackupUploadExecutable(backup, remoteArtifact, instance, logger)
-		actualError = executable.Execute()
+		executable = orchestrator.NewBackupUploadExecutable(backup, remoteArtifact, instance, retryPolicy, logger)
+		actualError = executable.Execute(context.Background())
 
 	})
 
@@ -63,7 +69,7 @@ var _ = Describe("BackupUploadExecutable", func() {
 
 			By("streaming from the remote artifact", func() {
 				Expect(remoteArtifact.StreamToRemoteCallCount()).To(Equal(1))
-				streamReader := remoteArtifact.StreamToRemoteArgsForCall(0)
+				_, streamReader := remoteArtifact.StreamToRemoteArgsForCall(0)
 				Expect(streamReader).To(BeAssignableToTypeOf(&readwriter.LogPercentageReader{}))
 				logPercentageReader := streamReader.(*readwriter.LogPercentageReader)
 				Expect(logPercentageReader.Reader).To(Equal(localBackupArtifactReader))
@@ -124,6 +130,21 @@ var _ = Describe("BackupUploadExecutable", func() {
 		})
 	})
 
+	Context("When streaming to remote drops the connection and connection errors are retried", func() {
+		BeforeEach(func() {
+			retryPolicy = retry.Policy{Attempts: 2, Backoff: time.Millisecond, ErrorClasses: []retry.ErrorClass{retry.ConnectionErrors}}
+			remoteArtifact.StreamToRemoteReturnsOnCall(0, io.EOF)
+			remoteArtifact.StreamToRemoteReturnsOnCall(1, nil)
+		})
+
+		It("uploads the backup on the next attempt", func() {
+			Expect(actualError).NotTo(HaveOccurred())
+			Expect(backup.ReadArtifactCallCount()).To(Equal(2))
+			Expect(remoteArtifact.StreamToRemoteCallCount()).To(Equal(2))
+			Expect(logger.WarnCallCount()).To(Equal(1))
+		})
+	})
+
 	Context("When the checksum cannot be fetched from the localbackup", func() {
 		BeforeEach(func() {
 			backup.FetchChecksumReturns(nil, fmt.Errorf("checksum error"))
@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 )
 
@@ -13,7 +15,7 @@ func NewBackupableStep(lockOrderer LockOrderer, logger Logger) Step {
 	return &BackupableStep{lockOrderer: lockOrderer, logger: logger}
 }
 
-func (s *BackupableStep) Run(session *Session) error {
+func (s *BackupableStep) Run(ctx context.Context, session *Session) error {
 	s.logger.Info("bbr", "Running pre-checks for backup of %s...\n", session.DeploymentName())
 
 	deployment := session.CurrentDeployment()
@@ -21,7 +23,7 @@ func (s *BackupableStep) Run(session *Session) error {
 		return errors.Errorf("Deployment '%s' has no backup scripts", session.DeploymentName())
 	}
 
-	err := deployment.CheckArtifactDir()
+	err := deployment.CheckArtifactDir(ctx)
 	if err != nil {
 		return NewArtifactDirError(err.Error())
 	}
@@ -1,45 +1,64 @@
 package orchestrator
 
 import (
+	"context"
 	"time"
 
 	exe "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 )
 
 func NewBackuper(backupManager BackupManager, logger Logger, deploymentManager DeploymentManager,
-	lockOrderer LockOrderer, executor exe.Executor, nowFunc func() time.Time, artifactCopier ArtifactCopier, timestamp string) *Backuper {
+	lockOrderer LockOrderer, executor exe.Executor, nowFunc func() time.Time, artifactCopier ArtifactCopier, timestamp, bbrVersion string, maxLockDuration time.Duration, observer Observer) *Backuper {
 
 	findDeploymentStep := NewFindDeploymentStep(deploymentManager, logger)
 	backupable := NewBackupableStep(lockOrderer, logger)
-	createArtifact := NewCreateArtifactStep(logger, backupManager, deploymentManager, nowFunc, timestamp)
+	createArtifact := NewCreateArtifactStep(logger, backupManager, deploymentManager, nowFunc, timestamp, bbrVersion)
+	uploadPreviousChecksums := NewUploadPreviousChecksumsStep(logger)
 	lock := NewLockStep(lockOrderer, executor)
 
-	backup := NewBackupStep(executor)
+	backup := NewBackupStep(executor, maxLockDuration, logger)
 	unlockAfterSuccessfulBackup := NewPostBackupUnlockStep(true, lockOrderer, executor)
 	unlockAfterFailedBackup := NewPostBackupUnlockStep(false, lockOrderer, executor)
 	drain := NewDrainStep(logger, artifactCopier)
 	cleanup := NewCleanupStep()
 	addFinishTimeStep := NewAddFinishTimeStep(nowFunc)
+	sign := NewSignBackupStep()
 
 	workflow := NewWorkflow()
 	workflow.StartWith(findDeploymentStep).OnSuccess(backupable)
 	workflow.Add(backupable).OnSuccess(createArtifact).OnFailure(cleanup)
-	workflow.Add(createArtifact).OnSuccess(lock).OnFailure(cleanup)
+	workflow.Add(createArtifact).OnSuccess(uploadPreviousChecksums).OnFailure(cleanup)
+	workflow.Add(uploadPreviousChecksums).OnSuccess(lock).OnFailure(cleanup)
 	workflow.Add(lock).OnSuccess(backup).OnFailure(unlockAfterFailedBackup)
 	workflow.Add(backup).OnSuccess(unlockAfterSuccessfulBackup).OnFailure(unlockAfterFailedBackup)
 	workflow.Add(unlockAfterSuccessfulBackup).OnSuccessOrFailure(drain)
 	workflow.Add(unlockAfterFailedBackup).OnSuccessOrFailure(cleanup)
 	workflow.Add(drain).OnSuccessOrFailure(cleanup)
 	workflow.Add(cleanup).OnSuccessOrFailure(addFinishTimeStep)
-	workflow.Add(addFinishTimeStep)
+	workflow.Add(addFinishTimeStep).OnSuccessOrFailure(sign)
+	workflow.Add(sign)
+
+	resumeArtifact := NewResumeArtifactStep(logger, backupManager)
+
+	resumeWorkflow := NewWorkflow()
+	resumeWorkflow.StartWith(findDeploymentStep).OnSuccess(resumeArtifact)
+	resumeWorkflow.Add(resumeArtifact).OnSuccess(drain).OnFailure(cleanup)
+	resumeWorkflow.Add(drain).OnSuccessOrFailure(cleanup)
+	resumeWorkflow.Add(cleanup).OnSuccessOrFailure(addFinishTimeStep)
+	resumeWorkflow.Add(addFinishTimeStep).OnSuccessOrFailure(sign)
+	resumeWorkflow.Add(sign)
 
 	return &Backuper{
-		workflow: workflow,
+		workflow:       workflow,
+		resumeWorkflow: resumeWorkflow,
+		observer:       observer,
 	}
 }
 
 type Backuper struct {
-	workflow *Workflow
+	workflow       *Workflow
+	resumeWorkflow *Workflow
+	observer       Observer
 }
 
 type AuthInfo struct {
@@ -47,12 +66,28 @@ type AuthInfo struct {
 	UaaUrl string
 }
 
-//Backup checks if a deployment has backupable instances and backs them up.
-func (b Backuper) Backup(deploymentName, artifactPath string) Error {
+//Backup checks if a deployment has backupable instances and backs them up. When
+//the context is cancelled the remote scripts are aborted and the instances are
+//unlocked and cleaned up.
+func (b Backuper) Backup(ctx context.Context, deploymentName, artifactPath string) Error {
 	session := NewSession(deploymentName)
 	session.SetCurrentArtifactPath(artifactPath)
+	session.SetObserver(b.observer)
 
-	err := b.workflow.Run(session)
+	err := b.workflow.Run(ctx, session)
 
 	return err
 }
+
+//Resume drains the artifacts that a previous, interrupted backup left on the
+//instances into that backup, without running the lock or backup scripts again.
+//The artifacts it had already drained are trusted to match the checksums
+//recorded for them, unless verifyDrained is set.
+func (b Backuper) Resume(ctx context.Context, deploymentName, backupPath string, verifyDrained bool) Error {
+	session := NewSession(deploymentName)
+	session.SetCurrentArtifactPath(backupPath)
+	session.SetVerifyDrained(verifyDrained)
+	session.SetObserver(b.observer)
+
+	return b.resumeWorkflow.Run(ctx, session)
+}
@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 )
 
 var _ = Describe("Backup", func() {
@@ -26,6 +28,10 @@ var _ = Describe("Backup", func() {
 		startTime, finishTime time.Time
 		artifactCopier        *fakes.FakeArtifactCopier
 		timeStamp             string
+		ctx                   context.Context
+		nowFunc               func() time.Time
+		maxLockDuration       time.Duration
+		observer              *fakes.FakeObserver
 	)
 
 	BeforeEach(func() {
@@ -34,24 +40,27 @@ var _ = Describe("Backup", func() {
 		fakeBackupManager = new(fakes.FakeBackupManager)
 		fakeBackup = new(fakes.FakeBackup)
 		logger = new(fakes.FakeLogger)
+		ctx = context.Background()
 
 		startTime = time.Now()
 		finishTime = startTime.Add(time.Hour)
 		timeStamp = time.Now().UTC().Format("20060102T150405Z")
 
 		nows := []time.Time{startTime, finishTime}
-		nowFunc := func() time.Time {
+		nowFunc = func() time.Time {
 			var now time.Time
 			now, nows = nows[0], nows[1:]
 			return now
 		}
 
 		artifactCopier = new(fakes.FakeArtifactCopier)
-		b = orchestrator.NewBackuper(fakeBackupManager, logger, deploymentManager, lockOrderer, executor.NewParallelExecutor(), nowFunc, artifactCopier, timeStamp)
+		maxLockDuration = 0
+		observer = new(fakes.FakeObserver)
 	})
 
 	JustBeforeEach(func() {
-		actualBackupError = b.Backup(deploymentName, "")
+		b = orchestrator.NewBackuper(fakeBackupManager, logger, deploymentManager, lockOrderer, executor.NewParallelExecutor(), nowFunc, artifactCopier, timeStamp, "1.2.3", maxLockDuration, observer)
+		actualBackupError = b.Backup(ctx, deploymentName, "")
 	})
 
 	Context("backs up a deployment", func() {
@@ -69,7 +78,8 @@ var _ = Describe("Backup", func() {
 
 		It("finds the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
-			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+			_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
 		})
 
 		It("saves the deployment manifest", func() {
@@ -93,7 +103,7 @@ var _ = Describe("Backup", func() {
 
 		It("runs post-backup-unlock scripts on the deployment", func() {
 			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
-			afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+			_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
 			Expect(afterSuccessfulBackup).To(BeTrue())
 		})
 
@@ -115,7 +125,7 @@ var _ = Describe("Backup", func() {
 		It("drains the backup to the artifact", func() {
 			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(Equal(1))
 
-			downloadedBackup, downloadedFromDeployment := artifactCopier.DownloadBackupFromDeploymentArgsForCall(0)
+			_, downloadedBackup, downloadedFromDeployment, _ := artifactCopier.DownloadBackupFromDeploymentArgsForCall(0)
 			Expect(downloadedBackup).To(Equal(fakeBackup))
 			Expect(downloadedFromDeployment).To(Equal(deployment))
 		})
@@ -124,6 +134,179 @@ var _ = Describe("Backup", func() {
 			Expect(fakeBackup.CreateMetadataFileWithStartTimeArgsForCall(0)).To(Equal(startTime))
 			Expect(fakeBackup.AddFinishTimeArgsForCall(0)).To(Equal(finishTime))
 		})
+
+		It("marks the backup as complete", func() {
+			Expect(fakeBackup.MarkCompleteCallCount()).To(Equal(1))
+		})
+
+		It("signs the backup once the finish time has been added", func() {
+			Expect(fakeBackup.SignCallCount()).To(Equal(1))
+		})
+
+		It("tells the observer when each step starts and succeeds", func() {
+			var startedSteps, succeededSteps []string
+			for i := 0; i < observer.NotifyCallCount(); i++ {
+				notifiedDeploymentName, event := observer.NotifyArgsForCall(i)
+				Expect(notifiedDeploymentName).To(Equal(deploymentName))
+
+				switch e := event.(type) {
+				case orchestrator.StepStarted:
+					startedSteps = append(startedSteps, e.Step)
+				case orchestrator.StepSucceeded:
+					succeededSteps = append(succeededSteps, e.Step)
+				default:
+					Fail(fmt.Sprintf("unexpected event %#v", event))
+				}
+			}
+
+			Expect(startedSteps).To(Equal([]string{
+				"find-deployment",
+				"backupable",
+				"create-artifact",
+				"upload-previous-checksums",
+				"lock",
+				"backup",
+				"post-backup-unlock",
+				"drain",
+				"cleanup",
+				"add-finish-time",
+				"sign-backup",
+			}))
+			Expect(succeededSteps).To(Equal(startedSteps))
+		})
+
+		Context("when the backup scripts notify the observer", func() {
+			BeforeEach(func() {
+				deployment.BackupStub = func(ctx context.Context, _ executor.Executor) error {
+					orchestrator.NotifyObserver(ctx, orchestrator.JobScriptStarted{Instance: "redis/0", Job: "redis-server", Script: "backup"})
+					return nil
+				}
+			})
+
+			It("passes their events on to the observer of the backup", func() {
+				var scriptEvents []orchestrator.Event
+				for i := 0; i < observer.NotifyCallCount(); i++ {
+					notifiedDeploymentName, event := observer.NotifyArgsForCall(i)
+					if _, ok := event.(orchestrator.JobScriptStarted); ok {
+						Expect(notifiedDeploymentName).To(Equal(deploymentName))
+						scriptEvents = append(scriptEvents, event)
+					}
+				}
+
+				Expect(scriptEvents).To(ConsistOf(orchestrator.JobScriptStarted{Instance: "redis/0", Job: "redis-server", Script: "backup"}))
+			})
+		})
+
+		Context("when the unlock scripts notify the observer", func() {
+			BeforeEach(func() {
+				deployment.PostBackupUnlockStub = func(ctx context.Context, _ bool, _ orchestrator.LockOrderer, _ executor.Executor) error {
+					orchestrator.NotifyObserver(ctx, orchestrator.JobScriptStarted{Instance: "redis/0", Job: "redis-server", Script: "post-backup-unlock"})
+					orchestrator.NotifyObserver(ctx, orchestrator.JobScriptFinished{Instance: "redis/0", Job: "redis-server", Script: "post-backup-unlock"})
+					return nil
+				}
+			})
+
+			It("passes their events on to the observer of the backup", func() {
+				var scriptEvents []orchestrator.Event
+				for i := 0; i < observer.NotifyCallCount(); i++ {
+					_, event := observer.NotifyArgsForCall(i)
+					switch event.(type) {
+					case orchestrator.JobScriptStarted, orchestrator.JobScriptFinished:
+						scriptEvents = append(scriptEvents, event)
+					}
+				}
+
+				Expect(scriptEvents).To(Equal([]orchestrator.Event{
+					orchestrator.JobScriptStarted{Instance: "redis/0", Job: "redis-server", Script: "post-backup-unlock"},
+					orchestrator.JobScriptFinished{Instance: "redis/0", Job: "redis-server", Script: "post-backup-unlock"},
+				}))
+			})
+		})
+
+		Context("when the deployment has backupable instances", func() {
+			BeforeEach(func() {
+				job := new(fakes.FakeJob)
+				job.NameReturns("redis-server")
+				job.ReleaseReturns("redis")
+
+				instance := new(fakes.FakeInstance)
+				instance.NameReturns("redis")
+				instance.IndexReturns("0")
+				instance.IDReturns("f1b2c3")
+				instance.IsBootstrapReturns(true)
+				instance.JobsReturns([]orchestrator.Job{job})
+
+				deployment.BackupableInstancesReturns([]orchestrator.Instance{instance})
+				deploymentManager.DirectorInfoReturns(orchestrator.DirectorInfo{UUID: "director-uuid", Name: "bosh", Version: "264.1.0"}, nil)
+			})
+
+			It("records the deployment, director and instances in the metadata file", func() {
+				Expect(fakeBackup.AddDeploymentMetadataCallCount()).To(Equal(1))
+				Expect(fakeBackup.AddDeploymentMetadataArgsForCall(0)).To(Equal(orchestrator.DeploymentMetadata{
+					Name:       deploymentName,
+					BBRVersion: "1.2.3",
+					Director:   orchestrator.DirectorInfo{UUID: "director-uuid", Name: "bosh", Version: "264.1.0"},
+					Instances: []orchestrator.InstanceMetadata{{
+						Name:      "redis",
+						Index:     "0",
+						ID:        "f1b2c3",
+						Bootstrap: true,
+						Jobs:      []orchestrator.JobSpecifier{{Name: "redis-server", Release: "redis"}},
+					}},
+				}))
+			})
+
+			Context("and the director info cannot be retrieved", func() {
+				BeforeEach(func() {
+					deploymentManager.DirectorInfoReturns(orchestrator.DirectorInfo{}, fmt.Errorf("director unreachable"))
+				})
+
+				It("records the metadata without it and warns", func() {
+					Expect(actualBackupError).NotTo(HaveOccurred())
+					Expect(fakeBackup.AddDeploymentMetadataArgsForCall(0).Director).To(Equal(orchestrator.DirectorInfo{}))
+					Expect(logger.WarnCallCount()).To(Equal(1))
+				})
+			})
+		})
+	})
+
+	Context("backs up a deployment incrementally", func() {
+		var instance *fakes.FakeInstance
+		var artifact *fakes.FakeBackupArtifact
+		var previousChecksum = orchestrator.BackupChecksum{"file1": "abcd"}
+
+		BeforeEach(func() {
+			instance = new(fakes.FakeInstance)
+			artifact = new(fakes.FakeBackupArtifact)
+			instance.ArtifactsToBackupReturns([]orchestrator.BackupArtifact{artifact})
+
+			fakeBackupManager.CreateReturns(fakeBackup, nil)
+			fakeBackup.FetchPreviousChecksumReturns(previousChecksum, nil)
+			deploymentManager.FindReturns(deployment, nil)
+			deployment.IsBackupableReturns(true)
+			deployment.BackupableInstancesReturns([]orchestrator.Instance{instance})
+		})
+
+		It("uploads the previous checksums before running the backup scripts", func() {
+			Expect(actualBackupError).NotTo(HaveOccurred())
+			Expect(fakeBackup.FetchPreviousChecksumArgsForCall(0)).To(Equal(artifact))
+			Expect(instance.MarkArtifactDirCreatedCallCount()).To(Equal(1))
+			Expect(artifact.UploadPreviousChecksumsCallCount()).To(Equal(1))
+			Expect(artifact.UploadPreviousChecksumsArgsForCall(0)).To(Equal(previousChecksum))
+			Expect(deployment.BackupCallCount()).To(Equal(1))
+		})
+
+		Context("when the previous checksums cannot be uploaded", func() {
+			BeforeEach(func() {
+				artifact.UploadPreviousChecksumsReturns(fmt.Errorf("upload error"))
+			})
+
+			It("fails without locking the deployment", func() {
+				Expect(actualBackupError).To(MatchError(ContainSubstring("upload error")))
+				Expect(deployment.PreBackupLockCallCount()).To(BeZero())
+				Expect(deployment.CleanupCallCount()).To(Equal(1))
+			})
+		})
 	})
 
 	Describe("failures", func() {
@@ -168,6 +351,41 @@ var _ = Describe("Backup", func() {
 			})
 		})
 
+		Context("fails if the deployment metadata can't be saved", func() {
+			var expectedError = fmt.Errorf("disk full")
+
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+				fakeBackup.AddDeploymentMetadataReturns(expectedError)
+			})
+
+			It("fails the backup process", func() {
+				Expect(actualBackupError).To(ConsistOf(expectedError))
+			})
+
+			It("does not lock the deployment", func() {
+				Expect(deployment.PreBackupLockCallCount()).To(BeZero())
+			})
+		})
+
+		Context("fails if the backup can't be signed", func() {
+			var expectedError = fmt.Errorf("signing key unavailable")
+
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+				fakeBackup.SignReturns(expectedError)
+			})
+
+			It("fails the backup process after recording the finish time", func() {
+				Expect(actualBackupError).To(ConsistOf(expectedError))
+				Expect(fakeBackup.AddFinishTimeCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("fails if the deployment is not backupable", func() {
 			BeforeEach(func() {
 				deploymentManager.FindReturns(deployment, nil)
@@ -176,7 +394,8 @@ var _ = Describe("Backup", func() {
 
 			It("finds a deployment with the deployment name", func() {
 				Expect(deploymentManager.FindCallCount()).To(Equal(1))
-				Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+				_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+				Expect(actualDeploymentName).To(Equal(deploymentName))
 			})
 
 			It("checks if the deployment is backupable", func() {
@@ -228,13 +447,38 @@ var _ = Describe("Backup", func() {
 
 			It("also runs post-backup-unlock", func() {
 				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
-				afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+				_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
 				Expect(afterSuccessfulBackup).To(BeFalse())
 			})
 
 			Context("cleanup fails as well", assertCleanupError)
 		})
 
+		Context("fails if a pre-backup-lock script times out", func() {
+			BeforeEach(func() {
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+
+				deployment.PreBackupLockReturns(orchestrator.NewError(
+					errors.Wrap(orchestrator.NewScriptTimeoutError("pre-backup-lock", time.Minute), "Error attempting to run pre-backup-lock for job redis on redis/0"),
+				))
+			})
+
+			It("returns a lock error that the timeout can still be found in", func() {
+				var lockError orchestrator.LockError
+				Expect(errors.As(actualBackupError, &lockError)).To(BeTrue())
+
+				var timeoutError orchestrator.ScriptTimeoutError
+				Expect(errors.As(actualBackupError, &timeoutError)).To(BeTrue())
+				Expect(timeoutError).To(Equal(orchestrator.NewScriptTimeoutError("pre-backup-lock", time.Minute)))
+			})
+
+			It("still unlocks the deployment", func() {
+				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("fails if post-backup-unlock fails", func() {
 			var unlockError orchestrator.UnlockError
 
@@ -301,13 +545,35 @@ var _ = Describe("Backup", func() {
 
 		Context("fails if backup cannot be drained", func() {
 			var drainError = fmt.Errorf("I would like a sandwich")
+			var instance *fakes.FakeInstance
 			BeforeEach(func() {
+				instance = new(fakes.FakeInstance)
 				deploymentManager.FindReturns(deployment, nil)
 				deployment.IsBackupableReturns(true)
+				deployment.BackupableInstancesReturns([]orchestrator.Instance{instance})
 				fakeBackupManager.CreateReturns(fakeBackup, nil)
 				artifactCopier.DownloadBackupFromDeploymentReturns(drainError)
 			})
 
+			It("leaves the artifacts that were not drained on the instances", func() {
+				Expect(instance.RetainArtifactDirCallCount()).To(Equal(1))
+			})
+
+			It("records that the backup failed while it was drained", func() {
+				Expect(fakeBackup.MarkDrainFailedCallCount()).To(Equal(1))
+			})
+
+			Context("and the failure cannot be recorded", func() {
+				BeforeEach(func() {
+					fakeBackup.MarkDrainFailedReturns(fmt.Errorf("disk full"))
+				})
+
+				It("cleans up the artifacts, as the backup cannot be resumed", func() {
+					Expect(actualBackupError.Error()).To(ContainSubstring(drainError.Error()))
+					Expect(instance.RetainArtifactDirCallCount()).To(BeZero())
+				})
+			})
+
 			It("check if the deployment is backupable", func() {
 				Expect(deploymentManager.FindCallCount()).To(Equal(1))
 				Expect(deployment.IsBackupableCallCount()).To(Equal(1))
@@ -325,9 +591,52 @@ var _ = Describe("Backup", func() {
 				Expect(deployment.CleanupCallCount()).To(Equal(1))
 			})
 
+			It("does not mark the backup as complete", func() {
+				Expect(fakeBackup.MarkCompleteCallCount()).To(BeZero())
+			})
+
 			Context("cleanup fails as well", assertCleanupError)
 		})
 
+		Context("fails if the backup cannot be marked as complete", func() {
+			var markCompleteError = fmt.Errorf("disk full")
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+				fakeBackup.MarkCompleteReturns(markCompleteError)
+			})
+
+			It("fails the backup process", func() {
+				Expect(actualBackupError).To(ConsistOf(And(
+					MatchError(markCompleteError.Error()),
+					BeAssignableToTypeOf(orchestrator.DrainError{}),
+				)))
+			})
+
+			It("still records the finish time", func() {
+				Expect(fakeBackup.AddFinishTimeCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("fails if the metadata file cannot be created", func() {
+			var metadataError = fmt.Errorf("read-only file system")
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+				fakeBackup.CreateMetadataFileWithStartTimeReturns(metadataError)
+			})
+
+			It("does not back up the deployment", func() {
+				Expect(deployment.BackupCallCount()).To(BeZero())
+			})
+
+			It("fails the backup process", func() {
+				Expect(actualBackupError).To(ConsistOf(metadataError))
+			})
+		})
+
 		Context("fails if artifact cannot be created", func() {
 			var artifactError = fmt.Errorf("I would like a sandwich")
 			BeforeEach(func() {
@@ -416,9 +725,22 @@ var _ = Describe("Backup", func() {
 				Expect(actualBackupError.Error()).To(ContainSubstring(backupError.Error()))
 			})
 
+			It("tells the observer that the backup step failed", func() {
+				var failedSteps []orchestrator.StepFailed
+				for i := 0; i < observer.NotifyCallCount(); i++ {
+					if _, event := observer.NotifyArgsForCall(i); event.EventType() == "step_failed" {
+						failedSteps = append(failedSteps, event.(orchestrator.StepFailed))
+					}
+				}
+
+				Expect(failedSteps).To(HaveLen(1))
+				Expect(failedSteps[0].Step).To(Equal("backup"))
+				Expect(failedSteps[0].Error).To(ContainSubstring("syzygy"))
+			})
+
 			It("runs post-backup-unlock scripts on the deployment", func() {
 				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
-				afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+				_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
 				Expect(afterSuccessfulBackup).To(BeFalse())
 			})
 
@@ -428,6 +750,334 @@ var _ = Describe("Backup", func() {
 
 			Context("cleanup fails as well", assertCleanupError)
 		})
+
+		Context("fails if a backup script times out", func() {
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+
+				deployment.BackupReturns(orchestrator.NewError(
+					errors.Wrap(orchestrator.NewScriptTimeoutError("backup", time.Minute), "Error attempting to run backup for job redis on redis/0"),
+				))
+			})
+
+			It("returns a backup error that the timeout can still be found in", func() {
+				var backupError orchestrator.BackupError
+				Expect(errors.As(actualBackupError, &backupError)).To(BeTrue())
+
+				var timeoutError orchestrator.ScriptTimeoutError
+				Expect(errors.As(actualBackupError, &timeoutError)).To(BeTrue())
+				Expect(timeoutError.Script).To(Equal("backup"))
+				Expect(actualBackupError.Error()).To(ContainSubstring("backup script timed out after 1m0s"))
+			})
+
+			It("still unlocks the deployment", func() {
+				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the backup is interrupted", func() {
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(context.Background())
+				deployment.BackupStub = func(backupCtx context.Context, _ executor.Executor) error {
+					cancel()
+					return backupCtx.Err()
+				}
+			})
+
+			It("passes the context to the backup scripts", func() {
+				Expect(deployment.BackupCallCount()).To(Equal(1))
+				backupCtx, _ := deployment.BackupArgsForCall(0)
+				Expect(backupCtx.Err()).To(Equal(context.Canceled))
+			})
+
+			It("fails the backup process", func() {
+				Expect(actualBackupError).To(MatchError(ContainSubstring("context canceled")))
+			})
+
+			It("runs post-backup-unlock scripts with a context that is not cancelled", func() {
+				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+				unlockCtx, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+				Expect(unlockCtx.Err()).NotTo(HaveOccurred())
+				Expect(unlockCtx.Done()).To(BeNil())
+				Expect(afterSuccessfulBackup).To(BeFalse())
+			})
+
+			It("does not drain the backup", func() {
+				Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+			})
+
+			It("ensures that deployment is cleaned up", func() {
+				Expect(deployment.CleanupCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the backup is interrupted after the backup scripts succeed", func() {
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(context.Background())
+				deployment.BackupStub = func(context.Context, executor.Executor) error {
+					cancel()
+					return nil
+				}
+			})
+
+			It("reports the interruption", func() {
+				Expect(actualBackupError).To(MatchError(ContainSubstring("interrupted")))
+			})
+
+			It("unlocks the deployment as after a failed backup and cleans it up", func() {
+				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+				_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+				Expect(afterSuccessfulBackup).To(BeFalse())
+				Expect(deployment.CleanupCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the backup scripts run for longer than the max lock duration", func() {
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+
+				maxLockDuration = 10 * time.Millisecond
+				deployment.BackupStub = func(backupCtx context.Context, _ executor.Executor) error {
+					<-backupCtx.Done()
+					return backupCtx.Err()
+				}
+			})
+
+			It("fails with a lock duration error", func() {
+				Expect(actualBackupError).To(MatchError(ContainSubstring(
+					"backup scripts were aborted because the deployment was locked for longer than the maximum lock duration of 10ms",
+				)))
+				Expect(actualBackupError).To(ContainElement(BeAssignableToTypeOf(orchestrator.LockDurationError{})))
+			})
+
+			It("unlocks the deployment as after a failed backup and cleans it up", func() {
+				Expect(deployment.PostBackupUnlockCallCount()).To(Equal(1))
+				unlockCtx, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+				Expect(unlockCtx.Err()).NotTo(HaveOccurred())
+				Expect(afterSuccessfulBackup).To(BeFalse())
+				Expect(deployment.CleanupCallCount()).To(Equal(1))
+			})
+
+			It("does not drain the backup", func() {
+				Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the backup scripts finish within the max lock duration", func() {
+			BeforeEach(func() {
+				deploymentManager.FindReturns(deployment, nil)
+				deployment.IsBackupableReturns(true)
+				fakeBackupManager.CreateReturns(fakeBackup, nil)
+
+				maxLockDuration = time.Hour
+			})
+
+			It("runs the backup scripts with the lock deadline and succeeds", func() {
+				Expect(actualBackupError).NotTo(HaveOccurred())
+
+				backupCtx, _ := deployment.BackupArgsForCall(0)
+				_, hasDeadline := backupCtx.Deadline()
+				Expect(hasDeadline).To(BeTrue())
+
+				_, afterSuccessfulBackup, _, _ := deployment.PostBackupUnlockArgsForCall(0)
+				Expect(afterSuccessfulBackup).To(BeTrue())
+			})
+		})
+	})
+})
+
+var _ = Describe("Resume", func() {
+	var (
+		b                 *orchestrator.Backuper
+		deployment        *fakes.FakeDeployment
+		deploymentManager *fakes.FakeDeploymentManager
+		fakeBackup        *fakes.FakeBackup
+		fakeBackupManager *fakes.FakeBackupManager
+		logger            *fakes.FakeLogger
+		instance          *fakes.FakeInstance
+		artifactCopier    *fakes.FakeArtifactCopier
+		deploymentName    = "foobarbaz"
+		backupPath        = "foobarbaz_20151021T010203Z"
+		verifyDrained     bool
+		finishTime        time.Time
+		actualResumeError error
+	)
+
+	BeforeEach(func() {
+		deployment = new(fakes.FakeDeployment)
+		deploymentManager = new(fakes.FakeDeploymentManager)
+		fakeBackupManager = new(fakes.FakeBackupManager)
+		fakeBackup = new(fakes.FakeBackup)
+		logger = new(fakes.FakeLogger)
+		instance = new(fakes.FakeInstance)
+		artifactCopier = new(fakes.FakeArtifactCopier)
+		verifyDrained = false
+		finishTime = time.Now()
+
+		deploymentManager.FindReturns(deployment, nil)
+		deployment.InstancesReturns([]orchestrator.Instance{instance})
+		deployment.BackupableInstancesReturns([]orchestrator.Instance{instance})
+		instance.NameReturns("redis")
+		instance.IDReturns("abc")
+		instance.ArtifactDirExistsReturns(true, nil)
+		fakeBackupManager.OpenReturns(fakeBackup, nil)
+		fakeBackup.DeploymentMatchesReturns(true, nil)
+
+		nowFunc := func() time.Time { return finishTime }
+		b = orchestrator.NewBackuper(fakeBackupManager, logger, deploymentManager, new(fakes.FakeLockOrderer), executor.NewParallelExecutor(), nowFunc, artifactCopier, "", "1.2.3", 0, nil)
+	})
+
+	JustBeforeEach(func() {
+		actualResumeError = b.Resume(context.Background(), deploymentName, backupPath, verifyDrained)
+	})
+
+	It("drains the missing artifacts into the existing backup", func() {
+		By("not failing", func() {
+			Expect(actualResumeError).NotTo(HaveOccurred())
+		})
+
+		By("opening the existing backup", func() {
+			actualPath, _ := fakeBackupManager.OpenArgsForCall(0)
+			Expect(actualPath).To(Equal(backupPath))
+			Expect(fakeBackupManager.CreateCallCount()).To(BeZero())
+		})
+
+		By("checking the backup matches the deployment", func() {
+			actualDeploymentName, actualInstances := fakeBackup.DeploymentMatchesArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
+			Expect(actualInstances).To(ConsistOf(instance))
+		})
+
+		By("reopening the backup", func() {
+			Expect(fakeBackup.MarkResumedCallCount()).To(Equal(1))
+		})
+
+		By("not running any lock or backup scripts", func() {
+			Expect(deployment.PreBackupLockCallCount()).To(BeZero())
+			Expect(deployment.BackupCallCount()).To(BeZero())
+			Expect(deployment.PostBackupUnlockCallCount()).To(BeZero())
+		})
+
+		By("draining the deployment into the backup", func() {
+			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(Equal(1))
+			_, drainedBackup, drainedDeployment, actualVerifyDrained := artifactCopier.DownloadBackupFromDeploymentArgsForCall(0)
+			Expect(drainedBackup).To(Equal(fakeBackup))
+			Expect(drainedDeployment).To(Equal(deployment))
+			Expect(actualVerifyDrained).To(BeFalse())
+			Expect(fakeBackup.MarkCompleteCallCount()).To(Equal(1))
+		})
+
+		By("cleaning up the artifacts on the instances", func() {
+			Expect(instance.MarkArtifactDirCreatedCallCount()).To(Equal(1))
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+
+		By("recording the finish time", func() {
+			Expect(fakeBackup.AddFinishTimeArgsForCall(0)).To(Equal(finishTime))
+		})
+	})
+
+	Context("when the drained artifacts should be verified", func() {
+		BeforeEach(func() {
+			verifyDrained = true
+		})
+
+		It("asks the drain to verify them", func() {
+			Expect(actualResumeError).NotTo(HaveOccurred())
+			_, _, _, actualVerifyDrained := artifactCopier.DownloadBackupFromDeploymentArgsForCall(0)
+			Expect(actualVerifyDrained).To(BeTrue())
+		})
+	})
+
+	Context("when the backup cannot be opened", func() {
+		BeforeEach(func() {
+			fakeBackupManager.OpenReturns(nil, fmt.Errorf("no such directory"))
+		})
+
+		It("fails without draining", func() {
+			Expect(actualResumeError).To(MatchError(ContainSubstring("no such directory")))
+			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the backup does not match the deployment", func() {
+		BeforeEach(func() {
+			fakeBackup.DeploymentMatchesReturns(false, nil)
+		})
+
+		It("fails without touching the backup", func() {
+			Expect(actualResumeError).To(MatchError(ContainSubstring("Backup does not match the instances of deployment 'foobarbaz'")))
+			Expect(fakeBackup.MarkResumedCallCount()).To(BeZero())
+			Expect(fakeBackup.AddFinishTimeCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the artifacts are no longer on the instances", func() {
+		BeforeEach(func() {
+			instance.ArtifactDirExistsReturns(false, nil)
+		})
+
+		It("fails without touching the backup", func() {
+			Expect(actualResumeError).To(MatchError(ContainSubstring("The artifacts of the interrupted backup are no longer on instances redis/abc")))
+			Expect(fakeBackup.MarkResumedCallCount()).To(BeZero())
+			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the artifacts on the instances cannot be checked", func() {
+		BeforeEach(func() {
+			instance.ArtifactDirExistsReturns(false, fmt.Errorf("ssh error"))
+		})
+
+		It("fails without touching the backup", func() {
+			Expect(actualResumeError).To(MatchError(ContainSubstring("ssh error")))
+			Expect(fakeBackup.MarkResumedCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the backup cannot be resumed", func() {
+		BeforeEach(func() {
+			fakeBackup.MarkResumedReturns(fmt.Errorf("backup is already complete"))
+		})
+
+		It("fails without draining or changing the finish time", func() {
+			Expect(actualResumeError).To(MatchError(ContainSubstring("backup is already complete")))
+			Expect(artifactCopier.DownloadBackupFromDeploymentCallCount()).To(BeZero())
+			Expect(fakeBackup.AddFinishTimeCallCount()).To(BeZero())
+		})
+
+		It("does not remove the artifacts from the instances", func() {
+			Expect(instance.MarkArtifactDirCreatedCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the drain fails again", func() {
+		BeforeEach(func() {
+			artifactCopier.DownloadBackupFromDeploymentReturns(fmt.Errorf("network blip"))
+		})
+
+		It("keeps the remaining artifacts on the instances", func() {
+			Expect(actualResumeError).To(MatchError(ContainSubstring("network blip")))
+			Expect(instance.RetainArtifactDirCallCount()).To(Equal(1))
+			Expect(fakeBackup.MarkDrainFailedCallCount()).To(Equal(1))
+			Expect(fakeBackup.MarkCompleteCallCount()).To(BeZero())
+			Expect(fakeBackup.AddFinishTimeCallCount()).To(Equal(1))
+		})
 	})
 })
 
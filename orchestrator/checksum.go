@@ -1,5 +1,16 @@
 package orchestrator
 
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
 type BackupChecksum map[string]string
 
 func (b BackupChecksum) Match(other BackupChecksum) (bool, []string) {
@@ -16,6 +27,34 @@ func (b BackupChecksum) Match(other BackupChecksum) (bool, []string) {
 	return true, []string{}
 }
 
+// ChangedSince returns the files whose checksum differs from the previous
+// checksum, including files that are new since then, in name order.
+func (b BackupChecksum) ChangedSince(previous BackupChecksum) []string {
+	files := []string{}
+
+	for key := range b {
+		if previousSha, found := previous[key]; !found || previousSha != b[key] {
+			files = append(files, key)
+		}
+	}
+
+	sort.Strings(files)
+	return files
+}
+
+// Only returns the checksums of the given files.
+func (b BackupChecksum) Only(files []string) BackupChecksum {
+	checksum := BackupChecksum{}
+
+	for _, file := range files {
+		if sha, found := b[file]; found {
+			checksum[file] = sha
+		}
+	}
+
+	return checksum
+}
+
 func (b BackupChecksum) getMismatchedFiles(other BackupChecksum) []string {
 	var files []string
 
@@ -27,3 +66,71 @@ func (b BackupChecksum) getMismatchedFiles(other BackupChecksum) []string {
 
 	return files
 }
+
+func CalculateTarChecksum(reader io.Reader, logger Logger) (BackupChecksum, error) {
+	tarReader := tar.NewReader(reader)
+	checksum := BackupChecksum{}
+	for {
+		tarHeader, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Error reading tar")
+		}
+		if tarHeader.FileInfo().IsDir() || tarHeader.FileInfo().Name() == "./" {
+			continue
+		}
+
+		fileShasum := sha256.New()
+		if _, err := io.Copy(fileShasum, tarReader); err != nil {
+			return nil, errors.Wrap(err, "Error calculating sha")
+		}
+		logger.Debug("bbr", "Calculating shasum for local file %s", tarHeader.Name)
+		checksum[tarHeader.Name] = fmt.Sprintf("%x", fileShasum.Sum(nil))
+	}
+
+	return checksum, nil
+}
+
+// tarChecksumWriter calculates the checksum of a tar stream as it is written,
+// so that an artifact does not have to be read back to be checksummed.
+type tarChecksumWriter struct {
+	pipeWriter *io.PipeWriter
+	result     chan tarChecksumResult
+}
+
+type tarChecksumResult struct {
+	checksum BackupChecksum
+	err      error
+}
+
+func newTarChecksumWriter(logger Logger) *tarChecksumWriter {
+	pipeReader, pipeWriter := io.Pipe()
+	writer := &tarChecksumWriter{pipeWriter: pipeWriter, result: make(chan tarChecksumResult, 1)}
+
+	go func() {
+		checksum, err := CalculateTarChecksum(pipeReader, logger)
+		// Consume the tar padding, or the rest of the stream after an error, so
+		// that writes never block.
+		io.Copy(ioutil.Discard, pipeReader)
+		writer.result <- tarChecksumResult{checksum: checksum, err: err}
+	}()
+
+	return writer
+}
+
+func (w *tarChecksumWriter) Write(p []byte) (int, error) {
+	return w.pipeWriter.Write(p)
+}
+
+func (w *tarChecksumWriter) Close() error {
+	return w.pipeWriter.Close()
+}
+
+// Checksum ends the stream and returns the checksum of every file in it.
+func (w *tarChecksumWriter) Checksum() (BackupChecksum, error) {
+	w.Close()
+	result := <-w.result
+	return result.checksum, result.err
+}
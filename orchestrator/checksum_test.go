@@ -1,7 +1,13 @@
 package orchestrator_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
 	. "github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -63,4 +69,56 @@ var _ = Describe("Backuper/Checksum", func() {
 			})
 		})
 	})
+
+	Describe("ChangedSince", func() {
+		It("returns the changed and new files in name order", func() {
+			files := BackupChecksum{
+				"/var/foo": "checksum1",
+				"/var/bar": "checksum2",
+				"/var/baz": "checksum3",
+			}.ChangedSince(BackupChecksum{
+				"/var/foo":     "checksum11111111",
+				"/var/baz":     "checksum3",
+				"/var/deleted": "checksum4",
+			})
+
+			Expect(files).To(Equal([]string{"/var/bar", "/var/foo"}))
+		})
+
+		It("returns an empty list when nothing has changed", func() {
+			Expect(BackupChecksum{"/var/foo": "bar"}.ChangedSince(BackupChecksum{"/var/foo": "bar"})).To(BeEmpty())
+		})
+	})
+
+	Describe("Only", func() {
+		It("returns the checksums of the given files", func() {
+			checksum := BackupChecksum{
+				"/var/foo": "checksum1",
+				"/var/bar": "checksum2",
+			}.Only([]string{"/var/foo", "/var/not-there"})
+
+			Expect(checksum).To(Equal(BackupChecksum{"/var/foo": "checksum1"}))
+		})
+	})
+
+	Describe("CalculateTarChecksum", func() {
+		It("returns the sha256 of every file in the tar", func() {
+			contents := createTarWithContents(map[string]string{
+				"file1": "This archive contains some text files.",
+				"file2": "Gopher names:\nGeorge\nGeoffrey\nGonzo",
+			})
+
+			checksum, err := CalculateTarChecksum(bytes.NewReader(contents), new(fakes.FakeLogger))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checksum).To(Equal(BackupChecksum{
+				"file1": fmt.Sprintf("%x", sha256.Sum256([]byte("This archive contains some text files."))),
+				"file2": fmt.Sprintf("%x", sha256.Sum256([]byte("Gopher names:\nGeorge\nGeoffrey\nGonzo"))),
+			}))
+		})
+
+		It("fails when the contents are not a tar", func() {
+			_, err := CalculateTarChecksum(strings.NewReader(strings.Repeat("not a tar file", 1000)), new(fakes.FakeLogger))
+			Expect(err).To(MatchError(ContainSubstring("Error reading tar")))
+		})
+	})
 })
@@ -1,11 +1,13 @@
 package orchestrator
 
+import "context"
+
 type CleanupPreviousStep struct{}
 
 func NewCleanupPreviousStep() Step {
 	return &CleanupPreviousStep{}
 }
 
-func (s *CleanupPreviousStep) Run(session *Session) error {
+func (s *CleanupPreviousStep) Run(ctx context.Context, session *Session) error {
 	return session.CurrentDeployment().CleanupPrevious()
 }
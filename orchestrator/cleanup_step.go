@@ -1,6 +1,9 @@
 package orchestrator
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type CleanupStep struct{}
 
@@ -8,7 +11,7 @@ func NewCleanupStep() Step {
 	return &CleanupStep{}
 }
 
-func (s *CleanupStep) Run(session *Session) error {
+func (s *CleanupStep) Run(ctx context.Context, session *Session) error {
 
 	if err := session.CurrentDeployment().Cleanup(); err != nil {
 		return NewCleanupError(
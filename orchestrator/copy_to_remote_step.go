@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 )
 
@@ -14,8 +16,8 @@ func NewCopyToRemoteStep(artifactCopier ArtifactCopier) Step {
 	}
 }
 
-func (s *CopyToRemoteStep) Run(session *Session) error {
-	err := s.artifactCopier.UploadBackupToDeployment(session.CurrentArtifact(), session.CurrentDeployment())
+func (s *CopyToRemoteStep) Run(ctx context.Context, session *Session) error {
+	err := s.artifactCopier.UploadBackupToDeployment(ctx, session.CurrentArtifact(), session.CurrentDeployment())
 	if err != nil {
 		return errors.Errorf("Unable to send backup to remote machine. Got error: %s", err)
 	}
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -11,9 +12,10 @@ type CreateArtifactStep struct {
 	deploymentManager DeploymentManager
 	nowFunc           func() time.Time
 	timeStamp         string
+	bbrVersion        string
 }
 
-func (s *CreateArtifactStep) Run(session *Session) error {
+func (s *CreateArtifactStep) Run(ctx context.Context, session *Session) error {
 	s.logger.Info("bbr", "Starting backup of %s...\n", session.DeploymentName())
 
 	directoryName := fmt.Sprintf("%s_%s", session.DeploymentName(), s.timeStamp)
@@ -21,16 +23,37 @@ func (s *CreateArtifactStep) Run(session *Session) error {
 	if err != nil {
 		return err
 	}
-	artifact.CreateMetadataFileWithStartTime(s.nowFunc())
+	err = artifact.CreateMetadataFileWithStartTime(s.nowFunc())
+	if err != nil {
+		return err
+	}
 	session.SetCurrentArtifact(artifact)
 
 	err = s.deploymentManager.SaveManifest(session.DeploymentName(), artifact)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	return artifact.AddDeploymentMetadata(s.deploymentMetadata(session))
+}
+
+func (s *CreateArtifactStep) deploymentMetadata(session *Session) DeploymentMetadata {
+	directorInfo, err := s.deploymentManager.DirectorInfo()
+	if err != nil {
+		s.logger.Warn("bbr", "Failed to get director info, it will not be recorded in the backup metadata: %s", err)
+	}
+
+	deploymentMetadata := DeploymentMetadata{
+		Name:       session.DeploymentName(),
+		BBRVersion: s.bbrVersion,
+		Director:   directorInfo,
+	}
+	for _, instance := range session.CurrentDeployment().BackupableInstances() {
+		deploymentMetadata.Instances = append(deploymentMetadata.Instances, NewInstanceMetadata(instance))
+	}
+	return deploymentMetadata
 }
 
-func NewCreateArtifactStep(logger Logger, backupManager BackupManager, deploymentManager DeploymentManager, nowFunc func() time.Time, timeStamp string) Step {
-	return &CreateArtifactStep{logger: logger, backupManager: backupManager, deploymentManager: deploymentManager, nowFunc: nowFunc, timeStamp: timeStamp}
+func NewCreateArtifactStep(logger Logger, backupManager BackupManager, deploymentManager DeploymentManager, nowFunc func() time.Time, timeStamp, bbrVersion string) Step {
+	return &CreateArtifactStep{logger: logger, backupManager: backupManager, deploymentManager: deploymentManager, nowFunc: nowFunc, timeStamp: timeStamp, bbrVersion: bbrVersion}
 }
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 
 	"strings"
@@ -15,18 +16,19 @@ const ArtifactDirectory = "/var/vcap/store/bbr-backup"
 type Deployment interface {
 	IsBackupable() bool
 	BackupableInstances() []Instance
-	CheckArtifactDir() error
+	CheckArtifactDir(context.Context) error
 	IsRestorable() bool
 	RestorableInstances() []Instance
-	PreBackupLock(LockOrderer, executor.Executor) error
-	Backup(executor.Executor) error
-	PostBackupUnlock(bool, LockOrderer, executor.Executor) error
-	Restore() error
+	PreBackupLock(context.Context, LockOrderer, executor.Executor) error
+	Backup(context.Context, executor.Executor) error
+	PostBackupUnlock(context.Context, bool, LockOrderer, executor.Executor) error
+	Restore(context.Context) error
 	Cleanup() error
 	CleanupPrevious() error
 	Instances() []Instance
-	PreRestoreLock(LockOrderer, executor.Executor) error
-	PostRestoreUnlock(LockOrderer, executor.Executor) error
+	PreRestoreLock(context.Context, LockOrderer, executor.Executor) error
+	PostRestoreUnlock(context.Context, LockOrderer, executor.Executor) error
+	PostRestoreValidate(context.Context, executor.Executor) error
 	ValidateLockingDependencies(orderer LockOrderer) error
 }
 
@@ -53,11 +55,11 @@ func (bd *deployment) BackupableInstances() []Instance {
 	return bd.instances.AllBackupable()
 }
 
-func (bd *deployment) CheckArtifactDir() error {
+func (bd *deployment) CheckArtifactDir(ctx context.Context) error {
 	var errs []string
 
 	for _, inst := range bd.instances {
-		exists, err := inst.ArtifactDirExists()
+		exists, err := inst.ArtifactDirExists(ctx)
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("Error checking %s on instance %s/%s", ArtifactDirectory, inst.Name(), inst.ID()))
 		} else if exists {
@@ -78,7 +80,7 @@ func (bd *deployment) ValidateLockingDependencies(lockOrderer LockOrderer) error
 	return err
 }
 
-func (bd *deployment) PreBackupLock(lockOrderer LockOrderer, executor executor.Executor) error {
+func (bd *deployment) PreBackupLock(ctx context.Context, lockOrderer LockOrderer, executor executor.Executor) error {
 	bd.Logger.Info("bbr", "Running pre-backup-lock scripts...")
 
 	jobs := bd.instances.Jobs()
@@ -88,13 +90,13 @@ func (bd *deployment) PreBackupLock(lockOrderer LockOrderer, executor executor.E
 		return err
 	}
 
-	preBackupLockErrors := executor.Run(newJobExecutables(orderedJobs, NewJobPreBackupLockExecutable))
+	preBackupLockErrors := executor.Run(ctx, newJobExecutables(orderedJobs, NewJobPreBackupLockExecutable))
 
 	bd.Logger.Info("bbr", "Finished running pre-backup-lock scripts.")
 	return ConvertErrors(preBackupLockErrors)
 }
 
-func (bd *deployment) Backup(exe executor.Executor) error {
+func (bd *deployment) Backup(ctx context.Context, exe executor.Executor) error {
 	bd.Logger.Info("bbr", "Running backup scripts...")
 
 	instances := bd.instances.AllBackupable()
@@ -107,13 +109,13 @@ func (bd *deployment) Backup(exe executor.Executor) error {
 		}
 	}
 
-	backupErr := exe.Run([][]executor.Executable{executables})
+	backupErr := exe.Run(ctx, [][]executor.Executable{executables})
 
 	bd.Logger.Info("bbr", "Finished running backup scripts.")
 	return ConvertErrors(backupErr)
 }
 
-func (bd *deployment) PostBackupUnlock(afterSuccessfulBackup bool, lockOrderer LockOrderer, executor executor.Executor) error {
+func (bd *deployment) PostBackupUnlock(ctx context.Context, afterSuccessfulBackup bool, lockOrderer LockOrderer, executor executor.Executor) error {
 	bd.Logger.Info("bbr", "Running post-backup-unlock scripts...")
 
 	jobs := bd.instances.Jobs()
@@ -129,13 +131,13 @@ func (bd *deployment) PostBackupUnlock(afterSuccessfulBackup bool, lockOrderer L
 		executableJobConstructor = NewJobPostSuccessfulBackupUnlockExecutable
 
 	}
-	postBackupUnlockErrors := executor.Run(newJobExecutables(reversedJobs, executableJobConstructor))
+	postBackupUnlockErrors := executor.Run(ctx, newJobExecutables(reversedJobs, executableJobConstructor))
 
 	bd.Logger.Info("bbr", "Finished running post-backup-unlock scripts.")
 	return ConvertErrors(postBackupUnlockErrors)
 }
 
-func (bd *deployment) PreRestoreLock(lockOrderer LockOrderer, executor executor.Executor) error {
+func (bd *deployment) PreRestoreLock(ctx context.Context, lockOrderer LockOrderer, executor executor.Executor) error {
 	bd.Logger.Info("bbr", "Running pre-restore-lock scripts...")
 
 	jobs := bd.instances.Jobs()
@@ -145,20 +147,20 @@ func (bd *deployment) PreRestoreLock(lockOrderer LockOrderer, executor executor.
 		return err
 	}
 
-	preRestoreLockErrors := executor.Run(newJobExecutables(orderedJobs, NewJobPreRestoreLockExecutable))
+	preRestoreLockErrors := executor.Run(ctx, newJobExecutables(orderedJobs, NewJobPreRestoreLockExecutable))
 
 	bd.Logger.Info("bbr", "Finished running pre-restore-lock scripts.")
 	return ConvertErrors(preRestoreLockErrors)
 }
 
-func (bd *deployment) Restore() error {
+func (bd *deployment) Restore(ctx context.Context) error {
 	bd.Logger.Info("bbr", "Running restore scripts...")
-	err := bd.instances.AllRestoreable().Restore()
+	err := bd.instances.AllRestoreable().Restore(ctx)
 	bd.Logger.Info("bbr", "Finished running restore scripts.")
 	return err
 }
 
-func (bd *deployment) PostRestoreUnlock(lockOrderer LockOrderer, executor executor.Executor) error {
+func (bd *deployment) PostRestoreUnlock(ctx context.Context, lockOrderer LockOrderer, executor executor.Executor) error {
 	bd.Logger.Info("bbr", "Running post-restore-unlock scripts...")
 
 	jobs := bd.instances.Jobs()
@@ -169,12 +171,28 @@ func (bd *deployment) PostRestoreUnlock(lockOrderer LockOrderer, executor execut
 	}
 	reversedJobs := Reverse(orderedJobs)
 
-	postRestoreUnlockErrors := executor.Run(newJobExecutables(reversedJobs, NewJobPostRestoreUnlockExecutable))
+	postRestoreUnlockErrors := executor.Run(ctx, newJobExecutables(reversedJobs, NewJobPostRestoreUnlockExecutable))
 
 	bd.Logger.Info("bbr", "Finished running post-restore-unlock scripts.")
 	return ConvertErrors(postRestoreUnlockErrors)
 }
 
+// PostRestoreValidate runs the post-restore-validate scripts of every restored
+// job at once, as the jobs have already been unlocked.
+func (bd *deployment) PostRestoreValidate(ctx context.Context, exe executor.Executor) error {
+	bd.Logger.Info("bbr", "Running post-restore-validate scripts...")
+
+	var executables []executor.Executable
+	for _, job := range restoredJobs(bd.instances) {
+		executables = append(executables, NewJobPostRestoreValidateExecutable(job))
+	}
+
+	validateErrors := exe.Run(ctx, [][]executor.Executable{executables})
+
+	bd.Logger.Info("bbr", "Finished running post-restore-validate scripts.")
+	return ConvertErrors(validateErrors)
+}
+
 func newJobExecutables(jobsList [][]Job, newJobExecutable func(Job) executor.Executable) [][]executor.Executable {
 	var executablesList [][]executor.Executable
 	for _, jobs := range jobsList {
@@ -1,7 +1,16 @@
 package orchestrator
 
+import "context"
+
 //go:generate counterfeiter -o fakes/fake_deployment_manager.go . DeploymentManager
 type DeploymentManager interface {
-	Find(deploymentName string) (Deployment, error)
+	Find(ctx context.Context, deploymentName string) (Deployment, error)
 	SaveManifest(deploymentName string, artifact Backup) error
+	DirectorInfo() (DirectorInfo, error)
+}
+
+type DirectorInfo struct {
+	UUID    string
+	Name    string
+	Version string
 }
@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
@@ -63,13 +64,14 @@ var _ = Describe("Deployment", func() {
 		})
 
 		JustBeforeEach(func() {
-			lockError = deployment.PreBackupLock(lockOrderer, fakeExecutor)
+			lockError = deployment.PreBackupLock(context.Background(), lockOrderer, fakeExecutor)
 		})
 
 		It("delegates the execution to the executor", func() {
 			Expect(lockError).NotTo(HaveOccurred())
 			Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
+			_, executables := fakeExecutor.RunArgsForCall(0)
+			Expect(executables).To(Equal([][]executor.Executable{
 				{orchestrator.NewJobPreBackupLockExecutable(job2a)},
 				{orchestrator.NewJobPreBackupLockExecutable(job3a), orchestrator.NewJobPreBackupLockExecutable(job1a)},
 				{orchestrator.NewJobPreBackupLockExecutable(job1b)},
@@ -108,7 +110,7 @@ var _ = Describe("Deployment", func() {
 		var fakeExecutor *executorFakes.FakeExecutor
 
 		JustBeforeEach(func() {
-			err = deployment.Backup(fakeExecutor)
+			err = deployment.Backup(context.Background(), fakeExecutor)
 		})
 
 		BeforeEach(func() {
@@ -126,7 +128,8 @@ var _ = Describe("Deployment", func() {
 		It("calls Backup() on all backupable instances", func() {
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
+			_, executables := fakeExecutor.RunArgsForCall(0)
+			Expect(executables).To(Equal([][]executor.Executable{
 				{orchestrator.NewBackupExecutable(job1a), orchestrator.NewBackupExecutable(job3a)},
 			}))
 		})
@@ -141,7 +144,8 @@ var _ = Describe("Deployment", func() {
 			It("fails and stops the backup", func() {
 				Expect(err).To(MatchError(ContainSubstring("backup instance1 failed")))
 
-				Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
+				_, executables := fakeExecutor.RunArgsForCall(0)
+				Expect(executables).To(Equal([][]executor.Executable{
 					{orchestrator.NewBackupExecutable(job1a), orchestrator.NewBackupExecutable(job3a)},
 				}))
 			})
@@ -163,11 +167,12 @@ var _ = Describe("Deployment", func() {
 		})
 
 		It("calls the executor with post successful backup executables", func() {
-			lockError = deployment.PostBackupUnlock(true, lockOrderer, fakeExecutor)
+			lockError = deployment.PostBackupUnlock(context.Background(), true, lockOrderer, fakeExecutor)
 
 			Expect(lockError).NotTo(HaveOccurred())
 			Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
+			_, executables := fakeExecutor.RunArgsForCall(0)
+			Expect(executables).To(Equal([][]executor.Executable{
 				{orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(job2a)},
 				{orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(job3a), orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(job1a)},
 				{orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(job1b)},
@@ -176,11 +181,12 @@ var _ = Describe("Deployment", func() {
 
 		Context("when called after a failed backup", func() {
 			It("calls the executor with post failed backup executables", func() {
-				lockError = deployment.PostBackupUnlock(false, lockOrderer, fakeExecutor)
+				lockError = deployment.PostBackupUnlock(context.Background(), false, lockOrderer, fakeExecutor)
 
 				Expect(lockError).NotTo(HaveOccurred())
 				Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-				Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
+				_, executables := fakeExecutor.RunArgsForCall(0)
+				Expect(executables).To(Equal([][]executor.Executable{
 					{orchestrator.NewJobPostFailedBackupUnlockExecutable(job2a)},
 					{orchestrator.NewJobPostFailedBackupUnlockExecutable(job3a), orchestrator.NewJobPostFailedBackupUnlockExecutable(job1a)},
 					{orchestrator.NewJobPostFailedBackupUnlockExecutable(job1b)},
@@ -195,7 +201,7 @@ var _ = Describe("Deployment", func() {
 					fmt.Errorf("job2a failed"),
 				})
 
-				lockError = deployment.PostBackupUnlock(true, lockOrderer, fakeExecutor)
+				lockError = deployment.PostBackupUnlock(context.Background(), true, lockOrderer, fakeExecutor)
 
 				Expect(lockError).To(MatchError(SatisfyAll(
 					ContainSubstring("job1b failed"),
@@ -208,7 +214,7 @@ var _ = Describe("Deployment", func() {
 			It("fails", func() {
 				lockOrderer.OrderReturns(nil, fmt.Errorf("test lock orderer error"))
 
-				lockError = deployment.PostBackupUnlock(true, lockOrderer, fakeExecutor)
+				lockError = deployment.PostBackupUnlock(context.Background(), true, lockOrderer, fakeExecutor)
 
 				Expect(lockError).To(MatchError(ContainSubstring("test lock orderer error")))
 			})
@@ -270,7 +276,7 @@ var _ = Describe("Deployment", func() {
 		})
 
 		JustBeforeEach(func() {
-			artifactDirError = deployment.CheckArtifactDir()
+			artifactDirError = deployment.CheckArtifactDir(context.Background())
 		})
 
 		Context("when artifact directory does not exist", func() {
@@ -309,7 +315,7 @@ var _ = Describe("Deployment", func() {
 		var err error
 
 		JustBeforeEach(func() {
-			err = deployment.Restore()
+			err = deployment.Restore(context.Background())
 		})
 
 		BeforeEach(func() {
@@ -357,13 +363,14 @@ var _ = Describe("Deployment", func() {
 		})
 
 		JustBeforeEach(func() {
-			lockError = deployment.PreRestoreLock(lockOrderer, fakeExecutor)
+			lockError = deployment.PreRestoreLock(context.Background(), lockOrderer, fakeExecutor)
 		})
 
 		It("delegates the execution to the executor", func() {
 			Expect(lockError).NotTo(HaveOccurred())
 			Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
+			_, executables := fakeExecutor.RunArgsForCall(0)
+			Expect(executables).To(Equal([][]executor.Executable{
 				{orchestrator.NewJobPreRestoreLockExecutable(job2a)},
 				{orchestrator.NewJobPreRestoreLockExecutable(job3a), orchestrator.NewJobPreRestoreLockExecutable(job1a)},
 				{orchestrator.NewJobPreRestoreLockExecutable(job1b)},
@@ -412,13 +419,14 @@ var _ = Describe("Deployment", func() {
 		})
 
 		JustBeforeEach(func() {
-			lockError = deployment.PostRestoreUnlock(lockOrderer, fakeExecutor)
+			lockError = deployment.PostRestoreUnlock(context.Background(), lockOrderer, fakeExecutor)
 		})
 
 		It("delegates the execution to the executor", func() {
 			Expect(lockError).NotTo(HaveOccurred())
 			Expect(lockOrderer.OrderArgsForCall(0)).To(ConsistOf(job1a, job1b, job2a, job3a))
-			Expect(fakeExecutor.RunArgsForCall(0)).To(Equal([][]executor.Executable{
+			_, executables := fakeExecutor.RunArgsForCall(0)
+			Expect(executables).To(Equal([][]executor.Executable{
 				{orchestrator.NewJobPostRestoreUnlockExecutable(job2a)},
 				{orchestrator.NewJobPostRestoreUnlockExecutable(job3a), orchestrator.NewJobPostRestoreUnlockExecutable(job1a)},
 				{orchestrator.NewJobPostRestoreUnlockExecutable(job1b)},
@@ -452,6 +460,49 @@ var _ = Describe("Deployment", func() {
 		})
 	})
 
+	Context("PostRestoreValidate", func() {
+		var (
+			validateError error
+			fakeExecutor  *executorFakes.FakeExecutor
+		)
+
+		BeforeEach(func() {
+			fakeExecutor = new(executorFakes.FakeExecutor)
+			instances = []orchestrator.Instance{instance1, instance2, instance3}
+		})
+
+		JustBeforeEach(func() {
+			validateError = deployment.PostRestoreValidate(context.Background(), fakeExecutor)
+		})
+
+		It("validates every job at once", func() {
+			Expect(validateError).NotTo(HaveOccurred())
+			_, executables := fakeExecutor.RunArgsForCall(0)
+			Expect(executables).To(Equal([][]executor.Executable{{
+				orchestrator.NewJobPostRestoreValidateExecutable(job1a),
+				orchestrator.NewJobPostRestoreValidateExecutable(job1b),
+				orchestrator.NewJobPostRestoreValidateExecutable(job2a),
+				orchestrator.NewJobPostRestoreValidateExecutable(job3a),
+			}}))
+		})
+
+		Context("if the post-restore-validate fails", func() {
+			BeforeEach(func() {
+				fakeExecutor.RunReturns([]error{
+					fmt.Errorf("job1b failed"),
+					fmt.Errorf("job2a failed"),
+				})
+			})
+
+			It("fails", func() {
+				Expect(validateError).To(MatchError(SatisfyAll(
+					ContainSubstring("job1b failed"),
+					ContainSubstring("job2a failed"),
+				)))
+			})
+		})
+	})
+
 	Context("IsRestorable", func() {
 		Context("when at least one instance is restorable", func() {
 			BeforeEach(func() {
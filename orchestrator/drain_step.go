@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"time"
 )
 
@@ -16,12 +17,36 @@ func NewDrainStep(logger Logger, artifactCopier ArtifactCopier) Step {
 	}
 }
 
-func (s *DrainStep) Run(session *Session) error {
-	err := s.artifactCopier.DownloadBackupFromDeployment(session.CurrentArtifact(), session.CurrentDeployment())
+func (s *DrainStep) Run(ctx context.Context, session *Session) error {
+	err := s.artifactCopier.DownloadBackupFromDeployment(ctx, session.CurrentArtifact(), session.CurrentDeployment(), session.VerifyDrained())
 	if err != nil {
 		s.logger.Info("bbr", "Failed to create backup of %s on %v, failed during drain step\n", session.DeploymentName(), time.Now())
+		if markErr := session.CurrentArtifact().MarkDrainFailed(); markErr != nil {
+			s.logger.Error("bbr", "Could not record that the backup of %s failed during the drain step, so it cannot be resumed: %s", session.DeploymentName(), markErr)
+		} else {
+			s.retainUndrainedArtifacts(session)
+		}
 		return NewDrainError(err.Error())
 	}
+
+	err = session.CurrentArtifact().MarkComplete()
+	if err != nil {
+		return NewDrainError(err.Error())
+	}
+
 	s.logger.Info("bbr", "Backup created of %s on %v\n", session.DeploymentName(), time.Now())
 	return nil
 }
+
+// retainUndrainedArtifacts leaves the artifacts that could not be drained on the
+// instances, so that the backup can be finished with --resume rather than taken
+// again from scratch.
+func (s *DrainStep) retainUndrainedArtifacts(session *Session) {
+	for _, instance := range session.CurrentDeployment().BackupableInstances() {
+		instance.RetainArtifactDir()
+	}
+
+	s.logger.Info("bbr", "Artifacts that were not copied have been left on the instances of %s. "+
+		"Run the backup again with --resume and the backup artifact to finish copying them, "+
+		"or run backup-cleanup to discard them.\n", session.DeploymentName())
+}
@@ -2,6 +2,8 @@ package orchestrator
 
 import (
 	"bytes"
+	"strings"
+	"time"
 
 	"fmt"
 
@@ -18,6 +20,8 @@ type UnlockError customError
 type CleanupError customError
 type ArtifactDirError customError
 type DrainError customError
+type LockDurationError customError
+type PostRestoreValidateError customError
 
 func NewLockError(errorMessage string) LockError {
 	return LockError{errors.New(errorMessage)}
@@ -27,6 +31,24 @@ func NewBackupError(errorMessage string) BackupError {
 	return BackupError{errors.New(errorMessage)}
 }
 
+// WrapLockError and WrapBackupError keep the errors of the scripts that failed,
+// so that a ScriptTimeoutError among them can still be found with errors.As.
+func WrapLockError(err error) LockError {
+	return LockError{err}
+}
+
+func WrapBackupError(err error) BackupError {
+	return BackupError{err}
+}
+
+func (err LockError) Unwrap() error {
+	return err.error
+}
+
+func (err BackupError) Unwrap() error {
+	return err.error
+}
+
 func NewPostUnlockError(errorMessage string) UnlockError {
 	return UnlockError{errors.New(errorMessage)}
 }
@@ -35,6 +57,14 @@ func NewDrainError(errorMessage string) DrainError {
 	return DrainError{errors.New(errorMessage)}
 }
 
+func NewLockDurationError(errorMessage string) LockDurationError {
+	return LockDurationError{errors.New(errorMessage)}
+}
+
+func NewPostRestoreValidateError(errorMessage string) PostRestoreValidateError {
+	return PostRestoreValidateError{errors.New(errorMessage)}
+}
+
 func NewCleanupError(errorMessage string) CleanupError {
 	return CleanupError{errors.New(errorMessage)}
 }
@@ -43,6 +73,35 @@ func NewArtifactDirError(errorMessage string) ArtifactDirError {
 	return ArtifactDirError{errors.New(errorMessage)}
 }
 
+// ScriptTimeoutError is returned when a job script is killed for running
+// longer than its timeout.
+type ScriptTimeoutError struct {
+	Script  string
+	Timeout time.Duration
+}
+
+func NewScriptTimeoutError(script string, timeout time.Duration) ScriptTimeoutError {
+	return ScriptTimeoutError{Script: script, Timeout: timeout}
+}
+
+func (err ScriptTimeoutError) Error() string {
+	return fmt.Sprintf("%s script timed out after %s", err.Script, err.Timeout)
+}
+
+// DeploymentMismatchError is returned by Backup.DeploymentMatches, along with
+// false, when the backup has instances that the deployment does not have.
+type DeploymentMismatchError struct {
+	error
+	MissingInstances []string
+}
+
+func NewDeploymentMismatchError(message string, missingInstances []string) DeploymentMismatchError {
+	return DeploymentMismatchError{
+		error:            errors.Errorf("%s: %s", message, strings.Join(missingInstances, ", ")),
+		MissingInstances: missingInstances,
+	}
+}
+
 func ConvertErrors(errs []error) error {
 	flattenedErrors := flattenErrors(errs)
 
@@ -78,6 +137,10 @@ func (err Error) Error() string {
 	return err.PrettyError(false)
 }
 
+func (err Error) Unwrap() []error {
+	return err
+}
+
 func (err Error) PrettyError(includeStacktrace bool) string {
 	if err.IsNil() {
 		return ""
@@ -157,6 +220,23 @@ func (err Error) IsPostBackup() bool {
 	return foundPostBackupError
 }
 
+// IsPostRestoreValidation is true when the only thing that went wrong with a
+// restore was that the post-restore-validate scripts found the restored data
+// unhealthy, meaning the restore itself ran to completion.
+func (err Error) IsPostRestoreValidation() bool {
+	if err.IsNil() {
+		return false
+	}
+
+	for _, e := range err {
+		if _, ok := e.(PostRestoreValidateError); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (err Error) IsFatal() bool {
 	return !err.IsNil() && !err.IsCleanup() && !err.IsPostBackup()
 }
@@ -176,6 +256,10 @@ func BuildExitCode(errs Error) int {
 			exitCode = exitCode | 1<<3
 		case CleanupError:
 			exitCode = exitCode | 1<<4
+		case LockDurationError:
+			exitCode = exitCode | 1<<5
+		case PostRestoreValidateError:
+			exitCode = exitCode | 1<<6
 		default:
 			exitCode = exitCode | 1
 		}
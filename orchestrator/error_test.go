@@ -25,6 +25,8 @@ var _ = Describe("Error", func() {
 	var backupError = orchestrator.NewBackupError("BACKUP_ERROR")
 	var postBackupUnlockError = orchestrator.NewPostUnlockError("POST_BACKUP_ERROR")
 	var cleanupError = orchestrator.NewCleanupError("CLEANUP_ERROR")
+	var lockDurationError = orchestrator.NewLockDurationError("LOCK_DURATION_ERROR")
+	var postRestoreValidateError = orchestrator.NewPostRestoreValidateError("POST_RESTORE_VALIDATE_ERROR")
 
 	Describe("IsCleanup", func() {
 		It("returns true when there is only one error - a cleanup error", func() {
@@ -75,6 +77,23 @@ var _ = Describe("Error", func() {
 		})
 	})
 
+	Describe("IsPostRestoreValidation", func() {
+		It("returns false when empty", func() {
+			var errors orchestrator.Error
+			Expect(errors.IsPostRestoreValidation()).To(BeFalse())
+		})
+
+		It("returns true when there are only post-restore-validate errors", func() {
+			errors := orchestrator.Error{postRestoreValidateError, postRestoreValidateError}
+			Expect(errors.IsPostRestoreValidation()).To(BeTrue())
+		})
+
+		It("returns false when any of the errors is not a post-restore-validate error", func() {
+			errors := orchestrator.Error{postRestoreValidateError, cleanupError}
+			Expect(errors.IsPostRestoreValidation()).To(BeFalse())
+		})
+	})
+
 	Describe("IsFatal", func() {
 		It("returns true when there is one error - a generic error", func() {
 			errors := orchestrator.Error{genericError}
@@ -112,6 +131,8 @@ var _ = Describe("Error", func() {
 				{"lockError", []error{lockError}, 4},
 				{"unlockError", []error{postBackupUnlockError}, 8},
 				{"cleanupError", []error{cleanupError}, 16},
+				{"lockDurationError", []error{lockDurationError}, 32},
+				{"postRestoreValidateError", []error{postRestoreValidateError}, 64},
 			}
 
 			for i := range errorCases {
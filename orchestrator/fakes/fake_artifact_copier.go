@@ -2,17 +2,20 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 )
 
 type FakeArtifactCopier struct {
-	DownloadBackupFromDeploymentStub        func(orchestrator.Backup, orchestrator.Deployment) error
+	DownloadBackupFromDeploymentStub        func(context.Context, orchestrator.Backup, orchestrator.Deployment, bool) error
 	downloadBackupFromDeploymentMutex       sync.RWMutex
 	downloadBackupFromDeploymentArgsForCall []struct {
-		arg1 orchestrator.Backup
-		arg2 orchestrator.Deployment
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
+		arg4 bool
 	}
 	downloadBackupFromDeploymentReturns struct {
 		result1 error
@@ -20,11 +23,12 @@ type FakeArtifactCopier struct {
 	downloadBackupFromDeploymentReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UploadBackupToDeploymentStub        func(orchestrator.Backup, orchestrator.Deployment) error
+	UploadBackupToDeploymentStub        func(context.Context, orchestrator.Backup, orchestrator.Deployment) error
 	uploadBackupToDeploymentMutex       sync.RWMutex
 	uploadBackupToDeploymentArgsForCall []struct {
-		arg1 orchestrator.Backup
-		arg2 orchestrator.Deployment
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
 	}
 	uploadBackupToDeploymentReturns struct {
 		result1 error
@@ -36,17 +40,19 @@ type FakeArtifactCopier struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeArtifactCopier) DownloadBackupFromDeployment(arg1 orchestrator.Backup, arg2 orchestrator.Deployment) error {
+func (fake *FakeArtifactCopier) DownloadBackupFromDeployment(arg1 context.Context, arg2 orchestrator.Backup, arg3 orchestrator.Deployment, arg4 bool) error {
 	fake.downloadBackupFromDeploymentMutex.Lock()
 	ret, specificReturn := fake.downloadBackupFromDeploymentReturnsOnCall[len(fake.downloadBackupFromDeploymentArgsForCall)]
 	fake.downloadBackupFromDeploymentArgsForCall = append(fake.downloadBackupFromDeploymentArgsForCall, struct {
-		arg1 orchestrator.Backup
-		arg2 orchestrator.Deployment
-	}{arg1, arg2})
-	fake.recordInvocation("DownloadBackupFromDeployment", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
+		arg4 bool
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("DownloadBackupFromDeployment", []interface{}{arg1, arg2, arg3, arg4})
 	fake.downloadBackupFromDeploymentMutex.Unlock()
 	if fake.DownloadBackupFromDeploymentStub != nil {
-		return fake.DownloadBackupFromDeploymentStub(arg1, arg2)
+		return fake.DownloadBackupFromDeploymentStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -61,17 +67,17 @@ func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentCallCount() int {
 	return len(fake.downloadBackupFromDeploymentArgsForCall)
 }
 
-func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentCalls(stub func(orchestrator.Backup, orchestrator.Deployment) error) {
+func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentCalls(stub func(context.Context, orchestrator.Backup, orchestrator.Deployment, bool) error) {
 	fake.downloadBackupFromDeploymentMutex.Lock()
 	defer fake.downloadBackupFromDeploymentMutex.Unlock()
 	fake.DownloadBackupFromDeploymentStub = stub
 }
 
-func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentArgsForCall(i int) (orchestrator.Backup, orchestrator.Deployment) {
+func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentArgsForCall(i int) (context.Context, orchestrator.Backup, orchestrator.Deployment, bool) {
 	fake.downloadBackupFromDeploymentMutex.RLock()
 	defer fake.downloadBackupFromDeploymentMutex.RUnlock()
 	argsForCall := fake.downloadBackupFromDeploymentArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentReturns(result1 error) {
@@ -97,17 +103,18 @@ func (fake *FakeArtifactCopier) DownloadBackupFromDeploymentReturnsOnCall(i int,
 	}{result1}
 }
 
-func (fake *FakeArtifactCopier) UploadBackupToDeployment(arg1 orchestrator.Backup, arg2 orchestrator.Deployment) error {
+func (fake *FakeArtifactCopier) UploadBackupToDeployment(arg1 context.Context, arg2 orchestrator.Backup, arg3 orchestrator.Deployment) error {
 	fake.uploadBackupToDeploymentMutex.Lock()
 	ret, specificReturn := fake.uploadBackupToDeploymentReturnsOnCall[len(fake.uploadBackupToDeploymentArgsForCall)]
 	fake.uploadBackupToDeploymentArgsForCall = append(fake.uploadBackupToDeploymentArgsForCall, struct {
-		arg1 orchestrator.Backup
-		arg2 orchestrator.Deployment
-	}{arg1, arg2})
-	fake.recordInvocation("UploadBackupToDeployment", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.Backup
+		arg3 orchestrator.Deployment
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("UploadBackupToDeployment", []interface{}{arg1, arg2, arg3})
 	fake.uploadBackupToDeploymentMutex.Unlock()
 	if fake.UploadBackupToDeploymentStub != nil {
-		return fake.UploadBackupToDeploymentStub(arg1, arg2)
+		return fake.UploadBackupToDeploymentStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -122,17 +129,17 @@ func (fake *FakeArtifactCopier) UploadBackupToDeploymentCallCount() int {
 	return len(fake.uploadBackupToDeploymentArgsForCall)
 }
 
-func (fake *FakeArtifactCopier) UploadBackupToDeploymentCalls(stub func(orchestrator.Backup, orchestrator.Deployment) error) {
+func (fake *FakeArtifactCopier) UploadBackupToDeploymentCalls(stub func(context.Context, orchestrator.Backup, orchestrator.Deployment) error) {
 	fake.uploadBackupToDeploymentMutex.Lock()
 	defer fake.uploadBackupToDeploymentMutex.Unlock()
 	fake.UploadBackupToDeploymentStub = stub
 }
 
-func (fake *FakeArtifactCopier) UploadBackupToDeploymentArgsForCall(i int) (orchestrator.Backup, orchestrator.Deployment) {
+func (fake *FakeArtifactCopier) UploadBackupToDeploymentArgsForCall(i int) (context.Context, orchestrator.Backup, orchestrator.Deployment) {
 	fake.uploadBackupToDeploymentMutex.RLock()
 	defer fake.uploadBackupToDeploymentMutex.RUnlock()
 	argsForCall := fake.uploadBackupToDeploymentArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeArtifactCopier) UploadBackupToDeploymentReturns(result1 error) {
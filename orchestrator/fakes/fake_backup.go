@@ -22,6 +22,17 @@ type FakeBackup struct {
 	addChecksumReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AddDeploymentMetadataStub        func(orchestrator.DeploymentMetadata) error
+	addDeploymentMetadataMutex       sync.RWMutex
+	addDeploymentMetadataArgsForCall []struct {
+		arg1 orchestrator.DeploymentMetadata
+	}
+	addDeploymentMetadataReturns struct {
+		result1 error
+	}
+	addDeploymentMetadataReturnsOnCall map[int]struct {
+		result1 error
+	}
 	AddFinishTimeStub        func(time.Time) error
 	addFinishTimeMutex       sync.RWMutex
 	addFinishTimeArgsForCall []struct {
@@ -33,6 +44,31 @@ type FakeBackup struct {
 	addFinishTimeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ArtifactDrainedStub        func(orchestrator.ArtifactIdentifier) (bool, error)
+	artifactDrainedMutex       sync.RWMutex
+	artifactDrainedArgsForCall []struct {
+		arg1 orchestrator.ArtifactIdentifier
+	}
+	artifactDrainedReturns struct {
+		result1 bool
+		result2 error
+	}
+	artifactDrainedReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	BackedUpInstancesStub        func() ([]orchestrator.InstanceMetadata, error)
+	backedUpInstancesMutex       sync.RWMutex
+	backedUpInstancesArgsForCall []struct {
+	}
+	backedUpInstancesReturns struct {
+		result1 []orchestrator.InstanceMetadata
+		result2 error
+	}
+	backedUpInstancesReturnsOnCall map[int]struct {
+		result1 []orchestrator.InstanceMetadata
+		result2 error
+	}
 	CalculateChecksumStub        func(orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error)
 	calculateChecksumMutex       sync.RWMutex
 	calculateChecksumArgsForCall []struct {
@@ -97,6 +133,19 @@ type FakeBackup struct {
 		result1 orchestrator.BackupChecksum
 		result2 error
 	}
+	FetchPreviousChecksumStub        func(orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error)
+	fetchPreviousChecksumMutex       sync.RWMutex
+	fetchPreviousChecksumArgsForCall []struct {
+		arg1 orchestrator.ArtifactIdentifier
+	}
+	fetchPreviousChecksumReturns struct {
+		result1 orchestrator.BackupChecksum
+		result2 error
+	}
+	fetchPreviousChecksumReturnsOnCall map[int]struct {
+		result1 orchestrator.BackupChecksum
+		result2 error
+	}
 	GetArtifactByteSizeStub        func(orchestrator.ArtifactIdentifier) (int, error)
 	getArtifactByteSizeMutex       sync.RWMutex
 	getArtifactByteSizeArgsForCall []struct {
@@ -123,6 +172,36 @@ type FakeBackup struct {
 		result1 string
 		result2 error
 	}
+	MarkCompleteStub        func() error
+	markCompleteMutex       sync.RWMutex
+	markCompleteArgsForCall []struct {
+	}
+	markCompleteReturns struct {
+		result1 error
+	}
+	markCompleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	MarkDrainFailedStub        func() error
+	markDrainFailedMutex       sync.RWMutex
+	markDrainFailedArgsForCall []struct {
+	}
+	markDrainFailedReturns struct {
+		result1 error
+	}
+	markDrainFailedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	MarkResumedStub        func() error
+	markResumedMutex       sync.RWMutex
+	markResumedArgsForCall []struct {
+	}
+	markResumedReturns struct {
+		result1 error
+	}
+	markResumedReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ReadArtifactStub        func(orchestrator.ArtifactIdentifier) (io.ReadCloser, error)
 	readArtifactMutex       sync.RWMutex
 	readArtifactArgsForCall []struct {
@@ -147,6 +226,16 @@ type FakeBackup struct {
 	saveManifestReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SignStub        func() error
+	signMutex       sync.RWMutex
+	signArgsForCall []struct {
+	}
+	signReturns struct {
+		result1 error
+	}
+	signReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ValidStub        func() (bool, error)
 	validMutex       sync.RWMutex
 	validArgsForCall []struct {
@@ -159,6 +248,16 @@ type FakeBackup struct {
 		result1 bool
 		result2 error
 	}
+	VerifySignatureStub        func() error
+	verifySignatureMutex       sync.RWMutex
+	verifySignatureArgsForCall []struct {
+	}
+	verifySignatureReturns struct {
+		result1 error
+	}
+	verifySignatureReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -224,6 +323,66 @@ func (fake *FakeBackup) AddChecksumReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBackup) AddDeploymentMetadata(arg1 orchestrator.DeploymentMetadata) error {
+	fake.addDeploymentMetadataMutex.Lock()
+	ret, specificReturn := fake.addDeploymentMetadataReturnsOnCall[len(fake.addDeploymentMetadataArgsForCall)]
+	fake.addDeploymentMetadataArgsForCall = append(fake.addDeploymentMetadataArgsForCall, struct {
+		arg1 orchestrator.DeploymentMetadata
+	}{arg1})
+	fake.recordInvocation("AddDeploymentMetadata", []interface{}{arg1})
+	fake.addDeploymentMetadataMutex.Unlock()
+	if fake.AddDeploymentMetadataStub != nil {
+		return fake.AddDeploymentMetadataStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addDeploymentMetadataReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackup) AddDeploymentMetadataCallCount() int {
+	fake.addDeploymentMetadataMutex.RLock()
+	defer fake.addDeploymentMetadataMutex.RUnlock()
+	return len(fake.addDeploymentMetadataArgsForCall)
+}
+
+func (fake *FakeBackup) AddDeploymentMetadataCalls(stub func(orchestrator.DeploymentMetadata) error) {
+	fake.addDeploymentMetadataMutex.Lock()
+	defer fake.addDeploymentMetadataMutex.Unlock()
+	fake.AddDeploymentMetadataStub = stub
+}
+
+func (fake *FakeBackup) AddDeploymentMetadataArgsForCall(i int) orchestrator.DeploymentMetadata {
+	fake.addDeploymentMetadataMutex.RLock()
+	defer fake.addDeploymentMetadataMutex.RUnlock()
+	argsForCall := fake.addDeploymentMetadataArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBackup) AddDeploymentMetadataReturns(result1 error) {
+	fake.addDeploymentMetadataMutex.Lock()
+	defer fake.addDeploymentMetadataMutex.Unlock()
+	fake.AddDeploymentMetadataStub = nil
+	fake.addDeploymentMetadataReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) AddDeploymentMetadataReturnsOnCall(i int, result1 error) {
+	fake.addDeploymentMetadataMutex.Lock()
+	defer fake.addDeploymentMetadataMutex.Unlock()
+	fake.AddDeploymentMetadataStub = nil
+	if fake.addDeploymentMetadataReturnsOnCall == nil {
+		fake.addDeploymentMetadataReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addDeploymentMetadataReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBackup) AddFinishTime(arg1 time.Time) error {
 	fake.addFinishTimeMutex.Lock()
 	ret, specificReturn := fake.addFinishTimeReturnsOnCall[len(fake.addFinishTimeArgsForCall)]
@@ -284,6 +443,124 @@ func (fake *FakeBackup) AddFinishTimeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBackup) ArtifactDrained(arg1 orchestrator.ArtifactIdentifier) (bool, error) {
+	fake.artifactDrainedMutex.Lock()
+	ret, specificReturn := fake.artifactDrainedReturnsOnCall[len(fake.artifactDrainedArgsForCall)]
+	fake.artifactDrainedArgsForCall = append(fake.artifactDrainedArgsForCall, struct {
+		arg1 orchestrator.ArtifactIdentifier
+	}{arg1})
+	fake.recordInvocation("ArtifactDrained", []interface{}{arg1})
+	fake.artifactDrainedMutex.Unlock()
+	if fake.ArtifactDrainedStub != nil {
+		return fake.ArtifactDrainedStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.artifactDrainedReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBackup) ArtifactDrainedCallCount() int {
+	fake.artifactDrainedMutex.RLock()
+	defer fake.artifactDrainedMutex.RUnlock()
+	return len(fake.artifactDrainedArgsForCall)
+}
+
+func (fake *FakeBackup) ArtifactDrainedCalls(stub func(orchestrator.ArtifactIdentifier) (bool, error)) {
+	fake.artifactDrainedMutex.Lock()
+	defer fake.artifactDrainedMutex.Unlock()
+	fake.ArtifactDrainedStub = stub
+}
+
+func (fake *FakeBackup) ArtifactDrainedArgsForCall(i int) orchestrator.ArtifactIdentifier {
+	fake.artifactDrainedMutex.RLock()
+	defer fake.artifactDrainedMutex.RUnlock()
+	argsForCall := fake.artifactDrainedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBackup) ArtifactDrainedReturns(result1 bool, result2 error) {
+	fake.artifactDrainedMutex.Lock()
+	defer fake.artifactDrainedMutex.Unlock()
+	fake.ArtifactDrainedStub = nil
+	fake.artifactDrainedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBackup) ArtifactDrainedReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.artifactDrainedMutex.Lock()
+	defer fake.artifactDrainedMutex.Unlock()
+	fake.ArtifactDrainedStub = nil
+	if fake.artifactDrainedReturnsOnCall == nil {
+		fake.artifactDrainedReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.artifactDrainedReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBackup) BackedUpInstances() ([]orchestrator.InstanceMetadata, error) {
+	fake.backedUpInstancesMutex.Lock()
+	ret, specificReturn := fake.backedUpInstancesReturnsOnCall[len(fake.backedUpInstancesArgsForCall)]
+	fake.backedUpInstancesArgsForCall = append(fake.backedUpInstancesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("BackedUpInstances", []interface{}{})
+	fake.backedUpInstancesMutex.Unlock()
+	if fake.BackedUpInstancesStub != nil {
+		return fake.BackedUpInstancesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.backedUpInstancesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBackup) BackedUpInstancesCallCount() int {
+	fake.backedUpInstancesMutex.RLock()
+	defer fake.backedUpInstancesMutex.RUnlock()
+	return len(fake.backedUpInstancesArgsForCall)
+}
+
+func (fake *FakeBackup) BackedUpInstancesCalls(stub func() ([]orchestrator.InstanceMetadata, error)) {
+	fake.backedUpInstancesMutex.Lock()
+	defer fake.backedUpInstancesMutex.Unlock()
+	fake.BackedUpInstancesStub = stub
+}
+
+func (fake *FakeBackup) BackedUpInstancesReturns(result1 []orchestrator.InstanceMetadata, result2 error) {
+	fake.backedUpInstancesMutex.Lock()
+	defer fake.backedUpInstancesMutex.Unlock()
+	fake.BackedUpInstancesStub = nil
+	fake.backedUpInstancesReturns = struct {
+		result1 []orchestrator.InstanceMetadata
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBackup) BackedUpInstancesReturnsOnCall(i int, result1 []orchestrator.InstanceMetadata, result2 error) {
+	fake.backedUpInstancesMutex.Lock()
+	defer fake.backedUpInstancesMutex.Unlock()
+	fake.BackedUpInstancesStub = nil
+	if fake.backedUpInstancesReturnsOnCall == nil {
+		fake.backedUpInstancesReturnsOnCall = make(map[int]struct {
+			result1 []orchestrator.InstanceMetadata
+			result2 error
+		})
+	}
+	fake.backedUpInstancesReturnsOnCall[i] = struct {
+		result1 []orchestrator.InstanceMetadata
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBackup) CalculateChecksum(arg1 orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
 	fake.calculateChecksumMutex.Lock()
 	ret, specificReturn := fake.calculateChecksumReturnsOnCall[len(fake.calculateChecksumArgsForCall)]
@@ -602,6 +879,69 @@ func (fake *FakeBackup) FetchChecksumReturnsOnCall(i int, result1 orchestrator.B
 	}{result1, result2}
 }
 
+func (fake *FakeBackup) FetchPreviousChecksum(arg1 orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error) {
+	fake.fetchPreviousChecksumMutex.Lock()
+	ret, specificReturn := fake.fetchPreviousChecksumReturnsOnCall[len(fake.fetchPreviousChecksumArgsForCall)]
+	fake.fetchPreviousChecksumArgsForCall = append(fake.fetchPreviousChecksumArgsForCall, struct {
+		arg1 orchestrator.ArtifactIdentifier
+	}{arg1})
+	fake.recordInvocation("FetchPreviousChecksum", []interface{}{arg1})
+	fake.fetchPreviousChecksumMutex.Unlock()
+	if fake.FetchPreviousChecksumStub != nil {
+		return fake.FetchPreviousChecksumStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.fetchPreviousChecksumReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBackup) FetchPreviousChecksumCallCount() int {
+	fake.fetchPreviousChecksumMutex.RLock()
+	defer fake.fetchPreviousChecksumMutex.RUnlock()
+	return len(fake.fetchPreviousChecksumArgsForCall)
+}
+
+func (fake *FakeBackup) FetchPreviousChecksumCalls(stub func(orchestrator.ArtifactIdentifier) (orchestrator.BackupChecksum, error)) {
+	fake.fetchPreviousChecksumMutex.Lock()
+	defer fake.fetchPreviousChecksumMutex.Unlock()
+	fake.FetchPreviousChecksumStub = stub
+}
+
+func (fake *FakeBackup) FetchPreviousChecksumArgsForCall(i int) orchestrator.ArtifactIdentifier {
+	fake.fetchPreviousChecksumMutex.RLock()
+	defer fake.fetchPreviousChecksumMutex.RUnlock()
+	argsForCall := fake.fetchPreviousChecksumArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBackup) FetchPreviousChecksumReturns(result1 orchestrator.BackupChecksum, result2 error) {
+	fake.fetchPreviousChecksumMutex.Lock()
+	defer fake.fetchPreviousChecksumMutex.Unlock()
+	fake.FetchPreviousChecksumStub = nil
+	fake.fetchPreviousChecksumReturns = struct {
+		result1 orchestrator.BackupChecksum
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBackup) FetchPreviousChecksumReturnsOnCall(i int, result1 orchestrator.BackupChecksum, result2 error) {
+	fake.fetchPreviousChecksumMutex.Lock()
+	defer fake.fetchPreviousChecksumMutex.Unlock()
+	fake.FetchPreviousChecksumStub = nil
+	if fake.fetchPreviousChecksumReturnsOnCall == nil {
+		fake.fetchPreviousChecksumReturnsOnCall = make(map[int]struct {
+			result1 orchestrator.BackupChecksum
+			result2 error
+		})
+	}
+	fake.fetchPreviousChecksumReturnsOnCall[i] = struct {
+		result1 orchestrator.BackupChecksum
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBackup) GetArtifactByteSize(arg1 orchestrator.ArtifactIdentifier) (int, error) {
 	fake.getArtifactByteSizeMutex.Lock()
 	ret, specificReturn := fake.getArtifactByteSizeReturnsOnCall[len(fake.getArtifactByteSizeArgsForCall)]
@@ -728,6 +1068,162 @@ func (fake *FakeBackup) GetArtifactSizeReturnsOnCall(i int, result1 string, resu
 	}{result1, result2}
 }
 
+func (fake *FakeBackup) MarkComplete() error {
+	fake.markCompleteMutex.Lock()
+	ret, specificReturn := fake.markCompleteReturnsOnCall[len(fake.markCompleteArgsForCall)]
+	fake.markCompleteArgsForCall = append(fake.markCompleteArgsForCall, struct {
+	}{})
+	fake.recordInvocation("MarkComplete", []interface{}{})
+	fake.markCompleteMutex.Unlock()
+	if fake.MarkCompleteStub != nil {
+		return fake.MarkCompleteStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.markCompleteReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackup) MarkCompleteCallCount() int {
+	fake.markCompleteMutex.RLock()
+	defer fake.markCompleteMutex.RUnlock()
+	return len(fake.markCompleteArgsForCall)
+}
+
+func (fake *FakeBackup) MarkCompleteCalls(stub func() error) {
+	fake.markCompleteMutex.Lock()
+	defer fake.markCompleteMutex.Unlock()
+	fake.MarkCompleteStub = stub
+}
+
+func (fake *FakeBackup) MarkCompleteReturns(result1 error) {
+	fake.markCompleteMutex.Lock()
+	defer fake.markCompleteMutex.Unlock()
+	fake.MarkCompleteStub = nil
+	fake.markCompleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) MarkCompleteReturnsOnCall(i int, result1 error) {
+	fake.markCompleteMutex.Lock()
+	defer fake.markCompleteMutex.Unlock()
+	fake.MarkCompleteStub = nil
+	if fake.markCompleteReturnsOnCall == nil {
+		fake.markCompleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.markCompleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) MarkDrainFailed() error {
+	fake.markDrainFailedMutex.Lock()
+	ret, specificReturn := fake.markDrainFailedReturnsOnCall[len(fake.markDrainFailedArgsForCall)]
+	fake.markDrainFailedArgsForCall = append(fake.markDrainFailedArgsForCall, struct {
+	}{})
+	fake.recordInvocation("MarkDrainFailed", []interface{}{})
+	fake.markDrainFailedMutex.Unlock()
+	if fake.MarkDrainFailedStub != nil {
+		return fake.MarkDrainFailedStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.markDrainFailedReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackup) MarkDrainFailedCallCount() int {
+	fake.markDrainFailedMutex.RLock()
+	defer fake.markDrainFailedMutex.RUnlock()
+	return len(fake.markDrainFailedArgsForCall)
+}
+
+func (fake *FakeBackup) MarkDrainFailedCalls(stub func() error) {
+	fake.markDrainFailedMutex.Lock()
+	defer fake.markDrainFailedMutex.Unlock()
+	fake.MarkDrainFailedStub = stub
+}
+
+func (fake *FakeBackup) MarkDrainFailedReturns(result1 error) {
+	fake.markDrainFailedMutex.Lock()
+	defer fake.markDrainFailedMutex.Unlock()
+	fake.MarkDrainFailedStub = nil
+	fake.markDrainFailedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) MarkDrainFailedReturnsOnCall(i int, result1 error) {
+	fake.markDrainFailedMutex.Lock()
+	defer fake.markDrainFailedMutex.Unlock()
+	fake.MarkDrainFailedStub = nil
+	if fake.markDrainFailedReturnsOnCall == nil {
+		fake.markDrainFailedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.markDrainFailedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) MarkResumed() error {
+	fake.markResumedMutex.Lock()
+	ret, specificReturn := fake.markResumedReturnsOnCall[len(fake.markResumedArgsForCall)]
+	fake.markResumedArgsForCall = append(fake.markResumedArgsForCall, struct {
+	}{})
+	fake.recordInvocation("MarkResumed", []interface{}{})
+	fake.markResumedMutex.Unlock()
+	if fake.MarkResumedStub != nil {
+		return fake.MarkResumedStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.markResumedReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackup) MarkResumedCallCount() int {
+	fake.markResumedMutex.RLock()
+	defer fake.markResumedMutex.RUnlock()
+	return len(fake.markResumedArgsForCall)
+}
+
+func (fake *FakeBackup) MarkResumedCalls(stub func() error) {
+	fake.markResumedMutex.Lock()
+	defer fake.markResumedMutex.Unlock()
+	fake.MarkResumedStub = stub
+}
+
+func (fake *FakeBackup) MarkResumedReturns(result1 error) {
+	fake.markResumedMutex.Lock()
+	defer fake.markResumedMutex.Unlock()
+	fake.MarkResumedStub = nil
+	fake.markResumedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) MarkResumedReturnsOnCall(i int, result1 error) {
+	fake.markResumedMutex.Lock()
+	defer fake.markResumedMutex.Unlock()
+	fake.MarkResumedStub = nil
+	if fake.markResumedReturnsOnCall == nil {
+		fake.markResumedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.markResumedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBackup) ReadArtifact(arg1 orchestrator.ArtifactIdentifier) (io.ReadCloser, error) {
 	fake.readArtifactMutex.Lock()
 	ret, specificReturn := fake.readArtifactReturnsOnCall[len(fake.readArtifactArgsForCall)]
@@ -851,6 +1347,58 @@ func (fake *FakeBackup) SaveManifestReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBackup) Sign() error {
+	fake.signMutex.Lock()
+	ret, specificReturn := fake.signReturnsOnCall[len(fake.signArgsForCall)]
+	fake.signArgsForCall = append(fake.signArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Sign", []interface{}{})
+	fake.signMutex.Unlock()
+	if fake.SignStub != nil {
+		return fake.SignStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.signReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackup) SignCallCount() int {
+	fake.signMutex.RLock()
+	defer fake.signMutex.RUnlock()
+	return len(fake.signArgsForCall)
+}
+
+func (fake *FakeBackup) SignCalls(stub func() error) {
+	fake.signMutex.Lock()
+	defer fake.signMutex.Unlock()
+	fake.SignStub = stub
+}
+
+func (fake *FakeBackup) SignReturns(result1 error) {
+	fake.signMutex.Lock()
+	defer fake.signMutex.Unlock()
+	fake.SignStub = nil
+	fake.signReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) SignReturnsOnCall(i int, result1 error) {
+	fake.signMutex.Lock()
+	defer fake.signMutex.Unlock()
+	fake.SignStub = nil
+	if fake.signReturnsOnCall == nil {
+		fake.signReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.signReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBackup) Valid() (bool, error) {
 	fake.validMutex.Lock()
 	ret, specificReturn := fake.validReturnsOnCall[len(fake.validArgsForCall)]
@@ -906,13 +1454,71 @@ func (fake *FakeBackup) ValidReturnsOnCall(i int, result1 bool, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *FakeBackup) VerifySignature() error {
+	fake.verifySignatureMutex.Lock()
+	ret, specificReturn := fake.verifySignatureReturnsOnCall[len(fake.verifySignatureArgsForCall)]
+	fake.verifySignatureArgsForCall = append(fake.verifySignatureArgsForCall, struct {
+	}{})
+	fake.recordInvocation("VerifySignature", []interface{}{})
+	fake.verifySignatureMutex.Unlock()
+	if fake.VerifySignatureStub != nil {
+		return fake.VerifySignatureStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.verifySignatureReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackup) VerifySignatureCallCount() int {
+	fake.verifySignatureMutex.RLock()
+	defer fake.verifySignatureMutex.RUnlock()
+	return len(fake.verifySignatureArgsForCall)
+}
+
+func (fake *FakeBackup) VerifySignatureCalls(stub func() error) {
+	fake.verifySignatureMutex.Lock()
+	defer fake.verifySignatureMutex.Unlock()
+	fake.VerifySignatureStub = stub
+}
+
+func (fake *FakeBackup) VerifySignatureReturns(result1 error) {
+	fake.verifySignatureMutex.Lock()
+	defer fake.verifySignatureMutex.Unlock()
+	fake.VerifySignatureStub = nil
+	fake.verifySignatureReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackup) VerifySignatureReturnsOnCall(i int, result1 error) {
+	fake.verifySignatureMutex.Lock()
+	defer fake.verifySignatureMutex.Unlock()
+	fake.VerifySignatureStub = nil
+	if fake.verifySignatureReturnsOnCall == nil {
+		fake.verifySignatureReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.verifySignatureReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBackup) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.addChecksumMutex.RLock()
 	defer fake.addChecksumMutex.RUnlock()
+	fake.addDeploymentMetadataMutex.RLock()
+	defer fake.addDeploymentMetadataMutex.RUnlock()
 	fake.addFinishTimeMutex.RLock()
 	defer fake.addFinishTimeMutex.RUnlock()
+	fake.artifactDrainedMutex.RLock()
+	defer fake.artifactDrainedMutex.RUnlock()
+	fake.backedUpInstancesMutex.RLock()
+	defer fake.backedUpInstancesMutex.RUnlock()
 	fake.calculateChecksumMutex.RLock()
 	defer fake.calculateChecksumMutex.RUnlock()
 	fake.createArtifactMutex.RLock()
@@ -923,16 +1529,28 @@ func (fake *FakeBackup) Invocations() map[string][][]interface{} {
 	defer fake.deploymentMatchesMutex.RUnlock()
 	fake.fetchChecksumMutex.RLock()
 	defer fake.fetchChecksumMutex.RUnlock()
+	fake.fetchPreviousChecksumMutex.RLock()
+	defer fake.fetchPreviousChecksumMutex.RUnlock()
 	fake.getArtifactByteSizeMutex.RLock()
 	defer fake.getArtifactByteSizeMutex.RUnlock()
 	fake.getArtifactSizeMutex.RLock()
 	defer fake.getArtifactSizeMutex.RUnlock()
+	fake.markCompleteMutex.RLock()
+	defer fake.markCompleteMutex.RUnlock()
+	fake.markDrainFailedMutex.RLock()
+	defer fake.markDrainFailedMutex.RUnlock()
+	fake.markResumedMutex.RLock()
+	defer fake.markResumedMutex.RUnlock()
 	fake.readArtifactMutex.RLock()
 	defer fake.readArtifactMutex.RUnlock()
 	fake.saveManifestMutex.RLock()
 	defer fake.saveManifestMutex.RUnlock()
+	fake.signMutex.RLock()
+	defer fake.signMutex.RUnlock()
 	fake.validMutex.RLock()
 	defer fake.validMutex.RUnlock()
+	fake.verifySignatureMutex.RLock()
+	defer fake.verifySignatureMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"io"
 	"sync"
 
@@ -9,9 +10,10 @@ import (
 )
 
 type FakeBackupArtifact struct {
-	ChecksumStub        func() (orchestrator.BackupChecksum, error)
+	ChecksumStub        func(context.Context) (orchestrator.BackupChecksum, error)
 	checksumMutex       sync.RWMutex
 	checksumArgsForCall []struct {
+		arg1 context.Context
 	}
 	checksumReturns struct {
 		result1 orchestrator.BackupChecksum
@@ -81,9 +83,10 @@ type FakeBackupArtifact struct {
 	nameReturnsOnCall map[int]struct {
 		result1 string
 	}
-	SizeStub        func() (string, error)
+	SizeStub        func(context.Context) (string, error)
 	sizeMutex       sync.RWMutex
 	sizeArgsForCall []struct {
+		arg1 context.Context
 	}
 	sizeReturns struct {
 		result1 string
@@ -93,9 +96,10 @@ type FakeBackupArtifact struct {
 		result1 string
 		result2 error
 	}
-	SizeInBytesStub        func() (int, error)
+	SizeInBytesStub        func(context.Context) (int, error)
 	sizeInBytesMutex       sync.RWMutex
 	sizeInBytesArgsForCall []struct {
+		arg1 context.Context
 	}
 	sizeInBytesReturns struct {
 		result1 int
@@ -105,10 +109,24 @@ type FakeBackupArtifact struct {
 		result1 int
 		result2 error
 	}
-	StreamFromRemoteStub        func(io.Writer) error
+	StreamFilesFromRemoteStub        func(context.Context, []string, io.Writer) error
+	streamFilesFromRemoteMutex       sync.RWMutex
+	streamFilesFromRemoteArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 io.Writer
+	}
+	streamFilesFromRemoteReturns struct {
+		result1 error
+	}
+	streamFilesFromRemoteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StreamFromRemoteStub        func(context.Context, io.Writer) error
 	streamFromRemoteMutex       sync.RWMutex
 	streamFromRemoteArgsForCall []struct {
-		arg1 io.Writer
+		arg1 context.Context
+		arg2 io.Writer
 	}
 	streamFromRemoteReturns struct {
 		result1 error
@@ -116,10 +134,11 @@ type FakeBackupArtifact struct {
 	streamFromRemoteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	StreamToRemoteStub        func(io.Reader) error
+	StreamToRemoteStub        func(context.Context, io.Reader) error
 	streamToRemoteMutex       sync.RWMutex
 	streamToRemoteArgsForCall []struct {
-		arg1 io.Reader
+		arg1 context.Context
+		arg2 io.Reader
 	}
 	streamToRemoteReturns struct {
 		result1 error
@@ -127,19 +146,31 @@ type FakeBackupArtifact struct {
 	streamToRemoteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UploadPreviousChecksumsStub        func(orchestrator.BackupChecksum) error
+	uploadPreviousChecksumsMutex       sync.RWMutex
+	uploadPreviousChecksumsArgsForCall []struct {
+		arg1 orchestrator.BackupChecksum
+	}
+	uploadPreviousChecksumsReturns struct {
+		result1 error
+	}
+	uploadPreviousChecksumsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBackupArtifact) Checksum() (orchestrator.BackupChecksum, error) {
+func (fake *FakeBackupArtifact) Checksum(arg1 context.Context) (orchestrator.BackupChecksum, error) {
 	fake.checksumMutex.Lock()
 	ret, specificReturn := fake.checksumReturnsOnCall[len(fake.checksumArgsForCall)]
 	fake.checksumArgsForCall = append(fake.checksumArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Checksum", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Checksum", []interface{}{arg1})
 	fake.checksumMutex.Unlock()
 	if fake.ChecksumStub != nil {
-		return fake.ChecksumStub()
+		return fake.ChecksumStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -154,12 +185,19 @@ func (fake *FakeBackupArtifact) ChecksumCallCount() int {
 	return len(fake.checksumArgsForCall)
 }
 
-func (fake *FakeBackupArtifact) ChecksumCalls(stub func() (orchestrator.BackupChecksum, error)) {
+func (fake *FakeBackupArtifact) ChecksumCalls(stub func(context.Context) (orchestrator.BackupChecksum, error)) {
 	fake.checksumMutex.Lock()
 	defer fake.checksumMutex.Unlock()
 	fake.ChecksumStub = stub
 }
 
+func (fake *FakeBackupArtifact) ChecksumArgsForCall(i int) context.Context {
+	fake.checksumMutex.RLock()
+	defer fake.checksumMutex.RUnlock()
+	argsForCall := fake.checksumArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeBackupArtifact) ChecksumReturns(result1 orchestrator.BackupChecksum, result2 error) {
 	fake.checksumMutex.Lock()
 	defer fake.checksumMutex.Unlock()
@@ -498,15 +536,16 @@ func (fake *FakeBackupArtifact) NameReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
-func (fake *FakeBackupArtifact) Size() (string, error) {
+func (fake *FakeBackupArtifact) Size(arg1 context.Context) (string, error) {
 	fake.sizeMutex.Lock()
 	ret, specificReturn := fake.sizeReturnsOnCall[len(fake.sizeArgsForCall)]
 	fake.sizeArgsForCall = append(fake.sizeArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Size", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Size", []interface{}{arg1})
 	fake.sizeMutex.Unlock()
 	if fake.SizeStub != nil {
-		return fake.SizeStub()
+		return fake.SizeStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -521,12 +560,19 @@ func (fake *FakeBackupArtifact) SizeCallCount() int {
 	return len(fake.sizeArgsForCall)
 }
 
-func (fake *FakeBackupArtifact) SizeCalls(stub func() (string, error)) {
+func (fake *FakeBackupArtifact) SizeCalls(stub func(context.Context) (string, error)) {
 	fake.sizeMutex.Lock()
 	defer fake.sizeMutex.Unlock()
 	fake.SizeStub = stub
 }
 
+func (fake *FakeBackupArtifact) SizeArgsForCall(i int) context.Context {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	argsForCall := fake.sizeArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeBackupArtifact) SizeReturns(result1 string, result2 error) {
 	fake.sizeMutex.Lock()
 	defer fake.sizeMutex.Unlock()
@@ -553,15 +599,16 @@ func (fake *FakeBackupArtifact) SizeReturnsOnCall(i int, result1 string, result2
 	}{result1, result2}
 }
 
-func (fake *FakeBackupArtifact) SizeInBytes() (int, error) {
+func (fake *FakeBackupArtifact) SizeInBytes(arg1 context.Context) (int, error) {
 	fake.sizeInBytesMutex.Lock()
 	ret, specificReturn := fake.sizeInBytesReturnsOnCall[len(fake.sizeInBytesArgsForCall)]
 	fake.sizeInBytesArgsForCall = append(fake.sizeInBytesArgsForCall, struct {
-	}{})
-	fake.recordInvocation("SizeInBytes", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("SizeInBytes", []interface{}{arg1})
 	fake.sizeInBytesMutex.Unlock()
 	if fake.SizeInBytesStub != nil {
-		return fake.SizeInBytesStub()
+		return fake.SizeInBytesStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -576,12 +623,19 @@ func (fake *FakeBackupArtifact) SizeInBytesCallCount() int {
 	return len(fake.sizeInBytesArgsForCall)
 }
 
-func (fake *FakeBackupArtifact) SizeInBytesCalls(stub func() (int, error)) {
+func (fake *FakeBackupArtifact) SizeInBytesCalls(stub func(context.Context) (int, error)) {
 	fake.sizeInBytesMutex.Lock()
 	defer fake.sizeInBytesMutex.Unlock()
 	fake.SizeInBytesStub = stub
 }
 
+func (fake *FakeBackupArtifact) SizeInBytesArgsForCall(i int) context.Context {
+	fake.sizeInBytesMutex.RLock()
+	defer fake.sizeInBytesMutex.RUnlock()
+	argsForCall := fake.sizeInBytesArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeBackupArtifact) SizeInBytesReturns(result1 int, result2 error) {
 	fake.sizeInBytesMutex.Lock()
 	defer fake.sizeInBytesMutex.Unlock()
@@ -608,16 +662,84 @@ func (fake *FakeBackupArtifact) SizeInBytesReturnsOnCall(i int, result1 int, res
 	}{result1, result2}
 }
 
-func (fake *FakeBackupArtifact) StreamFromRemote(arg1 io.Writer) error {
+func (fake *FakeBackupArtifact) StreamFilesFromRemote(arg1 context.Context, arg2 []string, arg3 io.Writer) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.streamFilesFromRemoteMutex.Lock()
+	ret, specificReturn := fake.streamFilesFromRemoteReturnsOnCall[len(fake.streamFilesFromRemoteArgsForCall)]
+	fake.streamFilesFromRemoteArgsForCall = append(fake.streamFilesFromRemoteArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 io.Writer
+	}{arg1, arg2Copy, arg3})
+	fake.recordInvocation("StreamFilesFromRemote", []interface{}{arg1, arg2Copy, arg3})
+	fake.streamFilesFromRemoteMutex.Unlock()
+	if fake.StreamFilesFromRemoteStub != nil {
+		return fake.StreamFilesFromRemoteStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.streamFilesFromRemoteReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackupArtifact) StreamFilesFromRemoteCallCount() int {
+	fake.streamFilesFromRemoteMutex.RLock()
+	defer fake.streamFilesFromRemoteMutex.RUnlock()
+	return len(fake.streamFilesFromRemoteArgsForCall)
+}
+
+func (fake *FakeBackupArtifact) StreamFilesFromRemoteCalls(stub func(context.Context, []string, io.Writer) error) {
+	fake.streamFilesFromRemoteMutex.Lock()
+	defer fake.streamFilesFromRemoteMutex.Unlock()
+	fake.StreamFilesFromRemoteStub = stub
+}
+
+func (fake *FakeBackupArtifact) StreamFilesFromRemoteArgsForCall(i int) (context.Context, []string, io.Writer) {
+	fake.streamFilesFromRemoteMutex.RLock()
+	defer fake.streamFilesFromRemoteMutex.RUnlock()
+	argsForCall := fake.streamFilesFromRemoteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBackupArtifact) StreamFilesFromRemoteReturns(result1 error) {
+	fake.streamFilesFromRemoteMutex.Lock()
+	defer fake.streamFilesFromRemoteMutex.Unlock()
+	fake.StreamFilesFromRemoteStub = nil
+	fake.streamFilesFromRemoteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackupArtifact) StreamFilesFromRemoteReturnsOnCall(i int, result1 error) {
+	fake.streamFilesFromRemoteMutex.Lock()
+	defer fake.streamFilesFromRemoteMutex.Unlock()
+	fake.StreamFilesFromRemoteStub = nil
+	if fake.streamFilesFromRemoteReturnsOnCall == nil {
+		fake.streamFilesFromRemoteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamFilesFromRemoteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackupArtifact) StreamFromRemote(arg1 context.Context, arg2 io.Writer) error {
 	fake.streamFromRemoteMutex.Lock()
 	ret, specificReturn := fake.streamFromRemoteReturnsOnCall[len(fake.streamFromRemoteArgsForCall)]
 	fake.streamFromRemoteArgsForCall = append(fake.streamFromRemoteArgsForCall, struct {
-		arg1 io.Writer
-	}{arg1})
-	fake.recordInvocation("StreamFromRemote", []interface{}{arg1})
+		arg1 context.Context
+		arg2 io.Writer
+	}{arg1, arg2})
+	fake.recordInvocation("StreamFromRemote", []interface{}{arg1, arg2})
 	fake.streamFromRemoteMutex.Unlock()
 	if fake.StreamFromRemoteStub != nil {
-		return fake.StreamFromRemoteStub(arg1)
+		return fake.StreamFromRemoteStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -632,17 +754,17 @@ func (fake *FakeBackupArtifact) StreamFromRemoteCallCount() int {
 	return len(fake.streamFromRemoteArgsForCall)
 }
 
-func (fake *FakeBackupArtifact) StreamFromRemoteCalls(stub func(io.Writer) error) {
+func (fake *FakeBackupArtifact) StreamFromRemoteCalls(stub func(context.Context, io.Writer) error) {
 	fake.streamFromRemoteMutex.Lock()
 	defer fake.streamFromRemoteMutex.Unlock()
 	fake.StreamFromRemoteStub = stub
 }
 
-func (fake *FakeBackupArtifact) StreamFromRemoteArgsForCall(i int) io.Writer {
+func (fake *FakeBackupArtifact) StreamFromRemoteArgsForCall(i int) (context.Context, io.Writer) {
 	fake.streamFromRemoteMutex.RLock()
 	defer fake.streamFromRemoteMutex.RUnlock()
 	argsForCall := fake.streamFromRemoteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBackupArtifact) StreamFromRemoteReturns(result1 error) {
@@ -668,16 +790,17 @@ func (fake *FakeBackupArtifact) StreamFromRemoteReturnsOnCall(i int, result1 err
 	}{result1}
 }
 
-func (fake *FakeBackupArtifact) StreamToRemote(arg1 io.Reader) error {
+func (fake *FakeBackupArtifact) StreamToRemote(arg1 context.Context, arg2 io.Reader) error {
 	fake.streamToRemoteMutex.Lock()
 	ret, specificReturn := fake.streamToRemoteReturnsOnCall[len(fake.streamToRemoteArgsForCall)]
 	fake.streamToRemoteArgsForCall = append(fake.streamToRemoteArgsForCall, struct {
-		arg1 io.Reader
-	}{arg1})
-	fake.recordInvocation("StreamToRemote", []interface{}{arg1})
+		arg1 context.Context
+		arg2 io.Reader
+	}{arg1, arg2})
+	fake.recordInvocation("StreamToRemote", []interface{}{arg1, arg2})
 	fake.streamToRemoteMutex.Unlock()
 	if fake.StreamToRemoteStub != nil {
-		return fake.StreamToRemoteStub(arg1)
+		return fake.StreamToRemoteStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -692,17 +815,17 @@ func (fake *FakeBackupArtifact) StreamToRemoteCallCount() int {
 	return len(fake.streamToRemoteArgsForCall)
 }
 
-func (fake *FakeBackupArtifact) StreamToRemoteCalls(stub func(io.Reader) error) {
+func (fake *FakeBackupArtifact) StreamToRemoteCalls(stub func(context.Context, io.Reader) error) {
 	fake.streamToRemoteMutex.Lock()
 	defer fake.streamToRemoteMutex.Unlock()
 	fake.StreamToRemoteStub = stub
 }
 
-func (fake *FakeBackupArtifact) StreamToRemoteArgsForCall(i int) io.Reader {
+func (fake *FakeBackupArtifact) StreamToRemoteArgsForCall(i int) (context.Context, io.Reader) {
 	fake.streamToRemoteMutex.RLock()
 	defer fake.streamToRemoteMutex.RUnlock()
 	argsForCall := fake.streamToRemoteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBackupArtifact) StreamToRemoteReturns(result1 error) {
@@ -728,6 +851,66 @@ func (fake *FakeBackupArtifact) StreamToRemoteReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
+func (fake *FakeBackupArtifact) UploadPreviousChecksums(arg1 orchestrator.BackupChecksum) error {
+	fake.uploadPreviousChecksumsMutex.Lock()
+	ret, specificReturn := fake.uploadPreviousChecksumsReturnsOnCall[len(fake.uploadPreviousChecksumsArgsForCall)]
+	fake.uploadPreviousChecksumsArgsForCall = append(fake.uploadPreviousChecksumsArgsForCall, struct {
+		arg1 orchestrator.BackupChecksum
+	}{arg1})
+	fake.recordInvocation("UploadPreviousChecksums", []interface{}{arg1})
+	fake.uploadPreviousChecksumsMutex.Unlock()
+	if fake.UploadPreviousChecksumsStub != nil {
+		return fake.UploadPreviousChecksumsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.uploadPreviousChecksumsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeBackupArtifact) UploadPreviousChecksumsCallCount() int {
+	fake.uploadPreviousChecksumsMutex.RLock()
+	defer fake.uploadPreviousChecksumsMutex.RUnlock()
+	return len(fake.uploadPreviousChecksumsArgsForCall)
+}
+
+func (fake *FakeBackupArtifact) UploadPreviousChecksumsCalls(stub func(orchestrator.BackupChecksum) error) {
+	fake.uploadPreviousChecksumsMutex.Lock()
+	defer fake.uploadPreviousChecksumsMutex.Unlock()
+	fake.UploadPreviousChecksumsStub = stub
+}
+
+func (fake *FakeBackupArtifact) UploadPreviousChecksumsArgsForCall(i int) orchestrator.BackupChecksum {
+	fake.uploadPreviousChecksumsMutex.RLock()
+	defer fake.uploadPreviousChecksumsMutex.RUnlock()
+	argsForCall := fake.uploadPreviousChecksumsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBackupArtifact) UploadPreviousChecksumsReturns(result1 error) {
+	fake.uploadPreviousChecksumsMutex.Lock()
+	defer fake.uploadPreviousChecksumsMutex.Unlock()
+	fake.UploadPreviousChecksumsStub = nil
+	fake.uploadPreviousChecksumsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBackupArtifact) UploadPreviousChecksumsReturnsOnCall(i int, result1 error) {
+	fake.uploadPreviousChecksumsMutex.Lock()
+	defer fake.uploadPreviousChecksumsMutex.Unlock()
+	fake.UploadPreviousChecksumsStub = nil
+	if fake.uploadPreviousChecksumsReturnsOnCall == nil {
+		fake.uploadPreviousChecksumsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.uploadPreviousChecksumsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBackupArtifact) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -749,10 +932,14 @@ func (fake *FakeBackupArtifact) Invocations() map[string][][]interface{} {
 	defer fake.sizeMutex.RUnlock()
 	fake.sizeInBytesMutex.RLock()
 	defer fake.sizeInBytesMutex.RUnlock()
+	fake.streamFilesFromRemoteMutex.RLock()
+	defer fake.streamFilesFromRemoteMutex.RUnlock()
 	fake.streamFromRemoteMutex.RLock()
 	defer fake.streamFromRemoteMutex.RUnlock()
 	fake.streamToRemoteMutex.RLock()
 	defer fake.streamToRemoteMutex.RUnlock()
+	fake.uploadPreviousChecksumsMutex.RLock()
+	defer fake.uploadPreviousChecksumsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
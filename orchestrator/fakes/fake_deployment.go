@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
@@ -9,10 +10,11 @@ import (
 )
 
 type FakeDeployment struct {
-	BackupStub        func(executor.Executor) error
+	BackupStub        func(context.Context, executor.Executor) error
 	backupMutex       sync.RWMutex
 	backupArgsForCall []struct {
-		arg1 executor.Executor
+		arg1 context.Context
+		arg2 executor.Executor
 	}
 	backupReturns struct {
 		result1 error
@@ -30,9 +32,10 @@ type FakeDeployment struct {
 	backupableInstancesReturnsOnCall map[int]struct {
 		result1 []orchestrator.Instance
 	}
-	CheckArtifactDirStub        func() error
+	CheckArtifactDirStub        func(context.Context) error
 	checkArtifactDirMutex       sync.RWMutex
 	checkArtifactDirArgsForCall []struct {
+		arg1 context.Context
 	}
 	checkArtifactDirReturns struct {
 		result1 error
@@ -90,12 +93,13 @@ type FakeDeployment struct {
 	isRestorableReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	PostBackupUnlockStub        func(bool, orchestrator.LockOrderer, executor.Executor) error
+	PostBackupUnlockStub        func(context.Context, bool, orchestrator.LockOrderer, executor.Executor) error
 	postBackupUnlockMutex       sync.RWMutex
 	postBackupUnlockArgsForCall []struct {
-		arg1 bool
-		arg2 orchestrator.LockOrderer
-		arg3 executor.Executor
+		arg1 context.Context
+		arg2 bool
+		arg3 orchestrator.LockOrderer
+		arg4 executor.Executor
 	}
 	postBackupUnlockReturns struct {
 		result1 error
@@ -103,11 +107,12 @@ type FakeDeployment struct {
 	postBackupUnlockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PostRestoreUnlockStub        func(orchestrator.LockOrderer, executor.Executor) error
+	PostRestoreUnlockStub        func(context.Context, orchestrator.LockOrderer, executor.Executor) error
 	postRestoreUnlockMutex       sync.RWMutex
 	postRestoreUnlockArgsForCall []struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
 	}
 	postRestoreUnlockReturns struct {
 		result1 error
@@ -115,11 +120,24 @@ type FakeDeployment struct {
 	postRestoreUnlockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PreBackupLockStub        func(orchestrator.LockOrderer, executor.Executor) error
+	PostRestoreValidateStub        func(context.Context, executor.Executor) error
+	postRestoreValidateMutex       sync.RWMutex
+	postRestoreValidateArgsForCall []struct {
+		arg1 context.Context
+		arg2 executor.Executor
+	}
+	postRestoreValidateReturns struct {
+		result1 error
+	}
+	postRestoreValidateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PreBackupLockStub        func(context.Context, orchestrator.LockOrderer, executor.Executor) error
 	preBackupLockMutex       sync.RWMutex
 	preBackupLockArgsForCall []struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
 	}
 	preBackupLockReturns struct {
 		result1 error
@@ -127,11 +145,12 @@ type FakeDeployment struct {
 	preBackupLockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PreRestoreLockStub        func(orchestrator.LockOrderer, executor.Executor) error
+	PreRestoreLockStub        func(context.Context, orchestrator.LockOrderer, executor.Executor) error
 	preRestoreLockMutex       sync.RWMutex
 	preRestoreLockArgsForCall []struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
 	}
 	preRestoreLockReturns struct {
 		result1 error
@@ -149,9 +168,10 @@ type FakeDeployment struct {
 	restorableInstancesReturnsOnCall map[int]struct {
 		result1 []orchestrator.Instance
 	}
-	RestoreStub        func() error
+	RestoreStub        func(context.Context) error
 	restoreMutex       sync.RWMutex
 	restoreArgsForCall []struct {
+		arg1 context.Context
 	}
 	restoreReturns struct {
 		result1 error
@@ -174,16 +194,17 @@ type FakeDeployment struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeDeployment) Backup(arg1 executor.Executor) error {
+func (fake *FakeDeployment) Backup(arg1 context.Context, arg2 executor.Executor) error {
 	fake.backupMutex.Lock()
 	ret, specificReturn := fake.backupReturnsOnCall[len(fake.backupArgsForCall)]
 	fake.backupArgsForCall = append(fake.backupArgsForCall, struct {
-		arg1 executor.Executor
-	}{arg1})
-	fake.recordInvocation("Backup", []interface{}{arg1})
+		arg1 context.Context
+		arg2 executor.Executor
+	}{arg1, arg2})
+	fake.recordInvocation("Backup", []interface{}{arg1, arg2})
 	fake.backupMutex.Unlock()
 	if fake.BackupStub != nil {
-		return fake.BackupStub(arg1)
+		return fake.BackupStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -198,17 +219,17 @@ func (fake *FakeDeployment) BackupCallCount() int {
 	return len(fake.backupArgsForCall)
 }
 
-func (fake *FakeDeployment) BackupCalls(stub func(executor.Executor) error) {
+func (fake *FakeDeployment) BackupCalls(stub func(context.Context, executor.Executor) error) {
 	fake.backupMutex.Lock()
 	defer fake.backupMutex.Unlock()
 	fake.BackupStub = stub
 }
 
-func (fake *FakeDeployment) BackupArgsForCall(i int) executor.Executor {
+func (fake *FakeDeployment) BackupArgsForCall(i int) (context.Context, executor.Executor) {
 	fake.backupMutex.RLock()
 	defer fake.backupMutex.RUnlock()
 	argsForCall := fake.backupArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeDeployment) BackupReturns(result1 error) {
@@ -286,15 +307,16 @@ func (fake *FakeDeployment) BackupableInstancesReturnsOnCall(i int, result1 []or
 	}{result1}
 }
 
-func (fake *FakeDeployment) CheckArtifactDir() error {
+func (fake *FakeDeployment) CheckArtifactDir(arg1 context.Context) error {
 	fake.checkArtifactDirMutex.Lock()
 	ret, specificReturn := fake.checkArtifactDirReturnsOnCall[len(fake.checkArtifactDirArgsForCall)]
 	fake.checkArtifactDirArgsForCall = append(fake.checkArtifactDirArgsForCall, struct {
-	}{})
-	fake.recordInvocation("CheckArtifactDir", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("CheckArtifactDir", []interface{}{arg1})
 	fake.checkArtifactDirMutex.Unlock()
 	if fake.CheckArtifactDirStub != nil {
-		return fake.CheckArtifactDirStub()
+		return fake.CheckArtifactDirStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -309,12 +331,19 @@ func (fake *FakeDeployment) CheckArtifactDirCallCount() int {
 	return len(fake.checkArtifactDirArgsForCall)
 }
 
-func (fake *FakeDeployment) CheckArtifactDirCalls(stub func() error) {
+func (fake *FakeDeployment) CheckArtifactDirCalls(stub func(context.Context) error) {
 	fake.checkArtifactDirMutex.Lock()
 	defer fake.checkArtifactDirMutex.Unlock()
 	fake.CheckArtifactDirStub = stub
 }
 
+func (fake *FakeDeployment) CheckArtifactDirArgsForCall(i int) context.Context {
+	fake.checkArtifactDirMutex.RLock()
+	defer fake.checkArtifactDirMutex.RUnlock()
+	argsForCall := fake.checkArtifactDirArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeDeployment) CheckArtifactDirReturns(result1 error) {
 	fake.checkArtifactDirMutex.Lock()
 	defer fake.checkArtifactDirMutex.Unlock()
@@ -598,18 +627,19 @@ func (fake *FakeDeployment) IsRestorableReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
-func (fake *FakeDeployment) PostBackupUnlock(arg1 bool, arg2 orchestrator.LockOrderer, arg3 executor.Executor) error {
+func (fake *FakeDeployment) PostBackupUnlock(arg1 context.Context, arg2 bool, arg3 orchestrator.LockOrderer, arg4 executor.Executor) error {
 	fake.postBackupUnlockMutex.Lock()
 	ret, specificReturn := fake.postBackupUnlockReturnsOnCall[len(fake.postBackupUnlockArgsForCall)]
 	fake.postBackupUnlockArgsForCall = append(fake.postBackupUnlockArgsForCall, struct {
-		arg1 bool
-		arg2 orchestrator.LockOrderer
-		arg3 executor.Executor
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("PostBackupUnlock", []interface{}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 bool
+		arg3 orchestrator.LockOrderer
+		arg4 executor.Executor
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("PostBackupUnlock", []interface{}{arg1, arg2, arg3, arg4})
 	fake.postBackupUnlockMutex.Unlock()
 	if fake.PostBackupUnlockStub != nil {
-		return fake.PostBackupUnlockStub(arg1, arg2, arg3)
+		return fake.PostBackupUnlockStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -624,17 +654,17 @@ func (fake *FakeDeployment) PostBackupUnlockCallCount() int {
 	return len(fake.postBackupUnlockArgsForCall)
 }
 
-func (fake *FakeDeployment) PostBackupUnlockCalls(stub func(bool, orchestrator.LockOrderer, executor.Executor) error) {
+func (fake *FakeDeployment) PostBackupUnlockCalls(stub func(context.Context, bool, orchestrator.LockOrderer, executor.Executor) error) {
 	fake.postBackupUnlockMutex.Lock()
 	defer fake.postBackupUnlockMutex.Unlock()
 	fake.PostBackupUnlockStub = stub
 }
 
-func (fake *FakeDeployment) PostBackupUnlockArgsForCall(i int) (bool, orchestrator.LockOrderer, executor.Executor) {
+func (fake *FakeDeployment) PostBackupUnlockArgsForCall(i int) (context.Context, bool, orchestrator.LockOrderer, executor.Executor) {
 	fake.postBackupUnlockMutex.RLock()
 	defer fake.postBackupUnlockMutex.RUnlock()
 	argsForCall := fake.postBackupUnlockArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeDeployment) PostBackupUnlockReturns(result1 error) {
@@ -660,17 +690,18 @@ func (fake *FakeDeployment) PostBackupUnlockReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeDeployment) PostRestoreUnlock(arg1 orchestrator.LockOrderer, arg2 executor.Executor) error {
+func (fake *FakeDeployment) PostRestoreUnlock(arg1 context.Context, arg2 orchestrator.LockOrderer, arg3 executor.Executor) error {
 	fake.postRestoreUnlockMutex.Lock()
 	ret, specificReturn := fake.postRestoreUnlockReturnsOnCall[len(fake.postRestoreUnlockArgsForCall)]
 	fake.postRestoreUnlockArgsForCall = append(fake.postRestoreUnlockArgsForCall, struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
-	}{arg1, arg2})
-	fake.recordInvocation("PostRestoreUnlock", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("PostRestoreUnlock", []interface{}{arg1, arg2, arg3})
 	fake.postRestoreUnlockMutex.Unlock()
 	if fake.PostRestoreUnlockStub != nil {
-		return fake.PostRestoreUnlockStub(arg1, arg2)
+		return fake.PostRestoreUnlockStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -685,17 +716,17 @@ func (fake *FakeDeployment) PostRestoreUnlockCallCount() int {
 	return len(fake.postRestoreUnlockArgsForCall)
 }
 
-func (fake *FakeDeployment) PostRestoreUnlockCalls(stub func(orchestrator.LockOrderer, executor.Executor) error) {
+func (fake *FakeDeployment) PostRestoreUnlockCalls(stub func(context.Context, orchestrator.LockOrderer, executor.Executor) error) {
 	fake.postRestoreUnlockMutex.Lock()
 	defer fake.postRestoreUnlockMutex.Unlock()
 	fake.PostRestoreUnlockStub = stub
 }
 
-func (fake *FakeDeployment) PostRestoreUnlockArgsForCall(i int) (orchestrator.LockOrderer, executor.Executor) {
+func (fake *FakeDeployment) PostRestoreUnlockArgsForCall(i int) (context.Context, orchestrator.LockOrderer, executor.Executor) {
 	fake.postRestoreUnlockMutex.RLock()
 	defer fake.postRestoreUnlockMutex.RUnlock()
 	argsForCall := fake.postRestoreUnlockArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeDeployment) PostRestoreUnlockReturns(result1 error) {
@@ -721,17 +752,79 @@ func (fake *FakeDeployment) PostRestoreUnlockReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeDeployment) PreBackupLock(arg1 orchestrator.LockOrderer, arg2 executor.Executor) error {
+func (fake *FakeDeployment) PostRestoreValidate(arg1 context.Context, arg2 executor.Executor) error {
+	fake.postRestoreValidateMutex.Lock()
+	ret, specificReturn := fake.postRestoreValidateReturnsOnCall[len(fake.postRestoreValidateArgsForCall)]
+	fake.postRestoreValidateArgsForCall = append(fake.postRestoreValidateArgsForCall, struct {
+		arg1 context.Context
+		arg2 executor.Executor
+	}{arg1, arg2})
+	fake.recordInvocation("PostRestoreValidate", []interface{}{arg1, arg2})
+	fake.postRestoreValidateMutex.Unlock()
+	if fake.PostRestoreValidateStub != nil {
+		return fake.PostRestoreValidateStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.postRestoreValidateReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeDeployment) PostRestoreValidateCallCount() int {
+	fake.postRestoreValidateMutex.RLock()
+	defer fake.postRestoreValidateMutex.RUnlock()
+	return len(fake.postRestoreValidateArgsForCall)
+}
+
+func (fake *FakeDeployment) PostRestoreValidateCalls(stub func(context.Context, executor.Executor) error) {
+	fake.postRestoreValidateMutex.Lock()
+	defer fake.postRestoreValidateMutex.Unlock()
+	fake.PostRestoreValidateStub = stub
+}
+
+func (fake *FakeDeployment) PostRestoreValidateArgsForCall(i int) (context.Context, executor.Executor) {
+	fake.postRestoreValidateMutex.RLock()
+	defer fake.postRestoreValidateMutex.RUnlock()
+	argsForCall := fake.postRestoreValidateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDeployment) PostRestoreValidateReturns(result1 error) {
+	fake.postRestoreValidateMutex.Lock()
+	defer fake.postRestoreValidateMutex.Unlock()
+	fake.PostRestoreValidateStub = nil
+	fake.postRestoreValidateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployment) PostRestoreValidateReturnsOnCall(i int, result1 error) {
+	fake.postRestoreValidateMutex.Lock()
+	defer fake.postRestoreValidateMutex.Unlock()
+	fake.PostRestoreValidateStub = nil
+	if fake.postRestoreValidateReturnsOnCall == nil {
+		fake.postRestoreValidateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.postRestoreValidateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployment) PreBackupLock(arg1 context.Context, arg2 orchestrator.LockOrderer, arg3 executor.Executor) error {
 	fake.preBackupLockMutex.Lock()
 	ret, specificReturn := fake.preBackupLockReturnsOnCall[len(fake.preBackupLockArgsForCall)]
 	fake.preBackupLockArgsForCall = append(fake.preBackupLockArgsForCall, struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
-	}{arg1, arg2})
-	fake.recordInvocation("PreBackupLock", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("PreBackupLock", []interface{}{arg1, arg2, arg3})
 	fake.preBackupLockMutex.Unlock()
 	if fake.PreBackupLockStub != nil {
-		return fake.PreBackupLockStub(arg1, arg2)
+		return fake.PreBackupLockStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -746,17 +839,17 @@ func (fake *FakeDeployment) PreBackupLockCallCount() int {
 	return len(fake.preBackupLockArgsForCall)
 }
 
-func (fake *FakeDeployment) PreBackupLockCalls(stub func(orchestrator.LockOrderer, executor.Executor) error) {
+func (fake *FakeDeployment) PreBackupLockCalls(stub func(context.Context, orchestrator.LockOrderer, executor.Executor) error) {
 	fake.preBackupLockMutex.Lock()
 	defer fake.preBackupLockMutex.Unlock()
 	fake.PreBackupLockStub = stub
 }
 
-func (fake *FakeDeployment) PreBackupLockArgsForCall(i int) (orchestrator.LockOrderer, executor.Executor) {
+func (fake *FakeDeployment) PreBackupLockArgsForCall(i int) (context.Context, orchestrator.LockOrderer, executor.Executor) {
 	fake.preBackupLockMutex.RLock()
 	defer fake.preBackupLockMutex.RUnlock()
 	argsForCall := fake.preBackupLockArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeDeployment) PreBackupLockReturns(result1 error) {
@@ -782,17 +875,18 @@ func (fake *FakeDeployment) PreBackupLockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeDeployment) PreRestoreLock(arg1 orchestrator.LockOrderer, arg2 executor.Executor) error {
+func (fake *FakeDeployment) PreRestoreLock(arg1 context.Context, arg2 orchestrator.LockOrderer, arg3 executor.Executor) error {
 	fake.preRestoreLockMutex.Lock()
 	ret, specificReturn := fake.preRestoreLockReturnsOnCall[len(fake.preRestoreLockArgsForCall)]
 	fake.preRestoreLockArgsForCall = append(fake.preRestoreLockArgsForCall, struct {
-		arg1 orchestrator.LockOrderer
-		arg2 executor.Executor
-	}{arg1, arg2})
-	fake.recordInvocation("PreRestoreLock", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 orchestrator.LockOrderer
+		arg3 executor.Executor
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("PreRestoreLock", []interface{}{arg1, arg2, arg3})
 	fake.preRestoreLockMutex.Unlock()
 	if fake.PreRestoreLockStub != nil {
-		return fake.PreRestoreLockStub(arg1, arg2)
+		return fake.PreRestoreLockStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -807,17 +901,17 @@ func (fake *FakeDeployment) PreRestoreLockCallCount() int {
 	return len(fake.preRestoreLockArgsForCall)
 }
 
-func (fake *FakeDeployment) PreRestoreLockCalls(stub func(orchestrator.LockOrderer, executor.Executor) error) {
+func (fake *FakeDeployment) PreRestoreLockCalls(stub func(context.Context, orchestrator.LockOrderer, executor.Executor) error) {
 	fake.preRestoreLockMutex.Lock()
 	defer fake.preRestoreLockMutex.Unlock()
 	fake.PreRestoreLockStub = stub
 }
 
-func (fake *FakeDeployment) PreRestoreLockArgsForCall(i int) (orchestrator.LockOrderer, executor.Executor) {
+func (fake *FakeDeployment) PreRestoreLockArgsForCall(i int) (context.Context, orchestrator.LockOrderer, executor.Executor) {
 	fake.preRestoreLockMutex.RLock()
 	defer fake.preRestoreLockMutex.RUnlock()
 	argsForCall := fake.preRestoreLockArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeDeployment) PreRestoreLockReturns(result1 error) {
@@ -895,15 +989,16 @@ func (fake *FakeDeployment) RestorableInstancesReturnsOnCall(i int, result1 []or
 	}{result1}
 }
 
-func (fake *FakeDeployment) Restore() error {
+func (fake *FakeDeployment) Restore(arg1 context.Context) error {
 	fake.restoreMutex.Lock()
 	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
 	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Restore", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Restore", []interface{}{arg1})
 	fake.restoreMutex.Unlock()
 	if fake.RestoreStub != nil {
-		return fake.RestoreStub()
+		return fake.RestoreStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -918,12 +1013,19 @@ func (fake *FakeDeployment) RestoreCallCount() int {
 	return len(fake.restoreArgsForCall)
 }
 
-func (fake *FakeDeployment) RestoreCalls(stub func() error) {
+func (fake *FakeDeployment) RestoreCalls(stub func(context.Context) error) {
 	fake.restoreMutex.Lock()
 	defer fake.restoreMutex.Unlock()
 	fake.RestoreStub = stub
 }
 
+func (fake *FakeDeployment) RestoreArgsForCall(i int) context.Context {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeDeployment) RestoreReturns(result1 error) {
 	fake.restoreMutex.Lock()
 	defer fake.restoreMutex.Unlock()
@@ -1030,6 +1132,8 @@ func (fake *FakeDeployment) Invocations() map[string][][]interface{} {
 	defer fake.postBackupUnlockMutex.RUnlock()
 	fake.postRestoreUnlockMutex.RLock()
 	defer fake.postRestoreUnlockMutex.RUnlock()
+	fake.postRestoreValidateMutex.RLock()
+	defer fake.postRestoreValidateMutex.RUnlock()
 	fake.preBackupLockMutex.RLock()
 	defer fake.preBackupLockMutex.RUnlock()
 	fake.preRestoreLockMutex.RLock()
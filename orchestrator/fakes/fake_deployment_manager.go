@@ -2,16 +2,30 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 )
 
 type FakeDeploymentManager struct {
-	FindStub        func(string) (orchestrator.Deployment, error)
+	DirectorInfoStub        func() (orchestrator.DirectorInfo, error)
+	directorInfoMutex       sync.RWMutex
+	directorInfoArgsForCall []struct {
+	}
+	directorInfoReturns struct {
+		result1 orchestrator.DirectorInfo
+		result2 error
+	}
+	directorInfoReturnsOnCall map[int]struct {
+		result1 orchestrator.DirectorInfo
+		result2 error
+	}
+	FindStub        func(context.Context, string) (orchestrator.Deployment, error)
 	findMutex       sync.RWMutex
 	findArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	findReturns struct {
 		result1 orchestrator.Deployment
@@ -37,16 +51,72 @@ type FakeDeploymentManager struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeDeploymentManager) Find(arg1 string) (orchestrator.Deployment, error) {
+func (fake *FakeDeploymentManager) DirectorInfo() (orchestrator.DirectorInfo, error) {
+	fake.directorInfoMutex.Lock()
+	ret, specificReturn := fake.directorInfoReturnsOnCall[len(fake.directorInfoArgsForCall)]
+	fake.directorInfoArgsForCall = append(fake.directorInfoArgsForCall, struct {
+	}{})
+	fake.recordInvocation("DirectorInfo", []interface{}{})
+	fake.directorInfoMutex.Unlock()
+	if fake.DirectorInfoStub != nil {
+		return fake.DirectorInfoStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.directorInfoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDeploymentManager) DirectorInfoCallCount() int {
+	fake.directorInfoMutex.RLock()
+	defer fake.directorInfoMutex.RUnlock()
+	return len(fake.directorInfoArgsForCall)
+}
+
+func (fake *FakeDeploymentManager) DirectorInfoCalls(stub func() (orchestrator.DirectorInfo, error)) {
+	fake.directorInfoMutex.Lock()
+	defer fake.directorInfoMutex.Unlock()
+	fake.DirectorInfoStub = stub
+}
+
+func (fake *FakeDeploymentManager) DirectorInfoReturns(result1 orchestrator.DirectorInfo, result2 error) {
+	fake.directorInfoMutex.Lock()
+	defer fake.directorInfoMutex.Unlock()
+	fake.DirectorInfoStub = nil
+	fake.directorInfoReturns = struct {
+		result1 orchestrator.DirectorInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeploymentManager) DirectorInfoReturnsOnCall(i int, result1 orchestrator.DirectorInfo, result2 error) {
+	fake.directorInfoMutex.Lock()
+	defer fake.directorInfoMutex.Unlock()
+	fake.DirectorInfoStub = nil
+	if fake.directorInfoReturnsOnCall == nil {
+		fake.directorInfoReturnsOnCall = make(map[int]struct {
+			result1 orchestrator.DirectorInfo
+			result2 error
+		})
+	}
+	fake.directorInfoReturnsOnCall[i] = struct {
+		result1 orchestrator.DirectorInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeploymentManager) Find(arg1 context.Context, arg2 string) (orchestrator.Deployment, error) {
 	fake.findMutex.Lock()
 	ret, specificReturn := fake.findReturnsOnCall[len(fake.findArgsForCall)]
 	fake.findArgsForCall = append(fake.findArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("Find", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("Find", []interface{}{arg1, arg2})
 	fake.findMutex.Unlock()
 	if fake.FindStub != nil {
-		return fake.FindStub(arg1)
+		return fake.FindStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -61,17 +131,17 @@ func (fake *FakeDeploymentManager) FindCallCount() int {
 	return len(fake.findArgsForCall)
 }
 
-func (fake *FakeDeploymentManager) FindCalls(stub func(string) (orchestrator.Deployment, error)) {
+func (fake *FakeDeploymentManager) FindCalls(stub func(context.Context, string) (orchestrator.Deployment, error)) {
 	fake.findMutex.Lock()
 	defer fake.findMutex.Unlock()
 	fake.FindStub = stub
 }
 
-func (fake *FakeDeploymentManager) FindArgsForCall(i int) string {
+func (fake *FakeDeploymentManager) FindArgsForCall(i int) (context.Context, string) {
 	fake.findMutex.RLock()
 	defer fake.findMutex.RUnlock()
 	argsForCall := fake.findArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeDeploymentManager) FindReturns(result1 orchestrator.Deployment, result2 error) {
@@ -164,6 +234,8 @@ func (fake *FakeDeploymentManager) SaveManifestReturnsOnCall(i int, result1 erro
 func (fake *FakeDeploymentManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.directorInfoMutex.RLock()
+	defer fake.directorInfoMutex.RUnlock()
 	fake.findMutex.RLock()
 	defer fake.findMutex.RUnlock()
 	fake.saveManifestMutex.RLock()
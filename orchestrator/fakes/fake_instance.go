@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -18,9 +19,10 @@ type FakeInstance struct {
 	artifactDirCreatedReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	ArtifactDirExistsStub        func() (bool, error)
+	ArtifactDirExistsStub        func(context.Context) (bool, error)
 	artifactDirExistsMutex       sync.RWMutex
 	artifactDirExistsArgsForCall []struct {
+		arg1 context.Context
 	}
 	artifactDirExistsReturns struct {
 		result1 bool
@@ -50,9 +52,10 @@ type FakeInstance struct {
 	artifactsToRestoreReturnsOnCall map[int]struct {
 		result1 []orchestrator.BackupArtifact
 	}
-	BackupStub        func() error
+	BackupStub        func(context.Context) error
 	backupMutex       sync.RWMutex
 	backupArgsForCall []struct {
+		arg1 context.Context
 	}
 	backupReturns struct {
 		result1 error
@@ -120,6 +123,16 @@ type FakeInstance struct {
 	isBackupableReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsBootstrapStub        func() bool
+	isBootstrapMutex       sync.RWMutex
+	isBootstrapArgsForCall []struct {
+	}
+	isBootstrapReturns struct {
+		result1 bool
+	}
+	isBootstrapReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsRestorableStub        func() bool
 	isRestorableMutex       sync.RWMutex
 	isRestorableArgsForCall []struct {
@@ -154,9 +167,10 @@ type FakeInstance struct {
 	nameReturnsOnCall map[int]struct {
 		result1 string
 	}
-	RestoreStub        func() error
+	RestoreStub        func(context.Context) error
 	restoreMutex       sync.RWMutex
 	restoreArgsForCall []struct {
+		arg1 context.Context
 	}
 	restoreReturns struct {
 		result1 error
@@ -164,6 +178,10 @@ type FakeInstance struct {
 	restoreReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RetainArtifactDirStub        func()
+	retainArtifactDirMutex       sync.RWMutex
+	retainArtifactDirArgsForCall []struct {
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -220,15 +238,16 @@ func (fake *FakeInstance) ArtifactDirCreatedReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
-func (fake *FakeInstance) ArtifactDirExists() (bool, error) {
+func (fake *FakeInstance) ArtifactDirExists(arg1 context.Context) (bool, error) {
 	fake.artifactDirExistsMutex.Lock()
 	ret, specificReturn := fake.artifactDirExistsReturnsOnCall[len(fake.artifactDirExistsArgsForCall)]
 	fake.artifactDirExistsArgsForCall = append(fake.artifactDirExistsArgsForCall, struct {
-	}{})
-	fake.recordInvocation("ArtifactDirExists", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("ArtifactDirExists", []interface{}{arg1})
 	fake.artifactDirExistsMutex.Unlock()
 	if fake.ArtifactDirExistsStub != nil {
-		return fake.ArtifactDirExistsStub()
+		return fake.ArtifactDirExistsStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -243,12 +262,19 @@ func (fake *FakeInstance) ArtifactDirExistsCallCount() int {
 	return len(fake.artifactDirExistsArgsForCall)
 }
 
-func (fake *FakeInstance) ArtifactDirExistsCalls(stub func() (bool, error)) {
+func (fake *FakeInstance) ArtifactDirExistsCalls(stub func(context.Context) (bool, error)) {
 	fake.artifactDirExistsMutex.Lock()
 	defer fake.artifactDirExistsMutex.Unlock()
 	fake.ArtifactDirExistsStub = stub
 }
 
+func (fake *FakeInstance) ArtifactDirExistsArgsForCall(i int) context.Context {
+	fake.artifactDirExistsMutex.RLock()
+	defer fake.artifactDirExistsMutex.RUnlock()
+	argsForCall := fake.artifactDirExistsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeInstance) ArtifactDirExistsReturns(result1 bool, result2 error) {
 	fake.artifactDirExistsMutex.Lock()
 	defer fake.artifactDirExistsMutex.Unlock()
@@ -379,15 +405,16 @@ func (fake *FakeInstance) ArtifactsToRestoreReturnsOnCall(i int, result1 []orche
 	}{result1}
 }
 
-func (fake *FakeInstance) Backup() error {
+func (fake *FakeInstance) Backup(arg1 context.Context) error {
 	fake.backupMutex.Lock()
 	ret, specificReturn := fake.backupReturnsOnCall[len(fake.backupArgsForCall)]
 	fake.backupArgsForCall = append(fake.backupArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Backup", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Backup", []interface{}{arg1})
 	fake.backupMutex.Unlock()
 	if fake.BackupStub != nil {
-		return fake.BackupStub()
+		return fake.BackupStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -402,12 +429,19 @@ func (fake *FakeInstance) BackupCallCount() int {
 	return len(fake.backupArgsForCall)
 }
 
-func (fake *FakeInstance) BackupCalls(stub func() error) {
+func (fake *FakeInstance) BackupCalls(stub func(context.Context) error) {
 	fake.backupMutex.Lock()
 	defer fake.backupMutex.Unlock()
 	fake.BackupStub = stub
 }
 
+func (fake *FakeInstance) BackupArgsForCall(i int) context.Context {
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	argsForCall := fake.backupArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeInstance) BackupReturns(result1 error) {
 	fake.backupMutex.Lock()
 	defer fake.backupMutex.Unlock()
@@ -743,6 +777,58 @@ func (fake *FakeInstance) IsBackupableReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeInstance) IsBootstrap() bool {
+	fake.isBootstrapMutex.Lock()
+	ret, specificReturn := fake.isBootstrapReturnsOnCall[len(fake.isBootstrapArgsForCall)]
+	fake.isBootstrapArgsForCall = append(fake.isBootstrapArgsForCall, struct {
+	}{})
+	fake.recordInvocation("IsBootstrap", []interface{}{})
+	fake.isBootstrapMutex.Unlock()
+	if fake.IsBootstrapStub != nil {
+		return fake.IsBootstrapStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isBootstrapReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeInstance) IsBootstrapCallCount() int {
+	fake.isBootstrapMutex.RLock()
+	defer fake.isBootstrapMutex.RUnlock()
+	return len(fake.isBootstrapArgsForCall)
+}
+
+func (fake *FakeInstance) IsBootstrapCalls(stub func() bool) {
+	fake.isBootstrapMutex.Lock()
+	defer fake.isBootstrapMutex.Unlock()
+	fake.IsBootstrapStub = stub
+}
+
+func (fake *FakeInstance) IsBootstrapReturns(result1 bool) {
+	fake.isBootstrapMutex.Lock()
+	defer fake.isBootstrapMutex.Unlock()
+	fake.IsBootstrapStub = nil
+	fake.isBootstrapReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeInstance) IsBootstrapReturnsOnCall(i int, result1 bool) {
+	fake.isBootstrapMutex.Lock()
+	defer fake.isBootstrapMutex.Unlock()
+	fake.IsBootstrapStub = nil
+	if fake.isBootstrapReturnsOnCall == nil {
+		fake.isBootstrapReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isBootstrapReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeInstance) IsRestorable() bool {
 	fake.isRestorableMutex.Lock()
 	ret, specificReturn := fake.isRestorableReturnsOnCall[len(fake.isRestorableArgsForCall)]
@@ -922,15 +1008,16 @@ func (fake *FakeInstance) NameReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
-func (fake *FakeInstance) Restore() error {
+func (fake *FakeInstance) Restore(arg1 context.Context) error {
 	fake.restoreMutex.Lock()
 	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
 	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Restore", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Restore", []interface{}{arg1})
 	fake.restoreMutex.Unlock()
 	if fake.RestoreStub != nil {
-		return fake.RestoreStub()
+		return fake.RestoreStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -945,12 +1032,19 @@ func (fake *FakeInstance) RestoreCallCount() int {
 	return len(fake.restoreArgsForCall)
 }
 
-func (fake *FakeInstance) RestoreCalls(stub func() error) {
+func (fake *FakeInstance) RestoreCalls(stub func(context.Context) error) {
 	fake.restoreMutex.Lock()
 	defer fake.restoreMutex.Unlock()
 	fake.RestoreStub = stub
 }
 
+func (fake *FakeInstance) RestoreArgsForCall(i int) context.Context {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeInstance) RestoreReturns(result1 error) {
 	fake.restoreMutex.Lock()
 	defer fake.restoreMutex.Unlock()
@@ -974,6 +1068,29 @@ func (fake *FakeInstance) RestoreReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeInstance) RetainArtifactDir() {
+	fake.retainArtifactDirMutex.Lock()
+	fake.retainArtifactDirArgsForCall = append(fake.retainArtifactDirArgsForCall, struct {
+	}{})
+	fake.recordInvocation("RetainArtifactDir", []interface{}{})
+	fake.retainArtifactDirMutex.Unlock()
+	if fake.RetainArtifactDirStub != nil {
+		fake.RetainArtifactDirStub()
+	}
+}
+
+func (fake *FakeInstance) RetainArtifactDirCallCount() int {
+	fake.retainArtifactDirMutex.RLock()
+	defer fake.retainArtifactDirMutex.RUnlock()
+	return len(fake.retainArtifactDirArgsForCall)
+}
+
+func (fake *FakeInstance) RetainArtifactDirCalls(stub func()) {
+	fake.retainArtifactDirMutex.Lock()
+	defer fake.retainArtifactDirMutex.Unlock()
+	fake.RetainArtifactDirStub = stub
+}
+
 func (fake *FakeInstance) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -999,6 +1116,8 @@ func (fake *FakeInstance) Invocations() map[string][][]interface{} {
 	defer fake.indexMutex.RUnlock()
 	fake.isBackupableMutex.RLock()
 	defer fake.isBackupableMutex.RUnlock()
+	fake.isBootstrapMutex.RLock()
+	defer fake.isBootstrapMutex.RUnlock()
 	fake.isRestorableMutex.RLock()
 	defer fake.isRestorableMutex.RUnlock()
 	fake.jobsMutex.RLock()
@@ -1009,6 +1128,8 @@ func (fake *FakeInstance) Invocations() map[string][][]interface{} {
 	defer fake.nameMutex.RUnlock()
 	fake.restoreMutex.RLock()
 	defer fake.restoreMutex.RUnlock()
+	fake.retainArtifactDirMutex.RLock()
+	defer fake.retainArtifactDirMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
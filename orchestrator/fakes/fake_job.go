@@ -2,15 +2,17 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 )
 
 type FakeJob struct {
-	BackupStub        func() error
+	BackupStub        func(context.Context) error
 	backupMutex       sync.RWMutex
 	backupArgsForCall []struct {
+		arg1 context.Context
 	}
 	backupReturns struct {
 		result1 error
@@ -118,10 +120,11 @@ type FakeJob struct {
 	nameReturnsOnCall map[int]struct {
 		result1 string
 	}
-	PostBackupUnlockStub        func(bool) error
+	PostBackupUnlockStub        func(context.Context, bool) error
 	postBackupUnlockMutex       sync.RWMutex
 	postBackupUnlockArgsForCall []struct {
-		arg1 bool
+		arg1 context.Context
+		arg2 bool
 	}
 	postBackupUnlockReturns struct {
 		result1 error
@@ -129,9 +132,10 @@ type FakeJob struct {
 	postBackupUnlockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PostRestoreUnlockStub        func() error
+	PostRestoreUnlockStub        func(context.Context) error
 	postRestoreUnlockMutex       sync.RWMutex
 	postRestoreUnlockArgsForCall []struct {
+		arg1 context.Context
 	}
 	postRestoreUnlockReturns struct {
 		result1 error
@@ -139,9 +143,21 @@ type FakeJob struct {
 	postRestoreUnlockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PreBackupLockStub        func() error
+	PostRestoreValidateStub        func(context.Context) error
+	postRestoreValidateMutex       sync.RWMutex
+	postRestoreValidateArgsForCall []struct {
+		arg1 context.Context
+	}
+	postRestoreValidateReturns struct {
+		result1 error
+	}
+	postRestoreValidateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PreBackupLockStub        func(context.Context) error
 	preBackupLockMutex       sync.RWMutex
 	preBackupLockArgsForCall []struct {
+		arg1 context.Context
 	}
 	preBackupLockReturns struct {
 		result1 error
@@ -149,9 +165,10 @@ type FakeJob struct {
 	preBackupLockReturnsOnCall map[int]struct {
 		result1 error
 	}
-	PreRestoreLockStub        func() error
+	PreRestoreLockStub        func(context.Context) error
 	preRestoreLockMutex       sync.RWMutex
 	preRestoreLockArgsForCall []struct {
+		arg1 context.Context
 	}
 	preRestoreLockReturns struct {
 		result1 error
@@ -169,9 +186,10 @@ type FakeJob struct {
 	releaseReturnsOnCall map[int]struct {
 		result1 string
 	}
-	RestoreStub        func() error
+	RestoreStub        func(context.Context) error
 	restoreMutex       sync.RWMutex
 	restoreArgsForCall []struct {
+		arg1 context.Context
 	}
 	restoreReturns struct {
 		result1 error
@@ -213,15 +231,16 @@ type FakeJob struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeJob) Backup() error {
+func (fake *FakeJob) Backup(arg1 context.Context) error {
 	fake.backupMutex.Lock()
 	ret, specificReturn := fake.backupReturnsOnCall[len(fake.backupArgsForCall)]
 	fake.backupArgsForCall = append(fake.backupArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Backup", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Backup", []interface{}{arg1})
 	fake.backupMutex.Unlock()
 	if fake.BackupStub != nil {
-		return fake.BackupStub()
+		return fake.BackupStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -236,12 +255,19 @@ func (fake *FakeJob) BackupCallCount() int {
 	return len(fake.backupArgsForCall)
 }
 
-func (fake *FakeJob) BackupCalls(stub func() error) {
+func (fake *FakeJob) BackupCalls(stub func(context.Context) error) {
 	fake.backupMutex.Lock()
 	defer fake.backupMutex.Unlock()
 	fake.BackupStub = stub
 }
 
+func (fake *FakeJob) BackupArgsForCall(i int) context.Context {
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	argsForCall := fake.backupArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeJob) BackupReturns(result1 error) {
 	fake.backupMutex.Lock()
 	defer fake.backupMutex.Unlock()
@@ -785,16 +811,17 @@ func (fake *FakeJob) NameReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
-func (fake *FakeJob) PostBackupUnlock(arg1 bool) error {
+func (fake *FakeJob) PostBackupUnlock(arg1 context.Context, arg2 bool) error {
 	fake.postBackupUnlockMutex.Lock()
 	ret, specificReturn := fake.postBackupUnlockReturnsOnCall[len(fake.postBackupUnlockArgsForCall)]
 	fake.postBackupUnlockArgsForCall = append(fake.postBackupUnlockArgsForCall, struct {
-		arg1 bool
-	}{arg1})
-	fake.recordInvocation("PostBackupUnlock", []interface{}{arg1})
+		arg1 context.Context
+		arg2 bool
+	}{arg1, arg2})
+	fake.recordInvocation("PostBackupUnlock", []interface{}{arg1, arg2})
 	fake.postBackupUnlockMutex.Unlock()
 	if fake.PostBackupUnlockStub != nil {
-		return fake.PostBackupUnlockStub(arg1)
+		return fake.PostBackupUnlockStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -809,17 +836,17 @@ func (fake *FakeJob) PostBackupUnlockCallCount() int {
 	return len(fake.postBackupUnlockArgsForCall)
 }
 
-func (fake *FakeJob) PostBackupUnlockCalls(stub func(bool) error) {
+func (fake *FakeJob) PostBackupUnlockCalls(stub func(context.Context, bool) error) {
 	fake.postBackupUnlockMutex.Lock()
 	defer fake.postBackupUnlockMutex.Unlock()
 	fake.PostBackupUnlockStub = stub
 }
 
-func (fake *FakeJob) PostBackupUnlockArgsForCall(i int) bool {
+func (fake *FakeJob) PostBackupUnlockArgsForCall(i int) (context.Context, bool) {
 	fake.postBackupUnlockMutex.RLock()
 	defer fake.postBackupUnlockMutex.RUnlock()
 	argsForCall := fake.postBackupUnlockArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeJob) PostBackupUnlockReturns(result1 error) {
@@ -845,15 +872,16 @@ func (fake *FakeJob) PostBackupUnlockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeJob) PostRestoreUnlock() error {
+func (fake *FakeJob) PostRestoreUnlock(arg1 context.Context) error {
 	fake.postRestoreUnlockMutex.Lock()
 	ret, specificReturn := fake.postRestoreUnlockReturnsOnCall[len(fake.postRestoreUnlockArgsForCall)]
 	fake.postRestoreUnlockArgsForCall = append(fake.postRestoreUnlockArgsForCall, struct {
-	}{})
-	fake.recordInvocation("PostRestoreUnlock", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("PostRestoreUnlock", []interface{}{arg1})
 	fake.postRestoreUnlockMutex.Unlock()
 	if fake.PostRestoreUnlockStub != nil {
-		return fake.PostRestoreUnlockStub()
+		return fake.PostRestoreUnlockStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -868,12 +896,19 @@ func (fake *FakeJob) PostRestoreUnlockCallCount() int {
 	return len(fake.postRestoreUnlockArgsForCall)
 }
 
-func (fake *FakeJob) PostRestoreUnlockCalls(stub func() error) {
+func (fake *FakeJob) PostRestoreUnlockCalls(stub func(context.Context) error) {
 	fake.postRestoreUnlockMutex.Lock()
 	defer fake.postRestoreUnlockMutex.Unlock()
 	fake.PostRestoreUnlockStub = stub
 }
 
+func (fake *FakeJob) PostRestoreUnlockArgsForCall(i int) context.Context {
+	fake.postRestoreUnlockMutex.RLock()
+	defer fake.postRestoreUnlockMutex.RUnlock()
+	argsForCall := fake.postRestoreUnlockArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeJob) PostRestoreUnlockReturns(result1 error) {
 	fake.postRestoreUnlockMutex.Lock()
 	defer fake.postRestoreUnlockMutex.Unlock()
@@ -897,15 +932,76 @@ func (fake *FakeJob) PostRestoreUnlockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeJob) PreBackupLock() error {
+func (fake *FakeJob) PostRestoreValidate(arg1 context.Context) error {
+	fake.postRestoreValidateMutex.Lock()
+	ret, specificReturn := fake.postRestoreValidateReturnsOnCall[len(fake.postRestoreValidateArgsForCall)]
+	fake.postRestoreValidateArgsForCall = append(fake.postRestoreValidateArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("PostRestoreValidate", []interface{}{arg1})
+	fake.postRestoreValidateMutex.Unlock()
+	if fake.PostRestoreValidateStub != nil {
+		return fake.PostRestoreValidateStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.postRestoreValidateReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeJob) PostRestoreValidateCallCount() int {
+	fake.postRestoreValidateMutex.RLock()
+	defer fake.postRestoreValidateMutex.RUnlock()
+	return len(fake.postRestoreValidateArgsForCall)
+}
+
+func (fake *FakeJob) PostRestoreValidateCalls(stub func(context.Context) error) {
+	fake.postRestoreValidateMutex.Lock()
+	defer fake.postRestoreValidateMutex.Unlock()
+	fake.PostRestoreValidateStub = stub
+}
+
+func (fake *FakeJob) PostRestoreValidateArgsForCall(i int) context.Context {
+	fake.postRestoreValidateMutex.RLock()
+	defer fake.postRestoreValidateMutex.RUnlock()
+	argsForCall := fake.postRestoreValidateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeJob) PostRestoreValidateReturns(result1 error) {
+	fake.postRestoreValidateMutex.Lock()
+	defer fake.postRestoreValidateMutex.Unlock()
+	fake.PostRestoreValidateStub = nil
+	fake.postRestoreValidateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeJob) PostRestoreValidateReturnsOnCall(i int, result1 error) {
+	fake.postRestoreValidateMutex.Lock()
+	defer fake.postRestoreValidateMutex.Unlock()
+	fake.PostRestoreValidateStub = nil
+	if fake.postRestoreValidateReturnsOnCall == nil {
+		fake.postRestoreValidateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.postRestoreValidateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeJob) PreBackupLock(arg1 context.Context) error {
 	fake.preBackupLockMutex.Lock()
 	ret, specificReturn := fake.preBackupLockReturnsOnCall[len(fake.preBackupLockArgsForCall)]
 	fake.preBackupLockArgsForCall = append(fake.preBackupLockArgsForCall, struct {
-	}{})
-	fake.recordInvocation("PreBackupLock", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("PreBackupLock", []interface{}{arg1})
 	fake.preBackupLockMutex.Unlock()
 	if fake.PreBackupLockStub != nil {
-		return fake.PreBackupLockStub()
+		return fake.PreBackupLockStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -920,12 +1016,19 @@ func (fake *FakeJob) PreBackupLockCallCount() int {
 	return len(fake.preBackupLockArgsForCall)
 }
 
-func (fake *FakeJob) PreBackupLockCalls(stub func() error) {
+func (fake *FakeJob) PreBackupLockCalls(stub func(context.Context) error) {
 	fake.preBackupLockMutex.Lock()
 	defer fake.preBackupLockMutex.Unlock()
 	fake.PreBackupLockStub = stub
 }
 
+func (fake *FakeJob) PreBackupLockArgsForCall(i int) context.Context {
+	fake.preBackupLockMutex.RLock()
+	defer fake.preBackupLockMutex.RUnlock()
+	argsForCall := fake.preBackupLockArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeJob) PreBackupLockReturns(result1 error) {
 	fake.preBackupLockMutex.Lock()
 	defer fake.preBackupLockMutex.Unlock()
@@ -949,15 +1052,16 @@ func (fake *FakeJob) PreBackupLockReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeJob) PreRestoreLock() error {
+func (fake *FakeJob) PreRestoreLock(arg1 context.Context) error {
 	fake.preRestoreLockMutex.Lock()
 	ret, specificReturn := fake.preRestoreLockReturnsOnCall[len(fake.preRestoreLockArgsForCall)]
 	fake.preRestoreLockArgsForCall = append(fake.preRestoreLockArgsForCall, struct {
-	}{})
-	fake.recordInvocation("PreRestoreLock", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("PreRestoreLock", []interface{}{arg1})
 	fake.preRestoreLockMutex.Unlock()
 	if fake.PreRestoreLockStub != nil {
-		return fake.PreRestoreLockStub()
+		return fake.PreRestoreLockStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -972,12 +1076,19 @@ func (fake *FakeJob) PreRestoreLockCallCount() int {
 	return len(fake.preRestoreLockArgsForCall)
 }
 
-func (fake *FakeJob) PreRestoreLockCalls(stub func() error) {
+func (fake *FakeJob) PreRestoreLockCalls(stub func(context.Context) error) {
 	fake.preRestoreLockMutex.Lock()
 	defer fake.preRestoreLockMutex.Unlock()
 	fake.PreRestoreLockStub = stub
 }
 
+func (fake *FakeJob) PreRestoreLockArgsForCall(i int) context.Context {
+	fake.preRestoreLockMutex.RLock()
+	defer fake.preRestoreLockMutex.RUnlock()
+	argsForCall := fake.preRestoreLockArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeJob) PreRestoreLockReturns(result1 error) {
 	fake.preRestoreLockMutex.Lock()
 	defer fake.preRestoreLockMutex.Unlock()
@@ -1053,15 +1164,16 @@ func (fake *FakeJob) ReleaseReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
-func (fake *FakeJob) Restore() error {
+func (fake *FakeJob) Restore(arg1 context.Context) error {
 	fake.restoreMutex.Lock()
 	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
 	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
-	}{})
-	fake.recordInvocation("Restore", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Restore", []interface{}{arg1})
 	fake.restoreMutex.Unlock()
 	if fake.RestoreStub != nil {
-		return fake.RestoreStub()
+		return fake.RestoreStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1076,12 +1188,19 @@ func (fake *FakeJob) RestoreCallCount() int {
 	return len(fake.restoreArgsForCall)
 }
 
-func (fake *FakeJob) RestoreCalls(stub func() error) {
+func (fake *FakeJob) RestoreCalls(stub func(context.Context) error) {
 	fake.restoreMutex.Lock()
 	defer fake.restoreMutex.Unlock()
 	fake.RestoreStub = stub
 }
 
+func (fake *FakeJob) RestoreArgsForCall(i int) context.Context {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeJob) RestoreReturns(result1 error) {
 	fake.restoreMutex.Lock()
 	defer fake.restoreMutex.Unlock()
@@ -1290,6 +1409,8 @@ func (fake *FakeJob) Invocations() map[string][][]interface{} {
 	defer fake.postBackupUnlockMutex.RUnlock()
 	fake.postRestoreUnlockMutex.RLock()
 	defer fake.postRestoreUnlockMutex.RUnlock()
+	fake.postRestoreValidateMutex.RLock()
+	defer fake.postRestoreValidateMutex.RUnlock()
 	fake.preBackupLockMutex.RLock()
 	defer fake.preBackupLockMutex.RUnlock()
 	fake.preRestoreLockMutex.RLock()
@@ -1,5 +1,7 @@
 package orchestrator
 
+import "context"
+
 type FindDeploymentStep struct {
 	deploymentManager DeploymentManager
 	logger            Logger
@@ -9,9 +11,9 @@ func NewFindDeploymentStep(deploymentManager DeploymentManager, logger Logger) S
 	return &FindDeploymentStep{deploymentManager: deploymentManager, logger: logger}
 }
 
-func (s *FindDeploymentStep) Run(session *Session) error {
+func (s *FindDeploymentStep) Run(ctx context.Context, session *Session) error {
 	s.logger.Info("bbr", "Looking for scripts")
-	deployment, err := s.deploymentManager.Find(session.DeploymentName())
+	deployment, err := s.deploymentManager.Find(ctx, session.DeploymentName())
 	if err != nil {
 		return err
 	}
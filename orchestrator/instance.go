@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"io"
 )
 
@@ -14,17 +15,19 @@ type InstanceIdentifer interface {
 type Instance interface {
 	InstanceIdentifer
 	IsBackupable() bool
-	ArtifactDirExists() (bool, error)
+	ArtifactDirExists(context.Context) (bool, error)
 	ArtifactDirCreated() bool
 	MarkArtifactDirCreated()
+	RetainArtifactDir()
 	IsRestorable() bool
-	Backup() error
-	Restore() error
+	Backup(context.Context) error
+	Restore(context.Context) error
 	Cleanup() error
 	CleanupPrevious() error
 	ArtifactsToBackup() []BackupArtifact
 	ArtifactsToRestore() []BackupArtifact
 	HasMetadataRestoreNames() bool
+	IsBootstrap() bool
 	Jobs() []Job
 }
 
@@ -37,12 +40,13 @@ type Job interface {
 	BackupArtifactName() string
 	RestoreArtifactName() string
 	HasMetadataRestoreName() bool
-	Backup() error
-	PreBackupLock() error
-	PostBackupUnlock(afterSuccessfulBackup bool) error
-	PreRestoreLock() error
-	Restore() error
-	PostRestoreUnlock() error
+	Backup(context.Context) error
+	PreBackupLock(context.Context) error
+	PostBackupUnlock(ctx context.Context, afterSuccessfulBackup bool) error
+	PreRestoreLock(context.Context) error
+	Restore(context.Context) error
+	PostRestoreUnlock(context.Context) error
+	PostRestoreValidate(context.Context) error
 	Name() string
 	Release() string
 	InstanceIdentifier() string
@@ -68,12 +72,14 @@ type ArtifactIdentifier interface {
 //go:generate counterfeiter -o fakes/fake_backup_artifact.go . BackupArtifact
 type BackupArtifact interface {
 	ArtifactIdentifier
-	Size() (string, error)
-	SizeInBytes() (int, error)
-	Checksum() (BackupChecksum, error)
-	StreamFromRemote(io.Writer) error
+	Size(context.Context) (string, error)
+	SizeInBytes(context.Context) (int, error)
+	Checksum(context.Context) (BackupChecksum, error)
+	StreamFromRemote(context.Context, io.Writer) error
+	StreamFilesFromRemote(context.Context, []string, io.Writer) error
+	UploadPreviousChecksums(BackupChecksum) error
 	Delete() error
-	StreamToRemote(io.Reader) error
+	StreamToRemote(context.Context, io.Reader) error
 }
 
 type instances []Instance
@@ -144,9 +150,9 @@ func (is instances) CleanupPrevious() error {
 	return ConvertErrors(cleanupPreviousErrors)
 }
 
-func (is instances) Backup() error {
+func (is instances) Backup(ctx context.Context) error {
 	for _, instance := range is {
-		err := instance.Backup()
+		err := instance.Backup(ctx)
 		if err != nil {
 			return err
 		}
@@ -154,9 +160,9 @@ func (is instances) Backup() error {
 	return nil
 }
 
-func (is instances) Restore() error {
+func (is instances) Restore(ctx context.Context) error {
 	for _, instance := range is {
-		err := instance.Restore()
+		err := instance.Restore(ctx)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,171 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// InstanceMapping maps the instances of a backed up deployment to the
+// instances of the deployment that is restored, for restoring into a
+// deployment with different instance group names or indices. Entries map
+// either a whole instance group, as `group`, or a single instance, as
+// `group/index`, which takes precedence over the entry for its group.
+// Instances that are not mapped keep their name and index.
+type InstanceMapping struct {
+	entries []instanceMappingEntry
+}
+
+type instanceMappingEntry struct {
+	from, to instanceSpecifier
+}
+
+type instanceSpecifier struct {
+	group, index string
+}
+
+func (s instanceSpecifier) String() string {
+	if s.index == "" {
+		return s.group
+	}
+	return s.group + "/" + s.index
+}
+
+func (s instanceSpecifier) overlaps(other instanceSpecifier) bool {
+	return s.group == other.group && (s.index == "" || other.index == "" || s.index == other.index)
+}
+
+// ParseInstanceMapping reads a mapping in the form:
+//
+//	instance_groups:
+//	- from: redis
+//	  to: redis-staging
+//	- from: postgres/0
+//	  to: db/1
+func ParseInstanceMapping(contents []byte) (InstanceMapping, error) {
+	var file struct {
+		InstanceGroups []struct {
+			From string `yaml:"from"`
+			To   string `yaml:"to"`
+		} `yaml:"instance_groups"`
+	}
+	if err := yaml.UnmarshalStrict(contents, &file); err != nil {
+		return InstanceMapping{}, errors.Wrap(err, "failed to parse instance mapping")
+	}
+
+	var mapping InstanceMapping
+	var problems []string
+	for _, entry := range file.InstanceGroups {
+		from, fromErr := parseInstanceSpecifier(entry.From)
+		to, toErr := parseInstanceSpecifier(entry.To)
+		if fromErr != nil || toErr != nil {
+			problems = append(problems, fmt.Sprintf("invalid entry from '%s' to '%s', expected <group> or <group>/<index>", entry.From, entry.To))
+			continue
+		}
+		if (from.index == "") != (to.index == "") {
+			problems = append(problems, fmt.Sprintf("entry from '%s' to '%s' must map a group to a group or an instance to an instance", entry.From, entry.To))
+			continue
+		}
+
+		for _, existing := range mapping.entries {
+			if existing.from == from {
+				problems = append(problems, fmt.Sprintf("'%s' is mapped more than once", entry.From))
+			}
+			if existing.to == to {
+				problems = append(problems, fmt.Sprintf("'%s' is mapped to more than once", entry.To))
+			}
+		}
+		mapping.entries = append(mapping.entries, instanceMappingEntry{from: from, to: to})
+	}
+
+	if len(problems) > 0 {
+		return InstanceMapping{}, errors.Errorf("invalid instance mapping: %s", strings.Join(problems, "; "))
+	}
+	return mapping, nil
+}
+
+func parseInstanceSpecifier(specifier string) (instanceSpecifier, error) {
+	parts := strings.Split(specifier, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return instanceSpecifier{group: parts[0]}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return instanceSpecifier{group: parts[0], index: parts[1]}, nil
+	}
+	return instanceSpecifier{}, errors.Errorf("invalid instance %q", specifier)
+}
+
+func (m InstanceMapping) IsEmpty() bool {
+	return len(m.entries) == 0
+}
+
+// source returns the name and index the instance of the restored deployment
+// has in the backup. Instance mappings take precedence over group mappings.
+func (m InstanceMapping) source(name, index string) (string, string) {
+	for _, entry := range m.entries {
+		if entry.to.index != "" && entry.to.group == name && entry.to.index == index {
+			return entry.from.group, entry.from.index
+		}
+	}
+	for _, entry := range m.entries {
+		if entry.to.index == "" && entry.to.group == name {
+			return entry.from.group, index
+		}
+	}
+	return name, index
+}
+
+// check reports the entries that map from instances the backup does not have or
+// to instances the deployment does not have, and the backed up instances that
+// more than one instance of the deployment maps to.
+func (m InstanceMapping) check(deploymentName string, instances []Instance, backedUpInstances []InstanceMetadata) error {
+	var problems []string
+
+	for _, entry := range m.entries {
+		found := false
+		for _, instance := range backedUpInstances {
+			if entry.from.overlaps(instanceSpecifier{group: instance.Name, index: instance.Index}) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("'%s' is mapped to '%s', but is not in the backup", entry.from, entry.to))
+		}
+	}
+
+	for _, entry := range m.entries {
+		found := false
+		for _, instance := range instances {
+			if entry.to.overlaps(instanceSpecifier{group: instance.Name(), index: instance.Index()}) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("'%s' is mapped to '%s', which is not in deployment '%s'", entry.from, entry.to, deploymentName))
+		}
+	}
+
+	targets := map[instanceSpecifier][]string{}
+	for _, instance := range instances {
+		name, index := m.source(instance.Name(), instance.Index())
+		source := instanceSpecifier{group: name, index: index}
+		targets[source] = append(targets[source], instance.Name()+"/"+instance.Index())
+	}
+	for source, instanceNames := range targets {
+		if len(instanceNames) > 1 {
+			sort.Strings(instanceNames)
+			problems = append(problems, fmt.Sprintf("'%s' is ambiguous, as %s all map to it", source, strings.Join(instanceNames, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.Errorf("the instance mapping does not fit deployment '%s': %s", deploymentName, strings.Join(problems, "; "))
+	}
+	return nil
+}
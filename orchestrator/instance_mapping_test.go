@@ -0,0 +1,212 @@
+package orchestrator_test
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InstanceMapping", func() {
+	newInstance := func(name, index string) *fakes.FakeInstance {
+		instance := new(fakes.FakeInstance)
+		instance.NameReturns(name)
+		instance.IndexReturns(index)
+		return instance
+	}
+
+	Describe("ParseInstanceMapping", func() {
+		It("parses group and instance entries", func() {
+			mapping, err := orchestrator.ParseInstanceMapping([]byte(`---
+instance_groups:
+- from: redis
+  to: redis-staging
+- from: postgres/0
+  to: db/1
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mapping.IsEmpty()).To(BeFalse())
+		})
+
+		It("reports every invalid or ambiguous entry", func() {
+			_, err := orchestrator.ParseInstanceMapping([]byte(`---
+instance_groups:
+- from: redis
+  to: redis-staging
+- from: redis
+  to: other
+- from: postgres
+  to: redis-staging
+- from: web/0
+  to: web
+- from: a/b/c
+  to: x
+`))
+			Expect(err).To(MatchError(SatisfyAll(
+				ContainSubstring("'redis' is mapped more than once"),
+				ContainSubstring("'redis-staging' is mapped to more than once"),
+				ContainSubstring("entry from 'web/0' to 'web' must map a group to a group or an instance to an instance"),
+				ContainSubstring("invalid entry from 'a/b/c' to 'x'"),
+			)))
+		})
+
+		It("rejects unknown keys", func() {
+			_, err := orchestrator.ParseInstanceMapping([]byte("instance_group:\n- from: redis\n  to: other\n"))
+			Expect(err).To(MatchError(ContainSubstring("failed to parse instance mapping")))
+		})
+	})
+
+	Describe("a backup opened through a mapped backup manager", func() {
+		var backup *fakes.FakeBackup
+		var mappingFile string
+		var mappedBackup orchestrator.Backup
+
+		BeforeEach(func() {
+			mappingFile = `---
+instance_groups:
+- from: redis
+  to: redis-staging
+- from: redis/1
+  to: spare/0
+`
+			backup = new(fakes.FakeBackup)
+			backup.DeploymentMatchesReturns(true, nil)
+			backup.BackedUpInstancesReturns([]orchestrator.InstanceMetadata{
+				{Name: "redis", Index: "0"},
+				{Name: "redis", Index: "1"},
+				{Name: "web", Index: "2"},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			mapping, err := orchestrator.ParseInstanceMapping([]byte(mappingFile))
+			Expect(err).NotTo(HaveOccurred())
+
+			backupManager := new(fakes.FakeBackupManager)
+			backupManager.OpenReturns(backup, nil)
+
+			mappedBackup, err = orchestrator.NewMappedBackupManager(backupManager, mapping).Open("/some/path", new(fakes.FakeLogger))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("looks up artifacts by their instance in the backup", func() {
+			for _, instance := range [][]string{{"redis-staging", "0", "redis", "0"}, {"spare", "0", "redis", "1"}, {"web", "2", "web", "2"}} {
+				artifact := new(fakes.FakeBackupArtifact)
+				artifact.InstanceNameReturns(instance[0])
+				artifact.InstanceIndexReturns(instance[1])
+				artifact.NameReturns("job")
+
+				_, err := mappedBackup.ReadArtifact(artifact)
+				Expect(err).NotTo(HaveOccurred())
+
+				identifier := backup.ReadArtifactArgsForCall(backup.ReadArtifactCallCount() - 1)
+				Expect(identifier.InstanceName()).To(Equal(instance[2]))
+				Expect(identifier.InstanceIndex()).To(Equal(instance[3]))
+				Expect(identifier.Name()).To(Equal("job"))
+			}
+		})
+
+		It("leaves custom artifacts alone", func() {
+			artifact := new(fakes.FakeBackupArtifact)
+			artifact.HasCustomNameReturns(true)
+
+			_, err := mappedBackup.FetchChecksum(artifact)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.FetchChecksumArgsForCall(0)).To(Equal(artifact))
+		})
+
+		It("matches the deployment using the instances of the backup", func() {
+			match, err := mappedBackup.DeploymentMatches("staging", []orchestrator.Instance{
+				newInstance("redis-staging", "0"),
+				newInstance("spare", "0"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(BeTrue())
+
+			_, instances := backup.DeploymentMatchesArgsForCall(0)
+			Expect(instances[0].Name()).To(Equal("redis"))
+			Expect(instances[0].Index()).To(Equal("0"))
+			Expect(instances[1].Name()).To(Equal("redis"))
+			Expect(instances[1].Index()).To(Equal("1"))
+		})
+
+		It("reports entries that map to instances the deployment does not have", func() {
+			_, err := mappedBackup.DeploymentMatches("staging", []orchestrator.Instance{
+				newInstance("redis-staging", "0"),
+			})
+			Expect(err).To(MatchError(ContainSubstring("'redis/1' is mapped to 'spare/0', which is not in deployment 'staging'")))
+			Expect(backup.DeploymentMatchesCallCount()).To(Equal(0))
+		})
+
+		It("reports backed up instances that more than one instance maps to", func() {
+			_, err := mappedBackup.DeploymentMatches("staging", []orchestrator.Instance{
+				newInstance("redis-staging", "0"),
+				newInstance("redis-staging", "1"),
+				newInstance("spare", "0"),
+			})
+			Expect(err).To(MatchError(ContainSubstring("'redis/1' is ambiguous, as redis-staging/1, spare/0 all map to it")))
+		})
+
+		It("reports backed up instances that are not mapped", func() {
+			backup.DeploymentMatchesReturns(false, nil)
+
+			_, err := mappedBackup.DeploymentMatches("staging", []orchestrator.Instance{
+				newInstance("redis-staging", "0"),
+				newInstance("spare", "0"),
+			})
+			Expect(err).To(MatchError(ContainSubstring("neither in deployment 'staging' nor mapped")))
+		})
+
+		It("reports every backed up instance that is not mapped", func() {
+			backup.DeploymentMatchesReturns(false, orchestrator.NewDeploymentMismatchError(
+				"the backup has instances that are not in deployment 'staging'",
+				[]string{"broker/0", "broker/1", "worker/0"},
+			))
+
+			match, err := mappedBackup.DeploymentMatches("staging", []orchestrator.Instance{
+				newInstance("redis-staging", "0"),
+				newInstance("spare", "0"),
+			})
+			Expect(match).To(BeFalse())
+			Expect(err).To(MatchError(
+				"the backup has instances that are neither in deployment 'staging' nor mapped to one of its instances: broker/0, broker/1, worker/0",
+			))
+			Expect(err).To(BeAssignableToTypeOf(orchestrator.DeploymentMismatchError{}))
+		})
+
+		Context("when entries map from instances the backup does not have", func() {
+			BeforeEach(func() {
+				mappingFile = `---
+instance_groups:
+- from: rdis
+  to: redis-staging
+- from: web/3
+  to: spare/0
+`
+			})
+
+			It("reports every one of them before checking the backup", func() {
+				_, err := mappedBackup.DeploymentMatches("staging", []orchestrator.Instance{
+					newInstance("redis-staging", "0"),
+					newInstance("spare", "0"),
+				})
+				Expect(err).To(MatchError(ContainSubstring("'rdis' is mapped to 'redis-staging', but is not in the backup")))
+				Expect(err).To(MatchError(ContainSubstring("'web/3' is mapped to 'spare/0', but is not in the backup")))
+				Expect(backup.DeploymentMatchesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the instances of the backup cannot be read", func() {
+			BeforeEach(func() {
+				backup.BackedUpInstancesReturns(nil, fmt.Errorf("no metadata"))
+			})
+
+			It("fails", func() {
+				_, err := mappedBackup.DeploymentMatches("staging", []orchestrator.Instance{newInstance("redis-staging", "0")})
+				Expect(err).To(MatchError("no metadata"))
+			})
+		})
+	})
+})
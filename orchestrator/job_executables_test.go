@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -25,7 +26,7 @@ var _ = Describe("JobExecutables", func() {
 			executable = orchestrator.NewJobPreBackupLockExecutable(fakeJob)
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes pre backup lock", func() {
@@ -48,7 +49,7 @@ var _ = Describe("JobExecutables", func() {
 			executable = orchestrator.NewJobPostSuccessfulBackupUnlockExecutable(fakeJob)
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes pre backup lock", func() {
@@ -72,7 +73,7 @@ var _ = Describe("JobExecutables", func() {
 			executable = orchestrator.NewJobPreRestoreLockExecutable(fakeJob)
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes pre backup lock", func() {
@@ -96,7 +97,7 @@ var _ = Describe("JobExecutables", func() {
 			executable = orchestrator.NewJobPostRestoreUnlockExecutable(fakeJob)
 		})
 		JustBeforeEach(func() {
-			err = executable.Execute()
+			err = executable.Execute(context.Background())
 		})
 
 		It("executes pre backup lock", func() {
@@ -1,16 +1,20 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type LockStep struct {
 	lockOrderer LockOrderer
 	executor    executor.Executor
 }
 
-func (s *LockStep) Run(session *Session) error {
-	err := session.CurrentDeployment().PreBackupLock(s.lockOrderer, s.executor)
+func (s *LockStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().PreBackupLock(ctx, s.lockOrderer, s.executor)
 	if err != nil {
-		return NewLockError(err.Error())
+		return WrapLockError(err)
 	}
 	return nil
 }
@@ -0,0 +1,135 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// NewMappedBackupManager opens backups that look up their artifacts by the
+// instance names and indices they had when they were backed up, given the
+// instance names and indices of the deployment that is restored.
+func NewMappedBackupManager(backupManager BackupManager, mapping InstanceMapping) BackupManager {
+	return mappedBackupManager{BackupManager: backupManager, mapping: mapping}
+}
+
+type mappedBackupManager struct {
+	BackupManager
+	mapping InstanceMapping
+}
+
+func (m mappedBackupManager) Open(name string, logger Logger) (Backup, error) {
+	backup, err := m.BackupManager.Open(name, logger)
+	if err != nil {
+		return backup, err
+	}
+	return mappedBackup{Backup: backup, mapping: m.mapping}, nil
+}
+
+type mappedBackup struct {
+	Backup
+	mapping InstanceMapping
+}
+
+// DeploymentMatches checks the mapping against the backup and the deployment,
+// and then the mapped instances against the backup.
+func (b mappedBackup) DeploymentMatches(deployment string, instances []Instance) (bool, error) {
+	backedUpInstances, err := b.Backup.BackedUpInstances()
+	if err != nil {
+		return false, err
+	}
+
+	if err := b.mapping.check(deployment, instances, backedUpInstances); err != nil {
+		return false, err
+	}
+
+	var mappedInstances []Instance
+	for _, instance := range instances {
+		name, index := b.mapping.source(instance.Name(), instance.Index())
+		mappedInstances = append(mappedInstances, mappedInstance{Instance: instance, name: name, index: index})
+	}
+
+	var mismatch DeploymentMismatchError
+	match, err := b.Backup.DeploymentMatches(deployment, mappedInstances)
+	if errors.As(err, &mismatch) {
+		return false, NewDeploymentMismatchError(
+			fmt.Sprintf("the backup has instances that are neither in deployment '%s' nor mapped to one of its instances", deployment),
+			mismatch.MissingInstances,
+		)
+	}
+	if err == nil && !match {
+		return false, errors.Errorf("the backup has instances that are neither in deployment '%s' nor mapped to one of its instances", deployment)
+	}
+	return match, err
+}
+
+func (b mappedBackup) GetArtifactSize(artifactIdentifier ArtifactIdentifier) (string, error) {
+	return b.Backup.GetArtifactSize(b.source(artifactIdentifier))
+}
+
+func (b mappedBackup) GetArtifactByteSize(artifactIdentifier ArtifactIdentifier) (int, error) {
+	return b.Backup.GetArtifactByteSize(b.source(artifactIdentifier))
+}
+
+func (b mappedBackup) CreateArtifact(artifactIdentifier ArtifactIdentifier) (io.WriteCloser, error) {
+	return b.Backup.CreateArtifact(b.source(artifactIdentifier))
+}
+
+func (b mappedBackup) ReadArtifact(artifactIdentifier ArtifactIdentifier) (io.ReadCloser, error) {
+	return b.Backup.ReadArtifact(b.source(artifactIdentifier))
+}
+
+func (b mappedBackup) AddChecksum(artifactIdentifier ArtifactIdentifier, checksum BackupChecksum) error {
+	return b.Backup.AddChecksum(b.source(artifactIdentifier), checksum)
+}
+
+func (b mappedBackup) FetchChecksum(artifactIdentifier ArtifactIdentifier) (BackupChecksum, error) {
+	return b.Backup.FetchChecksum(b.source(artifactIdentifier))
+}
+
+func (b mappedBackup) FetchPreviousChecksum(artifactIdentifier ArtifactIdentifier) (BackupChecksum, error) {
+	return b.Backup.FetchPreviousChecksum(b.source(artifactIdentifier))
+}
+
+func (b mappedBackup) ArtifactDrained(artifactIdentifier ArtifactIdentifier) (bool, error) {
+	return b.Backup.ArtifactDrained(b.source(artifactIdentifier))
+}
+
+func (b mappedBackup) CalculateChecksum(artifactIdentifier ArtifactIdentifier) (BackupChecksum, error) {
+	return b.Backup.CalculateChecksum(b.source(artifactIdentifier))
+}
+
+func (b mappedBackup) source(artifactIdentifier ArtifactIdentifier) ArtifactIdentifier {
+	if artifactIdentifier.HasCustomName() {
+		return artifactIdentifier
+	}
+	name, index := b.mapping.source(artifactIdentifier.InstanceName(), artifactIdentifier.InstanceIndex())
+	return mappedArtifactIdentifier{ArtifactIdentifier: artifactIdentifier, instanceName: name, instanceIndex: index}
+}
+
+type mappedArtifactIdentifier struct {
+	ArtifactIdentifier
+	instanceName, instanceIndex string
+}
+
+func (i mappedArtifactIdentifier) InstanceName() string {
+	return i.instanceName
+}
+
+func (i mappedArtifactIdentifier) InstanceIndex() string {
+	return i.instanceIndex
+}
+
+type mappedInstance struct {
+	Instance
+	name, index string
+}
+
+func (i mappedInstance) Name() string {
+	return i.name
+}
+
+func (i mappedInstance) Index() string {
+	return i.index
+}
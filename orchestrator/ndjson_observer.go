@@ -0,0 +1,58 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// NDJSONObserver writes each event as a line of JSON, so that tooling can
+// follow a backup or restore without parsing the logs. It is safe to share
+// between workflows running concurrently. A failure to write the stream does
+// not fail the workflow: the first write error is kept for Err, and no further
+// events are written, so the stream is never left with gaps in the middle.
+type NDJSONObserver struct {
+	mutex    sync.Mutex
+	encoder  *json.Encoder
+	nowFunc  func() time.Time
+	writeErr error
+}
+
+type ndjsonEvent struct {
+	Time       time.Time `json:"time"`
+	Deployment string    `json:"deployment"`
+	Type       string    `json:"type"`
+	Data       Event     `json:"data"`
+}
+
+func NewNDJSONObserver(writer io.Writer, nowFunc func() time.Time) *NDJSONObserver {
+	return &NDJSONObserver{
+		encoder: json.NewEncoder(writer),
+		nowFunc: nowFunc,
+	}
+}
+
+func (o *NDJSONObserver) Notify(deploymentName string, event Event) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.writeErr != nil {
+		return
+	}
+
+	o.writeErr = o.encoder.Encode(ndjsonEvent{
+		Time:       o.nowFunc().UTC(),
+		Deployment: deploymentName,
+		Type:       event.EventType(),
+		Data:       event,
+	})
+}
+
+// Err returns the error that stopped events being written, if any.
+func (o *NDJSONObserver) Err() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.writeErr
+}
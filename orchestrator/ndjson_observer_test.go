@@ -0,0 +1,111 @@
+package orchestrator_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NDJSONObserver", func() {
+	var (
+		output   *bytes.Buffer
+		observer *orchestrator.NDJSONObserver
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		output = new(bytes.Buffer)
+		now = time.Date(2026, 10, 17, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+		observer = orchestrator.NewNDJSONObserver(output, func() time.Time { return now })
+	})
+
+	It("writes each event as a line of JSON", func() {
+		observer.Notify("redis", orchestrator.StepStarted{Step: "backup"})
+		observer.Notify("redis", orchestrator.StepFailed{Step: "backup", Duration: 2 * time.Second, Error: "boom"})
+		observer.Notify("redis", orchestrator.ChecksumResult{Instance: "redis/0", Artifact: "redis-server", Match: true})
+
+		lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[0]).To(MatchJSON(`{
+			"time": "2026-10-17T07:30:00Z",
+			"deployment": "redis",
+			"type": "step_started",
+			"data": {"step": "backup"}
+		}`))
+		Expect(lines[1]).To(MatchJSON(`{
+			"time": "2026-10-17T07:30:00Z",
+			"deployment": "redis",
+			"type": "step_failed",
+			"data": {"step": "backup", "duration_ns": 2000000000, "error": "boom"}
+		}`))
+		Expect(lines[2]).To(MatchJSON(`{
+			"time": "2026-10-17T07:30:00Z",
+			"deployment": "redis",
+			"type": "checksum_result",
+			"data": {"instance": "redis/0", "artifact": "redis-server", "match": true}
+		}`))
+	})
+
+	Context("when the events can not be written", func() {
+		var writer *failingWriter
+
+		BeforeEach(func() {
+			writer = &failingWriter{}
+			observer = orchestrator.NewNDJSONObserver(writer, func() time.Time { return now })
+		})
+
+		It("keeps the first write error and stops writing events", func() {
+			Expect(observer.Err()).NotTo(HaveOccurred())
+
+			observer.Notify("redis", orchestrator.StepStarted{Step: "backup"})
+			observer.Notify("redis", orchestrator.StepStarted{Step: "drain"})
+
+			Expect(observer.Err()).To(MatchError("no space left on device"))
+			Expect(writer.writes).To(Equal(1))
+		})
+	})
+
+	It("does not interleave events notified concurrently", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				observer.Notify("redis", orchestrator.TransferProgress{
+					Instance:         "redis/0",
+					Artifact:         "redis-server",
+					Direction:        orchestrator.Download,
+					BytesTransferred: 50,
+					TotalBytes:       100,
+				})
+			}()
+		}
+		wg.Wait()
+
+		lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(20))
+		for _, line := range lines {
+			Expect(line).To(MatchJSON(`{
+				"time": "2026-10-17T07:30:00Z",
+				"deployment": "redis",
+				"type": "transfer_progress",
+				"data": {"instance": "redis/0", "artifact": "redis-server", "direction": "download", "bytes_transferred": 50, "total_bytes": 100}
+			}`))
+		}
+	})
+})
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	w.writes++
+	return 0, errors.New("no space left on device")
+}
@@ -0,0 +1,146 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//go:generate counterfeiter -o fakes/fake_observer.go . Observer
+type Observer interface {
+	Notify(deploymentName string, event Event)
+}
+
+// Event is something that happened while a workflow was running. Its type
+// names the event in the event stream.
+type Event interface {
+	EventType() string
+}
+
+type StepStarted struct {
+	Step string `json:"step"`
+}
+
+type StepSucceeded struct {
+	Step     string        `json:"step"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+type StepFailed struct {
+	Step     string        `json:"step"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error"`
+}
+
+type JobScriptStarted struct {
+	Instance string `json:"instance"`
+	Job      string `json:"job"`
+	Script   string `json:"script"`
+}
+
+type JobScriptFinished struct {
+	Instance string        `json:"instance"`
+	Job      string        `json:"job"`
+	Script   string        `json:"script"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+type TransferDirection string
+
+const (
+	Download TransferDirection = "download"
+	Upload   TransferDirection = "upload"
+)
+
+type TransferProgress struct {
+	Instance         string            `json:"instance"`
+	Artifact         string            `json:"artifact"`
+	Direction        TransferDirection `json:"direction"`
+	BytesTransferred int               `json:"bytes_transferred"`
+	TotalBytes       int               `json:"total_bytes"`
+}
+
+type ChecksumResult struct {
+	Instance        string   `json:"instance"`
+	Artifact        string   `json:"artifact"`
+	Match           bool     `json:"match"`
+	MismatchedFiles []string `json:"mismatched_files,omitempty"`
+}
+
+func (StepStarted) EventType() string       { return "step_started" }
+func (StepSucceeded) EventType() string     { return "step_succeeded" }
+func (StepFailed) EventType() string        { return "step_failed" }
+func (JobScriptStarted) EventType() string  { return "job_script_started" }
+func (JobScriptFinished) EventType() string { return "job_script_finished" }
+func (TransferProgress) EventType() string  { return "transfer_progress" }
+func (ChecksumResult) EventType() string    { return "checksum_result" }
+
+type observerContextKey struct{}
+
+type deploymentObserver struct {
+	deploymentName string
+	observer       Observer
+}
+
+// ContextWithObserver makes the observer of a workflow available to the jobs
+// and executables that the workflow's steps run.
+func ContextWithObserver(ctx context.Context, deploymentName string, observer Observer) context.Context {
+	return context.WithValue(ctx, observerContextKey{}, deploymentObserver{
+		deploymentName: deploymentName,
+		observer:       observer,
+	})
+}
+
+// NotifyObserver tells the observer of the workflow running under ctx about an
+// event. It does nothing when the workflow has no observer.
+func NotifyObserver(ctx context.Context, event Event) {
+	if o, ok := ctx.Value(observerContextKey{}).(deploymentObserver); ok {
+		o.observer.Notify(o.deploymentName, event)
+	}
+}
+
+func notifyTransferProgress(ctx context.Context, artifact BackupArtifact, direction TransferDirection) func(transferred, total int) {
+	return func(transferred, total int) {
+		NotifyObserver(ctx, TransferProgress{
+			Instance:         fmt.Sprintf("%s/%s", artifact.InstanceName(), artifact.InstanceID()),
+			Artifact:         artifact.Name(),
+			Direction:        direction,
+			BytesTransferred: transferred,
+			TotalBytes:       total,
+		})
+	}
+}
+
+func notifyChecksumResult(ctx context.Context, artifact BackupArtifact, match bool, mismatchedFiles []string) {
+	NotifyObserver(ctx, ChecksumResult{
+		Instance:        fmt.Sprintf("%s/%s", artifact.InstanceName(), artifact.InstanceID()),
+		Artifact:        artifact.Name(),
+		Match:           match,
+		MismatchedFiles: mismatchedFiles,
+	})
+}
+
+// stepName names a step in the event stream after its type, so that
+// PostBackupUnlockStep becomes post-backup-unlock.
+func stepName(step Step) string {
+	stepType := reflect.TypeOf(step)
+	if stepType.Kind() == reflect.Ptr {
+		stepType = stepType.Elem()
+	}
+
+	var name strings.Builder
+	for i, r := range strings.TrimSuffix(stepType.Name(), "Step") {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				name.WriteRune('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		name.WriteRune(r)
+	}
+	return name.String()
+}
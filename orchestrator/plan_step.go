@@ -0,0 +1,117 @@
+package orchestrator
+
+import "context"
+
+type PlanBackupStep struct {
+	lockOrderer LockOrderer
+	logger      Logger
+}
+
+func NewPlanBackupStep(lockOrderer LockOrderer, logger Logger) Step {
+	return &PlanBackupStep{lockOrderer: lockOrderer, logger: logger}
+}
+
+func (s *PlanBackupStep) Run(ctx context.Context, session *Session) error {
+	deployment := session.CurrentDeployment()
+
+	plan, err := newPlan("backup", session.DeploymentName(), deployment.Instances(), s.lockOrderer)
+	if err != nil {
+		return err
+	}
+
+	for _, instance := range deployment.BackupableInstances() {
+		plan.Scripts = append(plan.Scripts, plannedInstance(instance, Job.HasBackup))
+
+		for _, artifact := range instance.ArtifactsToBackup() {
+			size, err := artifact.Size(ctx)
+			if err != nil {
+				s.logger.Debug("bbr", "Size of %s is not known yet: %v", artifact.Name(), err)
+				size = ""
+			}
+			plan.Artifacts = append(plan.Artifacts, plannedArtifact(artifact, size))
+		}
+	}
+
+	session.SetPlan(plan)
+	return nil
+}
+
+type PlanRestoreStep struct {
+	lockOrderer LockOrderer
+	logger      Logger
+}
+
+func NewPlanRestoreStep(lockOrderer LockOrderer, logger Logger) Step {
+	return &PlanRestoreStep{lockOrderer: lockOrderer, logger: logger}
+}
+
+func (s *PlanRestoreStep) Run(ctx context.Context, session *Session) error {
+	deployment := session.CurrentDeployment()
+
+	plan, err := newPlan("restore", session.DeploymentName(), deployment.Instances(), s.lockOrderer)
+	if err != nil {
+		return err
+	}
+
+	for _, instance := range deployment.RestorableInstances() {
+		plan.Scripts = append(plan.Scripts, plannedInstance(instance, Job.HasRestore))
+
+		for _, artifact := range instance.ArtifactsToRestore() {
+			size, err := session.CurrentArtifact().GetArtifactSize(artifact)
+			if err != nil {
+				s.logger.Warn("bbr", "Unable to check the size of %s: %v", artifact.Name(), err)
+				size = ""
+			}
+			plan.Artifacts = append(plan.Artifacts, plannedArtifact(artifact, size))
+		}
+	}
+
+	session.SetPlan(plan)
+	return nil
+}
+
+func newPlan(operation, deploymentName string, instanceList []Instance, lockOrderer LockOrderer) (*Plan, error) {
+	orderedJobs, err := lockOrderer.Order(instances(instanceList).Jobs())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{
+		Operation:   operation,
+		Deployment:  deploymentName,
+		LockOrder:   plannedJobs(orderedJobs),
+		Scripts:     []PlannedInstance{},
+		Artifacts:   []PlannedArtifact{},
+		UnlockOrder: plannedJobs(Reverse(orderedJobs)),
+	}, nil
+}
+
+func plannedJobs(orderedJobs [][]Job) [][]PlannedJob {
+	batches := [][]PlannedJob{}
+	for _, jobs := range orderedJobs {
+		var batch []PlannedJob
+		for _, job := range jobs {
+			batch = append(batch, PlannedJob{Instance: job.InstanceIdentifier(), Job: job.Name(), Release: job.Release()})
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func plannedInstance(instance Instance, hasScript func(Job) bool) PlannedInstance {
+	planned := PlannedInstance{Instance: instance.Name() + "/" + instance.Index(), ID: instance.ID(), Jobs: []string{}}
+	for _, job := range instance.Jobs() {
+		if hasScript(job) {
+			planned.Jobs = append(planned.Jobs, job.Name())
+		}
+	}
+	return planned
+}
+
+func plannedArtifact(artifact BackupArtifact, size string) PlannedArtifact {
+	planned := PlannedArtifact{Name: artifact.Name(), Size: size}
+	if !artifact.HasCustomName() {
+		planned.Instance = artifact.InstanceName() + "/" + artifact.InstanceIndex()
+	}
+	return planned
+}
@@ -0,0 +1,90 @@
+package orchestrator
+
+import "context"
+
+// Plan describes what a backup or restore of a deployment would do, without
+// running any of the scripts. Jobs are locked batch by batch in LockOrder and
+// unlocked in UnlockOrder.
+type Plan struct {
+	Operation   string            `json:"operation"`
+	Deployment  string            `json:"deployment"`
+	LockOrder   [][]PlannedJob    `json:"lock_order"`
+	Scripts     []PlannedInstance `json:"scripts"`
+	Artifacts   []PlannedArtifact `json:"artifacts"`
+	UnlockOrder [][]PlannedJob    `json:"unlock_order"`
+}
+
+type PlannedJob struct {
+	Instance string `json:"instance"`
+	Job      string `json:"job"`
+	Release  string `json:"release,omitempty"`
+}
+
+// PlannedInstance lists the jobs of an instance that have a backup script, or
+// a restore script when planning a restore.
+type PlannedInstance struct {
+	Instance string   `json:"instance"`
+	ID       string   `json:"id"`
+	Jobs     []string `json:"jobs"`
+}
+
+// PlannedArtifact is an artifact that would be drained from, or copied to, an
+// instance. The size is left empty when it cannot be known in advance, such as
+// for artifacts that the backup scripts have not written yet.
+type PlannedArtifact struct {
+	Instance string `json:"instance,omitempty"`
+	Name     string `json:"name"`
+	Size     string `json:"size,omitempty"`
+}
+
+type Planner struct {
+	workflow *Workflow
+}
+
+func NewBackupPlanner(logger Logger, deploymentManager DeploymentManager, lockOrderer LockOrderer) *Planner {
+	findDeployment := NewFindDeploymentStep(deploymentManager, logger)
+	backupable := NewBackupableStep(lockOrderer, logger)
+	plan := NewPlanBackupStep(lockOrderer, logger)
+	cleanup := NewCleanupStep()
+
+	workflow := NewWorkflow()
+	workflow.StartWith(findDeployment).OnSuccess(backupable)
+	workflow.Add(backupable).OnSuccess(plan).OnFailure(cleanup)
+	workflow.Add(plan).OnSuccessOrFailure(cleanup)
+	workflow.Add(cleanup)
+
+	return &Planner{workflow: workflow}
+}
+
+func NewRestorePlanner(logger Logger, backupManager BackupManager, deploymentManager DeploymentManager, lockOrderer LockOrderer, selection RestoreSelection) *Planner {
+	validateArtifact := NewValidateArtifactStep(logger, backupManager)
+	findDeployment := NewFindDeploymentStep(deploymentManager, logger)
+	restorable := NewRestorableStep(lockOrderer, logger)
+	selectRestoreTargets := NewSelectRestoreTargetsStep(selection, logger)
+	plan := NewPlanRestoreStep(lockOrderer, logger)
+	cleanup := NewCleanupStep()
+
+	workflow := NewWorkflow()
+	workflow.StartWith(validateArtifact).OnSuccess(findDeployment)
+	workflow.Add(findDeployment).OnSuccess(restorable)
+	workflow.Add(restorable).OnSuccess(selectRestoreTargets).OnFailure(cleanup)
+	workflow.Add(selectRestoreTargets).OnSuccess(plan).OnFailure(cleanup)
+	workflow.Add(plan).OnSuccessOrFailure(cleanup)
+	workflow.Add(cleanup)
+
+	return &Planner{workflow: workflow}
+}
+
+// Plan finds the deployment and runs the same checks as a backup or restore
+// would, then describes what it would do. The artifact path is only used when
+// planning a restore.
+func (p Planner) Plan(ctx context.Context, deploymentName, artifactPath string) (Plan, Error) {
+	session := NewSession(deploymentName)
+	session.SetCurrentArtifactPath(artifactPath)
+
+	err := p.workflow.Run(ctx, session)
+	if session.Plan() == nil {
+		return Plan{}, err
+	}
+	return *session.Plan(), err
+}
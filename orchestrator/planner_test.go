@@ -0,0 +1,194 @@
+package orchestrator_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Planner", func() {
+	var (
+		deployment        *fakes.FakeDeployment
+		deploymentManager *fakes.FakeDeploymentManager
+		logger            *fakes.FakeLogger
+		lockOrderer       *fakes.FakeLockOrderer
+		instance          *fakes.FakeInstance
+		redisJob          *fakes.FakeJob
+		loggerJob         *fakes.FakeJob
+		redisArtifact     *fakes.FakeBackupArtifact
+		sharedArtifact    *fakes.FakeBackupArtifact
+		deploymentName    = "redis"
+	)
+
+	newJob := func(name string, hasScripts bool) *fakes.FakeJob {
+		job := new(fakes.FakeJob)
+		job.NameReturns(name)
+		job.ReleaseReturns("redis-release")
+		job.InstanceIdentifierReturns("redis-server/fake-id")
+		job.HasBackupReturns(hasScripts)
+		job.HasRestoreReturns(hasScripts)
+		return job
+	}
+
+	BeforeEach(func() {
+		deployment = new(fakes.FakeDeployment)
+		deploymentManager = new(fakes.FakeDeploymentManager)
+		logger = new(fakes.FakeLogger)
+		lockOrderer = new(fakes.FakeLockOrderer)
+
+		redisJob = newJob("redis", true)
+		loggerJob = newJob("syslog", false)
+
+		redisArtifact = new(fakes.FakeBackupArtifact)
+		redisArtifact.NameReturns("redis")
+		redisArtifact.InstanceNameReturns("redis-server")
+		redisArtifact.InstanceIndexReturns("0")
+		sharedArtifact = new(fakes.FakeBackupArtifact)
+		sharedArtifact.NameReturns("shared")
+		sharedArtifact.HasCustomNameReturns(true)
+
+		instance = new(fakes.FakeInstance)
+		instance.NameReturns("redis-server")
+		instance.IndexReturns("0")
+		instance.IDReturns("fake-id")
+		instance.JobsReturns([]orchestrator.Job{redisJob, loggerJob})
+
+		deploymentManager.FindReturns(deployment, nil)
+		deployment.InstancesReturns([]orchestrator.Instance{instance})
+		lockOrderer.OrderReturns([][]orchestrator.Job{{redisJob}, {loggerJob}}, nil)
+	})
+
+	Describe("planning a backup", func() {
+		var plan orchestrator.Plan
+		var planError orchestrator.Error
+
+		BeforeEach(func() {
+			deployment.IsBackupableReturns(true)
+			deployment.BackupableInstancesReturns([]orchestrator.Instance{instance})
+			instance.ArtifactsToBackupReturns([]orchestrator.BackupArtifact{redisArtifact, sharedArtifact})
+			redisArtifact.SizeReturns("", errors.New("no such directory"))
+			sharedArtifact.SizeReturns("1.5M", nil)
+		})
+
+		JustBeforeEach(func() {
+			plan, planError = orchestrator.NewBackupPlanner(logger, deploymentManager, lockOrderer).Plan(context.Background(), deploymentName, "")
+		})
+
+		It("describes the backup", func() {
+			Expect(planError).NotTo(HaveOccurred())
+			Expect(plan).To(Equal(orchestrator.Plan{
+				Operation:  "backup",
+				Deployment: "redis",
+				LockOrder: [][]orchestrator.PlannedJob{
+					{{Instance: "redis-server/fake-id", Job: "redis", Release: "redis-release"}},
+					{{Instance: "redis-server/fake-id", Job: "syslog", Release: "redis-release"}},
+				},
+				Scripts: []orchestrator.PlannedInstance{
+					{Instance: "redis-server/0", ID: "fake-id", Jobs: []string{"redis"}},
+				},
+				Artifacts: []orchestrator.PlannedArtifact{
+					{Instance: "redis-server/0", Name: "redis"},
+					{Name: "shared", Size: "1.5M"},
+				},
+				UnlockOrder: [][]orchestrator.PlannedJob{
+					{{Instance: "redis-server/fake-id", Job: "syslog", Release: "redis-release"}},
+					{{Instance: "redis-server/fake-id", Job: "redis", Release: "redis-release"}},
+				},
+			}))
+		})
+
+		It("does not run any scripts", func() {
+			Expect(deployment.PreBackupLockCallCount()).To(Equal(0))
+			Expect(deployment.BackupCallCount()).To(Equal(0))
+			Expect(deployment.PostBackupUnlockCallCount()).To(Equal(0))
+		})
+
+		It("runs the backup pre-checks and cleans up", func() {
+			Expect(deployment.CheckArtifactDirCallCount()).To(Equal(1))
+			Expect(deployment.ValidateLockingDependenciesCallCount()).To(Equal(1))
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+
+		Context("when the deployment cannot be backed up", func() {
+			BeforeEach(func() {
+				deployment.IsBackupableReturns(false)
+			})
+
+			It("fails without a plan", func() {
+				Expect(planError).To(MatchError(ContainSubstring("Deployment 'redis' has no backup scripts")))
+				Expect(plan).To(Equal(orchestrator.Plan{}))
+				Expect(deployment.CleanupCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the jobs cannot be ordered", func() {
+			BeforeEach(func() {
+				lockOrderer.OrderReturns(nil, errors.New("cyclic dependency"))
+			})
+
+			It("fails", func() {
+				Expect(planError).To(MatchError(ContainSubstring("cyclic dependency")))
+			})
+		})
+	})
+
+	Describe("planning a restore", func() {
+		var backupManager *fakes.FakeBackupManager
+		var backup *fakes.FakeBackup
+		var plan orchestrator.Plan
+		var planError orchestrator.Error
+
+		BeforeEach(func() {
+			backupManager = new(fakes.FakeBackupManager)
+			backup = new(fakes.FakeBackup)
+			backupManager.OpenReturns(backup, nil)
+			backup.ValidReturns(true, nil)
+			backup.DeploymentMatchesReturns(true, nil)
+			backup.GetArtifactSizeReturns("10M", nil)
+
+			deployment.IsRestorableReturns(true)
+			deployment.RestorableInstancesReturns([]orchestrator.Instance{instance})
+			instance.ArtifactsToRestoreReturns([]orchestrator.BackupArtifact{redisArtifact})
+		})
+
+		JustBeforeEach(func() {
+			plan, planError = orchestrator.NewRestorePlanner(logger, backupManager, deploymentManager, lockOrderer, orchestrator.RestoreSelection{}).Plan(context.Background(), deploymentName, "/some/backup")
+		})
+
+		It("describes the restore", func() {
+			Expect(planError).NotTo(HaveOccurred())
+			Expect(plan.Operation).To(Equal("restore"))
+			Expect(plan.Scripts).To(Equal([]orchestrator.PlannedInstance{
+				{Instance: "redis-server/0", ID: "fake-id", Jobs: []string{"redis"}},
+			}))
+			Expect(plan.Artifacts).To(Equal([]orchestrator.PlannedArtifact{
+				{Instance: "redis-server/0", Name: "redis", Size: "10M"},
+			}))
+			Expect(plan.UnlockOrder[0][0].Job).To(Equal("syslog"))
+		})
+
+		It("validates the backup and does not run any scripts", func() {
+			path, _ := backupManager.OpenArgsForCall(0)
+			Expect(path).To(Equal("/some/backup"))
+			Expect(backup.ValidCallCount()).To(Equal(1))
+			Expect(deployment.PreRestoreLockCallCount()).To(Equal(0))
+			Expect(deployment.RestoreCallCount()).To(Equal(0))
+			Expect(deployment.PostRestoreUnlockCallCount()).To(Equal(0))
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+
+		Context("when the backup does not match the deployment", func() {
+			BeforeEach(func() {
+				backup.DeploymentMatchesReturns(false, nil)
+			})
+
+			It("fails", func() {
+				Expect(planError).To(MatchError(ContainSubstring("does not match the structure of the provided backup")))
+			})
+		})
+	})
+})
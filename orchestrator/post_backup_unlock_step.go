@@ -1,6 +1,10 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type PostBackupUnlockStep struct {
 	afterSuccessfulBackup bool
@@ -16,8 +20,10 @@ func NewPostBackupUnlockStep(afterSuccessfulBackup bool, lockOrderer LockOrderer
 	}
 }
 
-func (s *PostBackupUnlockStep) Run(session *Session) error {
-	err := session.CurrentDeployment().PostBackupUnlock(s.afterSuccessfulBackup, s.lockOrderer, s.executor)
+// Run unlocks the jobs even once the backup has been interrupted, so the unlock
+// scripts are given the values of the context but not its cancellation.
+func (s *PostBackupUnlockStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().PostBackupUnlock(withoutCancel(ctx), s.afterSuccessfulBackup, s.lockOrderer, s.executor)
 	if err != nil {
 		return NewPostUnlockError(err.Error())
 	}
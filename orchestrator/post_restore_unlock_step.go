@@ -1,6 +1,10 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type PostRestoreUnlockStep struct {
 	lockOrderer LockOrderer
@@ -14,8 +18,10 @@ func NewPostRestoreUnlockStep(lockOrderer LockOrderer, executor executor.Executo
 	}
 }
 
-func (s *PostRestoreUnlockStep) Run(session *Session) error {
-	err := session.CurrentDeployment().PostRestoreUnlock(s.lockOrderer, s.executor)
+// Run unlocks the jobs even once the restore has been interrupted, so the unlock
+// scripts are given the values of the context but not its cancellation.
+func (s *PostRestoreUnlockStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().PostRestoreUnlock(withoutCancel(ctx), s.lockOrderer, s.executor)
 
 	if err != nil {
 		return NewPostUnlockError(err.Error())
@@ -0,0 +1,29 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
+
+type PostRestoreValidateStep struct {
+	executor executor.Executor
+}
+
+func NewPostRestoreValidateStep(executor executor.Executor) Step {
+	return &PostRestoreValidateStep{
+		executor: executor,
+	}
+}
+
+// Run checks the restored data once the jobs have been unlocked. Its failures
+// are PostRestoreValidateErrors, as the restore itself has already completed.
+func (s *PostRestoreValidateStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().PostRestoreValidate(ctx, s.executor)
+
+	if err != nil {
+		return NewPostRestoreValidateError(err.Error())
+	}
+
+	return nil
+}
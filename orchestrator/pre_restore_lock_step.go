@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
 	"github.com/pkg/errors"
 )
@@ -17,8 +19,8 @@ func NewPreRestoreLockStep(lockOrderer LockOrderer, executor executor.Executor)
 	}
 }
 
-func (s *PreRestoreLockStep) Run(session *Session) error {
-	err := session.CurrentDeployment().PreRestoreLock(s.lockOrderer, s.executor)
+func (s *PreRestoreLockStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().PreRestoreLock(ctx, s.lockOrderer, s.executor)
 
 	if err != nil {
 		return errors.Wrap(err, "pre-restore-lock failed")
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -18,7 +19,7 @@ func NewRestorableStep(lockOrderer LockOrderer, logger Logger) Step {
 	}
 }
 
-func (s *RestorableStep) Run(session *Session) error {
+func (s *RestorableStep) Run(ctx context.Context, session *Session) error {
 
 	for _, instance := range session.CurrentDeployment().RestorableInstances() {
 		if instance.HasMetadataRestoreNames() {
@@ -32,13 +33,16 @@ func (s *RestorableStep) Run(session *Session) error {
 		return errors.Errorf("Deployment '%s' has no restore scripts", session.DeploymentName())
 	}
 
-	if match, err := session.CurrentArtifact().DeploymentMatches(session.DeploymentName(), session.CurrentDeployment().Instances()); err != nil {
-		return errors.Errorf("Unable to check if deployment '%s' matches the structure of the provided backup", session.DeploymentName())
+	var mismatch DeploymentMismatchError
+	if match, err := session.CurrentArtifact().DeploymentMatches(session.DeploymentName(), session.CurrentDeployment().Instances()); errors.As(err, &mismatch) {
+		return errors.Wrapf(err, "Deployment '%s' does not match the structure of the provided backup", session.DeploymentName())
+	} else if err != nil {
+		return errors.Wrapf(err, "Unable to check if deployment '%s' matches the structure of the provided backup", session.DeploymentName())
 	} else if match != true {
 		return errors.Errorf("Deployment '%s' does not match the structure of the provided backup", session.DeploymentName())
 	}
 
-	err := session.CurrentDeployment().CheckArtifactDir()
+	err := session.CurrentDeployment().CheckArtifactDir(ctx)
 	if err != nil {
 		return errors.Wrap(err, "Check artifact dir failed")
 	}
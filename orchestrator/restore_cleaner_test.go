@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
@@ -41,7 +42,8 @@ var _ = Describe("Restore Cleanup", func() {
 
 		It("finds the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
-			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+			_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
 		})
 
 		It("ensures that deployment is cleaned up", func() {
@@ -57,7 +59,7 @@ var _ = Describe("Restore Cleanup", func() {
 		var currentSequenceNumber, unlockCallIndex, cleanupCallIndex int
 		BeforeEach(func() {
 			deploymentManager.FindReturns(deployment, nil)
-			deployment.PostRestoreUnlockStub = func(orderer orchestrator.LockOrderer, _ executor.Executor) error {
+			deployment.PostRestoreUnlockStub = func(_ context.Context, orderer orchestrator.LockOrderer, _ executor.Executor) error {
 				unlockCallIndex = currentSequenceNumber
 				currentSequenceNumber = currentSequenceNumber + 1
 				return nil
@@ -83,7 +85,8 @@ var _ = Describe("Restore Cleanup", func() {
 
 		It("attempts to find the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
-			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+			_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
 		})
 
 		It("fails", func() {
@@ -0,0 +1,101 @@
+package orchestrator
+
+import "context"
+
+// RestoreSelection narrows a restore down to the restorable jobs of some
+// instance groups. An empty list of instance groups or jobs selects all of
+// them. Only the selected jobs are locked, unless LockDependents is set, in
+// which case the jobs that declare they should be locked before a selected job
+// are locked as well, without being restored.
+type RestoreSelection struct {
+	InstanceGroups []string
+	Jobs           []string
+	LockDependents bool
+}
+
+func (s RestoreSelection) IsEmpty() bool {
+	return len(s.InstanceGroups) == 0 && len(s.Jobs) == 0
+}
+
+func (s RestoreSelection) selectsInstanceGroup(name string) bool {
+	return len(s.InstanceGroups) == 0 || contains(s.InstanceGroups, name)
+}
+
+func (s RestoreSelection) selectsJob(name string) bool {
+	return len(s.Jobs) == 0 || contains(s.Jobs, name)
+}
+
+// selectedInstance only exposes the jobs of an instance that a restore
+// selection locks, and only restores the selected ones among those.
+type selectedInstance struct {
+	Instance
+	lockJobs    Jobs
+	restoreJobs Jobs
+}
+
+func (i selectedInstance) Jobs() []Job {
+	return i.lockJobs
+}
+
+func (i selectedInstance) IsRestorable() bool {
+	return i.restoreJobs.AnyAreRestorable()
+}
+
+func (i selectedInstance) HasMetadataRestoreNames() bool {
+	return i.restoreJobs.HasMetadataRestoreNames()
+}
+
+func (i selectedInstance) ArtifactsToRestore() []BackupArtifact {
+	names := map[string]bool{}
+	for _, job := range i.restoreJobs.Restorable() {
+		names[restoreArtifactName(job)] = true
+	}
+
+	artifacts := []BackupArtifact{}
+	for _, artifact := range i.Instance.ArtifactsToRestore() {
+		if names[artifact.Name()] {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	return artifacts
+}
+
+func (i selectedInstance) Restore(ctx context.Context) error {
+	var restoreErrors []error
+	for _, job := range i.restoreJobs {
+		if err := job.Restore(ctx); err != nil {
+			restoreErrors = append(restoreErrors, err)
+		}
+	}
+	return ConvertErrors(restoreErrors)
+}
+
+// restoredJobs returns the selected jobs of the instances that a restore
+// selection narrowed down, and every job of the other instances.
+func restoredJobs(instances []Instance) []Job {
+	var jobs []Job
+	for _, instance := range instances {
+		if selected, ok := instance.(selectedInstance); ok {
+			jobs = append(jobs, selected.restoreJobs...)
+		} else {
+			jobs = append(jobs, instance.Jobs()...)
+		}
+	}
+	return jobs
+}
+
+func restoreArtifactName(job Job) string {
+	if job.HasNamedRestoreArtifact() {
+		return job.RestoreArtifactName()
+	}
+	return job.Name()
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
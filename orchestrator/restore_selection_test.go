@@ -0,0 +1,188 @@
+package orchestrator_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator/fakes"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orderer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Selective restore", func() {
+	var (
+		selection         orchestrator.RestoreSelection
+		restoreError      orchestrator.Error
+		backupManager     *fakes.FakeBackupManager
+		backup            *fakes.FakeBackup
+		deploymentManager *fakes.FakeDeploymentManager
+		deployment        *fakes.FakeDeployment
+		artifactCopier    *fakes.FakeArtifactCopier
+		redisInstance     *fakes.FakeInstance
+		appInstance       *fakes.FakeInstance
+		redisJob          *fakes.FakeJob
+		syslogJob         *fakes.FakeJob
+		appJob            *fakes.FakeJob
+		appClientJob      *fakes.FakeJob
+		redisArtifact     *fakes.FakeBackupArtifact
+		appArtifact       *fakes.FakeBackupArtifact
+	)
+
+	newJob := func(name string, hasRestore bool, lockedBefore ...orchestrator.JobSpecifier) *fakes.FakeJob {
+		job := new(fakes.FakeJob)
+		job.NameReturns(name)
+		job.ReleaseReturns("release")
+		job.HasRestoreReturns(hasRestore)
+		job.RestoreShouldBeLockedBeforeReturns(lockedBefore)
+		return job
+	}
+
+	newInstance := func(name string, artifact *fakes.FakeBackupArtifact, jobs ...orchestrator.Job) *fakes.FakeInstance {
+		instance := new(fakes.FakeInstance)
+		instance.NameReturns(name)
+		instance.IndexReturns("0")
+		instance.IsRestorableReturns(true)
+		instance.JobsReturns(jobs)
+		instance.ArtifactsToRestoreReturns([]orchestrator.BackupArtifact{artifact})
+		return instance
+	}
+
+	newArtifact := func(instanceName, name string) *fakes.FakeBackupArtifact {
+		artifact := new(fakes.FakeBackupArtifact)
+		artifact.InstanceNameReturns(instanceName)
+		artifact.InstanceIndexReturns("0")
+		artifact.NameReturns(name)
+		return artifact
+	}
+
+	BeforeEach(func() {
+		redisJob = newJob("redis", true)
+		syslogJob = newJob("syslog", false)
+		appJob = newJob("app", true)
+		appClientJob = newJob("app-client", false, orchestrator.JobSpecifier{Name: "redis", Release: "release"})
+		redisArtifact = newArtifact("redis", "redis")
+		appArtifact = newArtifact("app", "app")
+		redisInstance = newInstance("redis", redisArtifact, redisJob, syslogJob)
+		appInstance = newInstance("app", appArtifact, appJob, appClientJob)
+
+		backupManager = new(fakes.FakeBackupManager)
+		backup = new(fakes.FakeBackup)
+		backupManager.OpenReturns(backup, nil)
+		backup.ValidReturns(true, nil)
+		backup.DeploymentMatchesReturns(true, nil)
+		backup.ArtifactDrainedReturns(true, nil)
+
+		deploymentManager = new(fakes.FakeDeploymentManager)
+		deployment = new(fakes.FakeDeployment)
+		deploymentManager.FindReturns(deployment, nil)
+		deployment.IsRestorableReturns(true)
+		deployment.InstancesReturns([]orchestrator.Instance{redisInstance, appInstance})
+		deployment.RestorableInstancesReturns([]orchestrator.Instance{redisInstance, appInstance})
+
+		artifactCopier = new(fakes.FakeArtifactCopier)
+		selection = orchestrator.RestoreSelection{InstanceGroups: []string{"redis"}}
+	})
+
+	JustBeforeEach(func() {
+		restorer := orchestrator.NewRestorer(backupManager, new(fakes.FakeLogger), deploymentManager,
+			orderer.NewKahnRestoreLockOrderer(), executor.NewSerialExecutor(), artifactCopier, selection, nil)
+		restoreError = restorer.Restore(context.Background(), "deployment", "/some/path")
+	})
+
+	It("only copies, locks, restores and unlocks the selected jobs", func() {
+		Expect(restoreError).NotTo(HaveOccurred())
+
+		_, _, selectedDeployment := artifactCopier.UploadBackupToDeploymentArgsForCall(0)
+		Expect(selectedDeployment.RestorableInstances()).To(HaveLen(1))
+		Expect(selectedDeployment.RestorableInstances()[0].ArtifactsToRestore()).To(ConsistOf(redisArtifact))
+
+		Expect(redisJob.PreRestoreLockCallCount()).To(Equal(1))
+		Expect(redisJob.RestoreCallCount()).To(Equal(1))
+		Expect(redisJob.PostRestoreUnlockCallCount()).To(Equal(1))
+		Expect(redisJob.PostRestoreValidateCallCount()).To(Equal(1))
+
+		for _, job := range []*fakes.FakeJob{syslogJob, appJob, appClientJob} {
+			Expect(job.PreRestoreLockCallCount()).To(Equal(0))
+			Expect(job.RestoreCallCount()).To(Equal(0))
+			Expect(job.PostRestoreUnlockCallCount()).To(Equal(0))
+			Expect(job.PostRestoreValidateCallCount()).To(Equal(0))
+		}
+		Expect(redisInstance.RestoreCallCount()).To(Equal(0))
+		Expect(appInstance.RestoreCallCount()).To(Equal(0))
+	})
+
+	It("still cleans up every instance", func() {
+		Expect(redisInstance.CleanupCallCount()).To(Equal(1))
+		Expect(appInstance.CleanupCallCount()).To(Equal(1))
+	})
+
+	Context("when the dependents of the selected jobs should be locked", func() {
+		BeforeEach(func() {
+			selection.LockDependents = true
+		})
+
+		It("locks them before the selected jobs without restoring them", func() {
+			Expect(restoreError).NotTo(HaveOccurred())
+			Expect(appClientJob.PreRestoreLockCallCount()).To(Equal(1))
+			Expect(appClientJob.PostRestoreUnlockCallCount()).To(Equal(1))
+			Expect(appClientJob.RestoreCallCount()).To(Equal(0))
+			Expect(appJob.PreRestoreLockCallCount()).To(Equal(0))
+		})
+
+		It("only validates the restored jobs", func() {
+			Expect(restoreError).NotTo(HaveOccurred())
+			Expect(redisJob.PostRestoreValidateCallCount()).To(Equal(1))
+			Expect(appClientJob.PostRestoreValidateCallCount()).To(Equal(0))
+		})
+
+		Context("and a job that was only locked fails to validate", func() {
+			BeforeEach(func() {
+				appClientJob.PostRestoreValidateReturns(fmt.Errorf("app-client is broken"))
+			})
+
+			It("does not fail the restore", func() {
+				Expect(restoreError).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Context("when selecting by job", func() {
+		BeforeEach(func() {
+			selection = orchestrator.RestoreSelection{Jobs: []string{"app"}}
+		})
+
+		It("restores the job on every instance group that has it", func() {
+			Expect(restoreError).NotTo(HaveOccurred())
+			Expect(appJob.RestoreCallCount()).To(Equal(1))
+			Expect(redisJob.RestoreCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the selection does not match the deployment", func() {
+		BeforeEach(func() {
+			selection = orchestrator.RestoreSelection{InstanceGroups: []string{"redis", "postgres"}, Jobs: []string{"syslog"}}
+		})
+
+		It("fails before copying anything", func() {
+			Expect(restoreError).To(MatchError(ContainSubstring("instance group 'postgres' has no selected jobs with restore scripts")))
+			Expect(restoreError).To(MatchError(ContainSubstring("job 'syslog' has no restore script")))
+			Expect(artifactCopier.UploadBackupToDeploymentCallCount()).To(Equal(0))
+			Expect(deployment.CleanupCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the backup does not contain the selected artifacts", func() {
+		BeforeEach(func() {
+			backup.ArtifactDrainedReturns(false, nil)
+		})
+
+		It("fails before copying anything", func() {
+			Expect(restoreError).To(MatchError(ContainSubstring("the backup does not contain the redis artifact of redis/0")))
+			Expect(artifactCopier.UploadBackupToDeploymentCallCount()).To(Equal(0))
+			Expect(redisJob.PreRestoreLockCallCount()).To(Equal(0))
+		})
+	})
+})
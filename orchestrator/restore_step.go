@@ -1,6 +1,10 @@
 package orchestrator
 
-import "github.com/pkg/errors"
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
 
 type RestoreStep struct {
 	logger Logger
@@ -10,8 +14,8 @@ func NewRestoreStep(logger Logger) Step {
 	return &RestoreStep{logger: logger}
 }
 
-func (s *RestoreStep) Run(session *Session) error {
-	err := session.CurrentDeployment().Restore()
+func (s *RestoreStep) Run(ctx context.Context, session *Session) error {
+	err := session.CurrentDeployment().Restore(ctx)
 
 	if err != nil {
 		return errors.Wrap(err, "Failed to restore")
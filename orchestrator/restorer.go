@@ -1,39 +1,52 @@
 package orchestrator
 
-import "github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/executor"
+)
 
 type Restorer struct {
 	workflow *Workflow
+	observer Observer
 }
 
 func NewRestorer(backupManager BackupManager, logger Logger, deploymentManager DeploymentManager,
-	lockOrderer LockOrderer, executor executor.Executor, artifactCopier ArtifactCopier) *Restorer {
+	lockOrderer LockOrderer, executor executor.Executor, artifactCopier ArtifactCopier, selection RestoreSelection, observer Observer) *Restorer {
 	workflow := NewWorkflow()
 	validateArtifactStep := NewValidateArtifactStep(logger, backupManager)
 	findDeploymentStep := NewFindDeploymentStep(deploymentManager, logger)
 	restorableStep := NewRestorableStep(lockOrderer, logger)
+	selectRestoreTargetsStep := NewSelectRestoreTargetsStep(selection, logger)
 	cleanupStep := NewCleanupStep()
 	copyToRemoteStep := NewCopyToRemoteStep(artifactCopier)
 	preRestoreLockStep := NewPreRestoreLockStep(lockOrderer, executor)
 	restoreStep := NewRestoreStep(logger)
 	postRestoreUnlockStep := NewPostRestoreUnlockStep(lockOrderer, executor)
+	unlockAfterFailedRestoreStep := NewPostRestoreUnlockStep(lockOrderer, executor)
+	postRestoreValidateStep := NewPostRestoreValidateStep(executor)
 
 	workflow.StartWith(validateArtifactStep).OnSuccess(findDeploymentStep)
 	workflow.Add(findDeploymentStep).OnSuccess(restorableStep)
-	workflow.Add(restorableStep).OnSuccess(copyToRemoteStep).OnFailure(cleanupStep)
+	workflow.Add(restorableStep).OnSuccess(selectRestoreTargetsStep).OnFailure(cleanupStep)
+	workflow.Add(selectRestoreTargetsStep).OnSuccess(copyToRemoteStep).OnFailure(cleanupStep)
 	workflow.Add(copyToRemoteStep).OnSuccess(preRestoreLockStep).OnFailure(cleanupStep)
-	workflow.Add(preRestoreLockStep).OnSuccess(restoreStep).OnFailure(postRestoreUnlockStep)
-	workflow.Add(restoreStep).OnSuccess(postRestoreUnlockStep).OnFailure(postRestoreUnlockStep)
-	workflow.Add(postRestoreUnlockStep).OnSuccessOrFailure(cleanupStep)
+	workflow.Add(preRestoreLockStep).OnSuccess(restoreStep).OnFailure(unlockAfterFailedRestoreStep)
+	workflow.Add(restoreStep).OnSuccess(postRestoreUnlockStep).OnFailure(unlockAfterFailedRestoreStep)
+	workflow.Add(postRestoreUnlockStep).OnSuccess(postRestoreValidateStep).OnFailure(cleanupStep)
+	workflow.Add(unlockAfterFailedRestoreStep).OnSuccessOrFailure(cleanupStep)
+	workflow.Add(postRestoreValidateStep).OnSuccessOrFailure(cleanupStep)
 	workflow.Add(cleanupStep)
 	return &Restorer{
 		workflow: workflow,
+		observer: observer,
 	}
 }
 
-func (r Restorer) Restore(deploymentName, backupPath string) Error {
+func (r Restorer) Restore(ctx context.Context, deploymentName, backupPath string) Error {
 	session := NewSession(deploymentName)
 	session.SetCurrentArtifactPath(backupPath)
+	session.SetObserver(r.observer)
 
-	return r.workflow.Run(session)
+	return r.workflow.Run(ctx, session)
 }
@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -27,6 +28,7 @@ var _ = Describe("restorer", func() {
 			artifactPath      string
 			lockOrderer       *fakes.FakeLockOrderer
 			artifactCopier    *fakes.FakeArtifactCopier
+			ctx               context.Context
 		)
 
 		BeforeEach(func() {
@@ -38,6 +40,7 @@ var _ = Describe("restorer", func() {
 			deployment = new(fakes.FakeDeployment)
 			lockOrderer = new(fakes.FakeLockOrderer)
 			artifactCopier = new(fakes.FakeArtifactCopier)
+			ctx = context.Background()
 
 			artifactManager.OpenReturns(artifact, nil)
 			deploymentManager.FindReturns(deployment, nil)
@@ -46,14 +49,14 @@ var _ = Describe("restorer", func() {
 			artifact.DeploymentMatchesReturns(true, nil)
 			artifact.ValidReturns(true, nil)
 
-			b = orchestrator.NewRestorer(artifactManager, logger, deploymentManager, lockOrderer, executor.NewSerialExecutor(), artifactCopier)
+			b = orchestrator.NewRestorer(artifactManager, logger, deploymentManager, lockOrderer, executor.NewSerialExecutor(), artifactCopier, orchestrator.RestoreSelection{}, nil)
 
 			deploymentName = "deployment-to-restore"
 			artifactPath = "/some/path"
 		})
 
 		JustBeforeEach(func() {
-			restoreError = b.Restore(deploymentName, artifactPath)
+			restoreError = b.Restore(ctx, deploymentName, artifactPath)
 		})
 
 		It("does not fail", func() {
@@ -76,7 +79,8 @@ var _ = Describe("restorer", func() {
 
 		It("finds the deployment", func() {
 			Expect(deploymentManager.FindCallCount()).To(Equal(1))
-			Expect(deploymentManager.FindArgsForCall(0)).To(Equal(deploymentName))
+			_, actualDeploymentName := deploymentManager.FindArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
 		})
 
 		It("checks if the deployment is restorable", func() {
@@ -95,7 +99,7 @@ var _ = Describe("restorer", func() {
 		It("streams the local backup to the deployment", func() {
 			Expect(artifactCopier.UploadBackupToDeploymentCallCount()).To(Equal(1))
 
-			uploadedArtifact, uploadedToDeployment := artifactCopier.UploadBackupToDeploymentArgsForCall(0)
+			_, uploadedArtifact, uploadedToDeployment := artifactCopier.UploadBackupToDeploymentArgsForCall(0)
 			Expect(uploadedArtifact).To(Equal(artifact))
 			Expect(uploadedToDeployment).To(Equal(deployment))
 		})
@@ -112,6 +116,44 @@ var _ = Describe("restorer", func() {
 			Expect(deployment.PostRestoreUnlockCallCount()).To(Equal(1))
 		})
 
+		Context("when the restore has an observer", func() {
+			var observer *fakes.FakeObserver
+
+			BeforeEach(func() {
+				observer = new(fakes.FakeObserver)
+				b = orchestrator.NewRestorer(artifactManager, logger, deploymentManager, lockOrderer, executor.NewSerialExecutor(), artifactCopier, orchestrator.RestoreSelection{}, observer)
+
+				deployment.PostRestoreUnlockStub = func(ctx context.Context, _ orchestrator.LockOrderer, _ executor.Executor) error {
+					orchestrator.NotifyObserver(ctx, orchestrator.JobScriptStarted{Instance: "redis/0", Job: "redis-server", Script: "post-restore-unlock"})
+					orchestrator.NotifyObserver(ctx, orchestrator.JobScriptFinished{Instance: "redis/0", Job: "redis-server", Script: "post-restore-unlock"})
+					return nil
+				}
+			})
+
+			It("passes the events of the unlock scripts on to it", func() {
+				var scriptEvents []orchestrator.Event
+				for i := 0; i < observer.NotifyCallCount(); i++ {
+					notifiedDeploymentName, event := observer.NotifyArgsForCall(i)
+					switch event.(type) {
+					case orchestrator.JobScriptStarted, orchestrator.JobScriptFinished:
+						Expect(notifiedDeploymentName).To(Equal(deploymentName))
+						scriptEvents = append(scriptEvents, event)
+					}
+				}
+
+				Expect(scriptEvents).To(Equal([]orchestrator.Event{
+					orchestrator.JobScriptStarted{Instance: "redis/0", Job: "redis-server", Script: "post-restore-unlock"},
+					orchestrator.JobScriptFinished{Instance: "redis/0", Job: "redis-server", Script: "post-restore-unlock"},
+				}))
+			})
+		})
+
+		It("validates the restore once the deployment has been unlocked", func() {
+			Expect(deployment.PostRestoreValidateCallCount()).To(Equal(1))
+			validateCtx, _ := deployment.PostRestoreValidateArgsForCall(0)
+			Expect(validateCtx).To(Equal(ctx))
+		})
+
 		Describe("failures", func() {
 
 			var assertCleanupError = func() {
@@ -157,6 +199,25 @@ var _ = Describe("restorer", func() {
 				})
 			})
 
+			Context("fails if the backup signature cannot be verified", func() {
+				BeforeEach(func() {
+					deploymentManager.FindReturns(deployment, nil)
+					artifactManager.OpenReturns(artifact, nil)
+					artifact.ValidReturns(true, nil)
+					artifact.VerifySignatureReturns(errors.New("the metadata signature does not match the trusted key"))
+				})
+
+				It("returns an error", func() {
+					Expect(restoreError).To(MatchError(ContainSubstring("Could not verify the backup signature")))
+					Expect(restoreError).To(MatchError(ContainSubstring("does not match the trusted key")))
+				})
+
+				It("does not restore the deployment", func() {
+					Expect(artifact.ValidCallCount()).To(BeZero())
+					Expect(deployment.RestoreCallCount()).To(BeZero())
+				})
+			})
+
 			Context("fails, if the cleanup fails", func() {
 				var cleanupError = fmt.Errorf("still too dirty")
 				BeforeEach(func() {
@@ -222,6 +283,22 @@ var _ = Describe("restorer", func() {
 				assertCleanupError()
 			})
 
+			Context("if the backup has instances that the deployment does not", func() {
+				BeforeEach(func() {
+					artifact.DeploymentMatchesReturns(false, orchestrator.NewDeploymentMismatchError(
+						"the backup has instances that are not in deployment 'deployment-to-restore'",
+						[]string{"broker/0", "worker/0"},
+					))
+				})
+
+				It("returns an error listing them", func() {
+					Expect(restoreError).To(MatchError(ContainSubstring(
+						"Deployment 'deployment-to-restore' does not match the structure of the provided backup: " +
+							"the backup has instances that are not in deployment 'deployment-to-restore': broker/0, worker/0",
+					)))
+				})
+			})
+
 			Context("if checking the deployment topology fails", func() {
 				BeforeEach(func() {
 					artifact.DeploymentMatchesReturns(true, fmt.Errorf("I am not the same"))
@@ -285,6 +362,9 @@ var _ = Describe("restorer", func() {
 					Expect(deployment.PostRestoreUnlockCallCount()).To(Equal(1))
 				})
 
+				It("does not validate the restore", func() {
+					Expect(deployment.PostRestoreValidateCallCount()).To(BeZero())
+				})
 			})
 
 			Context("if post-restore-unlock fails", func() {
@@ -301,6 +381,28 @@ var _ = Describe("restorer", func() {
 				It("should cleanup", func() {
 					Expect(deployment.CleanupCallCount()).To(Equal(1))
 				})
+
+				It("does not validate the restore", func() {
+					Expect(deployment.PostRestoreValidateCallCount()).To(BeZero())
+				})
+			})
+
+			Context("if post-restore-validate fails", func() {
+				BeforeEach(func() {
+					deployment.PostRestoreValidateReturns(fmt.Errorf("the data came back broken"))
+				})
+
+				It("returns a post-restore-validate error", func() {
+					Expect(restoreError).To(MatchError(ContainSubstring("the data came back broken")))
+					Expect(restoreError).To(HaveLen(1))
+					Expect(restoreError.IsPostRestoreValidation()).To(BeTrue())
+				})
+
+				It("should cleanup", func() {
+					Expect(deployment.CleanupCallCount()).To(Equal(1))
+				})
+
+				Context("cleanup fails as well", assertCleanupError)
 			})
 
 			Context("if running the restore script fails", func() {
@@ -320,6 +422,10 @@ var _ = Describe("restorer", func() {
 					Expect(deployment.PostRestoreUnlockCallCount()).To(Equal(1))
 				})
 
+				It("does not validate the restore", func() {
+					Expect(deployment.PostRestoreValidateCallCount()).To(BeZero())
+				})
+
 				Context("if post-restore unlock fails", func() {
 					BeforeEach(func() {
 						deployment.PostRestoreUnlockReturns(fmt.Errorf("I will not restart this thing"))
@@ -335,6 +441,36 @@ var _ = Describe("restorer", func() {
 
 				assertCleanupError()
 			})
+
+			Context("if the restore is interrupted", func() {
+				BeforeEach(func() {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithCancel(context.Background())
+					deployment.RestoreStub = func(restoreCtx context.Context) error {
+						cancel()
+						return restoreCtx.Err()
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(restoreError).To(MatchError(ContainSubstring("context canceled")))
+				})
+
+				It("calls post-restore-unlock with a context that is not cancelled", func() {
+					Expect(deployment.PostRestoreUnlockCallCount()).To(Equal(1))
+					unlockCtx, _, _ := deployment.PostRestoreUnlockArgsForCall(0)
+					Expect(unlockCtx.Err()).NotTo(HaveOccurred())
+					Expect(unlockCtx.Done()).To(BeNil())
+				})
+
+				It("should cleanup", func() {
+					Expect(deployment.CleanupCallCount()).To(Equal(1))
+				})
+
+				It("does not validate the restore", func() {
+					Expect(deployment.PostRestoreValidateCallCount()).To(BeZero())
+				})
+			})
 		})
 	})
 })
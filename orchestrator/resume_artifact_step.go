@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type ResumeArtifactStep struct {
+	logger        Logger
+	backupManager BackupManager
+}
+
+func NewResumeArtifactStep(logger Logger, backupManager BackupManager) Step {
+	return &ResumeArtifactStep{logger: logger, backupManager: backupManager}
+}
+
+func (s *ResumeArtifactStep) Run(ctx context.Context, session *Session) error {
+	s.logger.Info("bbr", "Resuming backup of %s...\n", session.DeploymentName())
+
+	backup, err := s.backupManager.Open(session.CurrentArtifactPath(), s.logger)
+	if err != nil {
+		return errors.Wrap(err, "Could not open backup")
+	}
+
+	deployment := session.CurrentDeployment()
+	var mismatch DeploymentMismatchError
+	match, err := backup.DeploymentMatches(session.DeploymentName(), deployment.Instances())
+	if errors.As(err, &mismatch) {
+		return errors.Wrapf(err, "Backup does not match the instances of deployment '%s'", session.DeploymentName())
+	} else if err != nil {
+		return errors.Wrap(err, "Could not check the backup against the deployment")
+	} else if !match {
+		return errors.Errorf("Backup does not match the instances of deployment '%s'", session.DeploymentName())
+	}
+
+	var missing []string
+	for _, instance := range deployment.BackupableInstances() {
+		exists, err := instance.ArtifactDirExists(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "Could not check for the artifacts left on instance %s/%s", instance.Name(), instance.ID())
+		} else if !exists {
+			missing = append(missing, fmt.Sprintf("%s/%s", instance.Name(), instance.ID()))
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("The artifacts of the interrupted backup are no longer on instances %s, run a new backup instead", strings.Join(missing, ", "))
+	}
+
+	if err := backup.MarkResumed(); err != nil {
+		return errors.Wrap(err, "Could not resume backup")
+	}
+	session.SetCurrentArtifact(backup)
+
+	for _, instance := range deployment.BackupableInstances() {
+		instance.MarkArtifactDirCreated()
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+type SelectRestoreTargetsStep struct {
+	selection RestoreSelection
+	logger    Logger
+}
+
+func NewSelectRestoreTargetsStep(selection RestoreSelection, logger Logger) Step {
+	return &SelectRestoreTargetsStep{selection: selection, logger: logger}
+}
+
+// jobPosition identifies a job by the position of its instance in the
+// deployment and its own position on the instance, as jobs are not comparable.
+type jobPosition struct {
+	instance, job int
+}
+
+func (s *SelectRestoreTargetsStep) Run(ctx context.Context, session *Session) error {
+	if s.selection.IsEmpty() {
+		return nil
+	}
+
+	deployment := session.CurrentDeployment()
+	allInstances := deployment.Instances()
+
+	restore := map[jobPosition]bool{}
+	matchedGroups, matchedJobs := map[string]bool{}, map[string]bool{}
+	for i, instance := range allInstances {
+		if !instance.IsRestorable() || !s.selection.selectsInstanceGroup(instance.Name()) {
+			continue
+		}
+		for j, job := range instance.Jobs() {
+			if job.HasRestore() && s.selection.selectsJob(job.Name()) {
+				restore[jobPosition{i, j}] = true
+				matchedGroups[instance.Name()] = true
+				matchedJobs[job.Name()] = true
+			}
+		}
+	}
+
+	var selectionErrors []error
+	for _, group := range s.selection.InstanceGroups {
+		if !matchedGroups[group] {
+			selectionErrors = append(selectionErrors, errors.Errorf("instance group '%s' has no selected jobs with restore scripts in deployment '%s'", group, session.DeploymentName()))
+		}
+	}
+	for _, job := range s.selection.Jobs {
+		if !matchedJobs[job] {
+			selectionErrors = append(selectionErrors, errors.Errorf("job '%s' has no restore script on the selected instance groups of deployment '%s'", job, session.DeploymentName()))
+		}
+	}
+	if len(selectionErrors) > 0 {
+		return ConvertErrors(selectionErrors)
+	}
+
+	lock := map[jobPosition]bool{}
+	for position := range restore {
+		lock[position] = true
+	}
+	if s.selection.LockDependents {
+		addDependents(allInstances, lock)
+	}
+
+	var selected []Instance
+	for i, instance := range allInstances {
+		selectedInstance := selectedInstance{Instance: instance, lockJobs: Jobs{}, restoreJobs: Jobs{}}
+		for j, job := range instance.Jobs() {
+			if lock[jobPosition{i, j}] {
+				selectedInstance.lockJobs = append(selectedInstance.lockJobs, job)
+			}
+			if restore[jobPosition{i, j}] {
+				selectedInstance.restoreJobs = append(selectedInstance.restoreJobs, job)
+			}
+		}
+		selected = append(selected, selectedInstance)
+	}
+
+	selectedDeployment := NewDeployment(s.logger, selected)
+	if err := checkBackupContainsSelection(session.CurrentArtifact(), selectedDeployment); err != nil {
+		return err
+	}
+
+	for _, instance := range selected {
+		for _, job := range instance.(selectedInstance).restoreJobs {
+			s.logger.Info("bbr", "Selected %s on %s/%s for restore", job.Name(), instance.Name(), instance.Index())
+		}
+	}
+
+	session.SetCurrentDeployment(selectedDeployment)
+	return nil
+}
+
+// addDependents adds the jobs that should be locked before any of the locked
+// jobs, until there are no more to add.
+func addDependents(instances []Instance, lock map[jobPosition]bool) {
+	for added := true; added; {
+		added = false
+		for i, instance := range instances {
+			for j, job := range instance.Jobs() {
+				if lock[jobPosition{i, j}] || !shouldBeLockedBeforeAny(job, instances, lock) {
+					continue
+				}
+				lock[jobPosition{i, j}] = true
+				added = true
+			}
+		}
+	}
+}
+
+func shouldBeLockedBeforeAny(job Job, instances []Instance, lock map[jobPosition]bool) bool {
+	for _, specifier := range job.RestoreShouldBeLockedBefore() {
+		for i, instance := range instances {
+			for j, lockedJob := range instance.Jobs() {
+				if lock[jobPosition{i, j}] && lockedJob.Name() == specifier.Name && lockedJob.Release() == specifier.Release {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func checkBackupContainsSelection(backup Backup, deployment Deployment) error {
+	var missing []error
+	for _, instance := range deployment.RestorableInstances() {
+		for _, artifact := range instance.ArtifactsToRestore() {
+			drained, err := backup.ArtifactDrained(artifact)
+			if err != nil {
+				return errors.Wrap(err, "Unable to check the backup contains the selected jobs")
+			}
+			if !drained {
+				missing = append(missing, errors.Errorf("the backup does not contain the %s artifact of %s/%s", artifact.Name(), instance.Name(), instance.Index()))
+			}
+		}
+	}
+	return ConvertErrors(missing)
+}
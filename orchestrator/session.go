@@ -5,6 +5,9 @@ type Session struct {
 	deployment          Deployment
 	currentArtifact     Backup
 	currentArtifactPath string
+	plan                *Plan
+	observer            Observer
+	verifyDrained       bool
 }
 
 func NewSession(deploymentName string) *Session {
@@ -38,3 +41,27 @@ func (session *Session) SetCurrentArtifactPath(artifactPath string) {
 func (session *Session) CurrentArtifactPath() string {
 	return session.currentArtifactPath
 }
+
+func (session *Session) SetPlan(plan *Plan) {
+	session.plan = plan
+}
+
+func (session *Session) Plan() *Plan {
+	return session.plan
+}
+
+func (session *Session) SetObserver(observer Observer) {
+	session.observer = observer
+}
+
+func (session *Session) Observer() Observer {
+	return session.observer
+}
+
+func (session *Session) SetVerifyDrained(verifyDrained bool) {
+	session.verifyDrained = verifyDrained
+}
+
+func (session *Session) VerifyDrained() bool {
+	return session.verifyDrained
+}
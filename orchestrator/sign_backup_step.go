@@ -0,0 +1,17 @@
+package orchestrator
+
+import "context"
+
+type SignBackupStep struct{}
+
+func NewSignBackupStep() Step {
+	return &SignBackupStep{}
+}
+
+func (s *SignBackupStep) Run(ctx context.Context, session *Session) error {
+	if session.CurrentArtifact() != nil {
+		return session.CurrentArtifact().Sign()
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// UploadPreviousChecksumsStep gives the backup scripts of an incremental backup
+// the checksums of the previous backup of their artifacts. It does nothing for
+// full backups.
+type UploadPreviousChecksumsStep struct {
+	logger Logger
+}
+
+func NewUploadPreviousChecksumsStep(logger Logger) Step {
+	return &UploadPreviousChecksumsStep{logger: logger}
+}
+
+func (s *UploadPreviousChecksumsStep) Run(ctx context.Context, session *Session) error {
+	backup := session.CurrentArtifact()
+
+	for _, instance := range session.CurrentDeployment().BackupableInstances() {
+		for _, artifact := range instance.ArtifactsToBackup() {
+			checksum, err := backup.FetchPreviousChecksum(artifact)
+			if err != nil {
+				return errors.Wrap(err, "Could not read the previous backup")
+			}
+			if checksum == nil {
+				continue
+			}
+
+			s.logger.Debug("bbr", "Uploading checksums of the previous backup of %s on %s/%s", artifact.Name(), instance.Name(), instance.ID())
+			instance.MarkArtifactDirCreated()
+			if err := artifact.UploadPreviousChecksums(checksum); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 )
 
@@ -13,7 +15,7 @@ type ValidateArtifactStep struct {
 	backupManager BackupManager
 }
 
-func (s *ValidateArtifactStep) Run(session *Session) error {
+func (s *ValidateArtifactStep) Run(ctx context.Context, session *Session) error {
 	s.logger.Info("bbr", "Starting restore of %s...\n", session.deploymentName)
 	backup, err := s.backupManager.Open(session.CurrentArtifactPath(), s.logger)
 	if err != nil {
@@ -21,6 +23,10 @@ func (s *ValidateArtifactStep) Run(session *Session) error {
 	}
 	session.SetCurrentArtifact(backup)
 
+	if err := backup.VerifySignature(); err != nil {
+		return errors.Wrap(err, "Could not verify the backup signature")
+	}
+
 	s.logger.Info("bbr", "Validating backup artifact for %s...\n", session.deploymentName)
 	if valid, err := backup.Valid(); err != nil {
 		return errors.Wrap(err, "Could not validate backup")
@@ -1,5 +1,12 @@
 package orchestrator
 
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
 type Workflow struct {
 	StartingNode *Node
 	Nodes        []*Node
@@ -9,14 +16,36 @@ func NewWorkflow() *Workflow {
 	return &Workflow{}
 }
 
-func (workflow *Workflow) Run(session *Session) Error {
+// Run runs the steps from the starting node, following the failure branches once
+// a step fails. Once the context is cancelled, the failure branches are followed
+// even when steps succeed, so that an interrupted workflow still unlocks and
+// cleans up; the steps on those branches do not pass the cancellation on.
+//
+// When the session has an observer, it is told when each step starts and how
+// it ended, and the steps can notify it of their progress through the context.
+func (workflow *Workflow) Run(ctx context.Context, session *Session) Error {
 	var errs Error
 	currentNode := workflow.StartingNode
+	interrupted := false
+
+	if session.Observer() != nil {
+		ctx = ContextWithObserver(ctx, session.DeploymentName(), session.Observer())
+	}
 
 	for currentNode != nil {
-		err := currentNode.step.Run(session)
+		err := workflow.runStep(ctx, session, currentNode.step)
+		if ctx.Err() != nil && !interrupted {
+			interrupted = true
+			if err == nil {
+				err = errors.Wrap(ctx.Err(), "interrupted")
+			}
+		}
+
 		if err != nil {
 			errs = append(errs, err)
+		}
+
+		if err != nil || interrupted {
 			currentNode = workflow.findNode(currentNode.failStep)
 		} else {
 			currentNode = workflow.findNode(currentNode.successStep)
@@ -26,6 +55,21 @@ func (workflow *Workflow) Run(session *Session) Error {
 	return errs
 }
 
+func (workflow *Workflow) runStep(ctx context.Context, session *Session, step Step) error {
+	name := stepName(step)
+	NotifyObserver(ctx, StepStarted{Step: name})
+	startedAt := time.Now()
+
+	err := step.Run(ctx, session)
+
+	if err != nil {
+		NotifyObserver(ctx, StepFailed{Step: name, Duration: time.Since(startedAt), Error: err.Error()})
+	} else {
+		NotifyObserver(ctx, StepSucceeded{Step: name, Duration: time.Since(startedAt)})
+	}
+	return err
+}
+
 func (workflow *Workflow) findNode(step Step) *Node {
 	if step == nil {
 		return nil
@@ -52,7 +96,7 @@ func (workflow *Workflow) StartWith(step Step) *Node {
 }
 
 type Step interface {
-	Run(*Session) error
+	Run(context.Context, *Session) error
 }
 
 type Node struct {
@@ -77,3 +121,25 @@ func (node *Node) OnSuccess(successStep Step) *Node {
 	node.successStep = successStep
 	return node
 }
+
+// uncancelledContext keeps the values of a workflow's context, such as its
+// observer, for the steps that still have to finish once it is cancelled.
+type uncancelledContext struct {
+	context.Context
+}
+
+func withoutCancel(ctx context.Context) context.Context {
+	return uncancelledContext{Context: ctx}
+}
+
+func (uncancelledContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (uncancelledContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (uncancelledContext) Err() error {
+	return nil
+}
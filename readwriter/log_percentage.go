@@ -11,6 +11,7 @@ type LogPercentage struct {
 	message             string
 	lastLogPercentage   int
 	percentageIncrement int
+	progress            func(transferred, total int)
 }
 
 type LogPercentageWriter struct {
@@ -81,12 +82,26 @@ func (lr *LogPercentageReader) Read(b []byte) (int, error) {
 	return n, nil
 }
 
+// OnProgress registers a function that is called with the number of bytes
+// transferred so far each time the percentage is logged.
+func (l *LogPercentage) OnProgress(progress func(transferred, total int)) {
+	l.progress = progress
+}
+
 func (l *LogPercentage) logPercentage(n, b int) {
 	percentageWrittenSoFar := (100 * b) / l.totalSize
 	if b > l.totalSize {
 		l.logger.Info(l.command, l.message, 100)
+		l.reportProgress(b)
 	} else if percentageWrittenSoFar >= l.lastLogPercentage+l.percentageIncrement {
 		l.lastLogPercentage = percentageWrittenSoFar
 		l.logger.Info(l.command, l.message, percentageWrittenSoFar)
+		l.reportProgress(b)
+	}
+}
+
+func (l *LogPercentage) reportProgress(transferred int) {
+	if l.progress != nil {
+		l.progress(transferred, l.totalSize)
 	}
 }
\ No newline at end of file
@@ -64,6 +64,29 @@ var _ = Describe("LogPercentageReadWriter", func() {
 				Expect(args[0]).To(Equal(100))
 			})
 		})
+		Context("when progress is being reported", func() {
+			var transferred, totals []int
+
+			BeforeEach(func() {
+				transferred, totals = nil, nil
+				fakeReadWriter.WriteReturns(1, nil)
+				logPercentageReadWriter = readwriter.NewLogPercentageWriter(fakeReadWriter, fakeLogger, 100, "schblam", "message")
+				logPercentageReadWriter.OnProgress(func(t, total int) {
+					transferred = append(transferred, t)
+					totals = append(totals, total)
+				})
+			})
+
+			It("reports the bytes transferred each time the percentage is logged", func() {
+				for i := 0; i < 10; i++ {
+					logPercentageReadWriter.Write([]byte("add 1 byte"))
+				}
+
+				Expect(fakeLogger.InfoCallCount()).To(Equal(2))
+				Expect(transferred).To(Equal([]int{5, 10}))
+				Expect(totals).To(Equal([]int{100, 100}))
+			})
+		})
 		Context("when writing a really big file", func() {
 			BeforeEach(func() {
 				fakeReadWriter.WriteReturns(1, nil)
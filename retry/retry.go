@@ -0,0 +1,142 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+type ErrorClass string
+
+const (
+	// ConnectionErrors are dropped or refused connections to an instance.
+	ConnectionErrors ErrorClass = "connection"
+	// ChecksumErrors are copied artifacts that do not match their checksum.
+	ChecksumErrors ErrorClass = "checksum"
+)
+
+var errorClasses = []ErrorClass{ConnectionErrors, ChecksumErrors}
+
+type Logger interface {
+	Warn(tag, msg string, args ...interface{})
+}
+
+// Policy says how many times to attempt an operation, how long to wait before
+// the first retry, and which classes of error are worth retrying. The wait is
+// doubled for each retry after the first. The zero Policy makes a single
+// attempt.
+type Policy struct {
+	Attempts     int
+	Backoff      time.Duration
+	ErrorClasses []ErrorClass
+}
+
+func NewPolicy(attempts int, backoff time.Duration, errorClassNames []string) (Policy, error) {
+	if attempts < 1 {
+		return Policy{}, errors.Errorf("retry attempts must be at least 1, got %d", attempts)
+	}
+	if backoff < 0 {
+		return Policy{}, errors.Errorf("retry backoff must not be negative, got %s", backoff)
+	}
+
+	var classes []ErrorClass
+	for _, name := range errorClassNames {
+		class := ErrorClass(name)
+		if !isKnownErrorClass(class) {
+			return Policy{}, errors.Errorf("unknown retry error class %s, must be one of %v", name, errorClasses)
+		}
+		classes = append(classes, class)
+	}
+
+	return Policy{Attempts: attempts, Backoff: backoff, ErrorClasses: classes}, nil
+}
+
+// Do calls attempt until it succeeds, fails with an error that is not
+// transient, or the attempts run out. Each retry is logged with the
+// description of the operation. It stops retrying once ctx is done.
+func (p Policy) Do(ctx context.Context, logger Logger, description string, attempt func() error) error {
+	backoff := p.Backoff
+	for attemptNumber := 1; ; attemptNumber++ {
+		err := attempt()
+		if err == nil || attemptNumber >= p.Attempts || !p.IsTransient(err) || ctx.Err() != nil {
+			return err
+		}
+
+		logger.Warn("bbr", "%s failed on attempt %d of %d, retrying in %s: %s", description, attemptNumber, p.Attempts, backoff, err.Error())
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (p Policy) IsTransient(err error) bool {
+	for _, class := range p.ErrorClasses {
+		if class.matches(err) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c ErrorClass) matches(err error) bool {
+	switch c {
+	case ConnectionErrors:
+		return isConnectionError(err)
+	case ChecksumErrors:
+		var checksumErr ChecksumError
+		return errors.As(err, &checksumErr)
+	default:
+		return false
+	}
+}
+
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var exitMissingErr *ssh.ExitMissingError
+	return errors.As(err, &exitMissingErr)
+}
+
+func isKnownErrorClass(class ErrorClass) bool {
+	for _, known := range errorClasses {
+		if known == class {
+			return true
+		}
+	}
+	return false
+}
+
+// ChecksumError marks an error as caused by a copied artifact not matching
+// its checksum, so that it is retried when ChecksumErrors are transient.
+type ChecksumError struct {
+	error
+}
+
+func NewChecksumError(err error) error {
+	return ChecksumError{err}
+}
+
+func (err ChecksumError) Unwrap() error {
+	return err.error
+}
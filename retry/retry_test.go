@@ -0,0 +1,169 @@
+package retry_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Policy", func() {
+	var (
+		policy    retry.Policy
+		logOutput *gbytes.Buffer
+		logger    boshlog.Logger
+		attempts  int
+		failures  []error
+		ctx       context.Context
+		err       error
+	)
+
+	BeforeEach(func() {
+		policy = retry.Policy{
+			Attempts:     3,
+			Backoff:      time.Millisecond,
+			ErrorClasses: []retry.ErrorClass{retry.ConnectionErrors},
+		}
+		logOutput = gbytes.NewBuffer()
+		logger = boshlog.New(boshlog.LevelDebug, log.New(logOutput, "[retry-test] ", log.Lshortfile))
+		attempts = 0
+		failures = nil
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		err = policy.Do(ctx, logger, "copying the artifact", func() error {
+			attempts++
+			if len(failures) == 0 {
+				return nil
+			}
+			failure := failures[0]
+			failures = failures[1:]
+			return failure
+		})
+	})
+
+	Context("when the first attempt succeeds", func() {
+		It("does not retry", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal(1))
+		})
+	})
+
+	Context("when an attempt fails with a transient error", func() {
+		BeforeEach(func() {
+			failures = []error{errors.Wrap(io.EOF, "ssh.Stream failed")}
+		})
+
+		It("retries and logs the retry", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal(2))
+			Expect(string(logOutput.Contents())).To(ContainSubstring(
+				"WARN - copying the artifact failed on attempt 1 of 3, retrying in 1ms: ssh.Stream failed: EOF",
+			))
+		})
+	})
+
+	Context("when every attempt fails with a transient error", func() {
+		BeforeEach(func() {
+			failures = []error{io.EOF, io.EOF, io.EOF, io.EOF}
+		})
+
+		It("gives up after the configured number of attempts", func() {
+			Expect(err).To(MatchError(io.EOF))
+			Expect(attempts).To(Equal(3))
+		})
+	})
+
+	Context("when an attempt fails with an error that is not transient", func() {
+		BeforeEach(func() {
+			failures = []error{errors.New("exit code 1")}
+		})
+
+		It("does not retry", func() {
+			Expect(err).To(MatchError("exit code 1"))
+			Expect(attempts).To(Equal(1))
+		})
+	})
+
+	Context("when an attempt fails with a checksum error", func() {
+		BeforeEach(func() {
+			failures = []error{retry.NewChecksumError(errors.New("checksums don't match"))}
+		})
+
+		It("does not retry it by default", func() {
+			Expect(err).To(MatchError("checksums don't match"))
+			Expect(attempts).To(Equal(1))
+		})
+
+		Context("and checksum errors are transient", func() {
+			BeforeEach(func() {
+				policy.ErrorClasses = []retry.ErrorClass{retry.ChecksumErrors}
+			})
+
+			It("retries", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(attempts).To(Equal(2))
+			})
+		})
+	})
+
+	Context("when the context is cancelled", func() {
+		BeforeEach(func() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(context.Background())
+			cancel()
+			failures = []error{io.EOF}
+		})
+
+		It("does not retry", func() {
+			Expect(err).To(MatchError(io.EOF))
+			Expect(attempts).To(Equal(1))
+		})
+	})
+
+	Context("with the zero policy", func() {
+		BeforeEach(func() {
+			policy = retry.Policy{}
+			failures = []error{io.EOF}
+		})
+
+		It("makes a single attempt", func() {
+			Expect(err).To(MatchError(io.EOF))
+			Expect(attempts).To(Equal(1))
+		})
+	})
+})
+
+var _ = Describe("NewPolicy", func() {
+	It("builds a policy", func() {
+		policy, err := retry.NewPolicy(3, time.Second, []string{"connection", "checksum"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(retry.Policy{
+			Attempts:     3,
+			Backoff:      time.Second,
+			ErrorClasses: []retry.ErrorClass{retry.ConnectionErrors, retry.ChecksumErrors},
+		}))
+	})
+
+	It("rejects unknown error classes", func() {
+		_, err := retry.NewPolicy(3, time.Second, []string{"everything"})
+
+		Expect(err).To(MatchError("unknown retry error class everything, must be one of [connection checksum]"))
+	})
+
+	It("rejects fewer than one attempt", func() {
+		_, err := retry.NewPolicy(0, time.Second, nil)
+
+		Expect(err).To(MatchError("retry attempts must be at least 1, got 0"))
+	})
+})
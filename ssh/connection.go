@@ -22,9 +22,9 @@ import (
 
 //go:generate counterfeiter -o fakes/fake_ssh_connection.go . SSHConnection
 type SSHConnection interface {
-	Stream(cmd string, writer io.Writer) ([]byte, int, error)
-	StreamStdin(cmd string, reader io.Reader) ([]byte, []byte, int, error)
-	Run(cmd string) ([]byte, []byte, int, error)
+	Stream(ctx context.Context, cmd string, writer io.Writer) ([]byte, int, error)
+	StreamStdin(ctx context.Context, cmd string, reader io.Reader) ([]byte, []byte, int, error)
+	Run(ctx context.Context, cmd string) ([]byte, []byte, int, error)
 	Username() string
 }
 
@@ -72,27 +72,27 @@ type Connection struct {
 	dialFunc            boshhttp.DialContextFunc
 }
 
-func (c Connection) Run(cmd string) (stdout, stderr []byte, exitCode int, err error) {
+func (c Connection) Run(ctx context.Context, cmd string) (stdout, stderr []byte, exitCode int, err error) {
 	stdoutBuffer := bytes.NewBuffer([]byte{})
 
-	stderr, exitCode, err = c.Stream(cmd, stdoutBuffer)
+	stderr, exitCode, err = c.Stream(ctx, cmd, stdoutBuffer)
 
 	return stdoutBuffer.Bytes(), stderr, exitCode, errors.Wrap(err, "ssh.Run failed")
 }
 
-func (c Connection) Stream(cmd string, stdoutWriter io.Writer) (stderr []byte, exitCode int, err error) {
+func (c Connection) Stream(ctx context.Context, cmd string, stdoutWriter io.Writer) (stderr []byte, exitCode int, err error) {
 	errBuffer := bytes.NewBuffer([]byte{})
 
-	exitCode, err = c.runInSession(cmd, stdoutWriter, errBuffer, nil)
+	exitCode, err = c.runInSession(ctx, cmd, stdoutWriter, errBuffer, nil)
 
 	return errBuffer.Bytes(), exitCode, errors.Wrap(err, "ssh.Stream failed")
 }
 
-func (c Connection) StreamStdin(cmd string, stdinReader io.Reader) (stdout, stderr []byte, exitCode int, err error) {
+func (c Connection) StreamStdin(ctx context.Context, cmd string, stdinReader io.Reader) (stdout, stderr []byte, exitCode int, err error) {
 	stdoutBuffer := bytes.NewBuffer([]byte{})
 	stderrBuffer := bytes.NewBuffer([]byte{})
 
-	exitCode, err = c.runInSession(cmd, stdoutBuffer, stderrBuffer, stdinReader)
+	exitCode, err = c.runInSession(ctx, cmd, stdoutBuffer, stderrBuffer, stdinReader)
 
 	return stdoutBuffer.Bytes(), stderrBuffer.Bytes(), exitCode, errors.Wrap(err, "ssh.StreamStdin failed")
 }
@@ -112,8 +112,8 @@ func (w *sessionClosingOnErrorWriter) Write(data []byte) (int, error) {
 	return n, err
 }
 
-func (c Connection) newClient() (*ssh.Client, error) {
-	conn, err := c.dialFunc(context.Background(), "tcp", c.host)
+func (c Connection) newClient(ctx context.Context) (*ssh.Client, error) {
+	conn, err := c.dialFunc(ctx, "tcp", c.host)
 	if err != nil {
 		return nil, err
 	}
@@ -143,8 +143,12 @@ func createDialContextFunc() boshhttp.DialContextFunc {
 	return dialFunc
 }
 
-func (c Connection) runInSession(cmd string, stdout, stderr io.Writer, stdin io.Reader) (int, error) {
-	client, err := c.newClient()
+func (c Connection) runInSession(ctx context.Context, cmd string, stdout, stderr io.Writer, stdin io.Reader) (int, error) {
+	if ctx.Err() != nil {
+		return -1, errors.Wrap(ctx.Err(), "ssh.Session.Run aborted")
+	}
+
+	client, err := c.newClient(ctx)
 	if err != nil {
 		return -1, errors.Wrap(err, "ssh.Dial failed")
 	}
@@ -159,6 +163,9 @@ func (c Connection) runInSession(cmd string, stdout, stderr io.Writer, stdin io.
 	stopKeepAliveLoop := c.startKeepAliveLoop(session)
 	defer close(stopKeepAliveLoop)
 
+	stopCancelWatch := c.startCancelWatch(ctx, session)
+	defer close(stopCancelWatch)
+
 	stdoutWrappingWriter := &sessionClosingOnErrorWriter{endGameWriter: stdout, sshSession: session}
 
 	session.Stdin = stdin
@@ -168,6 +175,9 @@ func (c Connection) runInSession(cmd string, stdout, stderr io.Writer, stdin io.
 	var exitCode int
 
 	err = session.Run(cmd)
+	if ctx.Err() != nil {
+		return -1, errors.Wrap(ctx.Err(), "ssh.Session.Run aborted")
+	}
 
 	if err == nil && stdoutWrappingWriter.writerError == nil {
 		exitCode = 0
@@ -203,6 +213,25 @@ func (c Connection) startKeepAliveLoop(session *ssh.Session) chan struct{} {
 	return terminate
 }
 
+// startCancelWatch aborts the command when the context is cancelled. The remote
+// process is sent SIGTERM, which not every SSH server passes on, and then the
+// session is closed so that the command returns straight away.
+func (c Connection) startCancelWatch(ctx context.Context, session *ssh.Session) chan struct{} {
+	terminate := make(chan struct{})
+	go func() {
+		select {
+		case <-terminate:
+		case <-ctx.Done():
+			c.logger.Debug("ssh", "aborting the remote command: %s", ctx.Err())
+			if err := session.Signal(ssh.SIGTERM); err != nil {
+				c.logger.Debug("ssh", "signalling the remote command failed: %+v", err)
+			}
+			session.Close()
+		}
+	}()
+	return terminate
+}
+
 func (c Connection) Username() string {
 	return c.sshConfig.User
 }
@@ -2,6 +2,7 @@ package ssh_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"io"
@@ -80,7 +81,7 @@ var _ = Describe("Connection", func() {
 
 			JustBeforeEach(func() {
 				Expect(connErr).NotTo(HaveOccurred())
-				stdOut, stdErr, exitCode, runError = conn.StreamStdin(command, reader)
+				stdOut, stdErr, exitCode, runError = conn.StreamStdin(context.Background(), command, reader)
 			})
 
 			BeforeEach(func() {
@@ -93,7 +94,7 @@ var _ = Describe("Connection", func() {
 			})
 
 			It("reads stdout from the reader", func() {
-				stdout, _, _, _ := conn.Run("cat /tmp/foo")
+				stdout, _, _, _ := conn.Run(context.Background(), "cat /tmp/foo")
 				Expect(string(stdout)).To(Equal("I am from the reader"))
 			})
 
@@ -117,7 +118,7 @@ var _ = Describe("Connection", func() {
 			var command string
 			JustBeforeEach(func() {
 				Expect(connErr).NotTo(HaveOccurred())
-				stdErr, exitCode, runError = conn.Stream(command, stdout)
+				stdErr, exitCode, runError = conn.Stream(context.Background(), command, stdout)
 			})
 			Context("success", func() {
 				BeforeEach(func() {
@@ -149,11 +150,13 @@ var _ = Describe("Connection", func() {
 			var exitCode int
 			var runError error
 			var command string
+			var ctx context.Context
 			JustBeforeEach(func() {
 				Expect(connErr).NotTo(HaveOccurred())
-				stdOut, stdErr, exitCode, runError = conn.Run(command)
+				stdOut, stdErr, exitCode, runError = conn.Run(ctx, command)
 			})
 			BeforeEach(func() {
+				ctx = context.Background()
 				command = "/tmp/foo"
 				instance1.CreateScript(command, `#!/usr/bin/env sh
 				echo "stdout"
@@ -178,9 +181,9 @@ var _ = Describe("Connection", func() {
 			Context("running multiple commands", func() {
 
 				It("does not fail", func() {
-					_, _, _, runError1 := conn.Run("ls")
-					_, _, _, runError2 := conn.Run("ls")
-					_, _, _, runError3 := conn.Run("ls")
+					_, _, _, runError1 := conn.Run(context.Background(), "ls")
+					_, _, _, runError2 := conn.Run(context.Background(), "ls")
+					_, _, _, runError3 := conn.Run(context.Background(), "ls")
 
 					Expect(runError1).NotTo(HaveOccurred())
 					Expect(runError2).NotTo(HaveOccurred())
@@ -211,6 +214,20 @@ var _ = Describe("Connection", func() {
 					Expect(exitCode).To(Equal(127))
 				})
 			})
+
+			Context("when the context is cancelled while the command runs", func() {
+				BeforeEach(func() {
+					instance1.CreateScript(command, `#!/usr/bin/env sh
+				sleep 60`)
+
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithCancel(context.Background())
+					time.AfterFunc(time.Second, cancel)
+				})
+				It("aborts the command", func() {
+					Expect(errors.Is(runError, context.Canceled)).To(BeTrue())
+				})
+			})
 		})
 	})
 
@@ -224,7 +241,7 @@ var _ = Describe("Connection", func() {
 
 			Context("Run", func() {
 				JustBeforeEach(func() {
-					_, _, _, err = conn.Run("ls")
+					_, _, _, err = conn.Run(context.Background(), "ls")
 				})
 
 				It("fails", func() {
@@ -234,7 +251,7 @@ var _ = Describe("Connection", func() {
 
 			Context("Stream", func() {
 				JustBeforeEach(func() {
-					_, _, err = conn.Stream("ls", bytes.NewBufferString("dont matter"))
+					_, _, err = conn.Stream(context.Background(), "ls", bytes.NewBufferString("dont matter"))
 				})
 
 				It("fails", func() {
@@ -244,7 +261,7 @@ var _ = Describe("Connection", func() {
 
 			Context("StreamStdin", func() {
 				JustBeforeEach(func() {
-					_, _, _, err = conn.StreamStdin("ls", bytes.NewBufferString("dont matter"))
+					_, _, _, err = conn.StreamStdin(context.Background(), "ls", bytes.NewBufferString("dont matter"))
 				})
 
 				It("fails", func() {
@@ -262,7 +279,7 @@ var _ = Describe("Connection", func() {
 
 			Context("Run", func() {
 				JustBeforeEach(func() {
-					_, _, _, err = conn.Run("ls")
+					_, _, _, err = conn.Run(context.Background(), "ls")
 				})
 
 				It("fails", func() {
@@ -272,7 +289,7 @@ var _ = Describe("Connection", func() {
 
 			Context("Stream", func() {
 				JustBeforeEach(func() {
-					_, _, err = conn.Stream("ls", bytes.NewBufferString("dont matter"))
+					_, _, err = conn.Stream(context.Background(), "ls", bytes.NewBufferString("dont matter"))
 				})
 
 				It("fails", func() {
@@ -282,7 +299,7 @@ var _ = Describe("Connection", func() {
 
 			Context("StreamStdin", func() {
 				JustBeforeEach(func() {
-					_, _, _, err = conn.StreamStdin("ls", bytes.NewBufferString("dont matter"))
+					_, _, _, err = conn.StreamStdin(context.Background(), "ls", bytes.NewBufferString("dont matter"))
 				})
 
 				It("fails", func() {
@@ -305,7 +322,7 @@ var _ = Describe("Connection", func() {
 			Expect(connErr).NotTo(HaveOccurred())
 
 			numGoRoutinesBeforeRun := runtime.NumGoroutine()
-			stdOut, _, _, _ = conn.Run("/tmp/produce")
+			stdOut, _, _, _ = conn.Run(context.Background(), "/tmp/produce")
 			Eventually(func() int {
 				return runtime.NumGoroutine()
 			}, 10).Should(Equal(numGoRoutinesBeforeRun))
@@ -338,7 +355,7 @@ var _ = Describe("Connection", func() {
 				rapidKeepAliveSignalInterval,
 				logger)
 			Expect(connErr).NotTo(HaveOccurred())
-			stdErr, _, runError = conn.Stream(command, stdout)
+			stdErr, _, runError = conn.Stream(context.Background(), command, stdout)
 		})
 
 		It("does not hang forever", func() {
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"io"
 	"sync"
 
@@ -9,11 +10,12 @@ import (
 )
 
 type FakeRemoteRunner struct {
-	ArchiveAndDownloadStub        func(string, io.Writer) error
+	ArchiveAndDownloadStub        func(context.Context, string, io.Writer) error
 	archiveAndDownloadMutex       sync.RWMutex
 	archiveAndDownloadArgsForCall []struct {
-		arg1 string
-		arg2 io.Writer
+		arg1 context.Context
+		arg2 string
+		arg3 io.Writer
 	}
 	archiveAndDownloadReturns struct {
 		result1 error
@@ -21,10 +23,25 @@ type FakeRemoteRunner struct {
 	archiveAndDownloadReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ChecksumDirectoryStub        func(string) (map[string]string, error)
+	ArchiveAndDownloadFilesStub        func(context.Context, string, []string, io.Writer) error
+	archiveAndDownloadFilesMutex       sync.RWMutex
+	archiveAndDownloadFilesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 io.Writer
+	}
+	archiveAndDownloadFilesReturns struct {
+		result1 error
+	}
+	archiveAndDownloadFilesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ChecksumDirectoryStub        func(context.Context, string) (map[string]string, error)
 	checksumDirectoryMutex       sync.RWMutex
 	checksumDirectoryArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	checksumDirectoryReturns struct {
 		result1 map[string]string
@@ -55,10 +72,11 @@ type FakeRemoteRunner struct {
 	createDirectoryReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DirectoryExistsStub        func(string) (bool, error)
+	DirectoryExistsStub        func(context.Context, string) (bool, error)
 	directoryExistsMutex       sync.RWMutex
 	directoryExistsArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	directoryExistsReturns struct {
 		result1 bool
@@ -68,11 +86,12 @@ type FakeRemoteRunner struct {
 		result1 bool
 		result2 error
 	}
-	ExtractAndUploadStub        func(io.Reader, string) error
+	ExtractAndUploadStub        func(context.Context, io.Reader, string) error
 	extractAndUploadMutex       sync.RWMutex
 	extractAndUploadArgsForCall []struct {
-		arg1 io.Reader
-		arg2 string
+		arg1 context.Context
+		arg2 io.Reader
+		arg3 string
 	}
 	extractAndUploadReturns struct {
 		result1 error
@@ -80,10 +99,11 @@ type FakeRemoteRunner struct {
 	extractAndUploadReturnsOnCall map[int]struct {
 		result1 error
 	}
-	FindFilesStub        func(string) ([]string, error)
+	FindFilesStub        func(context.Context, string) ([]string, error)
 	findFilesMutex       sync.RWMutex
 	findFilesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	findFilesReturns struct {
 		result1 []string
@@ -116,11 +136,12 @@ type FakeRemoteRunner struct {
 	removeDirectoryReturnsOnCall map[int]struct {
 		result1 error
 	}
-	RunScriptStub        func(string, string) (string, error)
+	RunScriptStub        func(context.Context, string, string) (string, error)
 	runScriptMutex       sync.RWMutex
 	runScriptArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	runScriptReturns struct {
 		result1 string
@@ -130,12 +151,13 @@ type FakeRemoteRunner struct {
 		result1 string
 		result2 error
 	}
-	RunScriptWithEnvStub        func(string, map[string]string, string) (string, error)
+	RunScriptWithEnvStub        func(context.Context, string, map[string]string, string) (string, error)
 	runScriptWithEnvMutex       sync.RWMutex
 	runScriptWithEnvArgsForCall []struct {
-		arg1 string
-		arg2 map[string]string
-		arg3 string
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+		arg4 string
 	}
 	runScriptWithEnvReturns struct {
 		result1 string
@@ -145,10 +167,11 @@ type FakeRemoteRunner struct {
 		result1 string
 		result2 error
 	}
-	SizeInBytesStub        func(string) (int, error)
+	SizeInBytesStub        func(context.Context, string) (int, error)
 	sizeInBytesMutex       sync.RWMutex
 	sizeInBytesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	sizeInBytesReturns struct {
 		result1 int
@@ -158,10 +181,11 @@ type FakeRemoteRunner struct {
 		result1 int
 		result2 error
 	}
-	SizeOfStub        func(string) (string, error)
+	SizeOfStub        func(context.Context, string) (string, error)
 	sizeOfMutex       sync.RWMutex
 	sizeOfArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	sizeOfReturns struct {
 		result1 string
@@ -171,21 +195,34 @@ type FakeRemoteRunner struct {
 		result1 string
 		result2 error
 	}
+	WriteFileStub        func(string, []byte) error
+	writeFileMutex       sync.RWMutex
+	writeFileArgsForCall []struct {
+		arg1 string
+		arg2 []byte
+	}
+	writeFileReturns struct {
+		result1 error
+	}
+	writeFileReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeRemoteRunner) ArchiveAndDownload(arg1 string, arg2 io.Writer) error {
+func (fake *FakeRemoteRunner) ArchiveAndDownload(arg1 context.Context, arg2 string, arg3 io.Writer) error {
 	fake.archiveAndDownloadMutex.Lock()
 	ret, specificReturn := fake.archiveAndDownloadReturnsOnCall[len(fake.archiveAndDownloadArgsForCall)]
 	fake.archiveAndDownloadArgsForCall = append(fake.archiveAndDownloadArgsForCall, struct {
-		arg1 string
-		arg2 io.Writer
-	}{arg1, arg2})
-	fake.recordInvocation("ArchiveAndDownload", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 io.Writer
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ArchiveAndDownload", []interface{}{arg1, arg2, arg3})
 	fake.archiveAndDownloadMutex.Unlock()
 	if fake.ArchiveAndDownloadStub != nil {
-		return fake.ArchiveAndDownloadStub(arg1, arg2)
+		return fake.ArchiveAndDownloadStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -200,17 +237,17 @@ func (fake *FakeRemoteRunner) ArchiveAndDownloadCallCount() int {
 	return len(fake.archiveAndDownloadArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) ArchiveAndDownloadCalls(stub func(string, io.Writer) error) {
+func (fake *FakeRemoteRunner) ArchiveAndDownloadCalls(stub func(context.Context, string, io.Writer) error) {
 	fake.archiveAndDownloadMutex.Lock()
 	defer fake.archiveAndDownloadMutex.Unlock()
 	fake.ArchiveAndDownloadStub = stub
 }
 
-func (fake *FakeRemoteRunner) ArchiveAndDownloadArgsForCall(i int) (string, io.Writer) {
+func (fake *FakeRemoteRunner) ArchiveAndDownloadArgsForCall(i int) (context.Context, string, io.Writer) {
 	fake.archiveAndDownloadMutex.RLock()
 	defer fake.archiveAndDownloadMutex.RUnlock()
 	argsForCall := fake.archiveAndDownloadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeRemoteRunner) ArchiveAndDownloadReturns(result1 error) {
@@ -236,16 +273,85 @@ func (fake *FakeRemoteRunner) ArchiveAndDownloadReturnsOnCall(i int, result1 err
 	}{result1}
 }
 
-func (fake *FakeRemoteRunner) ChecksumDirectory(arg1 string) (map[string]string, error) {
+func (fake *FakeRemoteRunner) ArchiveAndDownloadFiles(arg1 context.Context, arg2 string, arg3 []string, arg4 io.Writer) error {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.archiveAndDownloadFilesMutex.Lock()
+	ret, specificReturn := fake.archiveAndDownloadFilesReturnsOnCall[len(fake.archiveAndDownloadFilesArgsForCall)]
+	fake.archiveAndDownloadFilesArgsForCall = append(fake.archiveAndDownloadFilesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 io.Writer
+	}{arg1, arg2, arg3Copy, arg4})
+	fake.recordInvocation("ArchiveAndDownloadFiles", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.archiveAndDownloadFilesMutex.Unlock()
+	if fake.ArchiveAndDownloadFilesStub != nil {
+		return fake.ArchiveAndDownloadFilesStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.archiveAndDownloadFilesReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeRemoteRunner) ArchiveAndDownloadFilesCallCount() int {
+	fake.archiveAndDownloadFilesMutex.RLock()
+	defer fake.archiveAndDownloadFilesMutex.RUnlock()
+	return len(fake.archiveAndDownloadFilesArgsForCall)
+}
+
+func (fake *FakeRemoteRunner) ArchiveAndDownloadFilesCalls(stub func(context.Context, string, []string, io.Writer) error) {
+	fake.archiveAndDownloadFilesMutex.Lock()
+	defer fake.archiveAndDownloadFilesMutex.Unlock()
+	fake.ArchiveAndDownloadFilesStub = stub
+}
+
+func (fake *FakeRemoteRunner) ArchiveAndDownloadFilesArgsForCall(i int) (context.Context, string, []string, io.Writer) {
+	fake.archiveAndDownloadFilesMutex.RLock()
+	defer fake.archiveAndDownloadFilesMutex.RUnlock()
+	argsForCall := fake.archiveAndDownloadFilesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRemoteRunner) ArchiveAndDownloadFilesReturns(result1 error) {
+	fake.archiveAndDownloadFilesMutex.Lock()
+	defer fake.archiveAndDownloadFilesMutex.Unlock()
+	fake.ArchiveAndDownloadFilesStub = nil
+	fake.archiveAndDownloadFilesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRemoteRunner) ArchiveAndDownloadFilesReturnsOnCall(i int, result1 error) {
+	fake.archiveAndDownloadFilesMutex.Lock()
+	defer fake.archiveAndDownloadFilesMutex.Unlock()
+	fake.ArchiveAndDownloadFilesStub = nil
+	if fake.archiveAndDownloadFilesReturnsOnCall == nil {
+		fake.archiveAndDownloadFilesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.archiveAndDownloadFilesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRemoteRunner) ChecksumDirectory(arg1 context.Context, arg2 string) (map[string]string, error) {
 	fake.checksumDirectoryMutex.Lock()
 	ret, specificReturn := fake.checksumDirectoryReturnsOnCall[len(fake.checksumDirectoryArgsForCall)]
 	fake.checksumDirectoryArgsForCall = append(fake.checksumDirectoryArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("ChecksumDirectory", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("ChecksumDirectory", []interface{}{arg1, arg2})
 	fake.checksumDirectoryMutex.Unlock()
 	if fake.ChecksumDirectoryStub != nil {
-		return fake.ChecksumDirectoryStub(arg1)
+		return fake.ChecksumDirectoryStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -260,17 +366,17 @@ func (fake *FakeRemoteRunner) ChecksumDirectoryCallCount() int {
 	return len(fake.checksumDirectoryArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) ChecksumDirectoryCalls(stub func(string) (map[string]string, error)) {
+func (fake *FakeRemoteRunner) ChecksumDirectoryCalls(stub func(context.Context, string) (map[string]string, error)) {
 	fake.checksumDirectoryMutex.Lock()
 	defer fake.checksumDirectoryMutex.Unlock()
 	fake.ChecksumDirectoryStub = stub
 }
 
-func (fake *FakeRemoteRunner) ChecksumDirectoryArgsForCall(i int) string {
+func (fake *FakeRemoteRunner) ChecksumDirectoryArgsForCall(i int) (context.Context, string) {
 	fake.checksumDirectoryMutex.RLock()
 	defer fake.checksumDirectoryMutex.RUnlock()
 	argsForCall := fake.checksumDirectoryArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeRemoteRunner) ChecksumDirectoryReturns(result1 map[string]string, result2 error) {
@@ -411,16 +517,17 @@ func (fake *FakeRemoteRunner) CreateDirectoryReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeRemoteRunner) DirectoryExists(arg1 string) (bool, error) {
+func (fake *FakeRemoteRunner) DirectoryExists(arg1 context.Context, arg2 string) (bool, error) {
 	fake.directoryExistsMutex.Lock()
 	ret, specificReturn := fake.directoryExistsReturnsOnCall[len(fake.directoryExistsArgsForCall)]
 	fake.directoryExistsArgsForCall = append(fake.directoryExistsArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("DirectoryExists", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("DirectoryExists", []interface{}{arg1, arg2})
 	fake.directoryExistsMutex.Unlock()
 	if fake.DirectoryExistsStub != nil {
-		return fake.DirectoryExistsStub(arg1)
+		return fake.DirectoryExistsStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -435,17 +542,17 @@ func (fake *FakeRemoteRunner) DirectoryExistsCallCount() int {
 	return len(fake.directoryExistsArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) DirectoryExistsCalls(stub func(string) (bool, error)) {
+func (fake *FakeRemoteRunner) DirectoryExistsCalls(stub func(context.Context, string) (bool, error)) {
 	fake.directoryExistsMutex.Lock()
 	defer fake.directoryExistsMutex.Unlock()
 	fake.DirectoryExistsStub = stub
 }
 
-func (fake *FakeRemoteRunner) DirectoryExistsArgsForCall(i int) string {
+func (fake *FakeRemoteRunner) DirectoryExistsArgsForCall(i int) (context.Context, string) {
 	fake.directoryExistsMutex.RLock()
 	defer fake.directoryExistsMutex.RUnlock()
 	argsForCall := fake.directoryExistsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeRemoteRunner) DirectoryExistsReturns(result1 bool, result2 error) {
@@ -474,17 +581,18 @@ func (fake *FakeRemoteRunner) DirectoryExistsReturnsOnCall(i int, result1 bool,
 	}{result1, result2}
 }
 
-func (fake *FakeRemoteRunner) ExtractAndUpload(arg1 io.Reader, arg2 string) error {
+func (fake *FakeRemoteRunner) ExtractAndUpload(arg1 context.Context, arg2 io.Reader, arg3 string) error {
 	fake.extractAndUploadMutex.Lock()
 	ret, specificReturn := fake.extractAndUploadReturnsOnCall[len(fake.extractAndUploadArgsForCall)]
 	fake.extractAndUploadArgsForCall = append(fake.extractAndUploadArgsForCall, struct {
-		arg1 io.Reader
-		arg2 string
-	}{arg1, arg2})
-	fake.recordInvocation("ExtractAndUpload", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 io.Reader
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ExtractAndUpload", []interface{}{arg1, arg2, arg3})
 	fake.extractAndUploadMutex.Unlock()
 	if fake.ExtractAndUploadStub != nil {
-		return fake.ExtractAndUploadStub(arg1, arg2)
+		return fake.ExtractAndUploadStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -499,17 +607,17 @@ func (fake *FakeRemoteRunner) ExtractAndUploadCallCount() int {
 	return len(fake.extractAndUploadArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) ExtractAndUploadCalls(stub func(io.Reader, string) error) {
+func (fake *FakeRemoteRunner) ExtractAndUploadCalls(stub func(context.Context, io.Reader, string) error) {
 	fake.extractAndUploadMutex.Lock()
 	defer fake.extractAndUploadMutex.Unlock()
 	fake.ExtractAndUploadStub = stub
 }
 
-func (fake *FakeRemoteRunner) ExtractAndUploadArgsForCall(i int) (io.Reader, string) {
+func (fake *FakeRemoteRunner) ExtractAndUploadArgsForCall(i int) (context.Context, io.Reader, string) {
 	fake.extractAndUploadMutex.RLock()
 	defer fake.extractAndUploadMutex.RUnlock()
 	argsForCall := fake.extractAndUploadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeRemoteRunner) ExtractAndUploadReturns(result1 error) {
@@ -535,16 +643,17 @@ func (fake *FakeRemoteRunner) ExtractAndUploadReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
-func (fake *FakeRemoteRunner) FindFiles(arg1 string) ([]string, error) {
+func (fake *FakeRemoteRunner) FindFiles(arg1 context.Context, arg2 string) ([]string, error) {
 	fake.findFilesMutex.Lock()
 	ret, specificReturn := fake.findFilesReturnsOnCall[len(fake.findFilesArgsForCall)]
 	fake.findFilesArgsForCall = append(fake.findFilesArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("FindFiles", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("FindFiles", []interface{}{arg1, arg2})
 	fake.findFilesMutex.Unlock()
 	if fake.FindFilesStub != nil {
-		return fake.FindFilesStub(arg1)
+		return fake.FindFilesStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -559,17 +668,17 @@ func (fake *FakeRemoteRunner) FindFilesCallCount() int {
 	return len(fake.findFilesArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) FindFilesCalls(stub func(string) ([]string, error)) {
+func (fake *FakeRemoteRunner) FindFilesCalls(stub func(context.Context, string) ([]string, error)) {
 	fake.findFilesMutex.Lock()
 	defer fake.findFilesMutex.Unlock()
 	fake.FindFilesStub = stub
 }
 
-func (fake *FakeRemoteRunner) FindFilesArgsForCall(i int) string {
+func (fake *FakeRemoteRunner) FindFilesArgsForCall(i int) (context.Context, string) {
 	fake.findFilesMutex.RLock()
 	defer fake.findFilesMutex.RUnlock()
 	argsForCall := fake.findFilesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeRemoteRunner) FindFilesReturns(result1 []string, result2 error) {
@@ -713,17 +822,18 @@ func (fake *FakeRemoteRunner) RemoveDirectoryReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeRemoteRunner) RunScript(arg1 string, arg2 string) (string, error) {
+func (fake *FakeRemoteRunner) RunScript(arg1 context.Context, arg2 string, arg3 string) (string, error) {
 	fake.runScriptMutex.Lock()
 	ret, specificReturn := fake.runScriptReturnsOnCall[len(fake.runScriptArgsForCall)]
 	fake.runScriptArgsForCall = append(fake.runScriptArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
-	fake.recordInvocation("RunScript", []interface{}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("RunScript", []interface{}{arg1, arg2, arg3})
 	fake.runScriptMutex.Unlock()
 	if fake.RunScriptStub != nil {
-		return fake.RunScriptStub(arg1, arg2)
+		return fake.RunScriptStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -738,17 +848,17 @@ func (fake *FakeRemoteRunner) RunScriptCallCount() int {
 	return len(fake.runScriptArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) RunScriptCalls(stub func(string, string) (string, error)) {
+func (fake *FakeRemoteRunner) RunScriptCalls(stub func(context.Context, string, string) (string, error)) {
 	fake.runScriptMutex.Lock()
 	defer fake.runScriptMutex.Unlock()
 	fake.RunScriptStub = stub
 }
 
-func (fake *FakeRemoteRunner) RunScriptArgsForCall(i int) (string, string) {
+func (fake *FakeRemoteRunner) RunScriptArgsForCall(i int) (context.Context, string, string) {
 	fake.runScriptMutex.RLock()
 	defer fake.runScriptMutex.RUnlock()
 	argsForCall := fake.runScriptArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeRemoteRunner) RunScriptReturns(result1 string, result2 error) {
@@ -777,18 +887,19 @@ func (fake *FakeRemoteRunner) RunScriptReturnsOnCall(i int, result1 string, resu
 	}{result1, result2}
 }
 
-func (fake *FakeRemoteRunner) RunScriptWithEnv(arg1 string, arg2 map[string]string, arg3 string) (string, error) {
+func (fake *FakeRemoteRunner) RunScriptWithEnv(arg1 context.Context, arg2 string, arg3 map[string]string, arg4 string) (string, error) {
 	fake.runScriptWithEnvMutex.Lock()
 	ret, specificReturn := fake.runScriptWithEnvReturnsOnCall[len(fake.runScriptWithEnvArgsForCall)]
 	fake.runScriptWithEnvArgsForCall = append(fake.runScriptWithEnvArgsForCall, struct {
-		arg1 string
-		arg2 map[string]string
-		arg3 string
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("RunScriptWithEnv", []interface{}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("RunScriptWithEnv", []interface{}{arg1, arg2, arg3, arg4})
 	fake.runScriptWithEnvMutex.Unlock()
 	if fake.RunScriptWithEnvStub != nil {
-		return fake.RunScriptWithEnvStub(arg1, arg2, arg3)
+		return fake.RunScriptWithEnvStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -803,17 +914,17 @@ func (fake *FakeRemoteRunner) RunScriptWithEnvCallCount() int {
 	return len(fake.runScriptWithEnvArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) RunScriptWithEnvCalls(stub func(string, map[string]string, string) (string, error)) {
+func (fake *FakeRemoteRunner) RunScriptWithEnvCalls(stub func(context.Context, string, map[string]string, string) (string, error)) {
 	fake.runScriptWithEnvMutex.Lock()
 	defer fake.runScriptWithEnvMutex.Unlock()
 	fake.RunScriptWithEnvStub = stub
 }
 
-func (fake *FakeRemoteRunner) RunScriptWithEnvArgsForCall(i int) (string, map[string]string, string) {
+func (fake *FakeRemoteRunner) RunScriptWithEnvArgsForCall(i int) (context.Context, string, map[string]string, string) {
 	fake.runScriptWithEnvMutex.RLock()
 	defer fake.runScriptWithEnvMutex.RUnlock()
 	argsForCall := fake.runScriptWithEnvArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeRemoteRunner) RunScriptWithEnvReturns(result1 string, result2 error) {
@@ -842,16 +953,17 @@ func (fake *FakeRemoteRunner) RunScriptWithEnvReturnsOnCall(i int, result1 strin
 	}{result1, result2}
 }
 
-func (fake *FakeRemoteRunner) SizeInBytes(arg1 string) (int, error) {
+func (fake *FakeRemoteRunner) SizeInBytes(arg1 context.Context, arg2 string) (int, error) {
 	fake.sizeInBytesMutex.Lock()
 	ret, specificReturn := fake.sizeInBytesReturnsOnCall[len(fake.sizeInBytesArgsForCall)]
 	fake.sizeInBytesArgsForCall = append(fake.sizeInBytesArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("SizeInBytes", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("SizeInBytes", []interface{}{arg1, arg2})
 	fake.sizeInBytesMutex.Unlock()
 	if fake.SizeInBytesStub != nil {
-		return fake.SizeInBytesStub(arg1)
+		return fake.SizeInBytesStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -866,17 +978,17 @@ func (fake *FakeRemoteRunner) SizeInBytesCallCount() int {
 	return len(fake.sizeInBytesArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) SizeInBytesCalls(stub func(string) (int, error)) {
+func (fake *FakeRemoteRunner) SizeInBytesCalls(stub func(context.Context, string) (int, error)) {
 	fake.sizeInBytesMutex.Lock()
 	defer fake.sizeInBytesMutex.Unlock()
 	fake.SizeInBytesStub = stub
 }
 
-func (fake *FakeRemoteRunner) SizeInBytesArgsForCall(i int) string {
+func (fake *FakeRemoteRunner) SizeInBytesArgsForCall(i int) (context.Context, string) {
 	fake.sizeInBytesMutex.RLock()
 	defer fake.sizeInBytesMutex.RUnlock()
 	argsForCall := fake.sizeInBytesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeRemoteRunner) SizeInBytesReturns(result1 int, result2 error) {
@@ -905,16 +1017,17 @@ func (fake *FakeRemoteRunner) SizeInBytesReturnsOnCall(i int, result1 int, resul
 	}{result1, result2}
 }
 
-func (fake *FakeRemoteRunner) SizeOf(arg1 string) (string, error) {
+func (fake *FakeRemoteRunner) SizeOf(arg1 context.Context, arg2 string) (string, error) {
 	fake.sizeOfMutex.Lock()
 	ret, specificReturn := fake.sizeOfReturnsOnCall[len(fake.sizeOfArgsForCall)]
 	fake.sizeOfArgsForCall = append(fake.sizeOfArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("SizeOf", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("SizeOf", []interface{}{arg1, arg2})
 	fake.sizeOfMutex.Unlock()
 	if fake.SizeOfStub != nil {
-		return fake.SizeOfStub(arg1)
+		return fake.SizeOfStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -929,17 +1042,17 @@ func (fake *FakeRemoteRunner) SizeOfCallCount() int {
 	return len(fake.sizeOfArgsForCall)
 }
 
-func (fake *FakeRemoteRunner) SizeOfCalls(stub func(string) (string, error)) {
+func (fake *FakeRemoteRunner) SizeOfCalls(stub func(context.Context, string) (string, error)) {
 	fake.sizeOfMutex.Lock()
 	defer fake.sizeOfMutex.Unlock()
 	fake.SizeOfStub = stub
 }
 
-func (fake *FakeRemoteRunner) SizeOfArgsForCall(i int) string {
+func (fake *FakeRemoteRunner) SizeOfArgsForCall(i int) (context.Context, string) {
 	fake.sizeOfMutex.RLock()
 	defer fake.sizeOfMutex.RUnlock()
 	argsForCall := fake.sizeOfArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeRemoteRunner) SizeOfReturns(result1 string, result2 error) {
@@ -968,11 +1081,79 @@ func (fake *FakeRemoteRunner) SizeOfReturnsOnCall(i int, result1 string, result2
 	}{result1, result2}
 }
 
+func (fake *FakeRemoteRunner) WriteFile(arg1 string, arg2 []byte) error {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.writeFileMutex.Lock()
+	ret, specificReturn := fake.writeFileReturnsOnCall[len(fake.writeFileArgsForCall)]
+	fake.writeFileArgsForCall = append(fake.writeFileArgsForCall, struct {
+		arg1 string
+		arg2 []byte
+	}{arg1, arg2Copy})
+	fake.recordInvocation("WriteFile", []interface{}{arg1, arg2Copy})
+	fake.writeFileMutex.Unlock()
+	if fake.WriteFileStub != nil {
+		return fake.WriteFileStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.writeFileReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeRemoteRunner) WriteFileCallCount() int {
+	fake.writeFileMutex.RLock()
+	defer fake.writeFileMutex.RUnlock()
+	return len(fake.writeFileArgsForCall)
+}
+
+func (fake *FakeRemoteRunner) WriteFileCalls(stub func(string, []byte) error) {
+	fake.writeFileMutex.Lock()
+	defer fake.writeFileMutex.Unlock()
+	fake.WriteFileStub = stub
+}
+
+func (fake *FakeRemoteRunner) WriteFileArgsForCall(i int) (string, []byte) {
+	fake.writeFileMutex.RLock()
+	defer fake.writeFileMutex.RUnlock()
+	argsForCall := fake.writeFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRemoteRunner) WriteFileReturns(result1 error) {
+	fake.writeFileMutex.Lock()
+	defer fake.writeFileMutex.Unlock()
+	fake.WriteFileStub = nil
+	fake.writeFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRemoteRunner) WriteFileReturnsOnCall(i int, result1 error) {
+	fake.writeFileMutex.Lock()
+	defer fake.writeFileMutex.Unlock()
+	fake.WriteFileStub = nil
+	if fake.writeFileReturnsOnCall == nil {
+		fake.writeFileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeFileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRemoteRunner) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.archiveAndDownloadMutex.RLock()
 	defer fake.archiveAndDownloadMutex.RUnlock()
+	fake.archiveAndDownloadFilesMutex.RLock()
+	defer fake.archiveAndDownloadFilesMutex.RUnlock()
 	fake.checksumDirectoryMutex.RLock()
 	defer fake.checksumDirectoryMutex.RUnlock()
 	fake.connectedUsernameMutex.RLock()
@@ -997,6 +1178,8 @@ func (fake *FakeRemoteRunner) Invocations() map[string][][]interface{} {
 	defer fake.sizeInBytesMutex.RUnlock()
 	fake.sizeOfMutex.RLock()
 	defer fake.sizeOfMutex.RUnlock()
+	fake.writeFileMutex.RLock()
+	defer fake.writeFileMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"io"
 	"sync"
 
@@ -9,10 +10,11 @@ import (
 )
 
 type FakeSSHConnection struct {
-	RunStub        func(string) ([]byte, []byte, int, error)
+	RunStub        func(context.Context, string) ([]byte, []byte, int, error)
 	runMutex       sync.RWMutex
 	runArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	runReturns struct {
 		result1 []byte
@@ -26,11 +28,12 @@ type FakeSSHConnection struct {
 		result3 int
 		result4 error
 	}
-	StreamStub        func(string, io.Writer) ([]byte, int, error)
+	StreamStub        func(context.Context, string, io.Writer) ([]byte, int, error)
 	streamMutex       sync.RWMutex
 	streamArgsForCall []struct {
-		arg1 string
-		arg2 io.Writer
+		arg1 context.Context
+		arg2 string
+		arg3 io.Writer
 	}
 	streamReturns struct {
 		result1 []byte
@@ -42,11 +45,12 @@ type FakeSSHConnection struct {
 		result2 int
 		result3 error
 	}
-	StreamStdinStub        func(string, io.Reader) ([]byte, []byte, int, error)
+	StreamStdinStub        func(context.Context, string, io.Reader) ([]byte, []byte, int, error)
 	streamStdinMutex       sync.RWMutex
 	streamStdinArgsForCall []struct {
-		arg1 string
-		arg2 io.Reader
+		arg1 context.Context
+		arg2 string
+		arg3 io.Reader
 	}
 	streamStdinReturns struct {
 		result1 []byte
@@ -74,16 +78,17 @@ type FakeSSHConnection struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeSSHConnection) Run(arg1 string) ([]byte, []byte, int, error) {
+func (fake *FakeSSHConnection) Run(arg1 context.Context, arg2 string) ([]byte, []byte, int, error) {
 	fake.runMutex.Lock()
 	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
 	fake.runArgsForCall = append(fake.runArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("Run", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("Run", []interface{}{arg1, arg2})
 	fake.runMutex.Unlock()
 	if fake.RunStub != nil {
-		return fake.RunStub(arg1)
+		return fake.RunStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -98,17 +103,17 @@ func (fake *FakeSSHConnection) RunCallCount() int {
 	return len(fake.runArgsForCall)
 }
 
-func (fake *FakeSSHConnection) RunCalls(stub func(string) ([]byte, []byte, int, error)) {
+func (fake *FakeSSHConnection) RunCalls(stub func(context.Context, string) ([]byte, []byte, int, error)) {
 	fake.runMutex.Lock()
 	defer fake.runMutex.Unlock()
 	fake.RunStub = stub
 }
 
-func (fake *FakeSSHConnection) RunArgsForCall(i int) string {
+func (fake *FakeSSHConnection) RunArgsForCall(i int) (context.Context, string) {
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
 	argsForCall := fake.runArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeSSHConnection) RunReturns(result1 []byte, result2 []byte, result3 int, result4 error) {
@@ -143,17 +148,18 @@ func (fake *FakeSSHConnection) RunReturnsOnCall(i int, result1 []byte, result2 [
 	}{result1, result2, result3, result4}
 }
 
-func (fake *FakeSSHConnection) Stream(arg1 string, arg2 io.Writer) ([]byte, int, error) {
+func (fake *FakeSSHConnection) Stream(arg1 context.Context, arg2 string, arg3 io.Writer) ([]byte, int, error) {
 	fake.streamMutex.Lock()
 	ret, specificReturn := fake.streamReturnsOnCall[len(fake.streamArgsForCall)]
 	fake.streamArgsForCall = append(fake.streamArgsForCall, struct {
-		arg1 string
-		arg2 io.Writer
-	}{arg1, arg2})
-	fake.recordInvocation("Stream", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 io.Writer
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Stream", []interface{}{arg1, arg2, arg3})
 	fake.streamMutex.Unlock()
 	if fake.StreamStub != nil {
-		return fake.StreamStub(arg1, arg2)
+		return fake.StreamStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -168,17 +174,17 @@ func (fake *FakeSSHConnection) StreamCallCount() int {
 	return len(fake.streamArgsForCall)
 }
 
-func (fake *FakeSSHConnection) StreamCalls(stub func(string, io.Writer) ([]byte, int, error)) {
+func (fake *FakeSSHConnection) StreamCalls(stub func(context.Context, string, io.Writer) ([]byte, int, error)) {
 	fake.streamMutex.Lock()
 	defer fake.streamMutex.Unlock()
 	fake.StreamStub = stub
 }
 
-func (fake *FakeSSHConnection) StreamArgsForCall(i int) (string, io.Writer) {
+func (fake *FakeSSHConnection) StreamArgsForCall(i int) (context.Context, string, io.Writer) {
 	fake.streamMutex.RLock()
 	defer fake.streamMutex.RUnlock()
 	argsForCall := fake.streamArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeSSHConnection) StreamReturns(result1 []byte, result2 int, result3 error) {
@@ -210,17 +216,18 @@ func (fake *FakeSSHConnection) StreamReturnsOnCall(i int, result1 []byte, result
 	}{result1, result2, result3}
 }
 
-func (fake *FakeSSHConnection) StreamStdin(arg1 string, arg2 io.Reader) ([]byte, []byte, int, error) {
+func (fake *FakeSSHConnection) StreamStdin(arg1 context.Context, arg2 string, arg3 io.Reader) ([]byte, []byte, int, error) {
 	fake.streamStdinMutex.Lock()
 	ret, specificReturn := fake.streamStdinReturnsOnCall[len(fake.streamStdinArgsForCall)]
 	fake.streamStdinArgsForCall = append(fake.streamStdinArgsForCall, struct {
-		arg1 string
-		arg2 io.Reader
-	}{arg1, arg2})
-	fake.recordInvocation("StreamStdin", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 io.Reader
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("StreamStdin", []interface{}{arg1, arg2, arg3})
 	fake.streamStdinMutex.Unlock()
 	if fake.StreamStdinStub != nil {
-		return fake.StreamStdinStub(arg1, arg2)
+		return fake.StreamStdinStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -235,17 +242,17 @@ func (fake *FakeSSHConnection) StreamStdinCallCount() int {
 	return len(fake.streamStdinArgsForCall)
 }
 
-func (fake *FakeSSHConnection) StreamStdinCalls(stub func(string, io.Reader) ([]byte, []byte, int, error)) {
+func (fake *FakeSSHConnection) StreamStdinCalls(stub func(context.Context, string, io.Reader) ([]byte, []byte, int, error)) {
 	fake.streamStdinMutex.Lock()
 	defer fake.streamStdinMutex.Unlock()
 	fake.StreamStdinStub = stub
 }
 
-func (fake *FakeSSHConnection) StreamStdinArgsForCall(i int) (string, io.Reader) {
+func (fake *FakeSSHConnection) StreamStdinArgsForCall(i int) (context.Context, string, io.Reader) {
 	fake.streamStdinMutex.RLock()
 	defer fake.streamStdinMutex.RUnlock()
 	argsForCall := fake.streamStdinArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeSSHConnection) StreamStdinReturns(result1 []byte, result2 []byte, result3 int, result4 error) {
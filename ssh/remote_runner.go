@@ -1,6 +1,8 @@
 package ssh
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"strconv"
@@ -14,18 +16,20 @@ import (
 //go:generate counterfeiter -o fakes/fake_remote_runner.go . RemoteRunner
 type RemoteRunner interface {
 	ConnectedUsername() string
-	DirectoryExists(dir string) (bool, error)
+	DirectoryExists(ctx context.Context, dir string) (bool, error)
 	RemoveDirectory(dir string) error
-	ArchiveAndDownload(directory string, writer io.Writer) error
+	ArchiveAndDownload(ctx context.Context, directory string, writer io.Writer) error
+	ArchiveAndDownloadFiles(ctx context.Context, directory string, files []string, writer io.Writer) error
 	CreateDirectory(directory string) error
-	ExtractAndUpload(reader io.Reader, directory string) error
-	SizeOf(path string) (string, error)
-	SizeInBytes(path string) (int, error)
-	ChecksumDirectory(path string) (map[string]string, error)
-	RunScript(path, label string) (string, error)
-	RunScriptWithEnv(path string, env map[string]string, label string) (string, error)
-	FindFiles(pattern string) ([]string, error)
+	ExtractAndUpload(ctx context.Context, reader io.Reader, directory string) error
+	SizeOf(ctx context.Context, path string) (string, error)
+	SizeInBytes(ctx context.Context, path string) (int, error)
+	ChecksumDirectory(ctx context.Context, path string) (map[string]string, error)
+	RunScript(ctx context.Context, path, label string) (string, error)
+	RunScriptWithEnv(ctx context.Context, path string, env map[string]string, label string) (string, error)
+	FindFiles(ctx context.Context, pattern string) ([]string, error)
 	IsWindows() (bool, error)
+	WriteFile(path string, contents []byte) error
 }
 
 type SshRemoteRunner struct {
@@ -49,8 +53,8 @@ func (r SshRemoteRunner) ConnectedUsername() string {
 	return r.connection.Username()
 }
 
-func (r SshRemoteRunner) DirectoryExists(dir string) (bool, error) {
-	_, _, exitCode, err := r.connection.Run(fmt.Sprintf("sudo stat %s", dir))
+func (r SshRemoteRunner) DirectoryExists(ctx context.Context, dir string) (bool, error) {
+	_, _, exitCode, err := r.connection.Run(ctx, fmt.Sprintf("sudo stat %s", dir))
 	return exitCode == 0, err
 }
 
@@ -64,18 +68,47 @@ func (r SshRemoteRunner) RemoveDirectory(dir string) error {
 	return err
 }
 
-func (r SshRemoteRunner) ArchiveAndDownload(directory string, writer io.Writer) error {
-	stderr, exitCode, err := r.connection.Stream(fmt.Sprintf("sudo tar -C %s -c .", directory), writer)
+func (r SshRemoteRunner) ArchiveAndDownload(ctx context.Context, directory string, writer io.Writer) error {
+	stderr, exitCode, err := r.connection.Stream(ctx, fmt.Sprintf("sudo tar -C %s -c .", directory), writer)
 	return r.logAndCheckErrors([]byte{}, stderr, exitCode, err, "")
 }
 
-func (r SshRemoteRunner) ExtractAndUpload(reader io.Reader, directory string) error {
-	stdout, stderr, exitCode, err := r.connection.StreamStdin(fmt.Sprintf("sudo sh -c 'tar -C %s -x'", directory), reader)
+// ArchiveAndDownloadFiles streams a tar of only the given files, named relative
+// to the directory. The file list is written next to the directory first, as it
+// can be too long for the command line.
+func (r SshRemoteRunner) ArchiveAndDownloadFiles(ctx context.Context, directory string, files []string, writer io.Writer) error {
+	fileList := directory + ".files"
+
+	var contents []byte
+	for _, file := range files {
+		contents = append(append(contents, file...), 0)
+	}
+
+	if err := r.WriteFile(fileList, contents); err != nil {
+		return err
+	}
+
+	stderr, exitCode, err := r.connection.Stream(ctx, fmt.Sprintf("sudo tar -C %s -c --null -T %s", directory, fileList), writer)
+	if err := r.logAndCheckErrors([]byte{}, stderr, exitCode, err, ""); err != nil {
+		return err
+	}
+
+	_, err = r.runOnInstance(fmt.Sprintf("sudo rm -f %s", fileList))
+	return err
+}
+
+func (r SshRemoteRunner) WriteFile(path string, contents []byte) error {
+	stdout, stderr, exitCode, err := r.connection.StreamStdin(context.Background(), fmt.Sprintf("sudo sh -c 'mkdir -p $(dirname %s) && cat > %s'", path, path), bytes.NewReader(contents))
+	return r.logAndCheckErrors(stdout, stderr, exitCode, err, "")
+}
+
+func (r SshRemoteRunner) ExtractAndUpload(ctx context.Context, reader io.Reader, directory string) error {
+	stdout, stderr, exitCode, err := r.connection.StreamStdin(ctx, fmt.Sprintf("sudo sh -c 'tar -C %s -x'", directory), reader)
 	return r.logAndCheckErrors(stdout, stderr, exitCode, err, "")
 }
 
-func (r SshRemoteRunner) SizeOf(path string) (string, error) {
-	stdout, err := r.runOnInstance(fmt.Sprintf("sudo du -sh %s", path))
+func (r SshRemoteRunner) SizeOf(ctx context.Context, path string) (string, error) {
+	stdout, err := r.runOnInstanceWithLabel(ctx, fmt.Sprintf("sudo du -sh %s", path), "")
 	if err != nil {
 		return "", err
 	}
@@ -83,8 +116,8 @@ func (r SshRemoteRunner) SizeOf(path string) (string, error) {
 	return strings.Fields(string(stdout))[0], nil
 }
 
-func (r SshRemoteRunner) SizeInBytes(path string) (int, error) {
-	stdout, err := r.runOnInstance(fmt.Sprintf("sudo du -s %s", path))
+func (r SshRemoteRunner) SizeInBytes(ctx context.Context, path string) (int, error) {
+	stdout, err := r.runOnInstanceWithLabel(ctx, fmt.Sprintf("sudo du -s %s", path), "")
 	if err != nil {
 		return 0, err
 	}
@@ -97,8 +130,8 @@ func (r SshRemoteRunner) SizeInBytes(path string) (int, error) {
 	return size * 1024, nil
 }
 
-func (r SshRemoteRunner) ChecksumDirectory(path string) (map[string]string, error) {
-	stdout, err := r.runOnInstance(fmt.Sprintf("sudo sh -c 'cd %s && find . -type f | xargs shasum -a 256'", path))
+func (r SshRemoteRunner) ChecksumDirectory(ctx context.Context, path string) (map[string]string, error) {
+	stdout, err := r.runOnInstanceWithLabel(ctx, fmt.Sprintf("sudo sh -c 'cd %s && find . -type f | xargs shasum -a 256'", path), "")
 	if err != nil {
 		return nil, err
 	}
@@ -106,21 +139,21 @@ func (r SshRemoteRunner) ChecksumDirectory(path string) (map[string]string, erro
 	return convertShasToMap(stdout), nil
 }
 
-func (r SshRemoteRunner) RunScript(path, label string) (string, error) {
-	return r.RunScriptWithEnv(path, map[string]string{}, label)
+func (r SshRemoteRunner) RunScript(ctx context.Context, path, label string) (string, error) {
+	return r.RunScriptWithEnv(ctx, path, map[string]string{}, label)
 }
 
-func (r SshRemoteRunner) RunScriptWithEnv(path string, env map[string]string, label string) (string, error) {
+func (r SshRemoteRunner) RunScriptWithEnv(ctx context.Context, path string, env map[string]string, label string) (string, error) {
 	var varsList = ""
 	for varName, value := range env {
 		varsList = varsList + varName + "=" + value + " "
 	}
 
-	return r.runOnInstanceWithLabel("sudo "+varsList+path, label)
+	return r.runOnInstanceWithLabel(ctx, "sudo "+varsList+path, label)
 }
 
-func (r SshRemoteRunner) FindFiles(pattern string) ([]string, error) {
-	stdout, stderr, exitCode, err := r.connection.Run(fmt.Sprintf("sudo sh -c 'find %s -type f'", pattern))
+func (r SshRemoteRunner) FindFiles(ctx context.Context, pattern string) ([]string, error) {
+	stdout, stderr, exitCode, err := r.connection.Run(ctx, fmt.Sprintf("sudo sh -c 'find %s -type f'", pattern))
 
 	r.logOutput(stdout, stderr, "find files")
 
@@ -142,7 +175,7 @@ func (r SshRemoteRunner) FindFiles(pattern string) ([]string, error) {
 }
 
 func (r SshRemoteRunner) IsWindows() (bool, error) {
-	stdout, _, _, err := r.connection.Run(`echo %OS%`)
+	stdout, _, _, err := r.connection.Run(context.Background(), `echo %OS%`)
 	if err != nil {
 		return false, err
 	}
@@ -151,11 +184,11 @@ func (r SshRemoteRunner) IsWindows() (bool, error) {
 }
 
 func (r SshRemoteRunner) runOnInstance(cmd string) (string, error) {
-	return r.runOnInstanceWithLabel(cmd, "")
+	return r.runOnInstanceWithLabel(context.Background(), cmd, "")
 }
 
-func (r SshRemoteRunner) runOnInstanceWithLabel(cmd, label string) (string, error) {
-	stdout, stderr, exitCode, runErr := r.connection.Run(cmd)
+func (r SshRemoteRunner) runOnInstanceWithLabel(ctx context.Context, cmd, label string) (string, error) {
+	stdout, stderr, exitCode, runErr := r.connection.Run(ctx, cmd)
 
 	err := r.logAndCheckErrors(stdout, stderr, exitCode, runErr, label)
 	if err != nil {
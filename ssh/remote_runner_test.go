@@ -2,6 +2,7 @@ package ssh_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"log"
 
@@ -52,7 +53,7 @@ var _ = Describe("SshRemoteRunner", func() {
 	})
 
 	var runCommand = func(cmd string) string {
-		stdout, stderr, status, err := sshConnection.Run(cmd)
+		stdout, stderr, status, err := sshConnection.Run(context.Background(), cmd)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(status).To(Equal(0), string(stderr))
 		return string(stdout)
@@ -71,7 +72,7 @@ var _ = Describe("SshRemoteRunner", func() {
 	Describe("DirectoryExists", func() {
 		Context("When the directory does not exist", func() {
 			It("returns false", func() {
-				Expect(sshRemoteRunner.DirectoryExists("/tmp/non-existing-dir")).To(BeFalse())
+				Expect(sshRemoteRunner.DirectoryExists(context.Background(), "/tmp/non-existing-dir")).To(BeFalse())
 			})
 		})
 
@@ -82,7 +83,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns false", func() {
-				Expect(sshRemoteRunner.DirectoryExists("/tmp/an-existing-dir")).To(BeTrue())
+				Expect(sshRemoteRunner.DirectoryExists(context.Background(), "/tmp/an-existing-dir")).To(BeTrue())
 			})
 		})
 
@@ -92,7 +93,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.DirectoryExists("whatever")
+				_, err := sshRemoteRunner.DirectoryExists(context.Background(), "whatever")
 				Expect(err).To(MatchError(ContainSubstring("ssh.Dial failed")))
 			})
 		})
@@ -102,7 +103,7 @@ var _ = Describe("SshRemoteRunner", func() {
 		It("creates a directory", func() {
 			makeAccessibleOnlyByRoot("/tmp")
 			Expect(sshRemoteRunner.CreateDirectory("/tmp/a-new-directory")).To(Succeed())
-			Expect(sshRemoteRunner.DirectoryExists("/tmp/a-new-directory")).To(BeTrue())
+			Expect(sshRemoteRunner.DirectoryExists(context.Background(), "/tmp/a-new-directory")).To(BeTrue())
 		})
 
 		Context("When the ssh connection fails", func() {
@@ -128,7 +129,7 @@ var _ = Describe("SshRemoteRunner", func() {
 				err := sshRemoteRunner.RemoveDirectory("/tmp/existing-directory")
 
 				Expect(err).NotTo(HaveOccurred())
-				Expect(sshRemoteRunner.DirectoryExists("/tmp/non-existing-dir")).To(BeFalse())
+				Expect(sshRemoteRunner.DirectoryExists(context.Background(), "/tmp/non-existing-dir")).To(BeFalse())
 			})
 		})
 
@@ -153,7 +154,7 @@ var _ = Describe("SshRemoteRunner", func() {
 
 			By("downloading and archiving the directory")
 			archiveFile := makeTmpFile("remote-runner-test-")
-			err := sshRemoteRunner.ArchiveAndDownload("/tmp/dir-to-archive", archiveFile)
+			err := sshRemoteRunner.ArchiveAndDownload(context.Background(), "/tmp/dir-to-archive", archiveFile)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fileSize(archiveFile)).To(BeNumerically(">", 0))
 
@@ -161,7 +162,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			runCommand("mkdir -p /tmp/uploaded-dir")
 			makeAccessibleOnlyByRoot("/tmp/uploaded-dir")
 			archiveFile = resetCursor(archiveFile)
-			err = sshRemoteRunner.ExtractAndUpload(archiveFile, "/tmp/uploaded-dir")
+			err = sshRemoteRunner.ExtractAndUpload(context.Background(), archiveFile, "/tmp/uploaded-dir")
 			Expect(err).NotTo(HaveOccurred())
 			lsOutput := runCommand("sudo ls /tmp/uploaded-dir")
 			Expect(lsOutput).To(Equal("file1\nfile2\n"))
@@ -171,7 +172,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			Context("when the command fails", func() {
 				It("returns an error", func() {
 					archiveFile := makeTmpFile("remote-runner-test-")
-					err := sshRemoteRunner.ArchiveAndDownload("/tmp/unexisting-dir", archiveFile)
+					err := sshRemoteRunner.ArchiveAndDownload(context.Background(), "/tmp/unexisting-dir", archiveFile)
 					Expect(err).To(MatchError(ContainSubstring("No such file or directory")))
 				})
 			})
@@ -183,7 +184,7 @@ var _ = Describe("SshRemoteRunner", func() {
 
 				It("returns an error", func() {
 					archiveFile := makeTmpFile("remote-runner-test-")
-					err := sshRemoteRunner.ArchiveAndDownload("/tmp/unexisting-dir", archiveFile)
+					err := sshRemoteRunner.ArchiveAndDownload(context.Background(), "/tmp/unexisting-dir", archiveFile)
 					Expect(err).To(MatchError(ContainSubstring("ssh.Dial failed")))
 				})
 			})
@@ -193,7 +194,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			Context("when the command fails", func() {
 				It("returns an error", func() {
 					notATar := makeTmpFile("remote-runner-test-")
-					err := sshRemoteRunner.ExtractAndUpload(notATar, "/tmp/arbitrary-dir")
+					err := sshRemoteRunner.ExtractAndUpload(context.Background(), notATar, "/tmp/arbitrary-dir")
 					Expect(err).To(MatchError(ContainSubstring("This does not look like a tar archive")))
 				})
 			})
@@ -205,7 +206,7 @@ var _ = Describe("SshRemoteRunner", func() {
 
 				It("returns an error", func() {
 					arbitraryFile := makeTmpFile("remote-runner-test-")
-					err := sshRemoteRunner.ExtractAndUpload(arbitraryFile, "/tmp/arbitrary-dir")
+					err := sshRemoteRunner.ExtractAndUpload(context.Background(), arbitraryFile, "/tmp/arbitrary-dir")
 					Expect(err).To(MatchError(ContainSubstring("ssh.Dial failed")))
 				})
 			})
@@ -222,13 +223,13 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns a string with the specified file or directory size", func() {
-				Expect(sshRemoteRunner.SizeOf("/tmp/a-dir")).To(Equal("1.5M"))
+				Expect(sshRemoteRunner.SizeOf(context.Background(), "/tmp/a-dir")).To(Equal("1.5M"))
 			})
 		})
 
 		Context("when the directory does not exist", func() {
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.SizeOf("/tmp/not-a-file")
+				_, err := sshRemoteRunner.SizeOf(context.Background(), "/tmp/not-a-file")
 				Expect(err).To(MatchError(ContainSubstring("No such file or directory")))
 			})
 		})
@@ -239,7 +240,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.SizeOf("whatever")
+				_, err := sshRemoteRunner.SizeOf(context.Background(), "whatever")
 				Expect(err).To(MatchError(ContainSubstring("ssh.Dial failed")))
 			})
 		})
@@ -255,13 +256,13 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns a string with the specified file or directory size", func() {
-				Expect(sshRemoteRunner.SizeInBytes("/tmp/a-dir")).To(Equal(1540096))
+				Expect(sshRemoteRunner.SizeInBytes(context.Background(), "/tmp/a-dir")).To(Equal(1540096))
 			})
 		})
 
 		Context("when the directory does not exist", func() {
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.SizeInBytes("/tmp/not-a-file")
+				_, err := sshRemoteRunner.SizeInBytes(context.Background(), "/tmp/not-a-file")
 				Expect(err).To(MatchError(ContainSubstring("No such file or directory")))
 			})
 		})
@@ -272,7 +273,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.SizeInBytes("whatever")
+				_, err := sshRemoteRunner.SizeInBytes(context.Background(), "whatever")
 				Expect(err).To(MatchError(ContainSubstring("ssh.Dial failed")))
 			})
 		})
@@ -288,7 +289,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("calculates the SHA256 checksum for each file in the directory", func() {
-				Expect(sshRemoteRunner.ChecksumDirectory("/tmp/a-dir")).To(SatisfyAll(
+				Expect(sshRemoteRunner.ChecksumDirectory(context.Background(), "/tmp/a-dir")).To(SatisfyAll(
 					HaveLen(2),
 					HaveKeyWithValue("./file1", "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"),
 					HaveKeyWithValue("./file2", "7d865e959b2466918c9863afca942d0fb89d7c9ac0c99bafc3749504ded97730"),
@@ -298,7 +299,7 @@ var _ = Describe("SshRemoteRunner", func() {
 
 		Context("when the directory does not exist", func() {
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.ChecksumDirectory("/tmp/not-a-dir")
+				_, err := sshRemoteRunner.ChecksumDirectory(context.Background(), "/tmp/not-a-dir")
 				Expect(err).To(MatchError(ContainSubstring("can't cd to /tmp/not-a-dir")))
 			})
 		})
@@ -309,7 +310,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.ChecksumDirectory("whatever")
+				_, err := sshRemoteRunner.ChecksumDirectory(context.Background(), "whatever")
 				Expect(err).To(MatchError(ContainSubstring("ssh.Dial failed")))
 			})
 		})
@@ -322,7 +323,7 @@ var _ = Describe("SshRemoteRunner", func() {
 				runCommand("echo 'env' > /tmp/example-script")
 				makeAccessibleOnlyByRoot("/tmp/example-script")
 
-				stdout, err := sshRemoteRunner.RunScriptWithEnv("/tmp/example-script", map[string]string{"env1": "foo", "env2": "bar"}, "")
+				stdout, err := sshRemoteRunner.RunScriptWithEnv(context.Background(), "/tmp/example-script", map[string]string{"env1": "foo", "env2": "bar"}, "")
 
 				Expect(err).NotTo(HaveOccurred())
 
@@ -335,7 +336,7 @@ var _ = Describe("SshRemoteRunner", func() {
 
 		Context("when the script is not there", func() {
 			It("returns a helpful error", func() {
-				_, err := sshRemoteRunner.RunScriptWithEnv("/tmp/example-script", map[string]string{"env1": "foo", "env2": "bar"}, "")
+				_, err := sshRemoteRunner.RunScriptWithEnv(context.Background(), "/tmp/example-script", map[string]string{"env1": "foo", "env2": "bar"}, "")
 
 				Expect(err).To(MatchError(ContainSubstring("command not found")))
 
@@ -347,7 +348,7 @@ var _ = Describe("SshRemoteRunner", func() {
 				runCommand("echo '>&2 echo example script has errorred; exit 12' > /tmp/example-script")
 				runCommand("chmod +x /tmp/example-script")
 
-				_, err := sshRemoteRunner.RunScriptWithEnv("/tmp/example-script", map[string]string{"env1": "foo", "env2": "bar"}, "")
+				_, err := sshRemoteRunner.RunScriptWithEnv(context.Background(), "/tmp/example-script", map[string]string{"env1": "foo", "env2": "bar"}, "")
 
 				Expect(err).To(MatchError(ContainSubstring("example script has errorred - exit code 12")))
 
@@ -359,7 +360,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.RunScriptWithEnv("whatever", map[string]string{}, "")
+				_, err := sshRemoteRunner.RunScriptWithEnv(context.Background(), "whatever", map[string]string{}, "")
 				Expect(err).To(MatchError(ContainSubstring("ssh.Dial failed")))
 			})
 		})
@@ -373,7 +374,7 @@ var _ = Describe("SshRemoteRunner", func() {
 				runCommand("touch /tmp/script-to-not-find")
 				makeAccessibleOnlyByRoot("/tmp")
 
-				files, err := sshRemoteRunner.FindFiles("/tmp/*to-find*")
+				files, err := sshRemoteRunner.FindFiles(context.Background(), "/tmp/*to-find*")
 				Expect(err).NotTo(HaveOccurred())
 				Expect(files).To(ConsistOf(
 					"/tmp/script-to-find",
@@ -385,7 +386,7 @@ var _ = Describe("SshRemoteRunner", func() {
 
 		Context("when there are no files that match the pattern", func() {
 			It("returns exactly those files", func() {
-				files, err := sshRemoteRunner.FindFiles("/tmp/this-file")
+				files, err := sshRemoteRunner.FindFiles(context.Background(), "/tmp/this-file")
 				Expect(err).NotTo(HaveOccurred())
 				Expect(files).To(HaveLen(0))
 			})
@@ -393,7 +394,7 @@ var _ = Describe("SshRemoteRunner", func() {
 
 		Context("when the find command errors", func() {
 			It("bubbles the error up", func() {
-				_, err := sshRemoteRunner.FindFiles("; cause-an-error")
+				_, err := sshRemoteRunner.FindFiles(context.Background(), "; cause-an-error")
 				Expect(err).To(MatchError(ContainSubstring("not found")))
 			})
 		})
@@ -403,7 +404,7 @@ var _ = Describe("SshRemoteRunner", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := sshRemoteRunner.FindFiles("whatever")
+				_, err := sshRemoteRunner.FindFiles(context.Background(), "whatever")
 				Expect(err).To(MatchError(ContainSubstring("ssh.Dial failed")))
 			})
 		})
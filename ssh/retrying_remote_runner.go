@@ -0,0 +1,92 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
+	"golang.org/x/crypto/ssh"
+)
+
+// NewRetryingRemoteRunnerFactory wraps the remote runners built by factory so
+// that their idempotent, read-only commands are retried according to policy.
+func NewRetryingRemoteRunnerFactory(factory RemoteRunnerFactory, policy retry.Policy) RemoteRunnerFactory {
+	return func(host, user, privateKey string, publicKeyCallback ssh.HostKeyCallback, publicKeyAlgorithm []string, logger Logger) (RemoteRunner, error) {
+		remoteRunner, err := factory(host, user, privateKey, publicKeyCallback, publicKeyAlgorithm, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewRetryingRemoteRunner(remoteRunner, host, policy, logger), nil
+	}
+}
+
+type RetryingRemoteRunner struct {
+	RemoteRunner
+	host   string
+	policy retry.Policy
+	logger Logger
+}
+
+func NewRetryingRemoteRunner(remoteRunner RemoteRunner, host string, policy retry.Policy, logger Logger) RetryingRemoteRunner {
+	return RetryingRemoteRunner{
+		RemoteRunner: remoteRunner,
+		host:         host,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+func (r RetryingRemoteRunner) DirectoryExists(ctx context.Context, dir string) (bool, error) {
+	var exists bool
+	err := r.retry(ctx, fmt.Sprintf("checking whether %s exists", dir), func() error {
+		var err error
+		exists, err = r.RemoteRunner.DirectoryExists(ctx, dir)
+		return err
+	})
+	return exists, err
+}
+
+func (r RetryingRemoteRunner) SizeOf(ctx context.Context, path string) (string, error) {
+	var size string
+	err := r.retry(ctx, fmt.Sprintf("finding the size of %s", path), func() error {
+		var err error
+		size, err = r.RemoteRunner.SizeOf(ctx, path)
+		return err
+	})
+	return size, err
+}
+
+func (r RetryingRemoteRunner) SizeInBytes(ctx context.Context, path string) (int, error) {
+	var size int
+	err := r.retry(ctx, fmt.Sprintf("finding the size in bytes of %s", path), func() error {
+		var err error
+		size, err = r.RemoteRunner.SizeInBytes(ctx, path)
+		return err
+	})
+	return size, err
+}
+
+func (r RetryingRemoteRunner) ChecksumDirectory(ctx context.Context, path string) (map[string]string, error) {
+	var checksums map[string]string
+	err := r.retry(ctx, fmt.Sprintf("calculating the checksums of %s", path), func() error {
+		var err error
+		checksums, err = r.RemoteRunner.ChecksumDirectory(ctx, path)
+		return err
+	})
+	return checksums, err
+}
+
+func (r RetryingRemoteRunner) FindFiles(ctx context.Context, pattern string) ([]string, error) {
+	var files []string
+	err := r.retry(ctx, fmt.Sprintf("finding files matching %s", pattern), func() error {
+		var err error
+		files, err = r.RemoteRunner.FindFiles(ctx, pattern)
+		return err
+	})
+	return files, err
+}
+
+func (r RetryingRemoteRunner) retry(ctx context.Context, description string, attempt func() error) error {
+	return r.policy.Do(ctx, r.logger, fmt.Sprintf("%s on %s", description, r.host), attempt)
+}
@@ -0,0 +1,112 @@
+package ssh_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/retry"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
+	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("RetryingRemoteRunner", func() {
+	var (
+		remoteRunner         *fakes.FakeRemoteRunner
+		retryingRemoteRunner ssh.RetryingRemoteRunner
+		logOutput            *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		remoteRunner = new(fakes.FakeRemoteRunner)
+		logOutput = gbytes.NewBuffer()
+		logger := boshlog.New(boshlog.LevelDebug, log.New(logOutput, "[ssh-test] ", log.Lshortfile))
+		policy := retry.Policy{
+			Attempts:     2,
+			Backoff:      time.Millisecond,
+			ErrorClasses: []retry.ErrorClass{retry.ConnectionErrors},
+		}
+
+		retryingRemoteRunner = ssh.NewRetryingRemoteRunner(remoteRunner, "10.0.0.1", policy, logger)
+	})
+
+	It("retries idempotent commands that fail with a transient error", func() {
+		remoteRunner.SizeInBytesReturnsOnCall(0, 0, errors.Wrap(io.EOF, "ssh.Run failed"))
+		remoteRunner.SizeInBytesReturnsOnCall(1, 42, nil)
+
+		size, err := retryingRemoteRunner.SizeInBytes(context.Background(), "/var/vcap/store/bbr-backup")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(size).To(Equal(42))
+		Expect(remoteRunner.SizeInBytesCallCount()).To(Equal(2))
+		Expect(string(logOutput.Contents())).To(ContainSubstring(
+			"finding the size in bytes of /var/vcap/store/bbr-backup on 10.0.0.1 failed on attempt 1 of 2",
+		))
+	})
+
+	It("passes the caller's context to the remote runner", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, err := retryingRemoteRunner.FindFiles(ctx, "/var/vcap/jobs/*/bin/bbr/*")
+
+		Expect(err).NotTo(HaveOccurred())
+		actualCtx, _ := remoteRunner.FindFilesArgsForCall(0)
+		Expect(actualCtx).To(Equal(ctx))
+	})
+
+	Context("when the context is cancelled while waiting to retry", func() {
+		BeforeEach(func() {
+			logger := boshlog.New(boshlog.LevelDebug, log.New(logOutput, "[ssh-test] ", log.Lshortfile))
+			policy := retry.Policy{
+				Attempts:     5,
+				Backoff:      time.Hour,
+				ErrorClasses: []retry.ErrorClass{retry.ConnectionErrors},
+			}
+
+			retryingRemoteRunner = ssh.NewRetryingRemoteRunner(remoteRunner, "10.0.0.1", policy, logger)
+		})
+
+		It("stops retrying without waiting for the backoff", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			remoteRunner.DirectoryExistsStub = func(context.Context, string) (bool, error) {
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return false, errors.Wrap(io.EOF, "ssh.Run failed")
+			}
+
+			errs := make(chan error, 1)
+			go func() {
+				_, err := retryingRemoteRunner.DirectoryExists(ctx, "/var/vcap/store/bbr-backup")
+				errs <- err
+			}()
+
+			Eventually(errs, time.Second).Should(Receive(MatchError(ContainSubstring("ssh.Run failed"))))
+			Expect(remoteRunner.DirectoryExistsCallCount()).To(Equal(1))
+		})
+	})
+
+	It("does not retry commands that fail with an error that is not transient", func() {
+		remoteRunner.ChecksumDirectoryReturns(nil, errors.New("exit code 1"))
+
+		_, err := retryingRemoteRunner.ChecksumDirectory(context.Background(), "/var/vcap/store/bbr-backup")
+
+		Expect(err).To(MatchError("exit code 1"))
+		Expect(remoteRunner.ChecksumDirectoryCallCount()).To(Equal(1))
+	})
+
+	It("does not retry commands that are not idempotent", func() {
+		remoteRunner.RemoveDirectoryReturns(io.EOF)
+
+		err := retryingRemoteRunner.RemoveDirectory("/var/vcap/store/bbr-backup")
+
+		Expect(err).To(MatchError(io.EOF))
+		Expect(remoteRunner.RemoveDirectoryCallCount()).To(Equal(1))
+	})
+})
@@ -41,3 +41,8 @@ func (i DeployedInstance) cleanupArtifact() error {
 
 	return nil
 }
+
+// IsBootstrap is always true, as a standalone deployment only has one instance.
+func (i DeployedInstance) IsBootstrap() bool {
+	return true
+}
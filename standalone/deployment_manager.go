@@ -1,6 +1,8 @@
 package standalone
 
 import (
+	"context"
+
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/instance"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/orchestrator"
 	"github.com/cloudfoundry-incubator/bosh-backup-and-restore/ssh"
@@ -36,7 +38,7 @@ func NewDeploymentManager(
 	}
 }
 
-func (dm DeploymentManager) Find(deploymentName string) (orchestrator.Deployment, error) {
+func (dm DeploymentManager) Find(ctx context.Context, deploymentName string) (orchestrator.Deployment, error) {
 	keyContents, err := ioutil.ReadFile(dm.privateKeyFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed reading private key")
@@ -50,7 +52,7 @@ func (dm DeploymentManager) Find(deploymentName string) (orchestrator.Deployment
 	instanceIdentifier := instance.InstanceIdentifier{InstanceGroupName: "bosh", InstanceId: "0"}
 
 	//TODO: change instanceIdentifier, its not always bosh
-	jobs, err := dm.jobFinder.FindJobs(instanceIdentifier, remoteRunner, instance.NewNoopManifestQuerier())
+	jobs, err := dm.jobFinder.FindJobs(ctx, instanceIdentifier, remoteRunner, instance.NewNoopManifestQuerier())
 	if err != nil {
 		return nil, err
 	}
@@ -63,3 +65,7 @@ func (dm DeploymentManager) Find(deploymentName string) (orchestrator.Deployment
 func (DeploymentManager) SaveManifest(deploymentName string, artifact orchestrator.Backup) error {
 	return nil
 }
+
+func (DeploymentManager) DirectorInfo() (orchestrator.DirectorInfo, error) {
+	return orchestrator.DirectorInfo{}, nil
+}
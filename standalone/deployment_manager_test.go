@@ -1,6 +1,7 @@
 package standalone_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -50,7 +51,7 @@ var _ = Describe("DeploymentManager", func() {
 		var fakeJobs orchestrator.Jobs
 
 		JustBeforeEach(func() {
-			actualDeployment, actualError = deploymentManager.Find(deploymentName)
+			actualDeployment, actualError = deploymentManager.Find(context.Background(), deploymentName)
 		})
 
 		Context("success", func() {